@@ -23,22 +23,53 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
+	"slices"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/log"
 	zone "github.com/lrstanley/bubblezone"
+	"github.com/mproffitt/delorian/pkg/components"
+	"github.com/mproffitt/delorian/pkg/components/queryinput"
+	"github.com/mproffitt/delorian/pkg/components/tabview"
+	"github.com/mproffitt/delorian/pkg/components/yamlview"
+	"github.com/mproffitt/delorian/pkg/config"
+	"github.com/mproffitt/delorian/pkg/fixtures"
+	"github.com/mproffitt/delorian/pkg/kustomize"
 	"github.com/mproffitt/delorian/pkg/manager"
+	"github.com/mproffitt/delorian/pkg/notify"
+	"github.com/mproffitt/delorian/pkg/registry"
+	"github.com/mproffitt/delorian/pkg/repo/flux"
+	"github.com/mproffitt/delorian/pkg/report"
+	"github.com/mproffitt/delorian/pkg/theme"
 	"github.com/spf13/cobra"
+	"sigs.k8s.io/kustomize/api/types"
 )
 
-var logFile string
+var (
+	alphaPlugins   bool
+	colourDepth    string
+	configFile     string
+	enableExec     bool
+	glyphs         string
+	helm           string
+	loadRestrictor string
+	logFile        string
+	notifyMethod   string
+	paths          []string
+	profile        string
+)
 
 var rootCmd = &cobra.Command{
-	Use:   "ff",
+	Use:   "ff [path...]",
 	Short: "Flux Build and Diff UI",
 	Long: `Scans the current directory for kustomization files and offers
     intergrated and interactive build and search tooling for browsing
-    rendered manifests`,
+    rendered manifests.
+
+    One or more repository paths may be given to scan multiple
+    repositories at once`,
+	Args: cobra.ArbitraryArgs,
 	// Uncomment the following line if your bare application
 	// has an action associated with it:
 	Run: func(cmd *cobra.Command, args []string) {
@@ -49,7 +80,8 @@ var rootCmd = &cobra.Command{
 			}
 		}
 
-		log.SetOutput(io.Discard)
+		// Recent log lines are always kept for bug reports
+		log.SetOutput(report.Log)
 		if logFile != "" {
 			f, err := tea.LogToFile(logFile, "debug")
 			if err != nil {
@@ -61,23 +93,165 @@ var rootCmd = &cobra.Command{
 					log.Error("failed to close logfile", "file", logFile, "error", err)
 				}
 			}()
-			log.SetOutput(f)
+			log.SetOutput(io.MultiWriter(f, report.Log))
+		}
+
+		// Styles pick up the palette when created so the colours
+		// and depth are set before anything is drawn
+		themeFile, err := config.ThemeFile()
+		if err != nil {
+			fmt.Println("fatal:", err)
+			os.Exit(1)
+		}
+		warnings, err := theme.Load(themeFile)
+		if err != nil {
+			fmt.Println("fatal:", err)
+			os.Exit(1)
+		}
+		for _, warning := range warnings {
+			fmt.Println("warning: theme:", warning)
+			log.Warn("theme override ignored", "file", themeFile, "reason", warning)
+		}
+		if err := theme.SetDepth(colourDepth); err != nil {
+			fmt.Println("fatal:", err)
+			os.Exit(1)
+		}
+
+		method, err := notify.ParseMethod(notifyMethod)
+		if err != nil {
+			fmt.Println("fatal:", err)
+			os.Exit(1)
+		}
+		notify.DefaultMethod = method
+
+		restrictions, err := kustomize.ParseLoadRestrictions(loadRestrictor)
+		if err != nil {
+			fmt.Println("fatal:", err)
+			os.Exit(1)
+		}
+		kustomize.LoadRestrictions = restrictions
+		if err := kustomize.ValidateHelm(helm); err != nil {
+			fmt.Println("fatal:", err)
+			os.Exit(1)
+		}
+		kustomize.Helm = helm
+
+		if alphaPlugins {
+			kustomize.PluginRestrictions = types.PluginRestrictionsNone
+		}
+		if enableExec {
+			fmt.Println("warning: kustomize exec plugins are enabled. " +
+				"Repositories can run arbitrary commands as the current user")
+			log.Warn("kustomize exec plugins enabled")
+			kustomize.EnableExec = true
+		}
+
+		if fixtures.Enabled() {
+			if fi, err := os.Stat(fixtures.Dir); err != nil || !fi.IsDir() {
+				fmt.Println("fatal: fixtures must be a directory:", fixtures.Dir)
+				os.Exit(1)
+			}
+			log.Warn("using fixtures in place of flux and kustomize", "dir", fixtures.Dir)
+			components.SetRunner(fixtures.Runner{})
+			kustomize.SetBuilder(fixtures.Builder{})
+		}
+
+		cfg, err := loadConfig()
+		if err != nil {
+			fmt.Println("fatal:", err)
+			os.Exit(1)
+		}
+		if profile != "" {
+			if err := cfg.SetProfile(profile); err != nil {
+				fmt.Println("fatal:", err)
+				os.Exit(1)
+			}
+		}
+		if err := flux.SetExtraFlags(cfg.Flux.BuildFlags, cfg.Flux.DiffFlags); err != nil {
+			fmt.Println("fatal:", err)
+			os.Exit(1)
+		}
+		flux.KeepComments = cfg.Flux.KeepComments
+		if err := flux.SetBaseMarkers(cfg.Scan.BaseMarkers); err != nil {
+			fmt.Println("fatal:", err)
+			os.Exit(1)
+		}
+		flux.SetLabelKeys(cfg.Scan.Labels)
+		if cfg.Scan.WarnAfter != 0 {
+			flux.ScanWarnAfter = cfg.Scan.WarnAfter
+		}
+		if cfg.Scan.Limit != 0 {
+			flux.ScanLimit = cfg.Scan.Limit
+		}
+		if err := tabview.SetTabs(cfg.Layout.Tabs); err != nil {
+			fmt.Println("fatal:", err)
+			os.Exit(1)
+		}
+		yamlview.MaxContentWidth = cfg.Layout.MaxContentWidth
+		if cfg.Layout.TabWidth > 0 {
+			yamlview.TabWidth = cfg.Layout.TabWidth
+		}
+		if !cmd.Flags().Changed("glyphs") && cfg.Layout.Glyphs != "" {
+			glyphs = cfg.Layout.Glyphs
+		}
+		if err := theme.SetGlyphs(glyphs); err != nil {
+			fmt.Println("fatal:", err)
+			os.Exit(1)
+		}
+		queryinput.HistoryFile = cfg.HistoryFile()
+		flux.NotesFile = cfg.NotesFile()
+		components.PasteEndpoint, components.PasteToken = cfg.Paste.Endpoint, cfg.Paste.Token
+		if cfg.Paste.Timeout > 0 {
+			components.PasteTimeout = cfg.Paste.Timeout
+		}
+
+		roots, err := repositoryRoots(append(slices.Clone(paths), args...))
+		if err != nil {
+			fmt.Println("fatal:", err)
+			os.Exit(1)
 		}
 
 		// Enable bubblezone mouse support
 		zone.NewGlobal()
 		zone.SetEnabled(true)
 		// initialise the model and start the program
-		model := manager.New()
+		model := manager.New(cfg, roots...)
 		p := tea.NewProgram(model,
 			tea.WithAltScreen(),
-			tea.WithMouseCellMotion())
+			tea.WithMouseAllMotion())
 		if _, err := p.Run(); err != nil {
 			log.Fatal("could not start program:", "error", err)
 		}
 	},
 }
 
+// repositoryRoots resolves the paths to scan to absolute paths,
+// checking each is a directory. No paths scans the current
+// directory
+func repositoryRoots(paths []string) ([]string, error) {
+	if len(paths) == 0 {
+		paths = []string{"."}
+	}
+	roots := make([]string, 0, len(paths))
+	for _, path := range paths {
+		abs, err := filepath.Abs(path)
+		if err != nil {
+			return nil, fmt.Errorf("cannot resolve %s: %w", path, err)
+		}
+		fi, err := os.Stat(abs)
+		switch {
+		case os.IsNotExist(err):
+			return nil, fmt.Errorf("%s does not exist", path)
+		case err != nil:
+			return nil, fmt.Errorf("cannot scan %s: %w", path, err)
+		case !fi.IsDir():
+			return nil, fmt.Errorf("%s is not a directory", path)
+		}
+		roots = append(roots, abs)
+	}
+	return roots, nil
+}
+
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
@@ -94,4 +268,54 @@ func init() {
 
 	rootCmd.PersistentFlags().StringVarP(&logFile, "logfile", "l",
 		"", "log filename to use (empty = no log, default)")
+	rootCmd.PersistentFlags().StringVarP(&configFile, "config", "c",
+		"", "config file to use (default ~/.config/delorian/config.yaml)")
+	rootCmd.PersistentFlags().StringVarP(&profile, "profile", "p",
+		"", "named profile from the config file to start with")
+	rootCmd.PersistentFlags().StringVar(&flux.CompareRef, "compare-ref", flux.CompareRef,
+		"git ref the working tree is compared against on the Ref Diff tab")
+	rootCmd.PersistentFlags().DurationVar(&flux.WatchInterval, "watch-interval",
+		flux.WatchInterval, "interval between diffs when watch mode is enabled")
+	rootCmd.PersistentFlags().DurationVar(&flux.PollInterval, "poll-interval",
+		flux.PollInterval, "check for changed files on this interval and rescan (0 disables)")
+	rootCmd.PersistentFlags().BoolVar(&flux.LiveStatus, "live-status", false,
+		"show the status of each kustomization in the cluster when it can be reached")
+	rootCmd.PersistentFlags().DurationVar(&flux.LiveInterval, "live-interval",
+		flux.LiveInterval, "interval between fetches of the live status (0 fetches once)")
+	rootCmd.PersistentFlags().BoolVar(&flux.NoCache, "no-cache", false,
+		"parse every file instead of reusing those unchanged since the last run")
+	rootCmd.PersistentFlags().BoolVar(&flux.FileWatch, "watch", false,
+		"rescan as soon as yaml files in the repository change")
+	rootCmd.PersistentFlags().StringVar(&notifyMethod, "notify",
+		string(notify.None), "how to alert on new drift in watch mode (none, bell, notify)")
+	rootCmd.PersistentFlags().StringVar(&loadRestrictor, "load-restrictor",
+		kustomize.LoadRestrictions.String(),
+		"if set to 'LoadRestrictionsRootOnly', kustomizations may not read files outside their root")
+	rootCmd.PersistentFlags().BoolVar(&alphaPlugins, "enable-alpha-plugins",
+		false, "enable kustomize plugins beyond the builtins")
+	rootCmd.PersistentFlags().BoolVar(&enableExec, "enable-exec", false,
+		"DANGEROUS: allow kustomize exec/function plugins to run commands and use the network")
+	rootCmd.PersistentFlags().BoolVar(&registry.Enabled, "check-image-updates", false,
+		"query container registries for newer image tags (requires network access)")
+	rootCmd.PersistentFlags().BoolVar(&report.Redact, "report-redact", report.Redact,
+		"hide paths and substitution values in bug reports exported with ctrl+e")
+	rootCmd.PersistentFlags().StringVar(&helm, "helm", kustomize.HelmAuto,
+		"helm binary used to inflate charts ('auto' to detect from PATH, 'off' to disable)")
+	rootCmd.PersistentFlags().StringVar(&colourDepth, "color", theme.DepthAuto,
+		"colour depth to draw with ("+theme.Depths()+")")
+	rootCmd.PersistentFlags().StringArrayVar(&paths, "path", nil,
+		"repository to scan in place of the current directory, may be repeated")
+	rootCmd.PersistentFlags().StringVar(&glyphs, "glyphs", theme.GlyphsAuto,
+		"draw borders and symbols with ("+theme.Glyphs()+")")
+	rootCmd.PersistentFlags().StringVar(&fixtures.Dir, "fixtures", "",
+		"read flux and kustomize output from this directory instead of running them (for demos and tests)")
+}
+
+// loadConfig loads the config file given on the command line
+// or the default config file if none was given
+func loadConfig() (*config.Config, error) {
+	if configFile != "" {
+		return config.Load(configFile)
+	}
+	return config.New()
 }
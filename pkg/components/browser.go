@@ -0,0 +1,65 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package components
+
+import (
+	"context"
+	"os"
+	"runtime"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/log"
+	"github.com/mproffitt/bmx/pkg/components/toast"
+)
+
+// browser returns the command and arguments which open url in the
+// default browser on goos, or an empty name when there is no
+// display to open it on
+func browser(goos, url string) (string, []string) {
+	switch goos {
+	case "darwin":
+		return "open", []string{url}
+	case "windows":
+		return "rundll32", []string{"url.dll,FileProtocolHandler", url}
+	}
+	if os.Getenv("DISPLAY") == "" && os.Getenv("WAYLAND_DISPLAY") == "" {
+		return "", nil
+	}
+	return "xdg-open", []string{url}
+}
+
+// OpenURLCmd opens url in the default browser and reports the
+// result as a toast.
+//
+// When no browser can be started, for example over ssh, the URL
+// is copied to the clipboard instead
+func OpenURLCmd(url string) tea.Cmd {
+	return func() tea.Msg {
+		name, args := browser(runtime.GOOS, url)
+		if name == "" {
+			return CopyCmd(url, url)()
+		}
+		if _, _, err := Run(context.Background(), name, args); err != nil {
+			log.Debug("browser", "falling back to clipboard", err)
+			return CopyCmd(url, url)()
+		}
+		return toast.NewToastMsg{Type: toast.Info, Message: "Opened " + url}
+	}
+}
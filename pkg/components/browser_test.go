@@ -0,0 +1,86 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package components
+
+import (
+	"errors"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/mproffitt/bmx/pkg/components/toast"
+)
+
+func TestBrowser(t *testing.T) {
+	const url = "https://example.com"
+	t.Setenv("DISPLAY", ":0")
+	for goos, want := range map[string][]string{
+		"darwin":  {"open", url},
+		"windows": {"rundll32", "url.dll,FileProtocolHandler", url},
+		"linux":   {"xdg-open", url},
+	} {
+		name, args := browser(goos, url)
+		if got := append([]string{name}, args...); !slices.Equal(got, want) {
+			t.Errorf("%s: expected %v, got %v", goos, want, got)
+		}
+	}
+
+	t.Setenv("DISPLAY", "")
+	t.Setenv("WAYLAND_DISPLAY", "")
+	if name, _ := browser("linux", url); name != "" {
+		t.Errorf("expected no browser without a display, got %s", name)
+	}
+}
+
+func TestOpenURLCmd(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("the display is only checked on linux")
+	}
+	defer SetRunner(nil)
+	const url = "https://example.com"
+
+	for _, tc := range []struct {
+		name    string
+		display string
+		err     error
+		ran     bool
+		want    string
+	}{
+		{"opened", ":0", nil, true, "Opened " + url},
+		{"no display", "", nil, false, "Copied " + url},
+		{"start error", ":0", errors.New("xdg-open failed"), true, "Copied " + url},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv("DISPLAY", tc.display)
+			t.Setenv("WAYLAND_DISPLAY", "")
+			fake := &fakeRunner{errs: map[string]error{"xdg-open": tc.err}}
+			SetRunner(fake)
+
+			msg, ok := OpenURLCmd(url)().(toast.NewToastMsg)
+			if !ok || !strings.HasPrefix(msg.Message, tc.want) {
+				t.Errorf("expected a toast starting %q, got %#v", tc.want, msg)
+			}
+			if ran := len(fake.ran) == 1 && fake.ran[0][0] == "xdg-open"; ran != tc.ran {
+				t.Errorf("expected xdg-open to run %t, got %v", tc.ran, fake.ran)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package components
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// CadenceFlag marks a reconcile interval worth a second look
+type CadenceFlag int
+
+const (
+	CadenceNormal CadenceFlag = iota
+	CadenceShort
+	CadenceLong
+
+	// CadenceUnset is used when spec.interval is missing or
+	// cannot be parsed, for example when it is substituted
+	CadenceUnset
+)
+
+func (c CadenceFlag) String() string {
+	switch c {
+	case CadenceShort:
+		return "short"
+	case CadenceLong:
+		return "long"
+	case CadenceUnset:
+		return "unset"
+	}
+	return ""
+}
+
+// Cadence is the reconcile interval and timeout of a single
+// kustomization or source as written in the repository
+type Cadence struct {
+	Kind      string
+	Name      string
+	Namespace string
+	Filepath  string
+
+	// Interval and Timeout hold the values as written
+	Interval string
+	Timeout  string
+
+	// Every is the parsed interval, zero when unset
+	Every time.Duration
+	Flag  CadenceFlag
+
+	// TimeoutExceeds is set when the timeout is longer than
+	// the interval between reconciles
+	TimeoutExceeds bool
+}
+
+// Object returns the kind, namespace and name of the object
+func (c Cadence) Object() string {
+	if c.Namespace == "" {
+		return fmt.Sprintf("%s/%s", c.Kind, c.Name)
+	}
+	return fmt.Sprintf("%s/%s/%s", c.Kind, c.Namespace, c.Name)
+}
+
+// CadenceReportMsg carries the intervals found after scanning
+type CadenceReportMsg struct {
+	Entries []Cadence
+}
+
+// CadenceReportCmd returns the entries as a CadenceReportMsg
+func CadenceReportCmd(entries []Cadence) tea.Cmd {
+	return func() tea.Msg {
+		return CadenceReportMsg{Entries: entries}
+	}
+}
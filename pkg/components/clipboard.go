@@ -0,0 +1,47 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package components
+
+import (
+	"fmt"
+
+	"github.com/atotto/clipboard"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/log"
+	"github.com/mproffitt/bmx/pkg/components/toast"
+	"github.com/muesli/termenv"
+)
+
+// CopyCmd copies content to the system clipboard and reports the
+// result as a toast. The copy runs with the command so a slow
+// clipboard utility never blocks the update loop.
+//
+// When no clipboard utility is available, for example over ssh,
+// the terminal is asked to set the clipboard via OSC 52
+func CopyCmd(content, description string) tea.Cmd {
+	return func() tea.Msg {
+		if err := clipboard.WriteAll(content); err != nil {
+			log.Debug("clipboard", "falling back to osc52", err)
+			termenv.DefaultOutput().Copy(content)
+		}
+		return toast.NewToastMsg{Type: toast.Success,
+			Message: fmt.Sprintf("Copied %s to clipboard", description)}
+	}
+}
@@ -0,0 +1,145 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package components
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/log"
+)
+
+// ClusterStatusMsg is sent once the reachability of the
+// current kubernetes context has been checked.
+//
+// When Online is false, commands that require a cluster
+// such as `flux diff` should be disabled
+type ClusterStatusMsg struct {
+	Online  bool
+	Context string
+	Error   error
+}
+
+// kubeContext is the kubernetes context commands are run
+// against. When empty, the current context is used
+var kubeContext struct {
+	sync.RWMutex
+	name string
+}
+
+// SetKubeContext sets the kubernetes context used by flux and
+// kubectl commands. An empty name uses the current context
+func SetKubeContext(name string) {
+	kubeContext.Lock()
+	defer kubeContext.Unlock()
+	kubeContext.name = name
+}
+
+// KubeContext returns the kubernetes context set by SetKubeContext
+func KubeContext() string {
+	kubeContext.RLock()
+	defer kubeContext.RUnlock()
+	return kubeContext.name
+}
+
+// withContext appends the context flag to args when a
+// context has been set
+func withContext(args []string) []string {
+	if context := KubeContext(); context != "" {
+		return append(slices.Clone(args), "--context", context)
+	}
+	return args
+}
+
+// clusterChecks are tried in order until a binary is found
+// that can be used to test if the cluster is reachable
+var clusterChecks = [][]string{
+	{"kubectl", "cluster-info", "--request-timeout=3s"},
+	{"flux", "check", "--pre", "--timeout=3s"},
+}
+
+// ClusterCheckCmd tests if the current kubernetes context can
+// be reached and returns a ClusterStatusMsg with the result
+func ClusterCheckCmd() tea.Cmd {
+	return func() tea.Msg {
+		ctx := context.Background()
+		kubeContext := currentContext(ctx)
+		for _, check := range clusterChecks {
+			_, _, err := Run(ctx, check[0], withContext(check[1:]))
+			if notFound(err) {
+				continue
+			}
+			if err != nil {
+				log.Warn("cluster unreachable, entering offline mode",
+					"context", kubeContext, "error", err)
+				return ClusterStatusMsg{Online: false, Context: kubeContext, Error: err}
+			}
+			return ClusterStatusMsg{Online: true, Context: kubeContext}
+		}
+		return ClusterStatusMsg{
+			Online:  false,
+			Context: kubeContext,
+			Error:   fmt.Errorf("neither kubectl nor flux found in path"),
+		}
+	}
+}
+
+// KubectlExec runs kubectl against the configured context and
+// returns its output
+func KubectlExec(ctx context.Context, args []string) (string, error) {
+	args = withContext(args)
+	log.Debug("kubectl exec", "command", "kubectl "+strings.Join(args, " "))
+	out, _, err := Run(ctx, "kubectl", args)
+	if notFound(err) {
+		return "", fmt.Errorf("kubectl not found in path: %w", err)
+	}
+	return out, err
+}
+
+func currentContext(ctx context.Context) string {
+	if kubeContext := KubeContext(); kubeContext != "" {
+		return kubeContext
+	}
+	kubeContext, _, err := Run(ctx, "kubectl", []string{"config", "current-context"})
+	if err != nil {
+		return ""
+	}
+	return kubeContext
+}
+
+// StatusMsg sets the value of a named segment in the status bar
+//
+// Sending an empty value removes the segment
+type StatusMsg struct {
+	Key   string
+	Value string
+}
+
+// StatusCmd is returned by components that want to display
+// information in the status bar
+func StatusCmd(key, value string) tea.Cmd {
+	return func() tea.Msg {
+		return StatusMsg{Key: key, Value: value}
+	}
+}
@@ -20,12 +20,16 @@
 package diffview
 
 import (
+	"fmt"
 	"slices"
+	"strings"
 
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/charmbracelet/log"
+	zone "github.com/lrstanley/bubblezone"
+	"github.com/mproffitt/bmx/pkg/components/toast"
 	"github.com/mproffitt/bmx/pkg/exec"
 	"github.com/mproffitt/delorian/pkg/components"
 	"github.com/mproffitt/delorian/pkg/components/filter"
@@ -33,10 +37,17 @@ import (
 	"github.com/mproffitt/delorian/pkg/theme"
 )
 
+// DefaultFilters are the kinds and keys hidden from the diff
+// until the user changes the filter
+var DefaultFilters = []string{
+	"metadata.generation",
+}
+
 type Model struct {
 	border     bool
 	entries    []DiffEntry
 	filter     tea.Model
+	filters    []string
 	focus      components.FocusType
 	height     int
 	showFilter bool
@@ -44,9 +55,28 @@ type Model struct {
 	viewport   viewport.Model
 	width      int
 	splash     *splash.Model
-	error      error
+	load       components.Load
+	offline    bool
+	local      bool
+	refresh    bool
+	cursor     int
+	focused    string
+	folded     map[string]bool
+	id         string
+	offsets    []int
+	shown      []int
+	scope      string
+	raw        []components.FluxSection
+	showRaw    bool
+
+	// notices are lines flux printed before the first resource
+	// header, such as warnings
+	notices []string
 }
 
+// loadingMessage is shown on the splash while the diff loads
+const loadingMessage = "Waiting for Kustomization diffing..."
+
 // Create a new Diff model
 //
 // Diffview can be used to display the output of flux diff.
@@ -59,17 +89,35 @@ func New(w, h int, showFilter bool) *Model {
 	m := Model{
 		border:     false,
 		entries:    []DiffEntry{},
+		filters:    slices.Clone(DefaultFilters),
 		focus:      NoFocus,
+		folded:     map[string]bool{},
+		id:         components.NewID(),
 		showFilter: showFilter,
 		style: lipgloss.NewStyle().
 			BorderForeground(theme.Colours.Blue),
 		viewport: viewport.New(w, h),
-		splash:   splash.New("Waiting for Kustomization diffing..."),
+		splash:   splash.New(loadingMessage, splash.Branding{}),
 	}
+	m.load.Start()
 
 	return &m
 }
 
+// SetBranding brands the splash shown while the diff loads
+func (m *Model) SetBranding(branding splash.Branding) *Model {
+	m.splash = splash.New(loadingMessage, branding)
+	return m
+}
+
+// SetLocal marks the view as comparing content which does not
+// come from the cluster, such as two git refs, so it remains
+// available when the cluster is unreachable
+func (m *Model) SetLocal() *Model {
+	m.local = true
+	return m
+}
+
 // Init should be called whenever the diff view is being
 // created. This will initialise any components required
 // by the model
@@ -98,9 +146,18 @@ func (m *Model) NextFocus() components.FocusType {
 			m.filter.(components.Focusable).Blur()
 		}
 	}
+	m.reprint()
 	return m.focus
 }
 
+// reprint refreshes the content so the cursor highlight follows
+// the focus of the viewport
+func (m *Model) reprint() {
+	if m.filter != nil && (len(m.entries) > 0 || m.showRaw) {
+		m.viewport.SetContent(m.content())
+	}
+}
+
 // PreviousFocus returns the focus to the previous entry
 // in the view when tab key switching is required
 func (m *Model) PreviousFocus() components.FocusType {
@@ -122,6 +179,7 @@ func (m *Model) PreviousFocus() components.FocusType {
 			m.filter.(components.Focusable).Focus()
 		}
 	}
+	m.reprint()
 	return m.focus
 }
 
@@ -134,6 +192,8 @@ func (m *Model) SetSize(w, h int) tea.Model {
 	if m.filter != nil {
 		m.filter = m.filter.(*filter.Model).SetSize(w-(theme.Padding+1), h)
 	}
+	// Folded entries flow into as many columns as fit
+	m.reprint()
 	return m
 }
 
@@ -141,33 +201,252 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 	switch msg := msg.(type) {
 	case components.TabChangedMsg:
-		m.splash.SetVisible(true)
-		cmd = splash.TickCmd()
+		if m.offline {
+			break
+		}
+		m.load.Start()
+		cmd = m.splash.Follow(&m.load)
+	case components.LoadSkippedMsg:
+		m.load.Skip()
+		cmd = m.splash.Follow(&m.load)
+	case components.ClusterStatusMsg:
+		if m.local {
+			break
+		}
+		m.offline = !msg.Online
+		if m.offline {
+			m.load.Skip()
+			m.splash.Follow(&m.load)
+		}
+	case components.WatchRefreshMsg:
+		m.refresh = true
+	case components.DiffFiltersMsg:
+		m.filters = slices.Clone(msg.Filters)
+		if m.filter != nil {
+			m.filter = m.getFilter()
+			m.reprint()
+		}
 	case components.FluxExecMsg:
 		log.Debug("diffview", "update", msg)
-		m.entries = m.parseFluxDiff(msg.Output)
-		m.filter = m.getFilter()
-		m.viewport.SetContent(m.print(m.entries))
-		m.splash.SetVisible(false)
+		entries, notices := m.parseFluxDiff(msg.Output)
+		if len(msg.Sections) > 0 {
+			entries, notices = m.parseSections(msg.Sections)
+		}
+		m.notices = notices
+		if m.refresh && m.filter != nil {
+			// Keep the users filter selection when refreshed by
+			// the watch loop and highlight newly appeared drift
+			m.entries = markFresh(m.entries, entries)
+			if fresh := freshTitles(m.entries); len(fresh) > 0 {
+				cmd = components.DriftDetectedCmd(fresh)
+			}
+		} else {
+			m.entries = entries
+			m.filter = m.getFilter()
+		}
+		m.refresh = false
+		m.load.Done()
+		m.scope = msg.Scope
+		m.raw = rawSections(msg)
+		m.viewport.SetContent(m.content())
+		m.splash.Follow(&m.load)
 	case splash.TickMsg:
 		m.splash, cmd = m.splash.Update(msg)
 	case components.ModelErrorMsg:
-		m.error = msg.Error
-		m.splash.SetVisible(false)
+		m.refresh = false
+		m.load.Fail(msg.Error)
+		m.splash.Follow(&m.load)
 	case tea.KeyMsg, tea.MouseMsg:
 		switch m.focus {
 		case FilterFocus:
+			previous := m.filter.(*filter.Model).Values()
 			m.filter, cmd = m.filter.Update(msg)
-			m.viewport.SetContent(m.print(m.entries))
+			m.viewport.SetContent(m.content())
+			if !slices.Equal(previous, m.filter.(*filter.Model).Values()) {
+				cmd = tea.Batch(cmd, components.UndoPushCmd(m.filterAction(previous)))
+			}
 
 		case ViewportFocus:
+			switch msg := msg.(type) {
+			case tea.KeyMsg:
+				if handled, c := m.navigate(msg); handled {
+					return m, c
+				}
+			case tea.MouseMsg:
+				if m.click(msg) {
+					return m, nil
+				}
+			}
 			m.viewport, cmd = m.viewport.Update(msg)
 		}
 	}
 	return m, cmd
 }
 
+// filterAction restores the filter selection to previous
+func (m *Model) filterAction(previous []string) components.Action {
+	f := m.filter
+	return components.Action{
+		Name: "diff filter",
+		Undo: func() tea.Cmd {
+			// A new diff replaces the filter so there is
+			// nothing left to restore
+			if m.filter != f {
+				return nil
+			}
+			f.(*filter.Model).SetValues(previous)
+			m.reprint()
+			return nil
+		},
+	}
+}
+
+// navigate handles keys which move between entries or change the
+// scope of the diff. Scoping is only offered against the cluster
+func (m *Model) navigate(msg tea.KeyMsg) (bool, tea.Cmd) {
+	switch msg.String() {
+	case "r":
+		if m.local || m.offline {
+			return false, nil
+		}
+		m.load.Start()
+		return true, tea.Batch(
+			m.splash.Follow(&m.load),
+			components.RecursiveDiffCmd())
+	case "backspace":
+		if m.scope == "" {
+			return false, nil
+		}
+		return true, components.TabChangedCmd(components.TabFluxDiff)
+	case "p":
+		if len(m.raw) == 0 {
+			return false, nil
+		}
+		return true, components.PasteCmd(m.plain(), "diff.txt")
+	case "v":
+		// Fall back to the output of flux when the parsed
+		// view drops or misrenders something
+		if len(m.raw) == 0 {
+			return false, nil
+		}
+		m.showRaw = !m.showRaw
+		m.reprint()
+		m.viewport.GotoTop()
+		return true, nil
+	}
+
+	if len(m.shown) == 0 || m.showRaw {
+		return false, nil
+	}
+	switch msg.String() {
+	case "j", "n":
+		m.moveCursor(1)
+	case "k", "N":
+		m.moveCursor(-1)
+	case " ":
+		m.toggle()
+	case "z":
+		m.toggleAll()
+	case "enter":
+		// Grouped entries may come from another kustomization
+		// than the selected one so can't be scoped. Their
+		// drawer is toggled instead
+		entry := m.entries[m.shown[m.cursor]]
+		if m.local || m.offline || entry.Group != "" {
+			m.toggle()
+			return true, nil
+		}
+		kind, namespace, name := entry.Resource()
+		m.load.Start()
+		return true, tea.Batch(
+			m.splash.Follow(&m.load),
+			components.ResourceDiffCmd(kind, namespace, name))
+	case "o":
+		// Grouped entries may be built by another kustomization
+		// so their source can't be found from the selected one
+		entry := m.entries[m.shown[m.cursor]]
+		if entry.Group != "" {
+			return true, toast.NewToastCmd(toast.Warning,
+				"Select "+entry.Group+" to open the source of this resource")
+		}
+		return true, components.DefinitionCmd(entry.Resource())
+	default:
+		return false, nil
+	}
+	return true, nil
+}
+
+// toggle folds or unfolds the entry under the cursor
+func (m *Model) toggle() {
+	m.folded[m.focused] = !m.folded[m.focused]
+	m.reprint()
+	m.follow()
+}
+
+// toggleAll folds every visible entry, or unfolds them all when
+// they are already folded
+func (m *Model) toggleAll() {
+	fold := slices.ContainsFunc(m.shown, func(i int) bool {
+		return !m.folded[m.entries[i].ID()]
+	})
+	for _, i := range m.shown {
+		m.folded[m.entries[i].ID()] = fold
+	}
+	m.reprint()
+	m.follow()
+}
+
+// click moves the cursor to the entry whose title was clicked and
+// toggles its drawer
+func (m *Model) click(msg tea.MouseMsg) bool {
+	if msg.Button != tea.MouseButtonLeft || msg.Action != tea.MouseActionRelease || m.showRaw {
+		return false
+	}
+	for i, index := range m.shown {
+		if zone.Get(m.id + m.entries[index].ID()).InBounds(msg) {
+			m.cursor = i
+			m.focused = m.entries[index].ID()
+			m.toggle()
+			return true
+		}
+	}
+	return false
+}
+
+// moveCursor selects the next or previous visible entry
+func (m *Model) moveCursor(delta int) {
+	m.cursor = max(0, min(len(m.shown)-1, m.cursor+delta))
+	m.focused = m.entries[m.shown[m.cursor]].ID()
+	m.reprint()
+	m.follow()
+}
+
+// follow scrolls the focused entry to the top of the viewport
+// when its title is out of view
+func (m *Model) follow() {
+	offset := m.offsets[m.cursor]
+	if offset < m.viewport.YOffset || offset >= m.viewport.YOffset+m.viewport.Height {
+		m.viewport.SetYOffset(offset)
+	}
+}
+
+// LoadState reports if the view is waiting for content, showing
+// it or showing the error loading ended in
+func (m *Model) LoadState() components.LoadState {
+	return m.load.State()
+}
+
 func (m *Model) View() string {
+	if m.offline {
+		msg := lipgloss.NewStyle().
+			Foreground(theme.Colours.BrightBlack).
+			Render("Cluster unreachable - diff is unavailable in offline mode")
+		msg = lipgloss.Place(m.viewport.Width, m.viewport.Height,
+			lipgloss.Center, lipgloss.Center, msg)
+		m.viewport.SetContent(msg)
+		return m.viewport.View()
+	}
+
 	if m.splash.Visible() {
 		splash := lipgloss.Place(
 			m.viewport.Width,
@@ -180,11 +459,13 @@ func (m *Model) View() string {
 		return m.viewport.View()
 	}
 
-	if m.error != nil {
-		msg := m.error.Error()
-		switch e := m.error.(type) {
+	if err := m.load.Err(); err != nil {
+		msg := err.Error()
+		switch e := err.(type) {
 		case *exec.BmxExecError:
 			msg = e.StyledError(m.width)
+		case *components.FluxError:
+			msg = e.StyledError(m.width)
 		}
 		msg = lipgloss.NewStyle().
 			Foreground(theme.Colours.Red).
@@ -196,15 +477,18 @@ func (m *Model) View() string {
 		return m.viewport.View()
 	}
 
-	if len(m.entries) == 0 {
+	if len(m.entries) == 0 && !m.showRaw {
 		tick := lipgloss.NewStyle().
 			Foreground(theme.Colours.BrightGreen).
-			Render("✔")
+			Render(theme.Glyph("✔"))
 		msg := lipgloss.NewStyle().
 			Foreground(theme.Colours.Blue).
 			MarginLeft(1).
 			Render("No diff detected")
 		msg = lipgloss.JoinHorizontal(lipgloss.Top, tick, msg)
+		if header := m.header(); header != "" {
+			msg = lipgloss.JoinVertical(lipgloss.Center, msg, header)
+		}
 		msg = lipgloss.Place(m.viewport.Width, m.viewport.Height,
 			lipgloss.Center, lipgloss.Center, msg)
 		m.viewport.SetContent(msg)
@@ -213,9 +497,13 @@ func (m *Model) View() string {
 
 	m.viewport.Width = m.width
 	m.viewport.Height = m.height - m.filter.(*filter.Model).GetHeight() - theme.Padding
+	header := m.header()
+	if header != "" {
+		m.viewport.Height--
+	}
 	view := m.viewport.View()
 	if m.border {
-		m.style = m.style.Border(lipgloss.RoundedBorder(), true)
+		m.style = m.style.Border(theme.RoundedBorder(), true)
 	}
 
 	switch m.focus {
@@ -225,6 +513,10 @@ func (m *Model) View() string {
 		view = m.style.BorderForeground(theme.Colours.Black).Render(view)
 	}
 
+	if header != "" {
+		view = lipgloss.JoinVertical(lipgloss.Left, header, view)
+	}
+
 	content := view
 	if m.showFilter {
 		content = lipgloss.JoinVertical(lipgloss.Left, m.filter.View(), view)
@@ -235,10 +527,8 @@ func (m *Model) View() string {
 }
 
 func (m *Model) getFilter() tea.Model {
-	options := []string{
-		"metadata.generation",
-	}
-	selected := options
+	options := slices.Clone(m.filters)
+	selected := slices.Clone(m.filters)
 
 	for _, item := range m.entries {
 		options = append(options, item.GetKind())
@@ -250,14 +540,245 @@ func (m *Model) getFilter() tea.Model {
 		SetSize(m.width-(theme.Padding+1), m.height)
 }
 
+// markFresh flags any entry in current that was not present in
+// previous as newly appeared drift
+func markFresh(previous, current []DiffEntry) []DiffEntry {
+	seen := make(map[string]bool, len(previous))
+	for _, entry := range previous {
+		seen[entry.Title] = true
+	}
+	for i := range current {
+		current[i].fresh = !seen[current[i].Title]
+	}
+	return current
+}
+
+// freshTitles returns the titles of all entries marked as new
+func freshTitles(entries []DiffEntry) []string {
+	titles := make([]string, 0)
+	for _, entry := range entries {
+		if entry.fresh {
+			titles = append(titles, entry.Title)
+		}
+	}
+	return titles
+}
+
 func (m *Model) print(entries []DiffEntry) string {
 	content := make([]string, 0)
 	filters := m.filter.(*filter.Model).Values()
 	log.Debug("printing entries", "filters", filters)
-	for _, entry := range entries {
+
+	m.shown = m.shown[:0]
+	for i, entry := range entries {
 		if !slices.Contains(filters, entry.Kind) {
-			content = append(content, entry.WithFilter(filters...).View(m.width))
+			m.shown = append(m.shown, i)
+		}
+	}
+	m.cursor = m.focusIndex()
+
+	// Record where each visible entry starts so the cursor can
+	// scroll straight to it
+	m.offsets = m.offsets[:0]
+	offset := 0
+	group := ""
+
+	// Folded entries are held back until the next open entry or
+	// group so runs of them can be laid out in columns
+	run := make([]folded, 0)
+	flush := func() {
+		if len(run) == 0 {
+			return
+		}
+		grid, offsets := m.flow(run)
+		for i, o := range offsets {
+			if run[i].top >= 0 {
+				o = run[i].top - offset
+			}
+			m.offsets = append(m.offsets, offset+o)
+		}
+		offset += lipgloss.Height(grid)
+		content = append(content, grid)
+		run = run[:0]
+	}
+
+	for i, index := range m.shown {
+		entry := entries[index]
+		top := -1
+		if entry.Group != group {
+			flush()
+			top = offset
+			group = entry.Group
+			header := groupHeader(group, m.width)
+			offset += lipgloss.Height(header)
+			content = append(content, header)
+		}
+		state := EntryOpenIndicator
+		if m.folded[entry.ID()] {
+			state = EntryClosedIndicator
+		}
+		selected := m.focus == ViewportFocus && i == m.cursor
+		entry = entry.WithFilter(filters...).
+			WithZone(m.id + entry.ID()).
+			WithState(state).
+			WithSelected(selected)
+		if entry.closed() && m.columns() > 1 {
+			run = append(run, folded{view: entry.View(m.width / m.columns()), top: top})
+			continue
+		}
+
+		flush()
+		if top < 0 {
+			top = offset
 		}
+		m.offsets = append(m.offsets, top)
+		view := entry.View(m.width)
+		offset += lipgloss.Height(view)
+		content = append(content, view)
 	}
+	flush()
 	return lipgloss.JoinVertical(lipgloss.Left, content...)
 }
+
+// ColumnWidth is the narrowest column folded entries are laid out
+// in. Views wide enough for several columns flow runs of folded
+// entries into them side by side
+const ColumnWidth = 56
+
+// folded is a rendered entry waiting to be laid out in columns.
+// top is where the entry starts when a group header comes first,
+// or -1
+type folded struct {
+	view string
+	top  int
+}
+
+// columns is the number of columns which fit the view
+func (m *Model) columns() int {
+	return max(1, m.width/ColumnWidth)
+}
+
+// flow lays out folded entries in columns, filling each column
+// before the next, and returns where each entry starts relative to
+// the top of the layout. Columns are balanced so a short run isn't
+// spread thinly across the view
+func (m *Model) flow(run []folded) (string, []int) {
+	cols := min(m.columns(), len(run))
+	rows := (len(run) + cols - 1) / cols
+	width := m.width / m.columns()
+
+	offsets := make([]int, len(run))
+	columns := make([]string, 0, cols)
+	for start := 0; start < len(run); start += rows {
+		end := min(start+rows, len(run))
+		views := make([]string, 0, end-start)
+		height := 0
+		for i := start; i < end; i++ {
+			offsets[i] = height
+			height += lipgloss.Height(run[i].view)
+			views = append(views, run[i].view)
+		}
+		columns = append(columns, lipgloss.NewStyle().
+			Width(width).
+			Render(lipgloss.JoinVertical(lipgloss.Left, views...)))
+	}
+	return lipgloss.JoinHorizontal(lipgloss.Top, columns...), offsets
+}
+
+// header summarises the drift and explains how to return from a
+// scoped diff or the raw output of flux
+func (m *Model) header() string {
+	parts := make([]string, 0)
+	if summary := m.summary(); summary != "" {
+		parts = append(parts, summary)
+	}
+	if m.scope != "" {
+		parts = append(parts, theme.Glyph("Scoped to "+m.scope+" · backspace for the full diff"))
+	}
+	if m.showRaw {
+		parts = append(parts, theme.Glyph("Raw flux output · v for the parsed view"))
+	} else if len(m.notices) > 0 {
+		parts = append(parts, fmt.Sprintf(theme.Glyph("⚠ %d lines of flux output before the diff · v to view"),
+			len(m.notices)))
+	}
+	return lipgloss.NewStyle().
+		Foreground(theme.Colours.BrightBlack).
+		Render(strings.Join(parts, theme.Glyph(" · ")))
+}
+
+// summary counts the resources, additions and deletions left
+// visible by the filter
+func (m *Model) summary() string {
+	if m.showRaw || m.filter == nil || len(m.entries) == 0 {
+		return ""
+	}
+	filters := m.filter.(*filter.Model).Values()
+	var resources, additions, deletions int
+	for _, entry := range m.entries {
+		if slices.Contains(filters, entry.Kind) {
+			continue
+		}
+		resources++
+		for _, change := range entry.Changes {
+			if slices.Contains(filters, change.Key) {
+				continue
+			}
+			for _, set := range change.Changes {
+				additions += countLines(set.Addition)
+				deletions += countLines(set.Deletion)
+			}
+		}
+	}
+	return fmt.Sprintf(theme.Glyph("%s drifted · %s · %s"),
+		plural(resources, "resource"), plural(additions, "addition"),
+		plural(deletions, "deletion"))
+}
+
+// countLines counts the lines which are drawn, skipping the
+// blank ones ChangeSet.View leaves out
+func countLines(lines []string) int {
+	count := 0
+	for _, line := range lines {
+		if line != "" {
+			count++
+		}
+	}
+	return count
+}
+
+func plural(n int, noun string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, noun)
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
+
+// groupHeader names the kustomization the entries below it
+// belong to in a recursive diff
+func groupHeader(group string, width int) string {
+	return lipgloss.NewStyle().
+		Foreground(theme.Colours.Blue).
+		Bold(true).
+		Border(theme.NormalBorder(), false, false, true, false).
+		BorderForeground(theme.Colours.Black).
+		Width(max(0, width-theme.Padding)).
+		MarginBottom(1).
+		Render(group)
+}
+
+// focusIndex finds the focused entry amongst those shown. The
+// focus is kept by identity so it survives refreshes and filter
+// changes. When the entry has gone the nearest one is focused
+func (m *Model) focusIndex() int {
+	for i, index := range m.shown {
+		if m.entries[index].ID() == m.focused {
+			return i
+		}
+	}
+	if len(m.shown) == 0 {
+		return 0
+	}
+	cursor := max(0, min(len(m.shown)-1, m.cursor))
+	m.focused = m.entries[m.shown[cursor]].ID()
+	return cursor
+}
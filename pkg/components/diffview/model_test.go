@@ -0,0 +1,430 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package diffview
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mproffitt/delorian/pkg/components"
+	"github.com/mproffitt/delorian/pkg/components/filter"
+	"github.com/mproffitt/delorian/pkg/components/splash"
+	"github.com/mproffitt/delorian/pkg/testutil"
+)
+
+func TestMain(m *testing.M) {
+	testutil.Setup()
+	os.Exit(m.Run())
+}
+
+func fixture(t *testing.T, name string) string {
+	t.Helper()
+	content, err := os.ReadFile("testdata/" + name)
+	if err != nil {
+		t.Fatalf("unable to read fixture %q: %v", name, err)
+	}
+	return string(content)
+}
+
+func TestViewSplash(t *testing.T) {
+	m := New(80, 30, true).SetSize(80, 30)
+	testutil.RequireGolden(t, m.View())
+}
+
+func TestViewEmptyDiff(t *testing.T) {
+	m := testutil.Drive(New(80, 20, true).SetSize(80, 20),
+		components.FluxExecMsg{Output: ""})
+	testutil.RequireGolden(t, m.View())
+}
+
+func TestViewError(t *testing.T) {
+	m := testutil.Drive(New(80, 20, true).SetSize(80, 20),
+		components.ModelErrorMsg{Error: fmt.Errorf("unable to reach cluster")})
+	testutil.RequireGolden(t, m.View())
+}
+
+func TestViewDrift(t *testing.T) {
+	m := testutil.Drive(New(80, 30, true).SetSize(80, 30),
+		components.FluxExecMsg{Output: fixture(t, "drift.txt")})
+	testutil.RequireGolden(t, m.View())
+}
+
+func TestViewRefDiff(t *testing.T) {
+	m := testutil.Drive(New(80, 30, true).SetLocal().SetSize(80, 30),
+		components.ClusterStatusMsg{Online: false},
+		components.FluxExecMsg{Output: fixture(t, "refdiff.txt")})
+	testutil.RequireGolden(t, m.View())
+}
+
+func TestNavigateEntries(t *testing.T) {
+	m := New(80, 4, true).SetSize(80, 4).(*Model)
+	testutil.Drive(m, components.FluxExecMsg{Output: fixture(t, "drift.txt")})
+	m.NextFocus()
+	m.NextFocus()
+	if m.focus != ViewportFocus {
+		t.Fatalf("expected viewport focus, got %v", m.focus)
+	}
+
+	press := func(key string) tea.Msg {
+		if key == "enter" {
+			_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+			return drain(cmd)
+		}
+		_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+		return drain(cmd)
+	}
+
+	press("n")
+	if m.cursor != 1 || m.viewport.YOffset != m.offsets[1] {
+		t.Errorf("expected cursor on the second entry, got %d at %d", m.cursor, m.viewport.YOffset)
+	}
+	press("n")
+	if m.cursor != 1 {
+		t.Errorf("expected cursor to stop at the last entry, got %d", m.cursor)
+	}
+	press("N")
+	if m.cursor != 0 {
+		t.Errorf("expected cursor on the first entry, got %d", m.cursor)
+	}
+
+	msg, ok := press("enter").(components.ResourceDiffMsg)
+	if !ok {
+		t.Fatalf("expected ResourceDiffMsg from enter")
+	}
+	want := components.ResourceDiffMsg{Kind: "Deployment", Namespace: "default", Name: "podinfo"}
+	if msg != want {
+		t.Errorf("expected %+v, got %+v", want, msg)
+	}
+}
+
+func TestScopedDiff(t *testing.T) {
+	m := New(80, 30, true).SetSize(80, 30).(*Model)
+	testutil.Drive(m, components.FluxExecMsg{
+		Output: fixture(t, "drift.txt"),
+		Scope:  "Deployment/default/podinfo",
+	})
+	m.NextFocus()
+	m.NextFocus()
+	if !strings.Contains(m.View(), "Scoped to Deployment/default/podinfo") {
+		t.Errorf("expected the scope header in the view")
+	}
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	if msg, ok := drain(cmd).(components.TabChangedMsg); !ok || msg.NewTab != components.TabFluxDiff {
+		t.Errorf("expected backspace to rerun the full diff")
+	}
+}
+
+func TestResourceClusterScoped(t *testing.T) {
+	kind, namespace, name := DiffEntry{Kind: "Namespace", Namespace: "podinfo"}.Resource()
+	if kind != "Namespace" || namespace != "" || name != "podinfo" {
+		t.Errorf("unexpected resource %s/%s/%s", kind, namespace, name)
+	}
+}
+
+// drain runs cmd and returns the first message which is not
+// produced by the splash animation
+func drain(cmd tea.Cmd) tea.Msg {
+	if cmd == nil {
+		return nil
+	}
+	msg := cmd()
+	if batch, ok := msg.(tea.BatchMsg); ok {
+		for _, c := range batch {
+			if c == nil {
+				continue
+			}
+			if m := c(); m != nil {
+				if _, tick := m.(splash.TickMsg); !tick {
+					return m
+				}
+			}
+		}
+		return nil
+	}
+	return msg
+}
+
+func TestFoldPersists(t *testing.T) {
+	m := New(80, 30, true).SetSize(80, 30).(*Model)
+	testutil.Drive(m, components.FluxExecMsg{Output: fixture(t, "drift.txt")})
+	m.NextFocus()
+	m.NextFocus()
+
+	m.Update(tea.KeyMsg{Type: tea.KeySpace})
+	if !m.folded["Deployment/default/podinfo"] {
+		t.Fatal("expected space to fold the focused entry")
+	}
+	if strings.Contains(m.viewport.View(), "spec.replicas") {
+		t.Error("expected the folded entry to hide its changes")
+	}
+
+	// Focus the second entry then refresh with the entries reversed
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")})
+	testutil.Drive(m, components.WatchRefreshMsg{},
+		components.FluxExecMsg{Output: reversed(fixture(t, "drift.txt"))})
+	if m.focused != "ConfigMap/default/podinfo-config" || m.cursor != 0 {
+		t.Errorf("expected focus to follow the resource, got %q at %d", m.focused, m.cursor)
+	}
+	if !m.folded["Deployment/default/podinfo"] ||
+		strings.Contains(m.viewport.View(), "spec.replicas") {
+		t.Error("expected the fold to survive the refresh")
+	}
+}
+
+// reversed swaps the order of the entries in a flux diff
+func reversed(diff string) string {
+	entries := strings.Split(diff, EntryIndicator)[1:]
+	slices.Reverse(entries)
+	for i := range entries {
+		entries[i] = EntryIndicator + strings.TrimRight(entries[i], "\n") + "\n\n"
+	}
+	return strings.Join(entries, "")
+}
+
+func TestSections(t *testing.T) {
+	m := New(80, 40, true).SetSize(80, 40).(*Model)
+	testutil.Drive(m, components.FluxExecMsg{
+		Scope: "infra and 2 dependent kustomizations",
+		Sections: []components.FluxSection{
+			{Title: "flux-system/infra", Output: fixture(t, "drift.txt")},
+			{Title: "flux-system/apps", Output: ""},
+			{Title: "flux-system/podinfo", Error: fmt.Errorf("path not found")},
+		},
+	})
+	if len(m.entries) != 3 {
+		t.Fatalf("expected 3 entries, got %d", len(m.entries))
+	}
+	last := m.entries[2]
+	if last.Group != "flux-system/podinfo" || last.Kind != ErrorKind || !last.failed {
+		t.Errorf("expected the failed section as an error entry, got %+v", last)
+	}
+	if m.entries[0].ID() == "Deployment/default/podinfo" {
+		t.Error("expected grouped entries to include the group in their identity")
+	}
+
+	view := m.View()
+	for _, want := range []string{"flux-system/infra", "flux-system/podinfo", "path not found"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("expected %q in the view", want)
+		}
+	}
+	if strings.Contains(view, "flux-system/apps") {
+		t.Error("expected sections without drift to be left out")
+	}
+}
+
+func TestRawToggle(t *testing.T) {
+	m := New(80, 40, true).SetSize(80, 40).(*Model)
+	testutil.Drive(m, components.FluxExecMsg{Output: fixture(t, "drift.txt")})
+	m.NextFocus()
+	m.NextFocus()
+	if strings.Contains(m.viewport.View(), "metadata.generation") {
+		t.Fatal("expected the parsed view to hide filtered changes")
+	}
+
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("v")})
+	if !m.showRaw {
+		t.Fatal("expected v to show the raw output")
+	}
+	view := m.View()
+	if !strings.Contains(view, "metadata.generation") || !strings.Contains(view, "Raw flux output") {
+		t.Errorf("expected the raw output to be shown unfiltered, got\n%s", view)
+	}
+
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("v")})
+	if m.showRaw || strings.Contains(m.View(), "metadata.generation") {
+		t.Error("expected v to return to the parsed view")
+	}
+}
+
+func TestRawUnparsed(t *testing.T) {
+	m := New(80, 20, true).SetSize(80, 20).(*Model)
+	testutil.Drive(m, components.FluxExecMsg{Output: "✗ something the parser does not know\n"})
+	m.NextFocus()
+	m.NextFocus()
+	if !strings.Contains(m.View(), "No diff detected") {
+		t.Fatal("expected the parser to drop the output")
+	}
+
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("v")})
+	if !strings.Contains(m.View(), "something the parser does not know") {
+		t.Error("expected the raw view to show output the parser dropped")
+	}
+}
+
+func TestParseLeadingNotice(t *testing.T) {
+	m := New(80, 30, true)
+	entries, notices := m.parseFluxDiff(fixture(t, "notice.txt"))
+	if len(notices) != 3 {
+		t.Errorf("expected the 3 lines before the first header as notices, got %q", notices)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(entries))
+	}
+	// The first change is not followed by an empty line before
+	// the next header and must still be kept
+	for _, entry := range entries {
+		if len(entry.Changes) != 1 || len(entry.Changes[0].Changes) != 1 {
+			t.Errorf("expected a single change on %s, got %+v", entry.Title, entry.Changes)
+		}
+	}
+
+	v := testutil.Drive(New(80, 30, true).SetSize(80, 30),
+		components.FluxExecMsg{Output: fixture(t, "notice.txt")}).View()
+	if !strings.Contains(v, "3 lines of flux output before the diff") {
+		t.Errorf("expected the notices to be mentioned in the header, got\n%s", v)
+	}
+}
+
+func TestFoldAll(t *testing.T) {
+	m := New(80, 30, true).SetLocal().SetSize(80, 30).(*Model)
+	testutil.Drive(m, components.FluxExecMsg{Output: fixture(t, "drift.txt")})
+	m.NextFocus()
+	m.NextFocus()
+
+	// Local diffs can't be scoped so enter toggles the drawer
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if !m.folded["Deployment/default/podinfo"] {
+		t.Fatal("expected enter to fold the focused entry")
+	}
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("z")})
+	if !m.folded["Deployment/default/podinfo"] || !m.folded["ConfigMap/default/podinfo-config"] {
+		t.Errorf("expected z to fold every entry, got %v", m.folded)
+	}
+	if view := m.viewport.View(); !strings.Contains(view, string(EntryClosedIndicator)) ||
+		strings.Contains(view, "spec.replicas") {
+		t.Errorf("expected only the titles to be shown, got\n%s", view)
+	}
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("z")})
+	if m.folded["Deployment/default/podinfo"] || m.folded["ConfigMap/default/podinfo-config"] {
+		t.Errorf("expected z to unfold every entry once all are folded, got %v", m.folded)
+	}
+}
+
+func TestColumns(t *testing.T) {
+	m := New(ColumnWidth*2, 30, true).SetLocal().SetSize(ColumnWidth*2, 30).(*Model)
+	testutil.Drive(m, components.FluxExecMsg{Output: fixture(t, "drift.txt")})
+	m.NextFocus()
+	m.NextFocus()
+
+	sameLine := func() bool {
+		for _, line := range strings.Split(m.viewport.View(), "\n") {
+			if strings.Contains(line, "podinfo-config") &&
+				strings.Contains(strings.ReplaceAll(line, "podinfo-config", ""), "podinfo") {
+				return true
+			}
+		}
+		return false
+	}
+
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("z")})
+	if !sameLine() {
+		t.Errorf("expected folded entries side by side, got\n%s", m.viewport.View())
+	}
+	if m.offsets[0] != m.offsets[1] {
+		t.Errorf("expected entries in one row to start on the same line, got %v", m.offsets)
+	}
+
+	// An opened entry takes the full width
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if sameLine() || !strings.Contains(m.viewport.View(), "spec.replicas") {
+		t.Errorf("expected the opened entry on its own, got\n%s", m.viewport.View())
+	}
+
+	// Narrowing the view reflows into a single column
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m.SetSize(ColumnWidth, 30)
+	if sameLine() {
+		t.Errorf("expected a single column on a narrow view, got\n%s", m.viewport.View())
+	}
+}
+
+func TestSummary(t *testing.T) {
+	m := New(80, 30, true).SetSize(80, 30).(*Model)
+	testutil.Drive(m, components.FluxExecMsg{Output: fixture(t, "drift.txt")})
+	if want := "2 resources drifted · 2 additions · 2 deletions"; !strings.Contains(m.View(), want) {
+		t.Errorf("expected %q in the header, got\n%s", want, m.View())
+	}
+
+	// The counts follow the filter
+	f := m.filter.(*filter.Model)
+	f.SetValues(append(f.Values(), "ConfigMap"))
+	m.reprint()
+	if want := "1 resource drifted · 1 addition · 1 deletion"; !strings.Contains(m.View(), want) {
+		t.Errorf("expected %q in the header, got\n%s", want, m.View())
+	}
+}
+
+func TestParseTitles(t *testing.T) {
+	m := New(80, 30, true)
+	entries, _ := m.parseFluxDiff(fixture(t, "scopes.txt"))
+	want := [][3]string{
+		{"ClusterRole", "", "podinfo-reader"},
+		{"Deployment", "apps", "podinfo"},
+		{"ConfigMap", "apps", "nested/name"},
+		{"", "", ""},
+		{"malformed", "", ""},
+	}
+	if len(entries) != len(want) {
+		t.Fatalf("expected %d entries, got %d", len(want), len(entries))
+	}
+	for i, entry := range entries {
+		if got := [3]string{entry.Kind, entry.Namespace, entry.Name}; got != want[i] {
+			t.Errorf("entry %d: expected %v, got %v", i, want[i], got)
+		}
+	}
+	if c := entries[0].Changes; len(c) != 1 || c[0].Key != "rules.0.verbs" {
+		t.Errorf("expected the cluster role change to be parsed, got %+v", c)
+	}
+	if kind, namespace, name := entries[0].Resource(); kind != "ClusterRole" || namespace != "" ||
+		name != "podinfo-reader" {
+		t.Errorf("unexpected resource %s/%s/%s", kind, namespace, name)
+	}
+
+	// Malformed titles are shown rather than breaking the view
+	testutil.Drive(m.SetSize(80, 30), components.FluxExecMsg{Output: fixture(t, "scopes.txt")})
+	if view := m.View(); !strings.Contains(view, "malformed") {
+		t.Errorf("expected every entry to be shown, got\n%s", view)
+	}
+}
+
+func TestDiffFilters(t *testing.T) {
+	m := New(80, 30, true).SetSize(80, 30).(*Model)
+	testutil.Drive(m,
+		components.DiffFiltersMsg{Filters: []string{"metadata.generation", "Secret"}},
+		components.FluxExecMsg{Output: fixture(t, "drift.txt")})
+	if values := m.filter.(*filter.Model).Values(); !slices.Contains(values, "Secret") {
+		t.Errorf("expected the profile filter to be selected, got %v", values)
+	}
+
+	// Filters replaced after a diff apply to it straight away
+	testutil.Drive(m, components.DiffFiltersMsg{Filters: []string{"metadata.generation"}})
+	if values := m.filter.(*filter.Model).Values(); slices.Contains(values, "Secret") {
+		t.Errorf("expected the profile filter to be removed, got %v", values)
+	}
+	if !slices.Equal(DefaultFilters, []string{"metadata.generation"}) {
+		t.Errorf("expected the default filters to be unchanged, got %v", DefaultFilters)
+	}
+}
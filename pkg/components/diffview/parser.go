@@ -22,15 +22,50 @@ package diffview
 import (
 	"bufio"
 	"strings"
+
+	"github.com/mproffitt/delorian/pkg/components"
 )
 
-// ParseFluxDiff parses the flux diff into structured data
+// parseSections parses the output of several kustomizations,
+// grouping the entries of each under the title of its section.
+// A section which failed is shown as a single error entry
+func (m *Model) parseSections(sections []components.FluxSection) ([]DiffEntry, []string) {
+	results := make([]DiffEntry, 0)
+	notices := make([]string, 0)
+	for _, section := range sections {
+		if section.Error != nil {
+			message := section.Error.Error()
+			if e, ok := section.Error.(*components.FluxError); ok {
+				message = e.Summary
+			}
+			results = append(results, DiffEntry{
+				Title:  message,
+				Kind:   ErrorKind,
+				Group:  section.Title,
+				failed: true,
+			})
+			continue
+		}
+		entries, n := m.parseFluxDiff(section.Output)
+		for _, entry := range entries {
+			entry.Group = section.Title
+			results = append(results, entry)
+		}
+		notices = append(notices, n...)
+	}
+	return results, notices
+}
+
+// parseFluxDiff parses the flux diff into structured data
 //
-// This is basically a lexer for flux diff output
-func (m *Model) parseFluxDiff(input string) []DiffEntry {
+// This is basically a lexer for flux diff output. Lines printed
+// before the first resource header, such as warnings, are not
+// part of any entry and are returned as notices
+func (m *Model) parseFluxDiff(input string) ([]DiffEntry, []string) {
 	scanner := bufio.NewScanner(strings.NewReader(input))
 	var (
 		results        []DiffEntry
+		notices        []string
 		currentEntry   *DiffEntry
 		currentChange  *DiffChange
 		lastChange     *ChangeSet
@@ -44,7 +79,7 @@ func (m *Model) parseFluxDiff(input string) []DiffEntry {
 		if len(trimmed) == 0 {
 			switch lastType {
 			case Change:
-				if currentChange != nil {
+				if currentEntry != nil && currentChange != nil {
 					if lastChange != nil {
 						currentChange.Changes = append(currentChange.Changes, *lastChange)
 						lastChange = nil
@@ -62,15 +97,24 @@ func (m *Model) parseFluxDiff(input string) []DiffEntry {
 		// Detect new entry
 		if strings.HasPrefix(line, EntryIndicator) {
 			if currentEntry != nil {
+				// A change not closed by an empty line
+				if currentChange != nil {
+					if lastChange != nil {
+						currentChange.Changes = append(currentChange.Changes, *lastChange)
+					}
+					currentEntry.Changes = append(currentEntry.Changes, *currentChange)
+				}
 				results = append(results, *currentEntry)
 			}
-			title := strings.TrimPrefix(line, EntryIndicator)
-			parts := strings.Split(strings.TrimSuffix(title, " drifted"), "/")
+			currentChange, lastChange = nil, nil
+			lastChangeType = None
+			title := strings.TrimSpace(strings.TrimPrefix(line, EntryIndicator))
+			kind, namespace, name := parseTitle(title)
 			currentEntry = &DiffEntry{
-				Title:     strings.TrimSpace(title),
-				Kind:      parts[0],
-				Name:      parts[2],
-				Namespace: parts[1],
+				Title:     title,
+				Kind:      kind,
+				Name:      name,
+				Namespace: namespace,
 				Changes:   []DiffChange{},
 				state:     EntryOpenIndicator,
 			}
@@ -78,6 +122,11 @@ func (m *Model) parseFluxDiff(input string) []DiffEntry {
 			continue
 		}
 
+		if currentEntry == nil {
+			notices = append(notices, trimmed)
+			continue
+		}
+
 		switch lastType {
 		case Entry, Empty:
 			lastType = Key
@@ -85,6 +134,9 @@ func (m *Model) parseFluxDiff(input string) []DiffEntry {
 				Key: trimmed,
 			}
 		case Key:
+			if currentChange == nil {
+				continue
+			}
 			lastType = Title
 			currentChange.Title = trimmed
 			expected = []rune(trimmed)[0]
@@ -129,5 +181,23 @@ func (m *Model) parseFluxDiff(input string) []DiffEntry {
 		results = append(results, *currentEntry)
 	}
 
-	return results
+	return results, notices
+}
+
+// parseTitle finds the resource an entry title describes. Titles
+// are `kind/namespace/name` followed by the state of the object,
+// e.g. drifted, created or deleted. Cluster scoped resources are
+// titled `kind/name` and anything after the namespace is kept as
+// the name, slashes included. A title without a kind is taken as
+// the kind so the entry can still be shown and filtered
+func parseTitle(title string) (kind, namespace, name string) {
+	id, _, _ := strings.Cut(title, " ")
+	parts := strings.SplitN(id, "/", 3)
+	switch len(parts) {
+	case 1:
+		return parts[0], "", ""
+	case 2:
+		return parts[0], "", parts[1]
+	}
+	return parts[0], parts[1], parts[2]
 }
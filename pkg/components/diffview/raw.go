@@ -0,0 +1,102 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package diffview
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mproffitt/delorian/pkg/components"
+	"github.com/mproffitt/delorian/pkg/theme"
+)
+
+// content renders either the parsed entries or the raw output
+// of flux depending on the view selected
+func (m *Model) content() string {
+	if m.showRaw {
+		return m.printRaw()
+	}
+	return m.print(m.entries)
+}
+
+// printRaw renders the output of flux as it was received with
+// minimal styling so content the parser drops or misrenders can
+// still be read
+func (m *Model) printRaw() string {
+	content := make([]string, 0)
+	for _, section := range m.raw {
+		if section.Title != "" {
+			content = append(content, groupHeader(section.Title, m.width))
+		}
+		if section.Error != nil {
+			content = append(content, lipgloss.NewStyle().
+				Foreground(theme.Colours.Red).
+				Render(section.Error.Error()))
+			continue
+		}
+		for _, line := range strings.Split(strings.TrimRight(section.Output, "\n"), "\n") {
+			content = append(content, rawLine(line))
+		}
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, content...)
+}
+
+// plain returns the output of flux without styling, headed by the
+// title of each section, for sharing
+func (m *Model) plain() string {
+	var b strings.Builder
+	for _, section := range m.raw {
+		if section.Title != "" {
+			b.WriteString("# " + section.Title + "\n")
+		}
+		if section.Error != nil {
+			b.WriteString(section.Error.Error() + "\n")
+			continue
+		}
+		b.WriteString(strings.TrimRight(section.Output, "\n") + "\n")
+	}
+	return b.String()
+}
+
+// rawLine colours a line of flux diff output by its marker
+func rawLine(line string) string {
+	style := lipgloss.NewStyle()
+	trimmed := strings.TrimSpace(line)
+	switch {
+	case strings.HasPrefix(trimmed, "►"):
+		style = style.Foreground(theme.Colours.Blue).Bold(true)
+	case strings.HasPrefix(trimmed, "+"):
+		style = style.Foreground(theme.Colours.Green)
+	case strings.HasPrefix(trimmed, "-"):
+		style = style.Foreground(theme.Colours.Red)
+	case strings.HasPrefix(trimmed, "±"):
+		style = style.Foreground(theme.Colours.Yellow)
+	}
+	return style.Render(line)
+}
+
+// rawSections holds the output of flux as sections so single and
+// recursive diffs are shown the same way
+func rawSections(msg components.FluxExecMsg) []components.FluxSection {
+	if len(msg.Sections) > 0 {
+		return msg.Sections
+	}
+	return []components.FluxSection{{Output: msg.Output}}
+}
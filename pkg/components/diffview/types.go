@@ -24,6 +24,8 @@ import (
 	"slices"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	zone "github.com/lrstanley/bubblezone"
 	"github.com/mproffitt/delorian/pkg/components"
 	"github.com/mproffitt/delorian/pkg/theme"
 	"github.com/muesli/reflow/wrap"
@@ -62,6 +64,13 @@ const (
 
 const EntryIndicator = "► "
 
+// freshMarker follows the title of drift which newly appeared
+const freshMarker = "● new"
+
+// ErrorKind is the kind given to entries for sections of a
+// recursive diff which failed
+const ErrorKind = "Error"
+
 type DrawerState rune
 
 const (
@@ -76,8 +85,16 @@ type DiffEntry struct {
 	Name      string
 	Namespace string
 	Changes   []DiffChange
-	filter    []string
-	state     DrawerState
+
+	// Group is the kustomization the entry belongs to when
+	// several are diffed together
+	Group    string
+	filter   []string
+	state    DrawerState
+	zone     string
+	fresh    bool
+	selected bool
+	failed   bool
 }
 
 func (d DiffEntry) GetKind() string {
@@ -102,22 +119,93 @@ func (d DiffEntry) WithState(s DrawerState) DiffEntry {
 	return d
 }
 
+// WithZone marks the title so clicks on it can be found
+func (d DiffEntry) WithZone(id string) DiffEntry {
+	d.zone = id
+	return d
+}
+
+// WithSelected marks the entry as the one under the cursor
+func (d DiffEntry) WithSelected(selected bool) DiffEntry {
+	d.selected = selected
+	return d
+}
+
+// Resource returns the kind, namespace and name of the object the
+// entry describes. Cluster scoped objects are titled `kind/name`
+// so the second part is moved into the name
+func (d DiffEntry) Resource() (string, string, string) {
+	if d.Name == "" {
+		return d.Kind, "", d.Namespace
+	}
+	return d.Kind, d.Namespace, d.Name
+}
+
+// ID identifies the resource of the entry independently of its
+// position in the diff
+func (d DiffEntry) ID() string {
+	kind, namespace, name := d.Resource()
+	id := kind + "/" + namespace + "/" + name
+	if d.Group != "" {
+		id = d.Group + "|" + id
+	}
+	return id
+}
+
+// closed reports if the entry only shows its title, because it
+// is folded or the filter hides every change
+func (d DiffEntry) closed() bool {
+	if d.state == EntryClosedIndicator {
+		return true
+	}
+	return !slices.ContainsFunc(d.Changes, func(change DiffChange) bool {
+		return !slices.Contains(d.filter, change.Key)
+	})
+}
+
+// View renders the entry. Entries folded with WithState only
+// show their title, cut to fit width
 func (d DiffEntry) View(width int) string {
-	d.state = EntryOpenIndicator
 	changes := make([]string, 0)
-	for _, change := range d.Changes {
-		if !slices.Contains(d.filter, change.Key) {
-			changes = append(changes, change.View(width))
+	if d.state != EntryClosedIndicator {
+		d.state = EntryOpenIndicator
+		for _, change := range d.Changes {
+			if !slices.Contains(d.filter, change.Key) {
+				changes = append(changes, change.View(width))
+			}
 		}
 	}
 	if len(changes) == 0 {
 		d.state = EntryClosedIndicator
 	}
 
+	colour := theme.Colours.BrightYellow
+	if d.failed {
+		colour = theme.Colours.Red
+	}
+	text := fmt.Sprintf("%s %s", theme.Glyph(string(d.state)), d.Title)
+	if d.state == EntryClosedIndicator {
+		available := width - 1
+		if d.fresh {
+			available -= ansi.StringWidth(theme.Glyph(freshMarker)) + 1
+		}
+		text = ansi.Truncate(text, max(1, available), theme.Glyph("…"))
+	}
 	title := lipgloss.NewStyle().
-		Foreground(theme.Colours.BrightYellow).
-		Render(fmt.Sprintf("%s %s", string(d.state), d.Title))
+		Foreground(colour).
+		Reverse(d.selected).
+		Render(text)
+	if d.fresh {
+		title = lipgloss.JoinHorizontal(lipgloss.Top, title,
+			lipgloss.NewStyle().
+				Foreground(theme.Colours.BrightPurple).
+				MarginLeft(1).
+				Render(theme.Glyph(freshMarker)))
+	}
 
+	if d.zone != "" {
+		title = zone.Mark(d.zone, title)
+	}
 	if d.state == EntryClosedIndicator {
 		return lipgloss.NewStyle().MarginBottom(1).Render(title)
 	}
@@ -141,7 +229,7 @@ func (d DiffChange) View(width int) string {
 	title := lipgloss.NewStyle().
 		PaddingLeft(4).
 		Foreground(theme.Colours.Yellow).
-		Render(d.Title)
+		Render(theme.Glyph(d.Title))
 	changes := make([]string, 0)
 	for _, change := range d.Changes {
 		changes = append(changes, change.View(width))
@@ -0,0 +1,146 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package components
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	bmx "github.com/mproffitt/bmx/pkg/exec"
+	"github.com/mproffitt/delorian/pkg/theme"
+	"github.com/muesli/reflow/wrap"
+)
+
+// FluxError is returned when a flux execution fails with a
+// well known error that can be explained to the user.
+//
+// The raw execution error is kept so the full output remains
+// available underneath the friendly message
+type FluxError struct {
+	Summary string
+	Hint    string
+	Raw     *bmx.BmxExecError
+}
+
+// Error returns the friendly summary followed by the raw error
+func (f *FluxError) Error() string {
+	var builder strings.Builder
+	builder.WriteString(f.Summary)
+	if f.Hint != "" {
+		builder.WriteString(" - " + f.Hint)
+	}
+	if f.Raw != nil {
+		builder.WriteString("\n\n" + f.Raw.Error())
+	}
+	return builder.String()
+}
+
+// Unwrap returns the raw execution error
+func (f *FluxError) Unwrap() error {
+	return f.Raw
+}
+
+// StyledError renders the summary and hint above the
+// styled raw error
+func (f *FluxError) StyledError(w int) string {
+	summary := lipgloss.NewStyle().
+		Foreground(theme.Colours.BrightRed).
+		Bold(true).
+		Render(wrap.String(f.Summary, w))
+	content := []string{summary}
+	if f.Hint != "" {
+		content = append(content, lipgloss.NewStyle().
+			Foreground(theme.Colours.Yellow).
+			Render(wrap.String(f.Hint, w)))
+	}
+	if f.Raw != nil {
+		content = append(content, "", f.Raw.StyledError(w))
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, content...)
+}
+
+// fluxErrorRecogniser maps a pattern found in the output of
+// a failed flux command to a friendly message
+type fluxErrorRecogniser struct {
+	pattern *regexp.Regexp
+	summary string
+	hint    string
+}
+
+// fluxErrorRecognisers are checked in order and the first match
+// wins so more specific patterns must come before general ones
+var fluxErrorRecognisers = []fluxErrorRecogniser{
+	{
+		pattern: regexp.MustCompile(`(?i)(no configuration has been provided|current-context is not set|context "[^"]*" does not exist)`),
+		summary: "no kubernetes context configured",
+		hint:    "set a current context in your kubeconfig or export KUBECONFIG",
+	},
+	{
+		pattern: regexp.MustCompile(`(?i)(unauthorized|token has expired|must be logged in|provide credentials)`),
+		summary: "cluster rejected your credentials",
+		hint:    "log in to the cluster again and retry",
+	},
+	{
+		pattern: regexp.MustCompile(`(?i)(connection refused|no such host|i/o timeout|unable to connect to the server|dial tcp|tls handshake timeout)`),
+		summary: "cannot reach cluster",
+		hint:    "check your kubeconfig/context and network connection",
+	},
+	{
+		pattern: regexp.MustCompile(`(?i)(forbidden|cannot (get|list|watch|patch) resource)`),
+		summary: "permission denied by the cluster",
+		hint:    "check the RBAC permissions of the current user",
+	},
+	{
+		pattern: regexp.MustCompile(`(?i)(the server could not find the requested resource|no matches for kind)`),
+		summary: "flux resources are not installed on the cluster",
+		hint:    "check flux is bootstrapped on the current context",
+	},
+	{
+		// Only flux's resource forms, such as `kind.group "name" not
+		// found` or `Kind/namespace/name not found`, so that missing
+		// commands and files aren't reported as missing resources
+		pattern: regexp.MustCompile(`(?i)([^\s:"]+ "[^"]+" not found|\b[^\s:/]+/[^\s:]+ not found)`),
+		summary: "resource not found",
+		hint:    "check the kustomization name, namespace and path are correct",
+	},
+}
+
+// RecogniseFluxError translates well known flux failures into
+// a FluxError. Errors which are not recognised are returned as
+// they were given
+func RecogniseFluxError(err error) error {
+	execErr, ok := err.(*bmx.BmxExecError)
+	if !ok {
+		return err
+	}
+
+	output := execErr.Stderr + "\n" + execErr.Stdout
+	for _, r := range fluxErrorRecognisers {
+		if r.pattern.MatchString(output) {
+			return &FluxError{
+				Summary: r.summary,
+				Hint:    r.hint,
+				Raw:     execErr,
+			}
+		}
+	}
+	return err
+}
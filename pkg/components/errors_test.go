@@ -0,0 +1,113 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package components
+
+import (
+	"errors"
+	"testing"
+
+	bmx "github.com/mproffitt/bmx/pkg/exec"
+)
+
+func TestRecogniseFluxError(t *testing.T) {
+	tests := []struct {
+		name    string
+		stderr  string
+		summary string
+	}{
+		{
+			name:    "connection refused",
+			stderr:  `✗ Get "https://127.0.0.1:6443/api?timeout=32s": dial tcp 127.0.0.1:6443: connect: connection refused`,
+			summary: "cannot reach cluster",
+		},
+		{
+			name:    "no context",
+			stderr:  "✗ invalid configuration: no configuration has been provided",
+			summary: "no kubernetes context configured",
+		},
+		{
+			name:    "rbac denied",
+			stderr:  `✗ kustomizations.kustomize.toolkit.fluxcd.io "apps" is forbidden: User "dev" cannot get resource "kustomizations"`,
+			summary: "permission denied by the cluster",
+		},
+		{
+			name:    "unauthorized",
+			stderr:  "✗ Unauthorized",
+			summary: "cluster rejected your credentials",
+		},
+		{
+			name:    "not found",
+			stderr:  `✗ kustomizations.kustomize.toolkit.fluxcd.io "apps" not found`,
+			summary: "resource not found",
+		},
+		{
+			name:    "object not found",
+			stderr:  "✗ Kustomization/flux-system/apps not found",
+			summary: "resource not found",
+		},
+		{
+			name:    "crds missing",
+			stderr:  `✗ no matches for kind "Kustomization" in version "kustomize.toolkit.fluxcd.io/v1"`,
+			summary: "flux resources are not installed on the cluster",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raw := &bmx.BmxExecError{Command: "flux diff", Stderr: tt.stderr}
+			err := RecogniseFluxError(raw)
+
+			var fluxErr *FluxError
+			if !errors.As(err, &fluxErr) {
+				t.Fatalf("expected FluxError, got %T", err)
+			}
+			if fluxErr.Summary != tt.summary {
+				t.Errorf("expected summary %q, got %q", tt.summary, fluxErr.Summary)
+			}
+			if fluxErr.Raw != raw {
+				t.Error("expected raw error to be preserved")
+			}
+		})
+	}
+}
+
+func TestRecogniseFluxErrorUnknown(t *testing.T) {
+	raw := &bmx.BmxExecError{Command: "flux diff", Stderr: "something unexpected"}
+	if err := RecogniseFluxError(raw); err != raw {
+		t.Errorf("expected unrecognised error to be returned unchanged, got %v", err)
+	}
+
+	// Anything else not found isn't a missing resource
+	for _, stderr := range []string{
+		"sh: flux: command not found",
+		"sh: /usr/local/bin/flux: not found",
+		"error: file not found",
+	} {
+		raw := &bmx.BmxExecError{Command: "flux diff", Stderr: stderr}
+		if err := RecogniseFluxError(raw); err != raw {
+			t.Errorf("expected %q to be returned unchanged, got %v", stderr, err)
+		}
+	}
+
+	plain := errors.New("plain error")
+	if err := RecogniseFluxError(plain); err != plain {
+		t.Errorf("expected non exec error to be returned unchanged, got %v", err)
+	}
+}
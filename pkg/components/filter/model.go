@@ -23,15 +23,17 @@ import (
 	"math"
 	"slices"
 	"sort"
+	"strings"
 
+	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/huh"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/charmbracelet/log"
-	"github.com/google/uuid"
 	zone "github.com/lrstanley/bubblezone"
 	"github.com/mproffitt/bmx/pkg/components/overlay"
+	"github.com/mproffitt/delorian/pkg/components"
 	"github.com/mproffitt/delorian/pkg/theme"
 	"github.com/muesli/reflow/truncate"
 )
@@ -44,7 +46,9 @@ type Model struct {
 	height      int
 	itemWidth   uint
 	options     []string
+	search      textinput.Model
 	selected    []string
+	typing      bool
 	width       int
 	focused     bool
 	fields      []huh.Field
@@ -70,10 +74,13 @@ func unique(options []string) (uint, []string) {
 func New(options, selected []string) *Model {
 	var longest uint
 	longest, options = unique(options)
+	search := textinput.New()
+	search.Prompt = "/"
 	m := Model{
 		formOptions: make([][]huh.Option[string], 0),
 		itemWidth:   longest,
 		options:     options,
+		search:      search,
 		selected:    selected,
 		fields:      make([]huh.Field, 0),
 		zones:       map[string]string{},
@@ -138,7 +145,25 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		}
 	case tea.KeyMsg:
+		if m.typing {
+			m.updateSearch(msg)
+			break
+		}
 		switch msg.String() {
+		case "/":
+			m.typing = true
+			m.search.Focus()
+			m.search.CursorEnd()
+			m.setFilterLayout()
+		case "a":
+			m.SetValues(append(m.Values(), m.visible()...))
+		case "A", "ctrl+a":
+			// ctrl+a would otherwise only toggle the column
+			// under the cursor
+			visible := m.visible()
+			m.SetValues(slices.DeleteFunc(m.Values(), func(v string) bool {
+				return slices.Contains(visible, v)
+			}))
 		case "left":
 			for i := range m.fields {
 				m.fields[i].Blur()
@@ -167,14 +192,67 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
+// Values returns the selected options, including any hidden
+// by the search
 func (m *Model) Values() []string {
 	values := make([]string, 0)
 	for i := range m.values {
 		values = append(values, m.values[i]...)
 	}
+	for _, v := range m.selected {
+		if !m.matches(v) && !slices.Contains(values, v) {
+			values = append(values, v)
+		}
+	}
 	return values
 }
 
+// matches reports if an option contains the search, ignoring case
+func (m *Model) matches(option string) bool {
+	return strings.Contains(strings.ToLower(option), strings.ToLower(m.search.Value()))
+}
+
+// visible returns the options matching the search
+func (m *Model) visible() []string {
+	visible := make([]string, 0, len(m.options))
+	for _, option := range m.options {
+		if m.matches(option) {
+			visible = append(visible, option)
+		}
+	}
+	return visible
+}
+
+// searching reports if a search is being typed or narrowing the
+// options
+func (m *Model) searching() bool {
+	return m.typing || m.search.Value() != ""
+}
+
+// updateSearch handles keys while the search is being typed,
+// rebuilding the columns against the options it matches. Enter
+// stops typing and clearing the search shows every option again
+func (m *Model) updateSearch(msg tea.KeyMsg) {
+	if msg.Type == tea.KeyEnter {
+		m.typing = false
+		m.search.Blur()
+		m.setFilterLayout()
+		return
+	}
+	selected := m.Values()
+	m.search, _ = m.search.Update(msg)
+	m.selected = selected
+	m.setFilterLayout()
+}
+
+// SetValues replaces the selected options, for example to
+// restore an earlier selection
+func (m *Model) SetValues(values []string) *Model {
+	m.selected = slices.Clone(values)
+	m.setFilterLayout()
+	return m
+}
+
 func (m *Model) View() string {
 	form := m.form.View()
 	if m.form.(*huh.Form).State == huh.StateCompleted || form == "" {
@@ -183,6 +261,13 @@ func (m *Model) View() string {
 
 	view := viewport.New(m.width, m.height)
 	form = lipgloss.NewStyle().PaddingLeft(0).Render(m.form.View())
+	if m.searching() {
+		query := m.search.Prompt + m.search.Value()
+		if m.typing {
+			query = m.search.View()
+		}
+		form = lipgloss.JoinVertical(lipgloss.Left, query, form)
+	}
 	view.SetContent(form)
 
 	borderColour := theme.Colours.Black
@@ -193,7 +278,7 @@ func (m *Model) View() string {
 	}
 
 	content := lipgloss.NewStyle().
-		Border(lipgloss.RoundedBorder(), true).
+		Border(theme.RoundedBorder(), true).
 		BorderForeground(borderColour).Render(view.View())
 	title := lipgloss.NewStyle().Foreground(titleColour).Render("Filters")
 	return overlay.PlaceOverlay(2, 0, title, content, false)
@@ -209,19 +294,20 @@ func (m *Model) setFilterLayout() tea.Model {
 		return m.options[i] < m.options[j]
 	})
 
+	visible := m.visible()
 	for i := range cols {
 		m.values[i] = make([]string, 0)
-		start := (i * len(m.options) / cols)
-		end := ((i + 1) * len(m.options)) / cols
+		start := (i * len(visible) / cols)
+		end := ((i + 1) * len(visible)) / cols
 		options := make([]huh.Option[string], 0)
-		for _, option := range m.options[start:end] {
+		for _, option := range visible[start:end] {
 			if slices.Contains(m.selected, option) {
 				m.values[i] = append(m.values[i], option)
 			}
 			key := truncate.String(option, m.itemWidth)
-			uuid := uuid.NewString()[:8]
-			zone := zone.Mark(uuid, key)
-			m.zones[zone] = uuid
+			id := components.NewID()
+			zone := zone.Mark(id, key)
+			m.zones[zone] = id
 			options = append(options, huh.NewOption(zone, option))
 		}
 		length = max(length, len(options))
@@ -231,6 +317,7 @@ func (m *Model) setFilterLayout() tea.Model {
 	m.height = length + theme.Padding
 
 	m.fields = make([]huh.Field, len(m.formOptions))
+	m.groups = make([]*huh.Group, 0, len(m.formOptions))
 	for i, group := range m.formOptions {
 		m.fields[i] = huh.NewMultiSelect[string]().
 			Options(group...).
@@ -246,6 +333,9 @@ func (m *Model) setFilterLayout() tea.Model {
 		WithTheme(formTheme())
 
 	m.form.Init()
+	if m.searching() {
+		m.height++
+	}
 	return m
 }
 
@@ -257,8 +347,8 @@ func keymap() *huh.KeyMap {
 func formTheme() *huh.Theme {
 	t := huh.ThemeBase()
 	t.Focused.Base = t.Focused.Base.Border(lipgloss.HiddenBorder(), true)
-	t.Focused.SelectedPrefix = lipgloss.NewStyle().Foreground(theme.Colours.Red).SetString("✕ ")
-	t.Focused.UnselectedPrefix = lipgloss.NewStyle().Foreground(theme.Colours.Green).SetString("✓ ")
+	t.Focused.SelectedPrefix = lipgloss.NewStyle().Foreground(theme.Colours.Red).SetString(theme.Glyph("✕ "))
+	t.Focused.UnselectedPrefix = lipgloss.NewStyle().Foreground(theme.Colours.Green).SetString(theme.Glyph("✓ "))
 	t.Focused.MultiSelectSelector = lipgloss.NewStyle().Foreground(theme.Colours.BrightRed).SetString("> ")
 	t.Blurred = t.Focused
 	t.Blurred.Card = t.Blurred.Base
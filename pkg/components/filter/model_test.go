@@ -0,0 +1,126 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package filter
+
+import (
+	"os"
+	"slices"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mproffitt/delorian/pkg/testutil"
+)
+
+func TestMain(m *testing.M) {
+	testutil.Setup()
+	os.Exit(m.Run())
+}
+
+func TestViewLayout(t *testing.T) {
+	options := []string{
+		"metadata.generation",
+		"Deployment",
+		"spec.replicas",
+		"ConfigMap",
+		"data.LOG_LEVEL",
+	}
+	m := New(options, []string{"metadata.generation"}).SetSize(60, 10)
+	testutil.RequireGolden(t, m.View())
+}
+
+func TestSetValues(t *testing.T) {
+	options := []string{"Deployment", "ConfigMap", "spec.replicas"}
+	m := New(options, []string{"spec.replicas"}).SetSize(60, 10).(*Model)
+	m.SetValues([]string{"ConfigMap", "Deployment"})
+	got := m.Values()
+	slices.Sort(got)
+	if want := []string{"ConfigMap", "Deployment"}; !slices.Equal(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+	if len(m.groups) != len(m.fields) {
+		t.Errorf("expected one group per column, got %d for %d", len(m.groups), len(m.fields))
+	}
+}
+
+func TestSelectAll(t *testing.T) {
+	options := []string{"Deployment", "ConfigMap", "spec.replicas", "data.LOG_LEVEL"}
+	m := New(options, []string{"spec.replicas"}).SetSize(40, 10).(*Model)
+	m.Focus()
+	if len(m.fields) < 2 {
+		t.Fatalf("expected the options over several columns, got %d", len(m.fields))
+	}
+
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a")})
+	got := m.Values()
+	slices.Sort(got)
+	want := slices.Sorted(slices.Values(options))
+	if !slices.Equal(got, want) {
+		t.Errorf("expected every option selected, got %v", got)
+	}
+	if view := m.View(); strings.Contains(view, "✓") {
+		t.Errorf("expected every option to be shown as selected, got\n%s", view)
+	}
+
+	m.Update(tea.KeyMsg{Type: tea.KeyCtrlA})
+	if got := m.Values(); len(got) != 0 {
+		t.Errorf("expected no options selected, got %v", got)
+	}
+	if view := m.View(); strings.Contains(view, "✕") {
+		t.Errorf("expected every option to be shown as cleared, got\n%s", view)
+	}
+}
+
+func TestSearch(t *testing.T) {
+	options := []string{"Deployment", "ConfigMap", "spec.replicas", "data.LOG_LEVEL"}
+	m := New(options, []string{"ConfigMap"}).SetSize(60, 10).(*Model)
+	m.Focus()
+
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("/")})
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("SPEC")})
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if got := m.visible(); !slices.Equal(got, []string{"spec.replicas"}) {
+		t.Errorf("expected the search to narrow the options, got %v", got)
+	}
+	view := m.View()
+	if !strings.Contains(view, "/SPEC") || strings.Contains(view, "Deployment") {
+		t.Errorf("expected only the matching options under the search, got\n%s", view)
+	}
+
+	// Selecting all only selects what is shown, and the hidden
+	// selection is kept
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a")})
+	got := m.Values()
+	slices.Sort(got)
+	if want := []string{"ConfigMap", "spec.replicas"}; !slices.Equal(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("/")})
+	m.Update(tea.KeyMsg{Type: tea.KeyCtrlU})
+	if got := m.visible(); len(got) != len(options) {
+		t.Errorf("expected clearing the search to restore every option, got %v", got)
+	}
+	got = m.Values()
+	slices.Sort(got)
+	if want := []string{"ConfigMap", "spec.replicas"}; !slices.Equal(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
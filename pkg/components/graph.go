@@ -0,0 +1,59 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package components
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// GraphNode is a kustomization or source drawn on the graph tab
+type GraphNode struct {
+	Kind      string
+	Namespace string
+	Name      string
+
+	// Note describes the node, such as its type or the url of
+	// a source
+	Note      string
+	Children  []GraphNode
+	DependsOn []GraphDependency
+}
+
+// GraphDependency is a kustomization named in spec.dependsOn.
+// Missing is set when it was not found in the repository
+type GraphDependency struct {
+	Namespace string
+	Name      string
+	Missing   bool
+}
+
+// GraphMsg carries the relationships of the selected
+// kustomization. Parent is nil when nothing builds it
+type GraphMsg struct {
+	Root   GraphNode
+	Parent *GraphNode
+}
+
+// GraphCmd returns the relationships as a GraphMsg
+func GraphCmd(root GraphNode, parent *GraphNode) tea.Cmd {
+	return func() tea.Msg {
+		return GraphMsg{Root: root, Parent: parent}
+	}
+}
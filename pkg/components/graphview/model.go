@@ -0,0 +1,191 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package graphview
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/lipgloss/tree"
+	"github.com/mproffitt/delorian/pkg/components"
+	"github.com/mproffitt/delorian/pkg/theme"
+)
+
+const (
+	NoFocus components.FocusType = iota
+	ViewportFocus
+)
+
+type Model struct {
+	focus    components.FocusType
+	height   int
+	parent   *components.GraphNode
+	root     *components.GraphNode
+	styles   styles
+	viewport viewport.Model
+	width    int
+}
+
+type styles struct {
+	dependency lipgloss.Style
+	enumerator lipgloss.Style
+	item       lipgloss.Style
+	missing    lipgloss.Style
+	note       lipgloss.Style
+	source     lipgloss.Style
+}
+
+// New creates a new dependency graph view
+//
+// The graph view draws the selected kustomization as the root of
+// a tree, with the kustomizations it builds nested beneath and
+// the source it defines as an annotated leaf
+func New(w, h int) *Model {
+	m := Model{
+		focus: NoFocus,
+		styles: styles{
+			dependency: lipgloss.NewStyle().Foreground(theme.Colours.Blue),
+			enumerator: lipgloss.NewStyle().Foreground(theme.Colours.Black),
+			item:       lipgloss.NewStyle().Foreground(theme.Colours.Purple),
+			missing:    lipgloss.NewStyle().Foreground(theme.Colours.Red),
+			note:       lipgloss.NewStyle().Foreground(theme.Colours.BrightBlack),
+			source:     lipgloss.NewStyle().Foreground(theme.Colours.Cyan),
+		},
+		viewport: viewport.New(w, h),
+	}
+	return &m
+}
+
+func (m *Model) Init() tea.Cmd {
+	return nil
+}
+
+func (m *Model) NextFocus() components.FocusType {
+	switch m.focus {
+	case NoFocus:
+		m.focus = ViewportFocus
+	default:
+		m.focus = NoFocus
+	}
+	return m.focus
+}
+
+func (m *Model) PreviousFocus() components.FocusType {
+	return m.NextFocus()
+}
+
+func (m *Model) SetSize(w, h int) tea.Model {
+	m.width = w
+	m.height = h
+	m.viewport.Width = w
+	m.viewport.Height = h
+	return m
+}
+
+func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	switch msg := msg.(type) {
+	case components.GraphMsg:
+		m.root, m.parent = &msg.Root, msg.Parent
+		m.viewport.GotoTop()
+	case tea.KeyMsg, tea.MouseMsg:
+		if m.focus == ViewportFocus {
+			m.viewport, cmd = m.viewport.Update(msg)
+		}
+	}
+	return m, cmd
+}
+
+func (m *Model) View() string {
+	if m.root == nil {
+		msg := lipgloss.NewStyle().
+			Foreground(theme.Colours.Blue).
+			Render("No kustomization selected")
+		return lipgloss.Place(m.width, m.height,
+			lipgloss.Center, lipgloss.Center, msg)
+	}
+	m.viewport.SetContent(lipgloss.JoinVertical(lipgloss.Left,
+		m.summary(), m.build(*m.root).String()))
+	return m.viewport.View()
+}
+
+// summary names the kustomization building the root, if any
+func (m *Model) summary() string {
+	text := "Not built by another kustomization"
+	if m.parent != nil {
+		text = "Built by " + title(*m.parent)
+	}
+	return lipgloss.NewStyle().Bold(true).MarginBottom(1).
+		Foreground(theme.Colours.Blue).
+		Render(text)
+}
+
+// build draws n and everything beneath it. Kustomizations have
+// children of their own so are drawn as branches, while sources
+// are always leaves
+func (m *Model) build(n components.GraphNode) *tree.Tree {
+	t := tree.Root(m.label(n)).
+		Enumerator(tree.RoundedEnumerator).
+		EnumeratorStyle(m.styles.enumerator)
+	for _, c := range n.Children {
+		if len(c.Children) == 0 {
+			t.Child(m.label(c))
+			continue
+		}
+		t.Child(m.build(c))
+	}
+	return t
+}
+
+// label renders a node with its note and a line for each of its
+// dependencies. Sources are marked so they stand out from the
+// kustomizations around them
+func (m *Model) label(n components.GraphNode) string {
+	style := m.styles.item
+	text := title(n)
+	if n.Kind != "Kustomization" {
+		style = m.styles.source
+		text = theme.Glyph("⛁ ") + n.Kind + " " + text
+	}
+	label := style.Render(text)
+	if n.Note != "" {
+		label += m.styles.note.Render(theme.Glyph(" · ") + n.Note)
+	}
+	for _, d := range n.DependsOn {
+		line := m.styles.dependency.Render(theme.Glyph("⇢ depends on ") + name(d.Namespace, d.Name))
+		if d.Missing {
+			line += m.styles.missing.Render(" (not found)")
+		}
+		label += "\n" + line
+	}
+	return label
+}
+
+// title names the node as namespace/name
+func title(n components.GraphNode) string {
+	return name(n.Namespace, n.Name)
+}
+
+// name joins the namespace and name, leaving out an empty namespace
+func name(namespace, name string) string {
+	return strings.TrimPrefix(namespace+"/"+name, "/")
+}
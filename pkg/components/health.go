@@ -0,0 +1,82 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package components
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Severity indicates how serious a health issue is
+type Severity int
+
+// Severities are ordered from the least to the most serious
+const (
+	// SeverityInfo marks findings that are not misconfigurations
+	// but may point at something the scan missed
+	SeverityInfo Severity = iota
+	SeverityWarning
+	SeverityError
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityError:
+		return "error"
+	case SeverityInfo:
+		return "info"
+	}
+	return "warning"
+}
+
+// HealthIssue describes a misconfiguration found in the
+// repository during the scan. Issues with a Group are listed
+// together beneath it, after those without one
+type HealthIssue struct {
+	Severity  Severity
+	Group     string
+	Kind      string
+	Name      string
+	Namespace string
+	Filepath  string
+	Message   string
+}
+
+// Object returns the kind, namespace and name of the object
+// the issue was found on
+func (h HealthIssue) Object() string {
+	if h.Namespace == "" {
+		return fmt.Sprintf("%s/%s", h.Kind, h.Name)
+	}
+	return fmt.Sprintf("%s/%s/%s", h.Kind, h.Namespace, h.Name)
+}
+
+// HealthReportMsg carries the issues found after scanning
+type HealthReportMsg struct {
+	Issues []HealthIssue
+}
+
+// HealthReportCmd returns the issues as a HealthReportMsg
+func HealthReportCmd(issues []HealthIssue) tea.Cmd {
+	return func() tea.Msg {
+		return HealthReportMsg{Issues: issues}
+	}
+}
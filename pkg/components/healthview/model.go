@@ -0,0 +1,172 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package healthview
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mproffitt/delorian/pkg/components"
+	"github.com/mproffitt/delorian/pkg/theme"
+	"github.com/muesli/reflow/wrap"
+)
+
+const (
+	NoFocus components.FocusType = iota
+	ViewportFocus
+)
+
+type Model struct {
+	focus    components.FocusType
+	height   int
+	issues   []components.HealthIssue
+	viewport viewport.Model
+	width    int
+}
+
+// New creates a new repository health view
+//
+// The health view lists misconfigurations found while scanning
+// the repository so they surface before a build or reconcile
+// fails
+func New(w, h int) *Model {
+	m := Model{
+		focus:    NoFocus,
+		issues:   make([]components.HealthIssue, 0),
+		viewport: viewport.New(w, h),
+	}
+	return &m
+}
+
+func (m *Model) Init() tea.Cmd {
+	return nil
+}
+
+func (m *Model) NextFocus() components.FocusType {
+	switch m.focus {
+	case NoFocus:
+		m.focus = ViewportFocus
+	default:
+		m.focus = NoFocus
+	}
+	return m.focus
+}
+
+func (m *Model) PreviousFocus() components.FocusType {
+	return m.NextFocus()
+}
+
+func (m *Model) SetSize(w, h int) tea.Model {
+	m.width = w
+	m.height = h
+	m.viewport.Width = w
+	m.viewport.Height = h
+	return m
+}
+
+func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	switch msg := msg.(type) {
+	case components.HealthReportMsg:
+		m.issues = slices.Clone(msg.Issues)
+		slices.SortStableFunc(m.issues, func(a, b components.HealthIssue) int {
+			if a.Severity != b.Severity {
+				return cmp.Compare(b.Severity, a.Severity)
+			}
+			return strings.Compare(a.Object(), b.Object())
+		})
+	case tea.KeyMsg, tea.MouseMsg:
+		if m.focus == ViewportFocus {
+			m.viewport, cmd = m.viewport.Update(msg)
+		}
+	}
+	return m, cmd
+}
+
+func (m *Model) View() string {
+	if len(m.issues) == 0 {
+		tick := lipgloss.NewStyle().
+			Foreground(theme.Colours.BrightGreen).
+			Render(theme.Glyph("✔"))
+		msg := lipgloss.NewStyle().
+			Foreground(theme.Colours.Blue).
+			MarginLeft(1).
+			Render("No issues found")
+		return lipgloss.Place(m.width, m.height,
+			lipgloss.Center, lipgloss.Center,
+			lipgloss.JoinHorizontal(lipgloss.Top, tick, msg))
+	}
+	m.viewport.SetContent(m.print())
+	return m.viewport.View()
+}
+
+func (m *Model) print() string {
+	entries := make([]string, 0, len(m.issues))
+	groups := make(map[string][]string)
+	order := make([]string, 0)
+	for _, issue := range m.issues {
+		icon, colour := theme.Glyph("⚠"), theme.Colours.BrightYellow
+		switch issue.Severity {
+		case components.SeverityError:
+			colour = theme.Colours.BrightRed
+		case components.SeverityInfo:
+			icon, colour = theme.Glyph("ℹ"), theme.Colours.Cyan
+		}
+		entry := m.entry(issue, icon, colour)
+		if issue.Group == "" {
+			entries = append(entries, entry)
+			continue
+		}
+		if _, ok := groups[issue.Group]; !ok {
+			order = append(order, issue.Group)
+		}
+		groups[issue.Group] = append(groups[issue.Group], entry)
+	}
+
+	// Grouped findings are kept apart so they don't read as
+	// problems with the repository
+	for _, group := range order {
+		heading := lipgloss.NewStyle().Bold(true).MarginBottom(1).
+			Foreground(theme.Colours.Blue).
+			Render(group)
+		entries = append(entries, heading)
+		entries = append(entries, groups[group]...)
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, entries...)
+}
+
+// entry renders a single issue with its message and file
+func (m *Model) entry(issue components.HealthIssue, icon string, colour lipgloss.TerminalColor) string {
+	title := lipgloss.NewStyle().Foreground(colour).
+		Render(fmt.Sprintf("%s %s", icon, issue.Object()))
+	message := lipgloss.NewStyle().PaddingLeft(2).
+		Foreground(theme.Colours.Fg).
+		Render(wrap.String(issue.Message, max(1, m.width-2)))
+	file := lipgloss.NewStyle().PaddingLeft(2).
+		Foreground(theme.Colours.BrightBlack).
+		Render(issue.Filepath)
+	return lipgloss.NewStyle().MarginBottom(1).
+		Render(lipgloss.JoinVertical(lipgloss.Left, title, message, file))
+}
@@ -0,0 +1,61 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package healthview
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/mproffitt/delorian/pkg/components"
+)
+
+func TestHealthOrder(t *testing.T) {
+	m := New(120, 40)
+	m.Update(components.HealthReportMsg{Issues: []components.HealthIssue{
+		{Severity: components.SeverityInfo, Kind: "Kustomization", Name: "notice"},
+		{Severity: components.SeverityWarning, Kind: "Kustomization", Name: "warning"},
+		{Severity: components.SeverityInfo, Group: "Unlinked kustomizations", Kind: "Kustomization", Name: "orphan"},
+		{Severity: components.SeverityError, Kind: "Kustomization", Name: "error"},
+	}})
+
+	// Info is the least serious so it sorts after warnings and errors
+	names := make([]string, 0, len(m.issues))
+	for _, issue := range m.issues {
+		names = append(names, issue.Name)
+	}
+	if got := strings.Join(names, ","); got != "error,warning,notice,orphan" {
+		t.Errorf("expected issues ordered by severity, got %s", got)
+	}
+
+	// Grouped issues follow the rest beneath their heading, whatever
+	// their severity
+	view := ansi.Strip(m.print())
+	order := []string{"Kustomization/error", "Kustomization/warning", "Kustomization/notice",
+		"Unlinked kustomizations", "Kustomization/orphan"}
+	last := -1
+	for _, want := range order {
+		i := strings.Index(view, want)
+		if i <= last {
+			t.Fatalf("expected %q after the previous entries, got\n%s", want, view)
+		}
+		last = i
+	}
+}
@@ -0,0 +1,57 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package components
+
+import (
+	"fmt"
+	"sync/atomic"
+
+	"github.com/google/uuid"
+)
+
+// IDGenerator creates the identifiers used to mark elements
+// such as list items, filter options and tabs so they can be
+// found again by mouse handling
+type IDGenerator func() string
+
+// NewID is the generator used by all components when creating
+// element identifiers.
+//
+// This defaults to a short random uuid but may be replaced (for
+// example in tests) to produce stable, predictable identifiers
+var NewID IDGenerator = RandomIDs()
+
+// RandomIDs returns a generator that creates a short random uuid
+func RandomIDs() IDGenerator {
+	return func() string {
+		return uuid.NewString()[:8]
+	}
+}
+
+// SequentialIDs returns a generator that creates identifiers from
+// the given prefix and an incrementing counter.
+//
+// The generator is safe to use concurrently
+func SequentialIDs(prefix string) IDGenerator {
+	var counter atomic.Uint64
+	return func() string {
+		return fmt.Sprintf("%s%04d", prefix, counter.Add(1))
+	}
+}
@@ -0,0 +1,155 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package components
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Image is a container image found in rendered manifests
+type Image struct {
+	// Repository is the image reference without tag or digest
+	Repository string
+	Tag        string
+	Digest     string
+
+	// UsedBy lists the kustomizations rendering this image
+	UsedBy []string
+
+	// Checked is true once the registry has been queried for
+	// newer tags. Latest holds the newest released tag and
+	// Outdated whether Tag is behind it
+	Checked  bool
+	Latest   string
+	Outdated bool
+}
+
+// ParseImage splits an image reference into its repository,
+// tag and digest. A missing tag is reported as "latest" unless
+// a digest is given
+func ParseImage(ref string) Image {
+	image := Image{Repository: ref}
+	if at := strings.Index(image.Repository, "@"); at >= 0 {
+		image.Digest = image.Repository[at+1:]
+		image.Repository = image.Repository[:at]
+	}
+
+	// A colon after the final slash separates the tag. Any earlier
+	// colon belongs to a registry port
+	if colon := strings.LastIndex(image.Repository, ":"); colon > strings.LastIndex(image.Repository, "/") {
+		image.Tag = image.Repository[colon+1:]
+		image.Repository = image.Repository[:colon]
+	}
+	if image.Tag == "" && image.Digest == "" {
+		image.Tag = "latest"
+	}
+	return image
+}
+
+// Reference returns the full image reference
+func (i Image) Reference() string {
+	ref := i.Repository
+	if i.Tag != "" {
+		ref += ":" + i.Tag
+	}
+	if i.Digest != "" {
+		ref += "@" + i.Digest
+	}
+	return ref
+}
+
+// ImageInventory is a deduplicated set of images
+type ImageInventory struct {
+	Scope  string
+	Images []Image
+}
+
+// NewImageInventory creates an empty inventory for the given scope
+func NewImageInventory(scope string) *ImageInventory {
+	return &ImageInventory{Scope: scope, Images: make([]Image, 0)}
+}
+
+// Add records that the image reference is used by the named
+// kustomization
+func (inv *ImageInventory) Add(ref, usedBy string) {
+	image := ParseImage(ref)
+	for i := range inv.Images {
+		if inv.Images[i].Reference() != image.Reference() {
+			continue
+		}
+		for _, u := range inv.Images[i].UsedBy {
+			if u == usedBy {
+				return
+			}
+		}
+		inv.Images[i].UsedBy = append(inv.Images[i].UsedBy, usedBy)
+		return
+	}
+	image.UsedBy = []string{usedBy}
+	inv.Images = append(inv.Images, image)
+}
+
+// Sort orders the inventory by image reference
+func (inv *ImageInventory) Sort() {
+	sort.SliceStable(inv.Images, func(i, j int) bool {
+		return inv.Images[i].Reference() < inv.Images[j].Reference()
+	})
+}
+
+// Export writes the image references one per line to filename
+func (inv *ImageInventory) Export(filename string) error {
+	var builder strings.Builder
+	for _, image := range inv.Images {
+		fmt.Fprintln(&builder, image.Reference())
+	}
+	return os.WriteFile(filename, []byte(builder.String()), 0o644)
+}
+
+// ImageInventoryMsg is returned once the images for the
+// current scope have been collected
+type ImageInventoryMsg struct {
+	Inventory *ImageInventory
+}
+
+// ImageInventoryCmd returns the inventory as an ImageInventoryMsg
+func ImageInventoryCmd(inventory *ImageInventory) tea.Cmd {
+	return func() tea.Msg {
+		return ImageInventoryMsg{Inventory: inventory}
+	}
+}
+
+// ImageScopeMsg is sent by the image view to switch between
+// the images of the selected kustomization and those of every
+// kustomization in its cluster
+type ImageScopeMsg struct {
+	Cluster bool
+}
+
+// ImageScopeCmd returns an ImageScopeMsg
+func ImageScopeCmd(cluster bool) tea.Cmd {
+	return func() tea.Msg {
+		return ImageScopeMsg{Cluster: cluster}
+	}
+}
@@ -0,0 +1,63 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package components
+
+import "testing"
+
+func TestParseImage(t *testing.T) {
+	tests := []struct {
+		ref  string
+		want Image
+	}{
+		{"nginx", Image{Repository: "nginx", Tag: "latest"}},
+		{"nginx:1.27", Image{Repository: "nginx", Tag: "1.27"}},
+		{"registry:5000/team/app", Image{Repository: "registry:5000/team/app", Tag: "latest"}},
+		{"registry:5000/team/app:v2", Image{Repository: "registry:5000/team/app", Tag: "v2"}},
+		{"ghcr.io/app@sha256:abc", Image{Repository: "ghcr.io/app", Digest: "sha256:abc"}},
+		{"ghcr.io/app:v1@sha256:abc", Image{Repository: "ghcr.io/app", Tag: "v1", Digest: "sha256:abc"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			got := ParseImage(tt.ref)
+			if got.Repository != tt.want.Repository || got.Tag != tt.want.Tag || got.Digest != tt.want.Digest {
+				t.Errorf("ParseImage(%q) = %+v, want %+v", tt.ref, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestImageInventoryDeduplicates(t *testing.T) {
+	inv := NewImageInventory("test")
+	inv.Add("nginx:1.27", "web")
+	inv.Add("nginx:1.27", "web")
+	inv.Add("nginx:1.27", "proxy")
+	inv.Add("busybox", "web")
+	inv.Sort()
+
+	if len(inv.Images) != 2 {
+		t.Fatalf("expected 2 images, got %d", len(inv.Images))
+	}
+	if inv.Images[0].Reference() != "busybox:latest" {
+		t.Errorf("expected busybox first, got %s", inv.Images[0].Reference())
+	}
+	if got := inv.Images[1].UsedBy; len(got) != 2 || got[0] != "web" || got[1] != "proxy" {
+		t.Errorf("unexpected users %v", got)
+	}
+}
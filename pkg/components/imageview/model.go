@@ -0,0 +1,274 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package imageview
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mproffitt/bmx/pkg/components/toast"
+	"github.com/mproffitt/bmx/pkg/exec"
+	"github.com/mproffitt/delorian/pkg/components"
+	"github.com/mproffitt/delorian/pkg/components/splash"
+	"github.com/mproffitt/delorian/pkg/theme"
+	"github.com/muesli/reflow/truncate"
+)
+
+const (
+	NoFocus components.FocusType = iota
+	ViewportFocus
+)
+
+// ExportFilename is the file the inventory is exported to
+var ExportFilename = "images.txt"
+
+// headerHeight is the number of lines above the image list
+const headerHeight = 3
+
+type Model struct {
+	cluster   bool
+	load      components.Load
+	focus     components.FocusType
+	height    int
+	inventory *components.ImageInventory
+	splash    *splash.Model
+	viewport  viewport.Model
+	width     int
+}
+
+// loadingMessage is shown on the splash while the images load
+const loadingMessage = "Collecting images..."
+
+// New creates a new image inventory view
+//
+// The image view lists the container images rendered by the
+// selected kustomization, or by every kustomization in its
+// cluster, so they can be reviewed or exported for auditing
+func New(w, h int) *Model {
+	m := Model{
+		focus:    NoFocus,
+		splash:   splash.New(loadingMessage, splash.Branding{}),
+		viewport: viewport.New(w, h),
+	}
+	m.load.Start()
+	return &m
+}
+
+// SetBranding brands the splash shown while the images load
+func (m *Model) SetBranding(branding splash.Branding) *Model {
+	m.splash = splash.New(loadingMessage, branding)
+	return m
+}
+
+func (m *Model) Init() tea.Cmd {
+	return m.splash.Init()
+}
+
+func (m *Model) NextFocus() components.FocusType {
+	switch m.focus {
+	case NoFocus:
+		m.focus = ViewportFocus
+	default:
+		m.focus = NoFocus
+	}
+	return m.focus
+}
+
+func (m *Model) PreviousFocus() components.FocusType {
+	return m.NextFocus()
+}
+
+func (m *Model) SetSize(w, h int) tea.Model {
+	m.width = w
+	m.height = h
+	// allow for the left border on the viewport
+	m.viewport.Width = w - 1
+	m.viewport.Height = h - headerHeight
+	return m
+}
+
+// LoadState reports if the view is waiting for content, showing
+// it or showing the error loading ended in
+func (m *Model) LoadState() components.LoadState {
+	return m.load.State()
+}
+
+func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	switch msg := msg.(type) {
+	case components.TabChangedMsg:
+		m.load.Start()
+		cmd = m.splash.Follow(&m.load)
+	case components.LoadSkippedMsg:
+		m.load.Skip()
+		cmd = m.splash.Follow(&m.load)
+	case splash.TickMsg:
+		m.splash, cmd = m.splash.Update(msg)
+	case components.ModelErrorMsg:
+		m.load.Fail(msg.Error)
+		m.splash.Follow(&m.load)
+	case components.ImageInventoryMsg:
+		m.load.Done()
+		m.inventory = msg.Inventory
+		m.viewport.SetContent(m.print())
+		m.viewport.GotoTop()
+		m.splash.Follow(&m.load)
+	case tea.KeyMsg:
+		if m.focus != ViewportFocus {
+			break
+		}
+		switch msg.String() {
+		case "c":
+			cmd = components.Action{
+				Name: "image scope",
+				Do:   m.toggleScope,
+				Undo: m.toggleScope,
+			}.Apply()
+		case "e":
+			cmd = m.export()
+		default:
+			m.viewport, cmd = m.viewport.Update(msg)
+		}
+	}
+	return m, cmd
+}
+
+// toggleScope switches between the images of the selected
+// kustomization and those of its whole cluster
+func (m *Model) toggleScope() tea.Cmd {
+	m.cluster = !m.cluster
+	m.load.Start()
+	return tea.Batch(m.splash.Follow(&m.load),
+		components.ImageScopeCmd(m.cluster))
+}
+
+// export writes the current inventory to ExportFilename
+func (m *Model) export() tea.Cmd {
+	if m.inventory == nil {
+		return nil
+	}
+	if err := m.inventory.Export(ExportFilename); err != nil {
+		return toast.NewToastCmd(toast.Error, err.Error())
+	}
+	return toast.NewToastCmd(toast.Success,
+		fmt.Sprintf("Exported %d images to %s", len(m.inventory.Images), ExportFilename))
+}
+
+func (m *Model) print() string {
+	if m.inventory == nil || len(m.inventory.Images) == 0 {
+		return lipgloss.Place(m.viewport.Width, m.viewport.Height,
+			lipgloss.Center, lipgloss.Center,
+			lipgloss.NewStyle().Foreground(theme.Colours.Cyan).Render("No images found"))
+	}
+
+	repoWidth, tagWidth := len("IMAGE"), len("TAG")
+	for _, image := range m.inventory.Images {
+		repoWidth = max(repoWidth, len(image.Repository))
+		tagWidth = max(tagWidth, len(image.Tag))
+	}
+	repoWidth = min(repoWidth, m.width/2)
+
+	row := func(repo, tag, usedBy string) string {
+		return fmt.Sprintf("%-*s  %-*s  %s", repoWidth,
+			truncate.StringWithTail(repo, uint(repoWidth), theme.Glyph("…")), tagWidth, tag, usedBy)
+	}
+
+	lines := []string{
+		lipgloss.NewStyle().Foreground(theme.Colours.BrightBlack).
+			Render(row("IMAGE", "TAG", "USED BY")),
+	}
+	for _, image := range m.inventory.Images {
+		tag := image.Tag
+		if image.Digest != "" {
+			tag = strings.TrimSpace(tag + " @" + truncate.String(image.Digest, 19))
+		}
+		line := row(image.Repository, tag, strings.Join(image.UsedBy, ", "))
+		line = truncate.StringWithTail(line, uint(max(0, m.width-badgeWidth)), theme.Glyph("…"))
+		lines = append(lines, lipgloss.JoinHorizontal(lipgloss.Top,
+			lipgloss.NewStyle().Width(m.width-badgeWidth).
+				Foreground(theme.Colours.Fg).Render(line),
+			badge(image)))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// badgeWidth is the space reserved for the freshness badge
+const badgeWidth = 14
+
+// badge shows whether a newer tag exists for the image. Images
+// that have not been checked against a registry have no badge
+func badge(image components.Image) string {
+	switch {
+	case !image.Checked:
+		return ""
+	case image.Outdated:
+		return lipgloss.NewStyle().Foreground(theme.Colours.BrightYellow).
+			Render(truncate.StringWithTail(theme.Glyph("⬆ ")+image.Latest, badgeWidth, theme.Glyph("…")))
+	}
+	return lipgloss.NewStyle().Foreground(theme.Colours.Green).Render(theme.Glyph("✔"))
+}
+
+func (m *Model) View() string {
+	if m.splash.Visible() {
+		return lipgloss.Place(m.width, m.height,
+			lipgloss.Center, lipgloss.Center,
+			m.splash.SetWidth(m.width).View())
+	}
+
+	if err := m.load.Err(); err != nil {
+		msg := err.Error()
+		switch e := err.(type) {
+		case *exec.BmxExecError:
+			msg = e.StyledError(m.width)
+		case *components.FluxError:
+			msg = e.StyledError(m.width)
+		}
+		msg = lipgloss.NewStyle().
+			Foreground(theme.Colours.Red).
+			MarginLeft(1).
+			Render(msg)
+		return lipgloss.Place(m.width, m.height,
+			lipgloss.Center, lipgloss.Center, msg)
+	}
+
+	scope, count := "", 0
+	if m.inventory != nil {
+		scope, count = m.inventory.Scope, len(m.inventory.Images)
+	}
+	title := lipgloss.NewStyle().Foreground(theme.Colours.BrightYellow).
+		Render(fmt.Sprintf("Images for %s (%d)", scope, count))
+	help := lipgloss.NewStyle().Foreground(theme.Colours.BrightBlack).
+		Render(theme.Glyph("c: toggle cluster scope • e: export to ") + ExportFilename)
+	header := lipgloss.NewStyle().MarginBottom(1).
+		Render(lipgloss.JoinVertical(lipgloss.Left, title, help))
+
+	border := theme.Colours.Black
+	if m.focus == ViewportFocus {
+		border = theme.Colours.Blue
+	}
+	view := lipgloss.NewStyle().
+		BorderForeground(border).
+		Border(theme.NormalBorder(), false, false, false, true).
+		Render(m.viewport.View())
+	return lipgloss.JoinVertical(lipgloss.Left, header, view)
+}
@@ -0,0 +1,208 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package intervalview
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mproffitt/delorian/pkg/components"
+	"github.com/mproffitt/delorian/pkg/theme"
+)
+
+const (
+	NoFocus components.FocusType = iota
+	ViewportFocus
+)
+
+type Model struct {
+	entries  []components.Cadence
+	focus    components.FocusType
+	height   int
+	viewport viewport.Model
+	width    int
+}
+
+// New creates a new interval summary view
+//
+// The interval view lists how often each kustomization and
+// source reconciles, shortest first, so the reconcile cadence
+// of the whole repository can be audited in one place
+func New(w, h int) *Model {
+	m := Model{
+		entries:  make([]components.Cadence, 0),
+		focus:    NoFocus,
+		viewport: viewport.New(w, h),
+	}
+	return &m
+}
+
+func (m *Model) Init() tea.Cmd {
+	return nil
+}
+
+func (m *Model) NextFocus() components.FocusType {
+	switch m.focus {
+	case NoFocus:
+		m.focus = ViewportFocus
+	default:
+		m.focus = NoFocus
+	}
+	return m.focus
+}
+
+func (m *Model) PreviousFocus() components.FocusType {
+	return m.NextFocus()
+}
+
+func (m *Model) SetSize(w, h int) tea.Model {
+	m.width = w
+	m.height = h
+	m.viewport.Width = w
+	m.viewport.Height = h
+	return m
+}
+
+func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	switch msg := msg.(type) {
+	case components.CadenceReportMsg:
+		m.entries = slices.Clone(msg.Entries)
+		slices.SortStableFunc(m.entries, compare)
+	case tea.KeyMsg, tea.MouseMsg:
+		if m.focus == ViewportFocus {
+			m.viewport, cmd = m.viewport.Update(msg)
+		}
+	}
+	return m, cmd
+}
+
+// compare orders entries by interval, shortest first, with those
+// whose interval is unset last
+func compare(a, b components.Cadence) int {
+	unsetA, unsetB := a.Flag == components.CadenceUnset, b.Flag == components.CadenceUnset
+	if unsetA != unsetB {
+		if unsetA {
+			return 1
+		}
+		return -1
+	}
+	if a.Every != b.Every {
+		return cmp.Compare(a.Every, b.Every)
+	}
+	return strings.Compare(a.Object(), b.Object())
+}
+
+func (m *Model) View() string {
+	if len(m.entries) == 0 {
+		msg := lipgloss.NewStyle().
+			Foreground(theme.Colours.Blue).
+			Render("No kustomizations or sources found")
+		return lipgloss.Place(m.width, m.height,
+			lipgloss.Center, lipgloss.Center, msg)
+	}
+	m.viewport.SetContent(lipgloss.JoinVertical(lipgloss.Left,
+		m.summary(), m.print()))
+	return m.viewport.View()
+}
+
+// summary counts the entries and gives the range of intervals
+func (m *Model) summary() string {
+	var kustomizations, sources, flagged int
+	for _, e := range m.entries {
+		if e.Kind == "Kustomization" {
+			kustomizations++
+		} else {
+			sources++
+		}
+		if e.Flag != components.CadenceNormal || e.TimeoutExceeds {
+			flagged++
+		}
+	}
+	parts := []string{
+		fmt.Sprintf("%d kustomizations", kustomizations),
+		fmt.Sprintf("%d sources", sources),
+	}
+	set := slices.DeleteFunc(slices.Clone(m.entries), func(e components.Cadence) bool {
+		return e.Flag == components.CadenceUnset
+	})
+	if len(set) > 0 {
+		parts = append(parts,
+			fmt.Sprintf("shortest %s", set[0].Every),
+			fmt.Sprintf("longest %s", set[len(set)-1].Every))
+	}
+	parts = append(parts, fmt.Sprintf("%d flagged", flagged))
+	return lipgloss.NewStyle().Bold(true).MarginBottom(1).
+		Foreground(theme.Colours.Blue).
+		Render(strings.Join(parts, theme.Glyph(" · ")))
+}
+
+func (m *Model) print() string {
+	width := len("interval")
+	for _, e := range m.entries {
+		width = max(width, lipgloss.Width(e.Interval))
+	}
+	rows := make([]string, 0, len(m.entries))
+	for _, e := range m.entries {
+		rows = append(rows, m.row(e, width))
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, rows...)
+}
+
+// row renders a single entry with its interval, timeout and
+// anything unusual about them
+func (m *Model) row(e components.Cadence, width int) string {
+	icon, colour := " ", theme.Colours.Fg
+	notes := make([]string, 0)
+	switch e.Flag {
+	case components.CadenceShort:
+		notes = append(notes, "reconciles unusually often")
+	case components.CadenceLong:
+		notes = append(notes, "reconciles unusually rarely")
+	case components.CadenceUnset:
+		notes = append(notes, "interval is unset or not a duration")
+	}
+	if e.TimeoutExceeds {
+		notes = append(notes, "timeout is longer than the interval")
+	}
+	if len(notes) > 0 {
+		icon, colour = theme.Glyph("⚠"), theme.Colours.BrightYellow
+	}
+
+	interval := e.Interval
+	if interval == "" {
+		interval = "-"
+	}
+	style := lipgloss.NewStyle().Foreground(colour)
+	line := style.Render(fmt.Sprintf("%s %-*s  %s", icon, width, interval, e.Object()))
+	if e.Timeout != "" {
+		line += lipgloss.NewStyle().Foreground(theme.Colours.BrightBlack).
+			Render(fmt.Sprintf(" (timeout %s)", e.Timeout))
+	}
+	if len(notes) > 0 {
+		line += style.Render(" " + strings.Join(notes, ", "))
+	}
+	return line
+}
@@ -0,0 +1,114 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package components
+
+import tea "github.com/charmbracelet/bubbletea"
+
+// LoadState is how far a tab has got in loading its content
+type LoadState int
+
+const (
+	// StateIdle tabs have not been asked for any content
+	StateIdle LoadState = iota
+
+	// StateLoading tabs are waiting for content and show a splash
+	StateLoading
+
+	// StateLoaded tabs are showing content
+	StateLoaded
+
+	// StateError tabs are showing the error the last load ended in
+	StateError
+)
+
+func (s LoadState) String() string {
+	switch s {
+	case StateLoading:
+		return "loading"
+	case StateLoaded:
+		return "loaded"
+	case StateError:
+		return "error"
+	}
+	return "idle"
+}
+
+// Load is the state machine behind the splash and error of a tab.
+//
+// The splash is shown only whilst loading and the error only in
+// the error state, so a tab can't show both at once. A load which
+// is skipped returns to whatever the tab showed before it started
+// rather than leaving the splash on screen
+type Load struct {
+	state   LoadState
+	settled LoadState
+	err     error
+}
+
+// Start begins waiting for content
+func (l *Load) Start() {
+	if l.state != StateLoading {
+		l.settled = l.state
+	}
+	l.state = StateLoading
+}
+
+// Done records that content arrived
+func (l *Load) Done() {
+	l.state, l.err = StateLoaded, nil
+}
+
+// Fail records the error the load ended in
+func (l *Load) Fail(err error) {
+	l.state, l.err = StateError, err
+}
+
+// Skip abandons a load for which no content will arrive
+func (l *Load) Skip() {
+	if l.state == StateLoading {
+		l.state = l.settled
+	}
+}
+
+// State returns the current state
+func (l *Load) State() LoadState {
+	return l.state
+}
+
+// Err returns the error to show, which is nil unless the last
+// load failed
+func (l *Load) Err() error {
+	if l.state != StateError {
+		return nil
+	}
+	return l.err
+}
+
+// LoadSkippedMsg tells the active tab that the tab change it has
+// started loading for has nothing to load, for example when no
+// kustomization is selected
+type LoadSkippedMsg struct{}
+
+// LoadSkippedCmd returns a LoadSkippedMsg
+func LoadSkippedCmd() tea.Cmd {
+	return func() tea.Msg {
+		return LoadSkippedMsg{}
+	}
+}
@@ -0,0 +1,61 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package components
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestLoad(t *testing.T) {
+	var l Load
+	if l.State() != StateIdle {
+		t.Fatalf("expected a new load to be idle, got %s", l.State())
+	}
+
+	l.Start()
+	l.Fail(errors.New("boom"))
+	if l.State() != StateError || l.Err() == nil {
+		t.Fatalf("expected the load to fail, got %s", l.State())
+	}
+
+	// A new load hides the previous error until it settles
+	l.Start()
+	if l.State() != StateLoading || l.Err() != nil {
+		t.Errorf("expected the error to be hidden whilst loading, got %s", l.State())
+	}
+
+	// Skipping returns to what was shown before the load
+	l.Skip()
+	if l.State() != StateError || l.Err() == nil {
+		t.Errorf("expected a skipped load to show the previous error, got %s", l.State())
+	}
+
+	l.Start()
+	l.Start()
+	l.Done()
+	if l.State() != StateLoaded || l.Err() != nil {
+		t.Errorf("expected the load to complete, got %s", l.State())
+	}
+	l.Skip()
+	if l.State() != StateLoaded {
+		t.Errorf("expected skip to be ignored once loaded, got %s", l.State())
+	}
+}
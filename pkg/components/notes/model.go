@@ -0,0 +1,91 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package notes
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mproffitt/bmx/pkg/components/dialog"
+	"github.com/mproffitt/delorian/pkg/components"
+	"github.com/mproffitt/delorian/pkg/theme"
+)
+
+// Width is the width of the notes overlay
+const Width = 64
+
+// Model shows the note kept for a kustomization and lets it be
+// edited
+type Model struct {
+	form *huh.Form
+	key  string
+	note string
+}
+
+// New opens the note for key, titled with the kustomization name
+func New(key, title, note string) *Model {
+	m := Model{
+		key:  key,
+		note: note,
+	}
+	m.form = huh.NewForm(huh.NewGroup(
+		huh.NewText().
+			Title("Notes for " + title).
+			Description("alt+enter adds a line, enter saves and esc cancels. " +
+				"Clear the note to remove it").
+			Lines(8).
+			CharLimit(0).
+			Value(&m.note),
+	)).WithWidth(Width - 4).WithShowHelp(false)
+	return &m
+}
+
+func (m *Model) Init() tea.Cmd {
+	return m.form.Init()
+}
+
+func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok && msg.String() == "esc" {
+		return m, dialog.DialogStatusCmd(dialog.DialogStatusMsg{Done: true})
+	}
+
+	form, cmd := m.form.Update(msg)
+	m.form = form.(*huh.Form)
+	switch m.form.State {
+	case huh.StateAborted:
+		return m, dialog.DialogStatusCmd(dialog.DialogStatusMsg{Done: true})
+	case huh.StateCompleted:
+		return m, tea.Batch(
+			components.NoteCmd(m.key, strings.TrimSpace(m.note)),
+			dialog.DialogStatusCmd(dialog.DialogStatusMsg{Done: true}))
+	}
+	return m, cmd
+}
+
+func (m *Model) View() string {
+	return lipgloss.NewStyle().
+		Border(theme.RoundedBorder(), true).
+		BorderForeground(theme.Colours.Blue).
+		Padding(1).
+		Width(Width).
+		Render(m.form.View())
+}
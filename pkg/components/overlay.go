@@ -0,0 +1,52 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package components
+
+import tea "github.com/charmbracelet/bubbletea"
+
+// EditAction is what to do with an edited overlay file
+type EditAction int
+
+const (
+	// EditPreview keeps the edit in memory and rebuilds with it
+	EditPreview EditAction = iota
+
+	// EditSave writes the edit to the file in the repository
+	EditSave
+
+	// EditDiscard drops the edit, restoring the file on disk
+	EditDiscard
+)
+
+// OverlayEditMsg carries an edit to one of the files making up
+// the overlay of the kustomization with the given ID
+type OverlayEditMsg struct {
+	ID      string
+	File    string
+	Content string
+	Action  EditAction
+}
+
+// OverlayEditCmd returns an OverlayEditMsg
+func OverlayEditCmd(id, file, content string, action EditAction) tea.Cmd {
+	return func() tea.Msg {
+		return OverlayEditMsg{ID: id, File: file, Content: content, Action: action}
+	}
+}
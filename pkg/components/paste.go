@@ -0,0 +1,161 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package components
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mproffitt/bmx/pkg/components/toast"
+)
+
+var (
+	// PasteEndpoint receives shared views. Sharing is disabled
+	// while it is empty. An endpoint ending in `/gists` is sent a
+	// GitHub gist, anything else the plain text
+	PasteEndpoint string
+
+	// PasteToken is sent as a bearer token with each upload
+	PasteToken string
+
+	// PasteTimeout limits how long an upload may take
+	PasteTimeout = 15 * time.Second
+)
+
+// paste holds the cancel func of the upload in flight
+var paste struct {
+	sync.Mutex
+	cancel context.CancelFunc
+}
+
+// PasteCmd uploads content to the paste service and copies the
+// URL it is published at. Sharing again while an upload is in
+// flight cancels it
+func PasteCmd(content, filename string) tea.Cmd {
+	if PasteEndpoint == "" {
+		return toast.NewToastCmd(toast.Warning,
+			"Sharing needs a paste endpoint in the config file")
+	}
+	paste.Lock()
+	defer paste.Unlock()
+	if paste.cancel != nil {
+		paste.cancel()
+		paste.cancel = nil
+		return toast.NewToastCmd(toast.Info, "Upload cancelled")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), PasteTimeout)
+	paste.cancel = cancel
+	send := func() tea.Msg {
+		defer func() {
+			paste.Lock()
+			cancel()
+			paste.cancel = nil
+			paste.Unlock()
+		}()
+		link, err := upload(ctx, content, filename)
+		switch {
+		case errors.Is(err, context.Canceled):
+			return nil
+		case errors.Is(err, context.DeadlineExceeded):
+			return toast.NewToastMsg{Type: toast.Error,
+				Message: fmt.Sprintf("Upload timed out after %s", PasteTimeout)}
+		case err != nil:
+			return toast.NewToastMsg{Type: toast.Error, Message: "Upload failed: " + err.Error()}
+		}
+		return CopyCmd(link, link)()
+	}
+	return tea.Batch(toast.NewToastCmd(toast.Info, "Uploading "+filename), send)
+}
+
+// upload sends content to the paste service and returns the URL
+// it is published at
+func upload(ctx context.Context, content, filename string) (string, error) {
+	gist := isGist(PasteEndpoint)
+	body := []byte(content)
+	contentType := "text/plain; charset=utf-8"
+	if gist {
+		var err error
+		body, err = json.Marshal(map[string]any{
+			"description": filename + " shared from delorian",
+			"public":      false,
+			"files":       map[string]any{filename: map[string]string{"content": content}},
+		})
+		if err != nil {
+			return "", err
+		}
+		contentType = "application/json"
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, PasteEndpoint, bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", contentType)
+	if token := os.ExpandEnv(PasteToken); token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	reply, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		return "", fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(reply)))
+	}
+	if gist {
+		var created struct {
+			URL string `json:"html_url"`
+		}
+		if err := json.Unmarshal(reply, &created); err != nil || created.URL == "" {
+			return "", fmt.Errorf("unexpected reply from %s", PasteEndpoint)
+		}
+		return created.URL, nil
+	}
+	// Plain paste services reply with the URL of the paste
+	link := strings.TrimSpace(string(reply))
+	if u, err := url.Parse(link); err != nil || u.Host == "" {
+		return "", fmt.Errorf("unexpected reply from %s", PasteEndpoint)
+	}
+	return link, nil
+}
+
+// isGist reports if endpoint is the GitHub gist API
+func isGist(endpoint string) bool {
+	u, err := url.Parse(endpoint)
+	return err == nil && path.Base(u.Path) == "gists"
+}
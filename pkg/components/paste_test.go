@@ -0,0 +1,83 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package components
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestUpload(t *testing.T) {
+	t.Setenv("PASTE_TOKEN", "secret")
+	defer func() { PasteEndpoint, PasteToken = "", "" }()
+	PasteToken = "${PASTE_TOKEN}"
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			http.Error(w, "bad credentials", http.StatusUnauthorized)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		switch r.URL.Path {
+		case "/gists":
+			var gist struct {
+				Files map[string]struct{ Content string }
+			}
+			if err := json.Unmarshal(body, &gist); err != nil || gist.Files["diff.txt"].Content != "+ added" {
+				http.Error(w, "bad gist", http.StatusUnprocessableEntity)
+				return
+			}
+			_, _ = w.Write([]byte(`{"html_url": "https://gist.example.com/abc"}`))
+		case "/paste":
+			_, _ = w.Write([]byte("https://paste.example.com/" + string(body) + "\n"))
+		default:
+			_, _ = w.Write([]byte("<html>"))
+		}
+	}))
+	defer server.Close()
+
+	for _, tc := range []struct {
+		name, path, token, want, err string
+	}{
+		{"gist", "/gists", PasteToken, "https://gist.example.com/abc", ""},
+		{"plain", "/paste", PasteToken, "https://paste.example.com/+ added", ""},
+		{"no url", "/other", PasteToken, "", "unexpected reply"},
+		{"rejected", "/paste", "wrong", "", "401 Unauthorized: bad credentials"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			PasteEndpoint, PasteToken = server.URL+tc.path, tc.token
+			got, err := upload(context.Background(), "+ added", "diff.txt")
+			if tc.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.err) {
+					t.Fatalf("expected %q, got %v", tc.err, err)
+				}
+				return
+			}
+			if err != nil || got != tc.want {
+				t.Errorf("expected %q, got %q %v", tc.want, got, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+package picker
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mproffitt/bmx/pkg/components/toast"
+	"github.com/mproffitt/delorian/pkg/components"
+	"github.com/mproffitt/delorian/pkg/yaml"
+)
+
+// CopyDocumentCmd opens a picker listing each resource in a multi
+// document stream. The exact YAML of the chosen resource is
+// copied to the clipboard
+func CopyDocumentCmd(input string) tea.Cmd {
+	documents := yaml.Documents(input)
+	if len(documents) == 0 {
+		return toast.NewToastCmd(toast.Warning, "No resources to copy")
+	}
+
+	items := make([]Item, 0, len(documents))
+	for _, d := range documents {
+		items = append(items, Item{Label: d.Title(), Value: d.Raw})
+	}
+	return components.OpenDialogCmd(New("Copy resource", items, func(item Item) tea.Cmd {
+		return components.CopyCmd(item.Value, item.Label)
+	}))
+}
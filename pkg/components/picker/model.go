@@ -0,0 +1,161 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+package picker
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mproffitt/bmx/pkg/components/dialog"
+	"github.com/mproffitt/delorian/pkg/theme"
+	"github.com/muesli/reflow/truncate"
+)
+
+const (
+	// Width is the width of the picker overlay
+	Width = 64
+
+	// MaxVisible is the number of items shown at once
+	MaxVisible = 15
+)
+
+// Item is a single choice in the picker
+type Item struct {
+	Label string
+	Value string
+}
+
+// Model is an overlay for choosing one item from a list.
+//
+// Typing filters the items, enter selects the highlighted item
+// and esc closes the picker without a selection
+type Model struct {
+	cursor   int
+	filter   string
+	items    []Item
+	offset   int
+	onSelect func(Item) tea.Cmd
+	title    string
+}
+
+// New creates a picker which calls onSelect with the chosen item
+func New(title string, items []Item, onSelect func(Item) tea.Cmd) *Model {
+	return &Model{
+		items:    items,
+		onSelect: onSelect,
+		title:    title,
+	}
+}
+
+func (m *Model) Init() tea.Cmd {
+	return nil
+}
+
+// visible returns the items matching the filter
+func (m *Model) visible() []Item {
+	if m.filter == "" {
+		return m.items
+	}
+	items := make([]Item, 0)
+	for _, item := range m.items {
+		if strings.Contains(strings.ToLower(item.Label), strings.ToLower(m.filter)) {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	key, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+
+	done := dialog.DialogStatusCmd(dialog.DialogStatusMsg{Done: true})
+	items := m.visible()
+	switch key.Type {
+	case tea.KeyEsc, tea.KeyCtrlC:
+		return m, done
+	case tea.KeyEnter:
+		if len(items) == 0 {
+			return m, nil
+		}
+		// The picker is closed first so the selection may open
+		// another dialog in its place
+		return m, tea.Sequence(done, m.onSelect(items[m.cursor]))
+	case tea.KeyUp:
+		m.cursor--
+	case tea.KeyDown:
+		m.cursor++
+	case tea.KeyPgUp:
+		m.cursor -= MaxVisible
+	case tea.KeyPgDown:
+		m.cursor += MaxVisible
+	case tea.KeyBackspace:
+		if m.filter != "" {
+			m.filter = m.filter[:len(m.filter)-1]
+			m.cursor = 0
+		}
+	case tea.KeyRunes, tea.KeySpace:
+		m.filter += string(key.Runes)
+		m.cursor = 0
+	}
+
+	items = m.visible()
+	m.cursor = max(0, min(m.cursor, len(items)-1))
+	switch {
+	case m.cursor < m.offset:
+		m.offset = m.cursor
+	case m.cursor >= m.offset+MaxVisible:
+		m.offset = m.cursor - MaxVisible + 1
+	}
+	return m, nil
+}
+
+func (m *Model) View() string {
+	width := Width - 6
+	lines := []string{
+		lipgloss.NewStyle().Foreground(theme.Colours.BrightYellow).Render(m.title),
+		lipgloss.NewStyle().Foreground(theme.Colours.BrightBlack).
+			MarginBottom(1).Render("filter: " + m.filter + theme.Glyph("█")),
+	}
+
+	items := m.visible()
+	if len(items) == 0 {
+		lines = append(lines, lipgloss.NewStyle().
+			Foreground(theme.Colours.BrightBlack).Render("no matches"))
+	}
+	end := min(len(items), m.offset+MaxVisible)
+	for i := m.offset; i < end; i++ {
+		style := lipgloss.NewStyle().Foreground(theme.Colours.Fg).Width(width)
+		if i == m.cursor {
+			style = style.Foreground(theme.Colours.BrightWhite).
+				Background(theme.Colours.SelectionBg)
+		}
+		lines = append(lines, style.Render(truncate.StringWithTail(items[i].Label, uint(width), theme.Glyph("…"))))
+	}
+
+	return lipgloss.NewStyle().
+		Border(theme.RoundedBorder(), true).
+		BorderForeground(theme.Colours.Blue).
+		Padding(1, 2).
+		Width(Width).
+		Render(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,64 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+package picker
+
+import (
+	"reflect"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type selectedMsg Item
+
+func TestFilterAndSelect(t *testing.T) {
+	items := []Item{
+		{Label: "Deployment/default/podinfo", Value: "deployment"},
+		{Label: "Service/default/podinfo", Value: "service"},
+		{Label: "ConfigMap/default/podinfo-config", Value: "configmap"},
+	}
+	m := New("Copy resource", items, func(item Item) tea.Cmd {
+		return func() tea.Msg { return selectedMsg(item) }
+	})
+
+	for _, r := range "config" {
+		m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	if got := len(m.visible()); got != 1 {
+		t.Fatalf("expected 1 visible item, got %d", got)
+	}
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatal("expected a command on enter")
+	}
+	// The picker closes before the selection runs, in a sequence
+	// whose message type is not exported
+	sequence := reflect.ValueOf(cmd())
+	for i := range sequence.Len() {
+		msg := sequence.Index(i).Interface().(tea.Cmd)
+		if s, ok := msg().(selectedMsg); ok {
+			if s.Value != "configmap" {
+				t.Errorf("selected %q, want %q", s.Value, "configmap")
+			}
+			return
+		}
+	}
+	t.Errorf("selection was not returned")
+}
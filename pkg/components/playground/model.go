@@ -0,0 +1,110 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package playground
+
+import (
+	"path/filepath"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mproffitt/bmx/pkg/components/dialog"
+	"github.com/mproffitt/delorian/pkg/components"
+	"github.com/mproffitt/delorian/pkg/theme"
+	"gopkg.in/yaml.v3"
+)
+
+// Width is the width of the editor overlay
+const Width = 80
+
+// Model edits one file of a kustomization overlay in memory so the
+// effect can be previewed before anything is written
+type Model struct {
+	action  components.EditAction
+	content string
+	file    string
+	form    *huh.Form
+	id      string
+}
+
+// New creates an editor for file, belonging to the kustomization
+// with the given id, starting from content
+func New(id, file, content string) *Model {
+	m := Model{
+		content: content,
+		file:    file,
+		id:      id,
+	}
+	m.form = huh.NewForm(huh.NewGroup(
+		huh.NewText().
+			Title("Edit "+filepath.Base(file)).
+			Description("alt+enter adds a line, enter continues and esc cancels").
+			Lines(16).
+			CharLimit(0).
+			Validate(validate).
+			Value(&m.content),
+		huh.NewSelect[components.EditAction]().
+			Options(
+				huh.NewOption("Preview the build", components.EditPreview),
+				huh.NewOption("Save to "+file, components.EditSave),
+				huh.NewOption("Discard edits", components.EditDiscard),
+			).
+			Value(&m.action),
+	)).WithWidth(Width - 4).WithShowHelp(false)
+	return &m
+}
+
+// validate checks the content is YAML so mistakes are caught in
+// the editor rather than by the build
+func validate(content string) error {
+	var v any
+	return yaml.Unmarshal([]byte(content), &v)
+}
+
+func (m *Model) Init() tea.Cmd {
+	return m.form.Init()
+}
+
+func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok && msg.String() == "esc" {
+		return m, dialog.DialogStatusCmd(dialog.DialogStatusMsg{Done: true})
+	}
+
+	form, cmd := m.form.Update(msg)
+	m.form = form.(*huh.Form)
+	switch m.form.State {
+	case huh.StateAborted:
+		return m, dialog.DialogStatusCmd(dialog.DialogStatusMsg{Done: true})
+	case huh.StateCompleted:
+		return m, tea.Batch(
+			components.OverlayEditCmd(m.id, m.file, m.content, m.action),
+			dialog.DialogStatusCmd(dialog.DialogStatusMsg{Done: true}))
+	}
+	return m, cmd
+}
+
+func (m *Model) View() string {
+	return lipgloss.NewStyle().
+		Border(theme.RoundedBorder(), true).
+		BorderForeground(theme.Colours.Blue).
+		Padding(1).
+		Width(Width).
+		Render(m.form.View())
+}
@@ -0,0 +1,116 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package queryinput
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/charmbracelet/log"
+)
+
+// MaxHistory is the number of queries remembered
+const MaxHistory = 50
+
+// HistoryFile is where queries are remembered between runs.
+// History is kept in memory only when this is empty
+var HistoryFile string
+
+// history holds the most recent queries that evaluated without
+// error, oldest first. It is shared by every query input
+type history struct {
+	sync.Mutex
+	entries []string
+	loaded  bool
+}
+
+var recent history
+
+// load reads the history file the first time it is needed
+func (h *history) load() {
+	if h.loaded {
+		return
+	}
+	h.loaded = true
+	if HistoryFile == "" {
+		return
+	}
+	content, err := os.ReadFile(HistoryFile)
+	if err != nil {
+		return
+	}
+	for _, line := range strings.Split(string(content), "\n") {
+		if line != "" {
+			h.entries = append(h.entries, line)
+		}
+	}
+	h.entries = h.entries[max(0, len(h.entries)-MaxHistory):]
+}
+
+// push remembers query as the most recent. A query already in the
+// history is moved to the end rather than repeated
+func (h *history) push(query string) {
+	query = strings.TrimSpace(query)
+	if query == "" || strings.Contains(query, "\n") {
+		return
+	}
+	h.Lock()
+	defer h.Unlock()
+	h.load()
+	for i, entry := range h.entries {
+		if entry == query {
+			h.entries = append(h.entries[:i], h.entries[i+1:]...)
+			break
+		}
+	}
+	h.entries = append(h.entries, query)
+	h.entries = h.entries[max(0, len(h.entries)-MaxHistory):]
+	h.save()
+}
+
+// save writes the history file. Failing to save only loses the
+// history so is logged rather than shown
+func (h *history) save() {
+	if HistoryFile == "" {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(HistoryFile), 0o755); err != nil {
+		log.Warn("saving query history", "error", err)
+		return
+	}
+	content := strings.Join(h.entries, "\n") + "\n"
+	if err := os.WriteFile(HistoryFile, []byte(content), 0o600); err != nil {
+		log.Warn("saving query history", "error", err)
+	}
+}
+
+// at returns the entry n places back from the most recent, where
+// 1 is the most recent. ok is false beyond the oldest entry
+func (h *history) at(n int) (string, bool) {
+	h.Lock()
+	defer h.Unlock()
+	h.load()
+	if n < 1 || n > len(h.entries) {
+		return "", false
+	}
+	return h.entries[len(h.entries)-n], true
+}
@@ -0,0 +1,115 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package queryinput
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// resetHistory points the history at a file in a fresh directory
+func resetHistory(t *testing.T) {
+	t.Helper()
+	HistoryFile = filepath.Join(t.TempDir(), ".query_history")
+	recent = history{}
+	t.Cleanup(func() {
+		HistoryFile = ""
+		recent = history{}
+	})
+}
+
+func typeQuery(m *Model, query string) {
+	for _, r := range query {
+		m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+}
+
+func TestHistoryRecall(t *testing.T) {
+	resetHistory(t)
+	input := "kind: ConfigMap\nmetadata:\n  name: app\n"
+	m := New(&input, 40)
+	m.Focus()
+
+	typeQuery(m, ".kind")
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m.filter.SetValue("")
+	typeQuery(m, ".metadata.name")
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+	// Invalid queries are not remembered
+	m.filter.SetValue("")
+	typeQuery(m, ".[[")
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+	m.filter.SetValue("")
+	for _, want := range []string{".metadata.name", ".kind", ".kind"} {
+		_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyUp})
+		if got := m.filter.Value(); got != want {
+			t.Fatalf("expected %q, got %q", want, got)
+		}
+		if cmd != nil {
+			if _, ok := cmd().(YqOutputMsg); !ok {
+				t.Errorf("expected %q to be evaluated", want)
+			}
+		}
+	}
+	m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	if got := m.filter.Value(); got != "" {
+		t.Errorf("expected moving past the newest query to clear the filter, got %q", got)
+	}
+
+	content, err := os.ReadFile(HistoryFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(content); got != ".kind\n.metadata.name\n" {
+		t.Errorf("expected both valid queries to be saved, got %q", got)
+	}
+
+	// A new session reads the saved history
+	recent = history{}
+	if query, ok := recent.at(1); !ok || query != ".metadata.name" {
+		t.Errorf("expected the saved history to be loaded, got %q", query)
+	}
+}
+
+func TestHistoryBounded(t *testing.T) {
+	resetHistory(t)
+	for i := range MaxHistory + 10 {
+		recent.push(fmt.Sprintf(".item%d", i))
+	}
+	recent.push(".item20")
+
+	if len(recent.entries) != MaxHistory {
+		t.Fatalf("expected %d entries, got %d", MaxHistory, len(recent.entries))
+	}
+	if recent.entries[0] != ".item10" {
+		t.Errorf("expected the oldest entries to be dropped, got %q", recent.entries[0])
+	}
+	if last, _ := recent.at(1); last != ".item20" || slices.Index(recent.entries, ".item20") != MaxHistory-1 {
+		t.Errorf("expected a repeated query to move to the end, got %v", recent.entries)
+	}
+}
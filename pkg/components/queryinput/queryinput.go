@@ -21,6 +21,8 @@ package queryinput
 
 import (
 	"io"
+	"sync/atomic"
+	"time"
 
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
@@ -34,6 +36,10 @@ import (
 
 const title = "yaml query"
 
+// QueryDelay is how long typing must pause before the filter is
+// evaluated. Each keystroke supersedes the evaluation before it
+var QueryDelay = 150 * time.Millisecond
+
 type YqErrorMsg struct {
 	Error error
 }
@@ -61,11 +67,12 @@ func YqErrorCmd(err error) tea.Cmd {
 }
 
 type Model struct {
-	decoder yqlib.Decoder
-	encoder yqlib.Encoder
-	filter  textinput.Model
-	input   *string
-	style   lipgloss.Style
+	filter textinput.Model
+	input  *string
+	prefs  yqlib.YamlPreferences
+	recall int
+	style  lipgloss.Style
+	tag    atomic.Int64
 }
 
 func disableLogging() {
@@ -75,14 +82,13 @@ func disableLogging() {
 
 func New(input *string, width int) *Model {
 	disableLogging()
-	prefs := yqlib.NewDefaultYamlPreferences()
+	yqlib.InitExpressionParser()
 	m := Model{
-		decoder: yqlib.NewYamlDecoder(prefs),
-		encoder: yqlib.NewYamlEncoder(prefs),
-		filter:  textinput.New(),
-		input:   input,
+		filter: textinput.New(),
+		input:  input,
+		prefs:  yqlib.NewDefaultYamlPreferences(),
 		style: lipgloss.NewStyle().
-			Border(lipgloss.RoundedBorder(), true).
+			Border(theme.RoundedBorder(), true).
 			BorderForeground(theme.Colours.Green),
 	}
 	m.filter.TextStyle = m.filter.TextStyle.UnsetMargins()
@@ -113,32 +119,73 @@ func (m *Model) SetSize(width, height int) tea.Model {
 }
 
 func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
-	var (
-		err error
-		cmd tea.Cmd
-	)
+	var cmd tea.Cmd
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		switch {
+		case (msg.Type == tea.KeyUp || msg.Type == tea.KeyDown) &&
+			(m.filter.Value() == "" || m.recall > 0):
+			cmd = m.recallHistory(msg.Type == tea.KeyUp)
+		case msg.Type == tea.KeyEnter:
+			if _, err := yqlib.ExpressionParser.ParseExpression(m.filter.Value()); err == nil {
+				recent.push(m.filter.Value())
+			}
 		default:
+			m.recall = 0
 			m.filter, _ = m.filter.Update(msg)
-			filter := m.filter.Value()
-			var output string
-			{
-				output, err = yqlib.NewStringEvaluator().
-					Evaluate(filter, *m.input, m.encoder, m.decoder)
-				log.Debug("query", "filter", filter, "input", m.input, "output", output, "error", err)
-				cmd = YqOutputCmd(output)
-				if err != nil {
-					cmd = YqErrorCmd(err)
-				}
-			}
+			cmd = m.evaluate()
 		}
 	}
 	return m, cmd
 }
 
+// evaluate runs the filter against the input once typing has
+// paused for QueryDelay. Nothing is sent when a later keystroke
+// has superseded it, before or after it ran
+func (m *Model) evaluate() tea.Cmd {
+	tag := m.tag.Add(1)
+	filter, input := m.filter.Value(), *m.input
+	return func() tea.Msg {
+		time.Sleep(QueryDelay)
+		if m.tag.Load() != tag {
+			return nil
+		}
+		output, err := yqlib.NewStringEvaluator().Evaluate(filter, input,
+			yqlib.NewYamlEncoder(m.prefs), yqlib.NewYamlDecoder(m.prefs))
+		log.Debug("query", "filter", filter, "output", output, "error", err)
+		switch {
+		case m.tag.Load() != tag:
+			return nil
+		case err != nil:
+			return YqErrorMsg{Error: err}
+		}
+		return YqOutputMsg{Filter: filter, Input: input, Output: output}
+	}
+}
+
+// recallHistory replaces the filter with an older query, or a
+// newer one when back is false. Moving past the most recent
+// query empties the filter again
+func (m *Model) recallHistory(back bool) tea.Cmd {
+	n := m.recall - 1
+	if back {
+		n = m.recall + 1
+	}
+	query, ok := recent.at(n)
+	switch {
+	case ok:
+	case n <= 0:
+		n = 0
+	default:
+		return nil
+	}
+	m.recall = n
+	m.filter.SetValue(query)
+	m.filter.CursorEnd()
+	return m.evaluate()
+}
+
 func (m *Model) View() string {
 	colour := theme.Colours.Black
 	titleColour := theme.Colours.Black
@@ -0,0 +1,68 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package queryinput
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestDebounce(t *testing.T) {
+	QueryDelay = 10 * time.Millisecond
+	t.Cleanup(func() { QueryDelay = 150 * time.Millisecond })
+
+	input := "kind: ConfigMap\n"
+	m := New(&input, 40)
+	m.Focus()
+
+	var cmds []tea.Cmd
+	for _, r := range ".kind" {
+		_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		cmds = append(cmds, cmd)
+	}
+	if m.filter.Value() != ".kind" {
+		t.Fatalf("expected the filter to update immediately, got %q", m.filter.Value())
+	}
+
+	msgs := make([]tea.Msg, len(cmds))
+	var wg sync.WaitGroup
+	for i, cmd := range cmds {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			msgs[i] = cmd()
+		}()
+	}
+	wg.Wait()
+
+	for i, msg := range msgs[:len(msgs)-1] {
+		if msg != nil {
+			t.Errorf("expected keystroke %d to be superseded, got %#v", i, msg)
+		}
+	}
+	out, ok := msgs[len(msgs)-1].(YqOutputMsg)
+	if !ok || strings.TrimSpace(out.Output) != "ConfigMap" {
+		t.Errorf("expected the last keystroke to be evaluated, got %#v", msgs[len(msgs)-1])
+	}
+}
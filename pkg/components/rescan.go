@@ -0,0 +1,60 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+package components
+
+import tea "github.com/charmbracelet/bubbletea"
+
+// RescanMsg asks the repository model to scan its roots again
+type RescanMsg struct{}
+
+// RescanCmd returns a RescanMsg
+func RescanCmd() tea.Cmd {
+	return func() tea.Msg {
+		return RescanMsg{}
+	}
+}
+
+// PollMsg is delivered each time the files found by the last scan
+// have been checked for changes
+//
+// Polls carry the tag of the scan that scheduled them so polls
+// from a previous scan can be discarded
+type PollMsg struct {
+	Tag     int
+	Changed bool
+}
+
+// FilesChangedMsg is sent by the file watcher once YAML files in
+// the repository have changed and stopped changing
+type FilesChangedMsg struct{}
+
+// ScanStartedMsg is sent when the repository model starts a scan
+//
+// FromCache is true when files unchanged since the previous run
+// are being loaded from the scan cache rather than parsed again
+type ScanStartedMsg struct {
+	FromCache bool
+}
+
+// ScanStartedCmd returns a ScanStartedMsg
+func ScanStartedCmd(fromCache bool) tea.Cmd {
+	return func() tea.Msg {
+		return ScanStartedMsg{FromCache: fromCache}
+	}
+}
@@ -0,0 +1,364 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package resourceview
+
+import (
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/lipgloss/tree"
+	"github.com/mproffitt/bmx/pkg/exec"
+	"github.com/mproffitt/delorian/pkg/components"
+	"github.com/mproffitt/delorian/pkg/components/picker"
+	"github.com/mproffitt/delorian/pkg/components/splash"
+	"github.com/mproffitt/delorian/pkg/components/yamlview"
+	"github.com/mproffitt/delorian/pkg/theme"
+)
+
+const (
+	NoFocus components.FocusType = iota
+	TreeFocus
+	ViewportFocus
+)
+
+// MinTreeWidth is the minimum width of the tree pane
+const MinTreeWidth = 30
+
+type Model struct {
+	cursor   int
+	load     components.Load
+	flat     []*Node
+	focus    components.FocusType
+	height   int
+	input    string
+	nodes    []*Node
+	splash   *splash.Model
+	styles   styles
+	viewport viewport.Model
+	width    int
+	yaml     *yamlview.Model
+}
+
+type styles struct {
+	enumerator lipgloss.Style
+	item       lipgloss.Style
+	relation   lipgloss.Style
+	selected   lipgloss.Style
+	implied    lipgloss.Style
+}
+
+// loadingMessage is shown on the splash while the resources load
+const loadingMessage = "Building kustomization resources..."
+
+// New creates a new resource tree view
+//
+// The resource view displays the rendered output of a kustomization
+// as a tree of resources related by ownership and reference, with
+// the YAML of the selected resource displayed alongside
+func New(w, h int) *Model {
+	m := Model{
+		flat:  make([]*Node, 0),
+		nodes: make([]*Node, 0),
+		focus: NoFocus,
+		styles: styles{
+			enumerator: lipgloss.NewStyle().Foreground(theme.Colours.Black),
+			item:       lipgloss.NewStyle().Foreground(theme.Colours.Purple),
+			relation:   lipgloss.NewStyle().Foreground(theme.Colours.BrightBlack),
+			selected: lipgloss.NewStyle().
+				Foreground(theme.Colours.BrightWhite).
+				Background(theme.Colours.SelectionBg),
+			implied: lipgloss.NewStyle().Foreground(theme.Colours.BrightBlack).Italic(true),
+		},
+		splash:   splash.New(loadingMessage, splash.Branding{}),
+		viewport: viewport.New(w, h),
+		yaml:     yamlview.New(w, h, false),
+	}
+	m.load.Start()
+	m.SetSize(w, h)
+	return &m
+}
+
+// SetBranding brands the splash shown while the resources load
+func (m *Model) SetBranding(branding splash.Branding) *Model {
+	m.splash = splash.New(loadingMessage, branding)
+	m.yaml.SetBranding(branding)
+	return m
+}
+
+func (m *Model) Init() tea.Cmd {
+	return m.splash.Init()
+}
+
+// NextFocus moves focus from the tree to the YAML viewport
+func (m *Model) NextFocus() components.FocusType {
+	switch m.focus {
+	case NoFocus:
+		m.focus = TreeFocus
+	case TreeFocus:
+		m.focus = ViewportFocus
+		m.yaml.NextFocus()
+	case ViewportFocus:
+		m.focus = NoFocus
+		m.yaml.NextFocus()
+	}
+	return m.focus
+}
+
+// PreviousFocus moves focus from the YAML viewport to the tree
+func (m *Model) PreviousFocus() components.FocusType {
+	switch m.focus {
+	case NoFocus:
+		m.focus = ViewportFocus
+		m.yaml.PreviousFocus()
+	case ViewportFocus:
+		m.focus = TreeFocus
+		m.yaml.PreviousFocus()
+	case TreeFocus:
+		m.focus = NoFocus
+	}
+	return m.focus
+}
+
+// SetSize sets the size of the view
+func (m *Model) SetSize(w, h int) tea.Model {
+	m.width = w
+	m.height = h
+	m.viewport.Width = m.treeWidth()
+	m.viewport.Height = h - theme.Padding
+	m.yaml.SetSize(w-m.treeWidth()-theme.Padding, h)
+	return m
+}
+
+func (m *Model) treeWidth() int {
+	return min(m.width, max(MinTreeWidth, m.width/3))
+}
+
+// LoadState reports if the view is waiting for content, showing
+// it or showing the error loading ended in
+func (m *Model) LoadState() components.LoadState {
+	return m.load.State()
+}
+
+func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	switch msg := msg.(type) {
+	case components.TabChangedMsg:
+		m.load.Start()
+		cmd = m.splash.Follow(&m.load)
+	case components.LoadSkippedMsg:
+		m.load.Skip()
+		cmd = m.splash.Follow(&m.load)
+	case splash.TickMsg:
+		m.splash, cmd = m.splash.Update(msg)
+	case components.ModelErrorMsg:
+		m.load.Fail(msg.Error)
+		m.splash.Follow(&m.load)
+	case components.FluxExecMsg:
+		resources, err := ParseResources(msg.Output)
+		if err != nil {
+			m.load.Fail(err)
+			m.splash.Follow(&m.load)
+			break
+		}
+		m.load.Done()
+		m.splash.Follow(&m.load)
+		m.input = msg.Output
+		m.nodes = BuildTree(resources)
+		m.flat = flatten(m.nodes)
+		m.cursor = 0
+		m.viewport.SetYOffset(0)
+		m.selected()
+	case tea.KeyMsg:
+		switch {
+		case m.focus == NoFocus:
+		case msg.String() == "c":
+			cmd = picker.CopyDocumentCmd(m.input)
+		case m.focus == TreeFocus && msg.String() == "y":
+			cmd = m.copySelected()
+		case m.focus == TreeFocus:
+			m.moveCursor(msg)
+		case m.focus == ViewportFocus:
+			_, cmd = m.yaml.Update(msg)
+		}
+	}
+	return m, cmd
+}
+
+// moveCursor handles navigation keys while the tree has focus
+func (m *Model) moveCursor(msg tea.KeyMsg) {
+	if len(m.flat) == 0 {
+		return
+	}
+	cursor := m.cursor
+	switch msg.String() {
+	case "up", "k":
+		cursor--
+	case "down", "j":
+		cursor++
+	case "pgup":
+		cursor -= m.viewport.Height
+	case "pgdown":
+		cursor += m.viewport.Height
+	case "home", "g":
+		cursor = 0
+	case "end", "G":
+		cursor = len(m.flat) - 1
+	}
+	cursor = max(0, min(cursor, len(m.flat)-1))
+	if cursor == m.cursor {
+		return
+	}
+	m.cursor = cursor
+
+	// keep the cursor inside the visible area of the tree
+	switch {
+	case m.cursor < m.viewport.YOffset:
+		m.viewport.SetYOffset(m.cursor)
+	case m.cursor >= m.viewport.YOffset+m.viewport.Height:
+		m.viewport.SetYOffset(m.cursor - m.viewport.Height + 1)
+	}
+	m.selected()
+}
+
+// selected displays the node under the cursor in the YAML view
+func (m *Model) selected() {
+	if len(m.flat) == 0 {
+		return
+	}
+	node := m.flat[m.cursor]
+	m.yaml.Update(components.FileMsg{
+		File:    node,
+		Ok:      true,
+		Content: node.GetContent(),
+	})
+}
+
+// copySelected copies the YAML of the resource under the cursor
+func (m *Model) copySelected() tea.Cmd {
+	if len(m.flat) == 0 || m.flat[m.cursor].Resource == nil {
+		return nil
+	}
+	r := m.flat[m.cursor].Resource
+	return components.CopyCmd(r.Raw(), r.GetName())
+}
+
+// flatten returns the nodes in the order they are rendered
+func flatten(nodes []*Node) []*Node {
+	flat := make([]*Node, 0)
+	for _, n := range nodes {
+		flat = append(flat, n)
+		flat = append(flat, flatten(n.Children)...)
+	}
+	return flat
+}
+
+func (m *Model) View() string {
+	if m.splash.Visible() {
+		return lipgloss.Place(m.width, m.height,
+			lipgloss.Center, lipgloss.Center,
+			m.splash.SetWidth(m.width).View())
+	}
+
+	if err := m.load.Err(); err != nil {
+		msg := err.Error()
+		switch e := err.(type) {
+		case *exec.BmxExecError:
+			msg = e.StyledError(m.width)
+		case *components.FluxError:
+			msg = e.StyledError(m.width)
+		}
+		msg = lipgloss.NewStyle().
+			Foreground(theme.Colours.Red).
+			MarginLeft(1).
+			Render(msg)
+		return lipgloss.Place(m.width, m.height,
+			lipgloss.Center, lipgloss.Center, msg)
+	}
+
+	if len(m.nodes) == 0 {
+		msg := lipgloss.NewStyle().
+			Foreground(theme.Colours.Cyan).
+			Render("No resources")
+		return lipgloss.Place(m.width, m.height,
+			lipgloss.Center, lipgloss.Center, msg)
+	}
+
+	m.viewport.SetContent(m.renderTree())
+	border := theme.Colours.Black
+	if m.focus == TreeFocus {
+		border = theme.Colours.Blue
+	}
+	left := lipgloss.NewStyle().
+		Border(theme.RoundedBorder(), true).
+		BorderForeground(border).
+		Render(m.viewport.View())
+	return lipgloss.JoinHorizontal(lipgloss.Top, left, m.yaml.View())
+}
+
+// renderTree draws the resource tree highlighting the node
+// under the cursor
+func (m *Model) renderTree() string {
+	index := 0
+	var build func(n *Node) *tree.Tree
+	build = func(n *Node) *tree.Tree {
+		t := tree.Root(m.label(n, index == m.cursor))
+		index++
+		for _, c := range n.Children {
+			if len(c.Children) == 0 {
+				t.Child(m.label(c, index == m.cursor))
+				index++
+				continue
+			}
+			t.Child(build(c))
+		}
+		return t
+	}
+
+	roots := tree.New().
+		Enumerator(tree.RoundedEnumerator).
+		EnumeratorStyle(m.styles.enumerator)
+	for _, n := range m.nodes {
+		if len(n.Children) == 0 {
+			roots.Child(m.label(n, index == m.cursor))
+			index++
+			continue
+		}
+		roots.Child(build(n).
+			Enumerator(tree.RoundedEnumerator).
+			EnumeratorStyle(m.styles.enumerator))
+	}
+	return roots.String()
+}
+
+// label renders the text for a single node
+func (m *Model) label(n *Node, selected bool) string {
+	style := m.styles.item
+	if n.Resource == nil {
+		style = m.styles.implied
+	}
+	if selected {
+		style = m.styles.selected
+	}
+	label := style.Render(n.Label)
+	if n.Relation != RelationNone {
+		label += m.styles.relation.Render(" (" + string(n.Relation) + ")")
+	}
+	return label
+}
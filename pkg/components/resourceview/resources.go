@@ -0,0 +1,421 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package resourceview
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	ryaml "github.com/mproffitt/delorian/pkg/yaml"
+	"gopkg.in/yaml.v3"
+)
+
+// Relation describes how a child node relates to its parent
+type Relation string
+
+const (
+	RelationNone           Relation = ""
+	RelationOwns           Relation = "owns"
+	RelationImplied        Relation = "implied"
+	RelationSelects        Relation = "selects"
+	RelationRoutes         Relation = "routes"
+	RelationScales         Relation = "scales"
+	RelationVolume         Relation = "volume"
+	RelationEnv            Relation = "env"
+	RelationImagePull      Relation = "image pull"
+	RelationServiceAccount Relation = "service account"
+)
+
+// Resource is a single document from the rendered output
+// of a kustomization
+type Resource struct {
+	APIVersion string
+	Kind       string
+	Name       string
+	Namespace  string
+	content    string
+	object     map[string]any
+	raw        string
+}
+
+// Key uniquely identifies the resource inside a build
+func (r *Resource) Key() string {
+	return key(r.Kind, r.Namespace, r.Name)
+}
+
+// GetName returns the kind and name of the resource
+func (r *Resource) GetName() string {
+	return r.Kind + "/" + r.Name
+}
+
+// GetPath is always empty as resources are rendered content
+func (r *Resource) GetPath() string {
+	return ""
+}
+
+// GetContent returns the YAML for this resource
+func (r *Resource) GetContent() string {
+	return r.content
+}
+
+// Raw returns the YAML for this resource exactly as it appeared
+// in the rendered output
+func (r *Resource) Raw() string {
+	if r.raw == "" {
+		return r.content
+	}
+	return r.raw
+}
+
+// Node is an entry in the resource tree.
+//
+// Nodes with no Resource are implied - these are objects that
+// will be created in the cluster by a controller but which do
+// not appear in the rendered output
+type Node struct {
+	Resource *Resource
+	Label    string
+	Relation Relation
+	Children []*Node
+}
+
+// GetName returns the label of this node
+func (n *Node) GetName() string {
+	return n.Label
+}
+
+// GetPath is always empty as nodes hold rendered content
+func (n *Node) GetPath() string {
+	return ""
+}
+
+// GetContent returns the YAML for the resource held by this
+// node, or a short description for implied nodes
+func (n *Node) GetContent() string {
+	if n.Resource != nil {
+		return n.Resource.GetContent()
+	}
+	return fmt.Sprintf("# %s is created at runtime by its parent\n"+
+		"# and does not appear in the rendered output\n", n.Label)
+}
+
+// edge is a relationship from one resource to another
+type edge struct {
+	to       *Resource
+	relation Relation
+}
+
+// ParseResources splits the multi-document output of a build
+// into individual resources
+func ParseResources(input string) ([]*Resource, error) {
+	resources := make([]*Resource, 0)
+	decoder := yaml.NewDecoder(strings.NewReader(input))
+	for {
+		var node yaml.Node
+		err := decoder.Decode(&node)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		var object map[string]any
+		if err := node.Decode(&object); err != nil || object == nil {
+			continue
+		}
+
+		var content strings.Builder
+		encoder := yaml.NewEncoder(&content)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(&node); err != nil {
+			return nil, err
+		}
+		r := Resource{
+			APIVersion: str(object, "apiVersion"),
+			Kind:       str(object, "kind"),
+			Name:       str(object, "metadata", "name"),
+			Namespace:  str(object, "metadata", "namespace"),
+			content:    content.String(),
+			object:     object,
+		}
+		if r.Kind == "" {
+			continue
+		}
+		resources = append(resources, &r)
+	}
+
+	for _, d := range ryaml.Documents(input) {
+		for _, r := range resources {
+			if r.Kind == d.Kind && r.Namespace == d.Namespace && r.Name == d.Name {
+				r.raw = d.Raw
+			}
+		}
+	}
+	return resources, nil
+}
+
+// BuildTree infers the relationships between resources and
+// returns the roots of the resulting tree.
+//
+// Relationships are found from ownerReferences, service and
+// ingress selectors, autoscaler targets and the volumes and
+// environment of pod templates. Resources which are not the
+// target of any relationship form the roots of the tree
+func BuildTree(resources []*Resource) []*Node {
+	index := make(map[string]*Resource, len(resources))
+	for _, r := range resources {
+		index[r.Key()] = r
+	}
+
+	edges := make(map[string][]edge)
+	targeted := make(map[string]bool)
+	add := func(from *Resource, kind, namespace, name string, relation Relation) {
+		to, ok := index[key(kind, namespace, name)]
+		if !ok || to == from {
+			return
+		}
+		for _, e := range edges[from.Key()] {
+			if e.to == to {
+				return
+			}
+		}
+		edges[from.Key()] = append(edges[from.Key()], edge{to: to, relation: relation})
+		targeted[to.Key()] = true
+	}
+
+	for _, r := range resources {
+		for _, owner := range list(r.object, "metadata", "ownerReferences") {
+			if o, ok := index[key(str(owner, "kind"), r.Namespace, str(owner, "name"))]; ok {
+				add(o, r.Kind, r.Namespace, r.Name, RelationOwns)
+			}
+		}
+
+		switch r.Kind {
+		case "Service":
+			selector := mapping(r.object, "spec", "selector")
+			if len(selector) == 0 {
+				break
+			}
+			for _, w := range resources {
+				if w.Namespace == r.Namespace && matches(selector, podLabels(w)) {
+					add(r, w.Kind, w.Namespace, w.Name, RelationSelects)
+				}
+			}
+		case "Ingress":
+			if name := str(r.object, "spec", "defaultBackend", "service", "name"); name != "" {
+				add(r, "Service", r.Namespace, name, RelationRoutes)
+			}
+			for _, rule := range list(r.object, "spec", "rules") {
+				for _, path := range list(rule, "http", "paths") {
+					add(r, "Service", r.Namespace,
+						str(path, "backend", "service", "name"), RelationRoutes)
+				}
+			}
+		case "HorizontalPodAutoscaler":
+			add(r, str(r.object, "spec", "scaleTargetRef", "kind"), r.Namespace,
+				str(r.object, "spec", "scaleTargetRef", "name"), RelationScales)
+		}
+
+		spec := podSpec(r)
+		if spec == nil {
+			continue
+		}
+		for _, ref := range podReferences(spec) {
+			add(r, ref.kind, r.Namespace, ref.name, ref.relation)
+		}
+	}
+
+	roots := make([]*Node, 0)
+	for _, r := range resources {
+		if !targeted[r.Key()] {
+			roots = append(roots, expand(r, RelationNone, edges, map[string]bool{}))
+		}
+	}
+	return roots
+}
+
+// expand builds the node for a resource and all of its children.
+//
+// visited holds the resources on the current path so that cyclic
+// relationships terminate
+func expand(r *Resource, relation Relation, edges map[string][]edge, visited map[string]bool) *Node {
+	node := Node{
+		Resource: r,
+		Label:    r.GetName(),
+		Relation: relation,
+		Children: make([]*Node, 0),
+	}
+	visited[r.Key()] = true
+	defer delete(visited, r.Key())
+
+	switch r.Kind {
+	case "Deployment":
+		node.Children = append(node.Children, implied("ReplicaSet", r.Name))
+	case "CronJob":
+		node.Children = append(node.Children, implied("Job", r.Name))
+	}
+
+	for _, e := range edges[r.Key()] {
+		if visited[e.to.Key()] {
+			continue
+		}
+		node.Children = append(node.Children, expand(e.to, e.relation, edges, visited))
+	}
+	return &node
+}
+
+// implied creates a node for a resource created by a controller
+func implied(kind, name string) *Node {
+	return &Node{
+		Label:    fmt.Sprintf("%s/%s-*", kind, name),
+		Relation: RelationImplied,
+		Children: make([]*Node, 0),
+	}
+}
+
+type reference struct {
+	kind     string
+	name     string
+	relation Relation
+}
+
+// podSpec finds the pod spec for workload resources
+func podSpec(r *Resource) map[string]any {
+	switch r.Kind {
+	case "Pod":
+		return mapping(r.object, "spec")
+	case "Deployment", "StatefulSet", "DaemonSet", "ReplicaSet", "Job":
+		return mapping(r.object, "spec", "template", "spec")
+	case "CronJob":
+		return mapping(r.object, "spec", "jobTemplate", "spec", "template", "spec")
+	}
+	return nil
+}
+
+// podLabels returns the labels applied to pods created from
+// the resource
+func podLabels(r *Resource) map[string]any {
+	switch r.Kind {
+	case "Pod":
+		return mapping(r.object, "metadata", "labels")
+	case "Deployment", "StatefulSet", "DaemonSet", "ReplicaSet", "Job":
+		return mapping(r.object, "spec", "template", "metadata", "labels")
+	case "CronJob":
+		return mapping(r.object, "spec", "jobTemplate", "spec", "template", "metadata", "labels")
+	}
+	return nil
+}
+
+// podReferences collects the config maps, secrets, volume claims
+// and service accounts used by a pod spec
+func podReferences(spec map[string]any) []reference {
+	refs := make([]reference, 0)
+	for _, v := range list(spec, "volumes") {
+		refs = append(refs,
+			reference{"ConfigMap", str(v, "configMap", "name"), RelationVolume},
+			reference{"Secret", str(v, "secret", "secretName"), RelationVolume},
+			reference{"PersistentVolumeClaim", str(v, "persistentVolumeClaim", "claimName"), RelationVolume},
+		)
+		for _, s := range list(v, "projected", "sources") {
+			refs = append(refs,
+				reference{"ConfigMap", str(s, "configMap", "name"), RelationVolume},
+				reference{"Secret", str(s, "secret", "name"), RelationVolume},
+			)
+		}
+	}
+
+	containers := append(list(spec, "initContainers"), list(spec, "containers")...)
+	for _, c := range containers {
+		for _, e := range list(c, "envFrom") {
+			refs = append(refs,
+				reference{"ConfigMap", str(e, "configMapRef", "name"), RelationEnv},
+				reference{"Secret", str(e, "secretRef", "name"), RelationEnv},
+			)
+		}
+		for _, e := range list(c, "env") {
+			refs = append(refs,
+				reference{"ConfigMap", str(e, "valueFrom", "configMapKeyRef", "name"), RelationEnv},
+				reference{"Secret", str(e, "valueFrom", "secretKeyRef", "name"), RelationEnv},
+			)
+		}
+	}
+
+	for _, s := range list(spec, "imagePullSecrets") {
+		refs = append(refs, reference{"Secret", str(s, "name"), RelationImagePull})
+	}
+	if sa := str(spec, "serviceAccountName"); sa != "" {
+		refs = append(refs, reference{"ServiceAccount", sa, RelationServiceAccount})
+	}
+
+	found := make([]reference, 0, len(refs))
+	for _, r := range refs {
+		if r.name != "" {
+			found = append(found, r)
+		}
+	}
+	return found
+}
+
+// matches reports if every entry in selector is present in labels
+func matches(selector, labels map[string]any) bool {
+	if len(labels) == 0 {
+		return false
+	}
+	for k, v := range selector {
+		if fmt.Sprint(labels[k]) != fmt.Sprint(v) {
+			return false
+		}
+	}
+	return true
+}
+
+func key(kind, namespace, name string) string {
+	return fmt.Sprintf("%s/%s/%s", kind, namespace, name)
+}
+
+// get walks a decoded YAML object along the given path
+func get(object any, path ...string) any {
+	for _, p := range path {
+		m, ok := object.(map[string]any)
+		if !ok {
+			return nil
+		}
+		object = m[p]
+	}
+	return object
+}
+
+func str(object any, path ...string) string {
+	s, _ := get(object, path...).(string)
+	return s
+}
+
+func mapping(object any, path ...string) map[string]any {
+	m, _ := get(object, path...).(map[string]any)
+	return m
+}
+
+func list(object any, path ...string) []any {
+	l, _ := get(object, path...).([]any)
+	return l
+}
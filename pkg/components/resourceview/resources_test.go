@@ -0,0 +1,123 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package resourceview
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+// describe renders the tree as indented "relation label" lines
+func describe(nodes []*Node, depth int) []string {
+	lines := make([]string, 0)
+	for _, n := range nodes {
+		line := strings.Repeat("  ", depth) + n.Label
+		if n.Relation != RelationNone {
+			line += " (" + string(n.Relation) + ")"
+		}
+		lines = append(lines, line)
+		lines = append(lines, describe(n.Children, depth+1)...)
+	}
+	return lines
+}
+
+func TestBuildTree(t *testing.T) {
+	content, err := os.ReadFile("testdata/podinfo.yaml")
+	if err != nil {
+		t.Fatalf("unable to read fixture: %v", err)
+	}
+	resources, err := ParseResources(string(content))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(resources) != 7 {
+		t.Fatalf("expected 7 resources, got %d", len(resources))
+	}
+
+	want := []string{
+		"Ingress/podinfo",
+		"  Service/podinfo (routes)",
+		"    Deployment/podinfo (selects)",
+		"      ReplicaSet/podinfo-* (implied)",
+		"      Secret/podinfo-tls (volume)",
+		"      ConfigMap/podinfo-config (env)",
+		"      ServiceAccount/podinfo (service account)",
+		"HorizontalPodAutoscaler/podinfo",
+		"  Deployment/podinfo (scales)",
+		"    ReplicaSet/podinfo-* (implied)",
+		"    Secret/podinfo-tls (volume)",
+		"    ConfigMap/podinfo-config (env)",
+		"    ServiceAccount/podinfo (service account)",
+	}
+	got := describe(BuildTree(resources), 0)
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("unexpected tree\n--- got ---\n%s\n--- want ---\n%s",
+			strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestBuildTreeOwnerReferenceCycle(t *testing.T) {
+	input := `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: a
+  ownerReferences:
+    - kind: ConfigMap
+      name: b
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: b
+  ownerReferences:
+    - kind: ConfigMap
+      name: a
+`
+	resources, err := ParseResources(input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Both resources are owned so neither is a root, but
+	// building the tree must still terminate
+	if roots := BuildTree(resources); len(roots) != 0 {
+		t.Errorf("expected no roots, got %v", describe(roots, 0))
+	}
+}
+
+func TestResourceRaw(t *testing.T) {
+	input := `apiVersion: v1
+kind: ConfigMap
+metadata:
+    name: settings # indented as rendered
+data:
+    key: "quoted"
+`
+	resources, err := ParseResources(input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(resources) != 1 {
+		t.Fatalf("expected 1 resource, got %d", len(resources))
+	}
+	if resources[0].Raw() != input {
+		t.Errorf("raw YAML was not preserved\ngot:\n%s\nwant:\n%s", resources[0].Raw(), input)
+	}
+}
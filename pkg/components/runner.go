@@ -0,0 +1,103 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package components
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	bmx "github.com/mproffitt/bmx/pkg/exec"
+)
+
+// Runner runs an external command such as flux or kubectl and
+// returns what it wrote to stdout and stderr.
+//
+// Commands which exit with a non-zero status return a
+// *bmx.BmxExecError holding the output so callers can inspect it
+type Runner interface {
+	Run(ctx context.Context, name string, args []string) (stdout, stderr string, err error)
+
+	// LookPath finds the binary Run would execute for name
+	LookPath(name string) (string, error)
+}
+
+// ExecRunner runs commands found in PATH. It is used unless
+// another runner has been set
+type ExecRunner struct{}
+
+// Run looks up name in PATH and executes it with args. The
+// command is killed if ctx is done before it exits
+func (r ExecRunner) Run(ctx context.Context, name string, args []string) (string, string, error) {
+	binary, err := r.LookPath(name)
+	if err != nil {
+		return "", "", err
+	}
+	var stdout, stderr strings.Builder
+	cmd := exec.CommandContext(ctx, binary, args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err = cmd.Run(); err != nil {
+		execErr := &bmx.BmxExecError{
+			Command: fmt.Sprintf("%s %s", binary, strings.Join(args, " ")),
+			Stdout:  strings.TrimSpace(stdout.String()),
+			Stderr:  strings.TrimSpace(stderr.String()),
+		}
+		execErr.SetError(err)
+		return "", "", execErr
+	}
+	return strings.TrimSpace(stdout.String()), strings.TrimSpace(stderr.String()), nil
+}
+
+// LookPath searches PATH for name
+func (ExecRunner) LookPath(name string) (string, error) {
+	return exec.LookPath(name)
+}
+
+var runner Runner = ExecRunner{}
+
+// SetRunner replaces the runner used to execute external
+// commands. A nil runner restores the default
+func SetRunner(r Runner) {
+	if r == nil {
+		r = ExecRunner{}
+	}
+	runner = r
+}
+
+// notFound reports if err was returned because the command
+// could not be found
+func notFound(err error) bool {
+	return errors.Is(err, exec.ErrNotFound)
+}
+
+// Run executes name with the runner set by SetRunner. The command
+// is stopped if ctx is done before it exits
+func Run(ctx context.Context, name string, args []string) (string, string, error) {
+	return runner.Run(ctx, name, args)
+}
+
+// LookPath finds name with the runner set by SetRunner
+func LookPath(name string) (string, error) {
+	return runner.LookPath(name)
+}
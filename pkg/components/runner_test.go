@@ -0,0 +1,101 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package components
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"slices"
+	"testing"
+
+	bmx "github.com/mproffitt/bmx/pkg/exec"
+)
+
+// fakeRunner returns canned output for each command name and
+// records what was run
+type fakeRunner struct {
+	stdout map[string]string
+	errs   map[string]error
+	ran    [][]string
+}
+
+func (f *fakeRunner) Run(_ context.Context, name string, args []string) (string, string, error) {
+	f.ran = append(f.ran, append([]string{name}, args...))
+	return f.stdout[name], "", f.errs[name]
+}
+
+func (f *fakeRunner) LookPath(name string) (string, error) {
+	return name, f.errs[name]
+}
+
+func TestFluxExecRunner(t *testing.T) {
+	defer SetRunner(nil)
+	changes := &bmx.BmxExecError{
+		Stdout: "► Deployment/apps/web drifted",
+		Stderr: "✗ identified at least one change, exiting with non-zero exit code",
+	}
+	for _, tc := range []struct {
+		name    string
+		stdout  string
+		err     error
+		want    string
+		wantErr bool
+	}{
+		{"output", "► no changes", nil, "► no changes", false},
+		{"changes found", "", changes, changes.Stdout, false},
+		{"failure", "", &bmx.BmxExecError{Stderr: "✗ Unauthorized"}, "", true},
+		{"not installed", "", fmt.Errorf("flux: %w", exec.ErrNotFound), "", true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			fake := &fakeRunner{
+				stdout: map[string]string{"flux": tc.stdout},
+				errs:   map[string]error{"flux": tc.err},
+			}
+			SetRunner(fake)
+			got, err := FluxExec(context.Background(), []string{"diff", "kustomization", "apps"})
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("expected error %v, got %v", tc.wantErr, err)
+			}
+			if got != tc.want {
+				t.Errorf("expected %q, got %q", tc.want, got)
+			}
+			if len(fake.ran) != 1 || !slices.Equal(fake.ran[0][:2], []string{"flux", "diff"}) {
+				t.Errorf("expected flux diff to be run, got %v", fake.ran)
+			}
+		})
+	}
+}
+
+func TestClusterCheckRunner(t *testing.T) {
+	defer SetRunner(nil)
+	fake := &fakeRunner{
+		stdout: map[string]string{"kubectl": "kind-dev"},
+		errs:   map[string]error{"kubectl": exec.ErrNotFound},
+	}
+	SetRunner(fake)
+	msg := ClusterCheckCmd()().(ClusterStatusMsg)
+	if !msg.Online {
+		t.Errorf("expected flux to be used when kubectl is missing, got %+v", msg)
+	}
+	if last := fake.ran[len(fake.ran)-1]; last[0] != "flux" {
+		t.Errorf("expected the last check to run flux, got %v", last)
+	}
+}
@@ -28,8 +28,27 @@ import (
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/mproffitt/delorian/pkg/components"
+	"github.com/mproffitt/delorian/pkg/theme"
 )
 
+// TickInterval is the time between animation frames
+//
+// Each tick wakes the program and forces a full render, so a
+// splash must only tick while it is visible on screen. Hidden
+// splashes, and splashes on tabs that are not active, schedule
+// nothing
+const TickInterval = 100 * time.Millisecond
+
+// Branding allows the application to be presented under a
+// custom name. Title and Subtitle are displayed above the message
+// when set. The zero value shows the flux logo with no title
+type Branding struct {
+	Title    string
+	Subtitle string
+	HideLogo bool
+}
+
 const fluxLogo = `
                     ▣▣▣
                  =▣▣▣▣▣▣▣≠
@@ -95,88 +114,181 @@ func FluxLogo(colourA, colourB string, width int) string {
 }
 
 type (
-	TickMsg time.Time
+	// TickMsg advances the animation of the splash with
+	// the matching ID
+	TickMsg struct {
+		ID   string
+		Time time.Time
+		tag  int
+	}
 
 	Model struct {
+		animated         bool
+		id               string
 		left             progress.Model
+		logo             bool
 		msg              string
 		percent          float64
-		visbible         bool
+		subtitle         string
+		title            string
+		tag              int
+		visible          bool
 		colourA, colourB string
 		width            int
 	}
 )
 
-func New(msg string) *Model {
+// New creates a splash showing msg, branded by branding
+func New(msg string, branding Branding) *Model {
 	m := Model{
+		animated: true,
+		id:       components.NewID(),
+		logo:     !branding.HideLogo,
 		msg:      msg,
-		visbible: true,
+		subtitle: branding.Subtitle,
+		title:    branding.Title,
+		visible:  true,
 		colourA:  "#3d6ddd",
 		colourB:  "#c3d2f4",
 	}
 
+	fill := []rune(theme.Glyph("━"))[0]
 	m.left = progress.New(
 		progress.WithScaledGradient(m.colourA, m.colourB),
 		progress.WithScaledEmptyGradient(m.colourB, m.colourA),
 		progress.WithoutPercentage(),
-		progress.WithFillCharacters('━', '━'),
+		progress.WithFillCharacters(fill, fill),
 	)
 	m.left.Width = 45
 	return &m
 }
 
 func (m *Model) Init() tea.Cmd {
-	return TickCmd()
+	return m.TickCmd()
+}
+
+// SetAnimated switches between the animated and static modes
+//
+// Switching to static mode halts any scheduled tick. Switching
+// back requires a call to TickCmd to restart the animation
+func (m *Model) SetAnimated(a bool) *Model {
+	m.animated = a
+	if !a {
+		m.tag++
+	}
+	return m
+}
+
+// SetVisible shows or hides the splash.
+//
+// Showing the splash returns the command that starts the
+// animation loop. Hiding the splash invalidates any tick
+// already scheduled so the loop halts as soon as the splash
+// is hidden rather than after the next frame.
+//
+// SetVisible is idempotent - repeated calls with the same
+// value leave the splash in the same state and never result
+// in more than one animation loop running
+func (m *Model) SetVisible(v bool) tea.Cmd {
+	if !v {
+		if m.visible {
+			m.visible = false
+			m.tag++
+		}
+		return nil
+	}
+	m.visible = true
+	return m.TickCmd()
 }
 
-func (m *Model) SetVisible(v bool) {
-	m.visbible = v
+// Follow shows the splash only while load is in progress
+func (m *Model) Follow(load *components.Load) tea.Cmd {
+	return m.SetVisible(load.State() == components.StateLoading)
 }
 
 func (m *Model) Visible() bool {
-	return m.visbible
+	return m.visible
+}
+
+// Progress returns the current position of the progress
+// bar as a value between 0 and 1
+func (m *Model) Progress() float64 {
+	return m.percent
 }
 
 func (m *Model) Update(msg tea.Msg) (*Model, tea.Cmd) {
-	switch msg.(type) {
+	switch msg := msg.(type) {
 	case TickMsg:
-		if !m.visbible {
+		if msg.ID != m.id || msg.tag != m.tag {
+			return m, nil
+		}
+		if !m.visible || !m.animated {
 			return m, nil
 		}
 		m.percent += 0.01
 		if m.percent >= 1.0 {
 			m.percent = 0.
 		}
-		return m, TickCmd()
+		return m, m.tick()
 
 	default:
 		return m, nil
 	}
 }
 
+// SetMessage replaces the message shown beneath the logo
+func (m *Model) SetMessage(msg string) *Model {
+	m.msg = msg
+	return m
+}
+
 func (m *Model) SetWidth(w int) *Model {
 	m.width = w
 	return m
 }
 
 func (m *Model) View() string {
-	if !m.visbible {
+	if !m.visible {
 		return ""
 	}
 	left := m.left.ViewAs(m.percent)
-	msg := lipgloss.NewStyle().
-		Width(m.width).Align(lipgloss.Center).
-		Foreground(lipgloss.Color(m.colourA)).Render(m.msg)
-	logo := FluxLogo(m.colourA, m.colourB, m.width)
-	lview := viewport.New(45, 20)
-	lview.SetContent(logo)
-	logo = lipgloss.NewStyle().Width(m.width).Align(lipgloss.Center).Render(lview.View())
-	content := lipgloss.JoinVertical(lipgloss.Center, logo, msg, left)
-	return content
+	style := lipgloss.NewStyle().Width(m.width).Align(lipgloss.Center)
+	parts := make([]string, 0)
+	if m.logo {
+		logo := FluxLogo(m.colourA, m.colourB, m.width)
+		lview := viewport.New(45, 20)
+		lview.SetContent(logo)
+		parts = append(parts, style.Render(lview.View()))
+	}
+	if m.title != "" {
+		parts = append(parts, style.Bold(true).
+			Foreground(lipgloss.Color(m.colourB)).Render(m.title))
+	}
+	if m.subtitle != "" {
+		parts = append(parts, style.
+			Foreground(lipgloss.Color(m.colourB)).Render(m.subtitle))
+	}
+	msg := style.Foreground(lipgloss.Color(m.colourA)).Render(m.msg)
+	parts = append(parts, msg, left)
+	return lipgloss.JoinVertical(lipgloss.Center, parts...)
+}
+
+// TickCmd starts the animation loop for this splash.
+//
+// Any tick already scheduled is superseded so calling this
+// repeatedly never results in more than one loop running.
+// When the splash is hidden or static no tick is scheduled
+func (m *Model) TickCmd() tea.Cmd {
+	if !m.visible || !m.animated {
+		return nil
+	}
+	m.tag++
+	return m.tick()
 }
 
-func TickCmd() tea.Cmd {
-	return tea.Tick(100*time.Millisecond, func(t time.Time) tea.Msg {
-		return TickMsg(t)
+func (m *Model) tick() tea.Cmd {
+	id, tag := m.id, m.tag
+	return tea.Tick(TickInterval, func(t time.Time) tea.Msg {
+		return TickMsg{ID: id, Time: t, tag: tag}
 	})
 }
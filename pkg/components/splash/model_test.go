@@ -0,0 +1,120 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package splash
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/mproffitt/delorian/pkg/testutil"
+)
+
+func TestMain(m *testing.M) {
+	testutil.Setup()
+	os.Exit(m.Run())
+}
+
+func TestView(t *testing.T) {
+	m := New("loading kustomizations...", Branding{}).SetWidth(60)
+	testutil.RequireGolden(t, m.View())
+}
+
+func TestViewBranded(t *testing.T) {
+	m := New("loading", Branding{Title: "platform", Subtitle: "by the platform team", HideLogo: true}).SetWidth(60)
+	view := m.View()
+	for _, want := range []string{"platform", "by the platform team", "loading"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("expected the view to show %q, got\n%s", want, view)
+		}
+	}
+	if strings.Contains(view, "▣") {
+		t.Errorf("expected the logo to be hidden, got\n%s", view)
+	}
+}
+
+func TestViewHidden(t *testing.T) {
+	m := New("loading kustomizations...", Branding{}).SetWidth(60)
+	m.SetVisible(false)
+	if view := m.View(); view != "" {
+		t.Errorf("expected empty view when hidden, got %q", view)
+	}
+}
+
+func TestStaticNeverTicks(t *testing.T) {
+	m := New("loading", Branding{}).SetAnimated(false)
+	if cmd := m.Init(); cmd != nil {
+		t.Error("expected static splash not to schedule a tick on Init")
+	}
+	if cmd := m.TickCmd(); cmd != nil {
+		t.Error("expected static splash not to schedule a tick")
+	}
+}
+
+func TestHiddenHaltsTicks(t *testing.T) {
+	m := New("loading", Branding{})
+	msg := m.TickCmd()()
+	m.SetVisible(false)
+	if _, cmd := m.Update(msg); cmd != nil {
+		t.Error("expected pending tick to be dropped once hidden")
+	}
+	if cmd := m.TickCmd(); cmd != nil {
+		t.Error("expected hidden splash not to schedule a tick")
+	}
+}
+
+func TestSupersededTickIgnored(t *testing.T) {
+	m := New("loading", Branding{})
+	stale := m.TickCmd()()
+	current := m.TickCmd()()
+
+	if _, cmd := m.Update(stale); cmd != nil {
+		t.Error("expected superseded tick to be ignored")
+	}
+	if _, cmd := m.Update(current); cmd == nil {
+		t.Error("expected current tick to schedule the next frame")
+	}
+
+	other := New("other", Branding{})
+	if _, cmd := other.Update(current); cmd != nil {
+		t.Error("expected tick for a different splash to be ignored")
+	}
+}
+
+func TestSetVisibleIdempotent(t *testing.T) {
+	m := New("loading", Branding{})
+	first := m.SetVisible(true)()
+	second := m.SetVisible(true)()
+	if _, cmd := m.Update(first); cmd != nil {
+		t.Error("expected only one animation loop to be running")
+	}
+	if _, cmd := m.Update(second); cmd == nil {
+		t.Error("expected the latest loop to continue")
+	}
+	if m.Progress() != 0.01 {
+		t.Errorf("expected progress to advance one frame, got %v", m.Progress())
+	}
+
+	m.SetVisible(false)
+	m.SetVisible(false)
+	if m.Visible() {
+		t.Error("expected splash to be hidden")
+	}
+}
@@ -0,0 +1,153 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package statusbar
+
+import (
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mproffitt/delorian/pkg/components"
+	"github.com/mproffitt/delorian/pkg/theme"
+	"github.com/muesli/reflow/truncate"
+)
+
+// Height is the number of lines taken by the status bar
+const Height = 1
+
+type segment struct {
+	key   string
+	value string
+}
+
+type Model struct {
+	title    string
+	subtitle string
+	segments []segment
+	offline  bool
+	context  string
+	width    int
+	styles   styles
+}
+
+type styles struct {
+	title   lipgloss.Style
+	segment lipgloss.Style
+	online  lipgloss.Style
+	offline lipgloss.Style
+	divider lipgloss.Style
+}
+
+// New creates a new status bar with the given title
+func New(title string) *Model {
+	m := Model{
+		title:    title,
+		segments: make([]segment, 0),
+		styles: styles{
+			title: lipgloss.NewStyle().
+				Foreground(theme.Colours.Bg).
+				Background(theme.Colours.Blue).
+				Padding(0, 1),
+			segment: lipgloss.NewStyle().
+				Foreground(theme.Colours.BrightBlack).
+				Padding(0, 1),
+			online:  lipgloss.NewStyle().Foreground(theme.Colours.Green).Padding(0, 1),
+			offline: lipgloss.NewStyle().Foreground(theme.Colours.Red).Padding(0, 1),
+			divider: lipgloss.NewStyle().Foreground(theme.Colours.Black),
+		},
+	}
+	return &m
+}
+
+// SetSubtitle sets the text displayed alongside the title
+func (m *Model) SetSubtitle(subtitle string) *Model {
+	m.subtitle = subtitle
+	return m
+}
+
+func (m *Model) Init() tea.Cmd {
+	return nil
+}
+
+func (m *Model) SetSize(w, h int) tea.Model {
+	m.width = w
+	return m
+}
+
+// Offline reports if the cluster was found to be unreachable
+func (m *Model) Offline() bool {
+	return m.offline
+}
+
+func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case components.ClusterStatusMsg:
+		m.offline = !msg.Online
+		m.context = msg.Context
+	case components.StatusMsg:
+		m.set(msg.Key, msg.Value)
+	}
+	return m, nil
+}
+
+func (m *Model) set(key, value string) {
+	index := slices.IndexFunc(m.segments, func(s segment) bool {
+		return s.key == key
+	})
+	switch {
+	case value == "" && index >= 0:
+		m.segments = slices.Delete(m.segments, index, index+1)
+	case value == "":
+	case index >= 0:
+		m.segments[index].value = value
+	default:
+		m.segments = append(m.segments, segment{key: key, value: value})
+	}
+}
+
+func (m *Model) View() string {
+	divider := m.styles.divider.Render(theme.Glyph("│"))
+	parts := []string{m.styles.title.Render(m.title)}
+	if m.subtitle != "" {
+		parts = append(parts, m.styles.segment.Render(m.subtitle))
+	}
+
+	cluster := theme.Glyph("● ") + m.context
+	style := m.styles.online
+	if m.offline {
+		cluster = theme.Glyph("○ offline")
+		if m.context != "" {
+			cluster += " (" + m.context + ")"
+		}
+		style = m.styles.offline
+	}
+	if strings.TrimSpace(cluster) != theme.Glyph("●") {
+		parts = append(parts, style.Render(cluster))
+	}
+
+	for _, s := range m.segments {
+		parts = append(parts, divider, m.styles.segment.Render(s.value))
+	}
+
+	content := lipgloss.JoinHorizontal(lipgloss.Top, parts...)
+	content = truncate.String(content, uint(max(m.width, 0)))
+	return lipgloss.NewStyle().Width(m.width).MaxHeight(Height).Render(content)
+}
@@ -0,0 +1,51 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+package components
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// OpenDialogMsg asks the manager to display a dialog above the
+// rest of the UI. Key presses are sent to the dialog until it
+// returns a dialog.DialogStatusMsg marked as done
+type OpenDialogMsg struct {
+	Dialog tea.Model
+}
+
+// OpenDialogCmd returns an OpenDialogMsg for the given dialog
+func OpenDialogCmd(dialog tea.Model) tea.Cmd {
+	return func() tea.Msg {
+		return OpenDialogMsg{Dialog: dialog}
+	}
+}
+
+// SubstitutionsMsg carries the postBuild substitution overrides
+// entered for the kustomization with the given ID
+type SubstitutionsMsg struct {
+	ID        string
+	Overrides map[string]string
+}
+
+// SubstitutionsCmd returns a SubstitutionsMsg
+func SubstitutionsCmd(id string, overrides map[string]string) tea.Cmd {
+	return func() tea.Msg {
+		return SubstitutionsMsg{ID: id, Overrides: overrides}
+	}
+}
@@ -0,0 +1,149 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+package substitutions
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mproffitt/bmx/pkg/components/dialog"
+	"github.com/mproffitt/delorian/pkg/components"
+	"github.com/mproffitt/delorian/pkg/theme"
+)
+
+// Width is the width of the editor overlay
+const Width = 64
+
+// Model is a small form for overriding the postBuild
+// substitutions of a kustomization
+type Model struct {
+	defaults map[string]string
+	form     *huh.Form
+	id       string
+	value    string
+}
+
+// New creates an editor for the kustomization with the given id.
+//
+// defaults are the substitutions defined in the repository and
+// overrides any previously entered values
+func New(id, name string, defaults, overrides map[string]string) *Model {
+	m := Model{
+		defaults: defaults,
+		id:       id,
+		value:    Format(merge(defaults, overrides)),
+	}
+	m.form = huh.NewForm(huh.NewGroup(
+		huh.NewText().
+			Title("Substitutions for " + name).
+			Description("One KEY=value per line. alt+enter adds a line,\n" +
+				"enter applies and esc cancels").
+			Lines(10).
+			Validate(func(s string) error {
+				_, err := Parse(s)
+				return err
+			}).
+			Value(&m.value),
+	)).WithWidth(Width - 4).WithShowHelp(false)
+	return &m
+}
+
+func (m *Model) Init() tea.Cmd {
+	return m.form.Init()
+}
+
+func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok && msg.String() == "esc" {
+		return m, dialog.DialogStatusCmd(dialog.DialogStatusMsg{Done: true})
+	}
+
+	form, cmd := m.form.Update(msg)
+	m.form = form.(*huh.Form)
+	switch m.form.State {
+	case huh.StateAborted:
+		return m, dialog.DialogStatusCmd(dialog.DialogStatusMsg{Done: true})
+	case huh.StateCompleted:
+		values, _ := Parse(m.value)
+		return m, tea.Batch(
+			components.SubstitutionsCmd(m.id, Overrides(m.defaults, values)),
+			dialog.DialogStatusCmd(dialog.DialogStatusMsg{Done: true}))
+	}
+	return m, cmd
+}
+
+func (m *Model) View() string {
+	return lipgloss.NewStyle().
+		Border(theme.RoundedBorder(), true).
+		BorderForeground(theme.Colours.Blue).
+		Padding(1).
+		Width(Width).
+		Render(m.form.View())
+}
+
+// Parse reads KEY=value lines into a map. Blank lines are ignored
+func Parse(input string) (map[string]string, error) {
+	values := make(map[string]string)
+	for i, line := range strings.Split(input, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		k, v, ok := strings.Cut(line, "=")
+		if !ok || strings.TrimSpace(k) == "" {
+			return nil, fmt.Errorf("line %d: expected KEY=value", i+1)
+		}
+		values[strings.TrimSpace(k)] = strings.TrimSpace(v)
+	}
+	return values, nil
+}
+
+// Format writes substitutions as sorted KEY=value lines
+func Format(values map[string]string) string {
+	lines := make([]string, 0, len(values))
+	for _, k := range slices.Sorted(maps.Keys(values)) {
+		lines = append(lines, k+"="+values[k])
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Overrides returns the values which are new or differ from
+// the defaults. Removing a default restores its original value
+func Overrides(defaults, values map[string]string) map[string]string {
+	overrides := make(map[string]string)
+	for k, v := range values {
+		if d, ok := defaults[k]; !ok || d != v {
+			overrides[k] = v
+		}
+	}
+	return overrides
+}
+
+func merge(defaults, overrides map[string]string) map[string]string {
+	merged := maps.Clone(defaults)
+	if merged == nil {
+		merged = make(map[string]string)
+	}
+	maps.Copy(merged, overrides)
+	return merged
+}
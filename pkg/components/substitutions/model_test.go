@@ -0,0 +1,49 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+package substitutions
+
+import (
+	"maps"
+	"testing"
+)
+
+func TestParseAndOverrides(t *testing.T) {
+	values, err := Parse("region=eu-west-1\n\n cluster = prod \nreplicas=3\n")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	defaults := map[string]string{"region": "eu-west-1", "cluster": "staging", "zone": "a"}
+	got := Overrides(defaults, values)
+	want := map[string]string{"cluster": "prod", "replicas": "3"}
+	if !maps.Equal(got, want) {
+		t.Errorf("Overrides() = %v, want %v", got, want)
+	}
+
+	if _, err := Parse("region"); err == nil {
+		t.Errorf("expected an error for a line without a value")
+	}
+}
+
+func TestFormat(t *testing.T) {
+	got := Format(map[string]string{"b": "2", "a": "1"})
+	if want := "a=1\nb=2"; got != want {
+		t.Errorf("Format() = %q, want %q", got, want)
+	}
+}
@@ -20,6 +20,8 @@
 package tabview
 
 import (
+	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/viewport"
@@ -28,6 +30,11 @@ import (
 	zone "github.com/lrstanley/bubblezone"
 	"github.com/mproffitt/delorian/pkg/components"
 	"github.com/mproffitt/delorian/pkg/components/diffview"
+	"github.com/mproffitt/delorian/pkg/components/graphview"
+	"github.com/mproffitt/delorian/pkg/components/healthview"
+	"github.com/mproffitt/delorian/pkg/components/imageview"
+	"github.com/mproffitt/delorian/pkg/components/intervalview"
+	"github.com/mproffitt/delorian/pkg/components/resourceview"
 	"github.com/mproffitt/delorian/pkg/components/splash"
 	"github.com/mproffitt/delorian/pkg/components/yamlview"
 	"github.com/mproffitt/delorian/pkg/theme"
@@ -36,6 +43,8 @@ import (
 type Model struct {
 	id         string
 	activeTab  int
+	compact    bool
+	disabled   map[components.TabType]bool
 	height     int
 	focus      bool
 	tabs       []components.TabType
@@ -49,44 +58,89 @@ type styles struct {
 	windowStyle      lipgloss.Style
 	activeTabStyle   lipgloss.Style
 	inactiveTabStyle lipgloss.Style
+	disabledTabStyle lipgloss.Style
 	tabGap           lipgloss.Style
 }
 
-func New() *Model {
-	id := zone.NewPrefix()
+// Tabs are the tabs shown, in the order they are drawn
+var Tabs = []components.TabType{
+	components.TabKustomize,
+	components.TabSource,
+	components.TabHelmRelease,
+	components.TabFluxBuild,
+	components.TabResources,
+	components.TabImages,
+	components.TabFluxDiff,
+	components.TabRefDiff,
+	components.TabHealth,
+	components.TabIntervals,
+	components.TabGraph,
+}
+
+// SetTabs replaces the tabs shown with the named tabs, in the given
+// order. Names are matched to the tab titles ignoring case. Leaving
+// names empty keeps the default tabs
+func SetTabs(names []string) error {
+	if len(names) == 0 {
+		return nil
+	}
+	tabs := make([]components.TabType, 0, len(names))
+	for _, name := range names {
+		i := slices.IndexFunc(Tabs, func(t components.TabType) bool {
+			return strings.EqualFold(string(t), strings.TrimSpace(name))
+		})
+		if i < 0 {
+			return fmt.Errorf("unknown tab %q", name)
+		}
+		if slices.Contains(tabs, Tabs[i]) {
+			return fmt.Errorf("tab %q is listed more than once", name)
+		}
+		tabs = append(tabs, Tabs[i])
+	}
+	Tabs = tabs
+	return nil
+}
+
+// New creates the tabs, branding the splash shown while each loads
+func New(branding splash.Branding) *Model {
+	id := components.NewID()
 	m := Model{
-		id: id,
-		tabs: []components.TabType{
-			components.TabKustomize,
-			components.TabSource,
-			components.TabFluxBuild,
-			components.TabFluxDiff,
-
-			/*components.TabGraph,*/
-		},
+		id:   id,
+		tabs: slices.Clone(Tabs),
 		tabContent: map[components.TabType]tea.Model{
-			components.TabKustomize: yamlview.New(0, 0, false),
-			components.TabSource:    yamlview.New(0, 0, false),
-			components.TabFluxBuild: yamlview.New(0, 0, true),
-			components.TabFluxDiff:  diffview.New(0, 0, true),
+			components.TabKustomize:   yamlview.New(0, 0, false).SetBranding(branding),
+			components.TabSource:      yamlview.New(0, 0, false).SetBranding(branding),
+			components.TabHelmRelease: yamlview.New(0, 0, false).SetBranding(branding),
+			components.TabFluxBuild:   yamlview.New(0, 0, true).SetBranding(branding),
+			components.TabResources:   resourceview.New(0, 0).SetBranding(branding),
+			components.TabImages:      imageview.New(0, 0).SetBranding(branding),
+			components.TabHealth:      healthview.New(0, 0),
+			components.TabIntervals:   intervalview.New(0, 0),
+			components.TabGraph:       graphview.New(0, 0),
+			components.TabFluxDiff:    diffview.New(0, 0, true).SetBranding(branding),
+			components.TabRefDiff:     diffview.New(0, 0, true).SetLocal().SetBranding(branding),
 		},
 		activeTab: 0,
+		disabled:  map[components.TabType]bool{},
 		styles: styles{
 			docStyle: lipgloss.NewStyle().Padding(0, 2, 0, 0),
 			windowStyle: lipgloss.NewStyle().
 				BorderForeground(theme.Colours.Blue).
 				Align(lipgloss.Left).
-				Border(lipgloss.RoundedBorder()).
+				Border(theme.RoundedBorder()).
 				UnsetBorderTop(),
-			inactiveTabStyle: lipgloss.NewStyle().Border(theme.TabBorder).
+			inactiveTabStyle: lipgloss.NewStyle().Border(theme.Border(theme.TabBorder)).
 				BorderForeground(theme.Colours.Blue).
 				Padding(0, 1),
 		},
 	}
 	m.styles.activeTabStyle = m.styles.inactiveTabStyle.
-		Border(theme.TabActiveBorder, true).
+		Border(theme.Border(theme.TabActiveBorder), true).
 		BorderForeground(theme.Colours.Blue)
-	m.styles.tabGap = m.styles.activeTabStyle.Border(theme.TabGapBorder, true)
+	m.styles.tabGap = m.styles.activeTabStyle.Border(theme.Border(theme.TabGapBorder), true)
+	m.styles.disabledTabStyle = m.styles.inactiveTabStyle.
+		Foreground(theme.Colours.Black).
+		Strikethrough(true)
 
 	return &m
 }
@@ -119,21 +173,59 @@ func (m *Model) Init() tea.Cmd {
 		cmd := tab.Init()
 		cmds = append(cmds, cmd)
 	}
+
+	// Views otherwise assume the first tab is the kustomization
+	if m.tabs[m.activeTab] != components.TabKustomize {
+		cmds = append(cmds, components.TabChangedCmd(m.tabs[m.activeTab]))
+	}
 	return tea.Batch(cmds...)
 }
 
 func (m *Model) SetSize(w, h int) tea.Model {
 	m.height = h - (2 * theme.Padding)
 	m.width = w - theme.Padding
+	height := m.height
+	if m.compact {
+		// The single line header is shorter than the tab row
+		height++
+	}
 	for t, v := range m.tabContent {
 		if _, ok := v.(components.Scalable); ok {
 			m.tabContent[t].(components.Scalable).
-				SetSize(m.width, m.height)
+				SetSize(m.width, height)
 		}
 	}
 	return m
 }
 
+// SetCompact replaces the row of tabs with a single line naming
+// the active tab. Call SetSize afterwards to resize the content
+func (m *Model) SetCompact(compact bool) *Model {
+	m.compact = compact
+	return m
+}
+
+// compactView draws the active tab without the row of tabs
+func (m *Model) compactView() string {
+	active := m.tabs[m.activeTab]
+	title := lipgloss.NewStyle().Bold(true).
+		Foreground(theme.Colours.Blue).
+		Render(string(active))
+	hint := lipgloss.NewStyle().Foreground(theme.Colours.BrightBlack).
+		Render(fmt.Sprintf(" %d/%d  ; previous  : next", m.activeTab+1, len(m.tabs)))
+
+	windowStyle := m.styles.windowStyle.BorderTop(true)
+	if !m.focus {
+		windowStyle = windowStyle.BorderForeground(theme.Colours.Black)
+	}
+	view := viewport.New(m.width, m.height+1)
+	view.SetContent(m.tabContent[active].View())
+	doc := lipgloss.JoinVertical(lipgloss.Left,
+		title+hint,
+		windowStyle.Render(view.View()))
+	return m.styles.docStyle.Render(doc)
+}
+
 func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 	switch msg := msg.(type) {
@@ -149,6 +241,9 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				break
 			}
 			for i, tab := range m.tabs {
+				if m.disabled[tab] {
+					continue
+				}
 				if zone.Get(m.id + string(tab)).InBounds(msg) {
 					m.activeTab = i
 					cmd = components.TabChangedCmd(m.tabs[m.activeTab])
@@ -161,22 +256,58 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.KeyMsg:
 		switch msg.String() {
 		case ":":
-			m.activeTab = min(m.activeTab+1, len(m.tabs)-1)
-			cmd = components.TabChangedCmd(m.tabs[m.activeTab])
+			cmd = m.selectTab(m.activeTab, 1)
 		case ";":
-			m.activeTab = max(m.activeTab-1, 0)
-			cmd = components.TabChangedCmd(m.tabs[m.activeTab])
+			cmd = m.selectTab(m.activeTab, -1)
 		default:
 			tab := m.tabs[m.activeTab]
 			m.tabContent[tab], cmd = m.tabContent[tab].Update(msg)
 		}
-	case splash.TickMsg:
-		cmds := make([]tea.Cmd, 0)
+	case components.ClusterStatusMsg:
+		// Diffing requires a live cluster so the tab is disabled
+		// for as long as the cluster cannot be reached
+		m.disabled[components.TabFluxDiff] = !msg.Online
 		for k, t := range m.tabContent {
-			m.tabContent[k], cmd = t.Update(msg)
-			cmds = append(cmds, cmd)
+			m.tabContent[k], _ = t.Update(msg)
+		}
+		if m.disabled[m.tabs[m.activeTab]] {
+			if cmd = m.selectTab(m.activeTab, -1); cmd == nil {
+				cmd = m.selectTab(m.activeTab, 1)
+			}
+		}
+	case components.HealthReportMsg:
+		// The report arrives once after scanning, irrespective of
+		// which tab is active
+		m.tabContent[components.TabHealth], cmd = m.tabContent[components.TabHealth].Update(msg)
+	case components.ShowFileMsg:
+		i := slices.Index(m.tabs, msg.Tab)
+		if i < 0 {
+			break
+		}
+		m.activeTab = i
+		m.tabContent[msg.Tab], cmd = m.tabContent[msg.Tab].Update(msg)
+	case components.ScanStartedMsg:
+		// The first scan runs behind whichever tab is active
+		for k, t := range m.tabContent {
+			m.tabContent[k], _ = t.Update(msg)
+		}
+	case components.DiffFiltersMsg:
+		// Both diff tabs hide the same filters, whichever is active
+		var fc, rc tea.Cmd
+		m.tabContent[components.TabFluxDiff], fc = m.tabContent[components.TabFluxDiff].Update(msg)
+		m.tabContent[components.TabRefDiff], rc = m.tabContent[components.TabRefDiff].Update(msg)
+		cmd = tea.Batch(fc, rc)
+	case components.CadenceReportMsg:
+		m.tabContent[components.TabIntervals], cmd = m.tabContent[components.TabIntervals].Update(msg)
+	case splash.TickMsg:
+		// Only the active tab is on screen so ticks are not
+		// delivered to any other tab. Their animation loops end
+		// here and are restarted by TabChangedMsg when the tab
+		// is next selected
+		tab := m.tabs[m.activeTab]
+		if l, ok := m.tabContent[tab].(components.Loader); ok && l.LoadState() == components.StateLoading {
+			m.tabContent[tab], cmd = m.tabContent[tab].Update(msg)
 		}
-		cmd = tea.Batch(cmds...)
 	default:
 		tab := m.tabs[m.activeTab]
 		m.tabContent[tab], cmd = m.tabContent[tab].Update(msg)
@@ -184,7 +315,23 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
+// selectTab moves the active tab in the given direction, skipping
+// over any tabs that are currently disabled. If no enabled tab is
+// found in that direction, the active tab is left unchanged
+func (m *Model) selectTab(from, direction int) tea.Cmd {
+	for i := from + direction; i >= 0 && i < len(m.tabs); i += direction {
+		if !m.disabled[m.tabs[i]] {
+			m.activeTab = i
+			return components.TabChangedCmd(m.tabs[m.activeTab])
+		}
+	}
+	return nil
+}
+
 func (m *Model) View() string {
+	if m.compact {
+		return m.compactView()
+	}
 	var renderedTabs []string
 
 	for i, t := range m.tabs {
@@ -192,9 +339,12 @@ func (m *Model) View() string {
 		tabTitle = zone.Mark(m.id+tabTitle, tabTitle)
 		var style lipgloss.Style
 		isFirst, isActive := i == 0, i == m.activeTab
-		if isActive {
+		switch {
+		case isActive:
 			style = m.styles.activeTabStyle
-		} else {
+		case m.disabled[t]:
+			style = m.styles.disabledTabStyle
+		default:
 			style = m.styles.inactiveTabStyle
 		}
 		if !m.focus {
@@ -202,9 +352,9 @@ func (m *Model) View() string {
 		}
 		border, _, _, _, _ := style.GetBorder()
 		if isFirst {
-			border.BottomLeft = "│"
+			border.BottomLeft = theme.Glyph("│")
 			if !isActive {
-				border.BottomLeft = "├"
+				border.BottomLeft = theme.Glyph("├")
 			}
 		}
 		style = style.Border(border)
@@ -0,0 +1,148 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package tabview
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mproffitt/delorian/pkg/components"
+	"github.com/mproffitt/delorian/pkg/components/splash"
+	"github.com/mproffitt/delorian/pkg/testutil"
+)
+
+func TestMain(m *testing.M) {
+	testutil.Setup()
+	os.Exit(m.Run())
+}
+
+// ticks executes the command and returns any splash ticks it produced
+func ticks(cmd tea.Cmd) []tea.Msg {
+	if cmd == nil {
+		return nil
+	}
+	msgs := make([]tea.Msg, 0)
+	switch msg := cmd().(type) {
+	case tea.BatchMsg:
+		for _, c := range msg {
+			msgs = append(msgs, ticks(c)...)
+		}
+	case splash.TickMsg:
+		msgs = append(msgs, msg)
+	}
+	return msgs
+}
+
+// Only the active tab may keep an animation loop alive and once its
+// content has loaded no further ticks should be scheduled at all
+func TestIdleSchedulesNoTicks(t *testing.T) {
+	m := New(splash.Branding{})
+	m.SetSize(80, 24)
+
+	var running []tea.Msg
+	for _, tick := range ticks(m.Init()) {
+		_, cmd := m.Update(tick)
+		running = append(running, ticks(cmd)...)
+	}
+	if len(running) != 1 {
+		t.Fatalf("expected only the active tab to keep ticking, got %d loops", len(running))
+	}
+
+	m.Update(components.FluxExecMsg{Output: "kind: Kustomization"})
+	for _, tick := range running {
+		if _, cmd := m.Update(tick); cmd != nil {
+			t.Error("expected no tick to be scheduled once content is loaded")
+		}
+	}
+}
+
+func TestSetTabs(t *testing.T) {
+	defaults := Tabs
+	defer func() { Tabs = defaults }()
+
+	if err := SetTabs([]string{"flux diff", "Kustomization", "unknown"}); err == nil {
+		t.Error("expected an unknown tab to be rejected")
+	}
+	if err := SetTabs([]string{"Health", "health"}); err == nil {
+		t.Error("expected a duplicate tab to be rejected")
+	}
+
+	if err := SetTabs([]string{"flux diff", "Kustomization"}); err != nil {
+		t.Fatal(err)
+	}
+	m := New(splash.Branding{})
+	if len(m.tabs) != 2 || m.tabs[0] != components.TabFluxDiff || m.tabs[1] != components.TabKustomize {
+		t.Errorf("expected the configured order, got %v", m.tabs)
+	}
+	if _, ok := m.tabContent[components.TabHealth]; !ok {
+		t.Error("expected hidden tabs to keep their content")
+	}
+}
+
+func TestCompactKeepsHeight(t *testing.T) {
+	m := New(splash.Branding{})
+	m.SetSize(80, 24)
+	normal := lipgloss.Height(m.View())
+
+	m.SetCompact(true).SetSize(80, 24)
+	view := m.View()
+	if h := lipgloss.Height(view); h != normal {
+		t.Errorf("expected the compact view to be %d lines, got %d", normal, h)
+	}
+	if !strings.Contains(view, string(components.TabKustomize)) {
+		t.Error("expected the active tab to be named")
+	}
+}
+
+type file struct{ content string }
+
+func (f file) GetName() string    { return "deployment.yaml" }
+func (f file) GetPath() string    { return "/repo/deployment.yaml" }
+func (f file) GetContent() string { return f.content }
+
+func TestShowFile(t *testing.T) {
+	m := New(splash.Branding{})
+	m.SetSize(80, 24)
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(":")})
+	if m.tabs[m.activeTab] == components.TabKustomize {
+		t.Fatal("expected to have moved off the kustomization tab")
+	}
+
+	lines := make([]string, 0, 100)
+	for i := range 100 {
+		lines = append(lines, "line"+strings.Repeat("x", i%3)+": "+string(rune('a'+i%26)))
+	}
+	lines[60] = "kind: Deployment"
+	m.Update(components.ShowFileMsg{
+		Tab:  components.TabKustomize,
+		File: file{content: strings.Join(lines, "\n")},
+		Line: 60,
+	})
+	if m.tabs[m.activeTab] != components.TabKustomize {
+		t.Fatalf("expected the kustomization tab, got %s", m.tabs[m.activeTab])
+	}
+	view := m.View()
+	if !strings.Contains(view, "kind: Deployment") || strings.Contains(view, "   1 │") {
+		t.Errorf("expected the view scrolled to the definition, got\n%s", view)
+	}
+}
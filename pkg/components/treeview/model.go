@@ -20,13 +20,46 @@
 package treeview
 
 import (
+	"slices"
+	"strings"
+
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/charmbracelet/lipgloss/tree"
+	"github.com/charmbracelet/x/ansi"
 	"github.com/mproffitt/delorian/pkg/theme"
 )
 
+const (
+	// Marker prefixes the branch that was last located in the tree
+	Marker = "◉ "
+
+	// Collapsed prefixes a branch whose children are hidden
+	Collapsed = "▸ "
+)
+
+// SelectMsg is sent when enter is pressed on a branch of the
+// tree. Path holds the names leading from the top of the tree
+// to the branch
+type SelectMsg struct {
+	Path []string
+}
+
+// SelectCmd sends the path of the selected branch
+func SelectCmd(path []string) tea.Cmd {
+	return func() tea.Msg {
+		return SelectMsg{Path: path}
+	}
+}
+
+// Highlight renders a located branch name so it stands out from
+// the rest of the tree and can be found when scrolling
+func Highlight(name string) string {
+	return lipgloss.NewStyle().Foreground(theme.Colours.BrightYellow).
+		Bold(true).Render(theme.Glyph(Marker) + name)
+}
+
 type Tree interface {
 	Tree() *tree.Tree
 	Matches(string) bool
@@ -34,12 +67,29 @@ type Tree interface {
 }
 
 type Model struct {
-	branches []Tree
-	height   int
-	styles   styles
-	title    string
-	viewport viewport.Model
-	width    int
+	branches  []Tree
+	collapsed map[string]bool
+	cursor    int
+	focus     bool
+	follow    bool
+	height    int
+	locate    bool
+	nodes     []node
+	styles    styles
+	title     string
+	viewport  viewport.Model
+	width     int
+}
+
+// node is a branch visible in the tree, in the order it is drawn
+type node struct {
+	path []string
+	tree tree.Node
+}
+
+// key identifies the branch at path across renders
+func key(path []string) string {
+	return strings.Join(path, "/")
 }
 
 type styles struct {
@@ -51,8 +101,9 @@ type styles struct {
 
 func New(title string, t []Tree, w, h int) *Model {
 	m := Model{
-		branches: t,
-		height:   h,
+		branches:  t,
+		collapsed: make(map[string]bool),
+		height:    h,
 		styles: styles{
 			enumerator: lipgloss.NewStyle().Foreground(theme.Colours.Black),
 			root:       lipgloss.NewStyle().Foreground(theme.Colours.BrightBlack),
@@ -64,15 +115,22 @@ func New(title string, t []Tree, w, h int) *Model {
 		width:    w,
 	}
 	return &m
+}
+
+// Focus shows the cursor and lets the tree be navigated
+func (m *Model) Focus() {
+	m.focus = true
+	m.follow = true
+}
+
+// Blur hides the cursor
+func (m *Model) Blur() {
+	m.focus = false
+}
 
-	/*
-			 Need: - tree.EnumeratorStyleFunc for walking the tree
-			         and highlighting selected items
-			       - To know  How to index the entire tree so the
-			         correct item is highlighted
-		           - Left / Right = collapse / expand branch
-		           - Enter logs in to current branch
-	*/
+// Focused reports if the tree is being navigated
+func (m *Model) Focused() bool {
+	return m.focus
 }
 
 func (m *Model) Init() tea.Cmd {
@@ -87,30 +145,134 @@ func (m *Model) SetSize(w, h int) tea.Model {
 	return m
 }
 
+// Update moves the cursor through the visible branches. Left and
+// right collapse and expand the branch under the cursor and enter
+// selects it
 func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		m.build()
+		if len(m.nodes) == 0 {
+			break
+		}
+		current := m.nodes[m.cursor]
 		switch msg.String() {
-		case "up":
-		case "down":
-		case "left":
-		case "right":
+		case "up", "k":
+			m.cursor = max(0, m.cursor-1)
+		case "down", "j":
+			m.cursor = min(len(m.nodes)-1, m.cursor+1)
+		case "left", "h":
+			switch {
+			case current.tree.Children().Length() > 0 && !m.collapsed[key(current.path)]:
+				m.collapsed[key(current.path)] = true
+			case len(current.path) > 1:
+				// Already collapsed so move up to the parent
+				m.cursor = m.find(current.path[:len(current.path)-1])
+			}
+		case "right", "l":
+			delete(m.collapsed, key(current.path))
 		case "enter":
+			cmd = SelectCmd(slices.Clone(current.path))
+		}
+		m.follow = true
+	}
+	return m, cmd
+}
+
+// find returns the index of the visible branch at path
+func (m *Model) find(path []string) int {
+	for i, n := range m.nodes {
+		if key(n.path) == key(path) {
+			return i
 		}
 	}
-	return m, nil
+	return m.cursor
 }
 
 func (m *Model) View() string {
 	m.viewport.Style = lipgloss.NewStyle().
-		Border(lipgloss.RoundedBorder(), true, false, true).
+		Border(theme.RoundedBorder(), true, false, true).
 		BorderForeground(theme.Colours.Black)
 
 	tree := m.renderTree()
 	m.viewport.SetContent(tree)
+	switch {
+	case m.locate:
+		m.locate = false
+		m.scrollTo(tree)
+	case m.follow && m.focus:
+		m.follow = false
+		m.scrollToCursor()
+	}
 	return m.viewport.View()
 }
 
+// Select marks the branch on each tree and scrolls the
+// viewport to it on the next render
+func (m *Model) Select(branch []string) {
+	for i := range m.branches {
+		m.branches[i].Select(branch)
+	}
+	m.locate = len(branch) > 0
+}
+
+// scrollTo centres the viewport on the line holding the marker
+func (m *Model) scrollTo(content string) {
+	for i, line := range strings.Split(content, "\n") {
+		if strings.Contains(line, theme.Glyph(Marker)) {
+			m.viewport.SetYOffset(max(0, i-m.viewport.Height/2))
+			return
+		}
+	}
+}
+
+// scrollToCursor keeps the line under the cursor in view. The
+// title takes the first line of the tree
+func (m *Model) scrollToCursor() {
+	line := m.cursor + 1
+	height := m.viewport.Height - m.viewport.Style.GetVerticalFrameSize()
+	switch {
+	case line < m.viewport.YOffset:
+		m.viewport.SetYOffset(line)
+	case line >= m.viewport.YOffset+height:
+		m.viewport.SetYOffset(line - height + 1)
+	}
+}
+
+// build creates the tree from the branches and indexes the
+// branches that are visible. Children of collapsed branches are
+// hidden
+func (m *Model) build() *tree.Tree {
+	t := tree.New().Root(m.title)
+	m.nodes = m.nodes[:0]
+	var index func(n tree.Node, path []string)
+	index = func(n tree.Node, path []string) {
+		name := strings.TrimPrefix(ansi.Strip(n.Value()), theme.Glyph(Marker))
+		path = append(slices.Clone(path), name)
+		m.nodes = append(m.nodes, node{path: path, tree: n})
+
+		children := n.Children()
+		if children.Length() > 0 && m.collapsed[key(path)] {
+			for i := range children.Length() {
+				children.At(i).SetHidden(true)
+			}
+			n.SetValue(theme.Glyph(Collapsed) + n.Value())
+			return
+		}
+		for i := range children.Length() {
+			index(children.At(i), path)
+		}
+	}
+	for i := range m.branches {
+		branch := m.branches[i].Tree()
+		index(branch, nil)
+		t = t.Child(branch)
+	}
+	m.cursor = max(0, min(m.cursor, len(m.nodes)-1))
+	return t
+}
+
 func (m *Model) renderTree() string {
 	if len(m.branches) == 0 {
 		text := lipgloss.NewStyle().
@@ -118,15 +280,24 @@ func (m *Model) renderTree() string {
 		text = lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, text)
 		return text
 	}
-	tree := tree.New().Root(m.title).
-		Enumerator(tree.RoundedEnumerator).
-		EnumeratorStyle(m.styles.enumerator).
-		RootStyle(m.styles.root).
-		ItemStyle(m.styles.item)
-
-	for i := range m.branches {
-		tree = tree.Child(m.branches[i].Tree())
+	t := m.build()
+	selected := func(children tree.Children, i int) bool {
+		return m.focus && len(m.nodes) > 0 && children.At(i) == m.nodes[m.cursor].tree
 	}
+	t = t.Enumerator(tree.RoundedEnumerator).
+		EnumeratorStyleFunc(func(children tree.Children, i int) lipgloss.Style {
+			if selected(children, i) {
+				return m.styles.selected
+			}
+			return m.styles.enumerator
+		}).
+		RootStyle(m.styles.root).
+		ItemStyleFunc(func(children tree.Children, i int) lipgloss.Style {
+			if selected(children, i) {
+				return m.styles.selected
+			}
+			return m.styles.item
+		})
 
-	return tree.String()
+	return t.String()
 }
@@ -0,0 +1,99 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package treeview
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss/tree"
+)
+
+type branch struct {
+	name     string
+	children []string
+}
+
+func (b branch) Tree() *tree.Tree {
+	t := tree.New().Root(b.name)
+	for _, c := range b.children {
+		t = t.Child(c)
+	}
+	return t
+}
+
+func (b branch) Matches(string) bool { return false }
+func (b branch) Select([]string)     {}
+
+func press(m *Model, keys ...string) tea.Cmd {
+	var cmd tea.Cmd
+	for _, k := range keys {
+		msg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)}
+		switch k {
+		case "up":
+			msg = tea.KeyMsg{Type: tea.KeyUp}
+		case "down":
+			msg = tea.KeyMsg{Type: tea.KeyDown}
+		case "left":
+			msg = tea.KeyMsg{Type: tea.KeyLeft}
+		case "right":
+			msg = tea.KeyMsg{Type: tea.KeyRight}
+		case "enter":
+			msg = tea.KeyMsg{Type: tea.KeyEnter}
+		}
+		_, cmd = m.Update(msg)
+	}
+	return cmd
+}
+
+func TestNavigate(t *testing.T) {
+	m := New("clusters", []Tree{
+		branch{name: "hub", children: []string{"prod", "staging"}},
+		branch{name: "dev"},
+	}, 40, 10)
+	m.Focus()
+
+	cmd := press(m, "down", "down", "enter")
+	if msg, ok := cmd().(SelectMsg); !ok || !slices.Equal(msg.Path, []string{"hub", "staging"}) {
+		t.Fatalf("expected hub/staging to be selected, got %v", cmd())
+	}
+
+	// left on a leaf moves to the parent, then collapses it
+	press(m, "left", "left")
+	if !m.collapsed["hub"] {
+		t.Fatal("expected hub to be collapsed")
+	}
+	view := m.renderTree()
+	if strings.Contains(view, "prod") || !strings.Contains(view, Collapsed+"hub") {
+		t.Errorf("expected the children of hub to be hidden, got\n%s", view)
+	}
+
+	cmd = press(m, "down", "enter")
+	if msg := cmd().(SelectMsg); !slices.Equal(msg.Path, []string{"dev"}) {
+		t.Errorf("expected dev to follow the collapsed branch, got %v", msg.Path)
+	}
+
+	press(m, "up", "right")
+	if view := m.renderTree(); !strings.Contains(view, "prod") {
+		t.Errorf("expected hub to be expanded again, got\n%s", view)
+	}
+}
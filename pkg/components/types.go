@@ -20,8 +20,8 @@
 package components
 
 import (
+	"context"
 	"fmt"
-	"os/exec"
 	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -50,6 +50,13 @@ type File interface {
 	GetContent() string
 }
 
+// Substituted is implemented by files whose content flux applies
+// postBuild substitutions to. Substitutions returns the values
+// keyed by variable name
+type Substituted interface {
+	Substitutions() map[string]string
+}
+
 // FileMsg is returned by a call from FileCmd
 // and contains the underlying file, whether that
 // file is Ok and the content of that file discovered
@@ -106,6 +113,15 @@ type Focusable interface {
 	Blur()
 }
 
+// Loader is the interface implemented by components that
+// display a loading state while waiting for content.
+//
+// Parents use this to only deliver animation ticks to
+// components that are actually loading
+type Loader interface {
+	LoadState() LoadState
+}
+
 // Scalable is the interface that defines if a component
 // can be resized directly.
 type Scalable interface {
@@ -116,6 +132,21 @@ type Scalable interface {
 // execution of a FluxExecCmd
 type FluxExecMsg struct {
 	Output string
+
+	// Scope names the single resource the output is limited to,
+	// if any
+	Scope string
+
+	// Sections hold the output for each kustomization when
+	// several are run together. Output is empty when set
+	Sections []FluxSection
+}
+
+// FluxSection is the output of flux for one kustomization
+type FluxSection struct {
+	Title  string
+	Output string
+	Error  error
 }
 
 // FluxExecCmd executes flux and captures the output
@@ -125,43 +156,48 @@ type FluxExecMsg struct {
 // function should handle a `FluxExecMsg`
 func FluxExecCmd(args []string) tea.Cmd {
 	return func() tea.Msg {
-		// TODO: This check should occur at program start and be
-		// handled in the same way as checking if this is a git repo.
-		// It shouldn't wait until the program is already running to
-		// know if flux is installed.
-		flux, err := exec.LookPath("flux")
+		out, err := FluxExec(context.Background(), args)
 		if err != nil {
-			log.Error("unable to find flux in path. is this installed?")
-			err = &bmx.BmxExecError{
-				Command: fmt.Sprintf("%s %s", flux, strings.Join(args, " ")),
-				Stdout:  "",
-				Stderr:  err.Error(),
-			}
 			return ModelErrorMsg{Error: err}
 		}
+		return FluxExecMsg{Output: out}
+	}
+}
 
-		out, _, err := bmx.Exec(flux, args)
-		if err != nil {
-			switch err := err.(type) {
-			case *bmx.BmxExecError:
-				// I almost certainly want the option to identify other error
-				// strings at this point as some errors contain large blocks of
-				// text which may be better displayed in a different manner.
-				msg := "identified at least one change, exiting with non-zero exit code"
-				if !strings.HasSuffix(err.Stderr, msg) {
-					log.Error("flux exec", "error", err)
-					return ModelErrorMsg{Error: err}
-				}
-				out = err.Stdout
-			default:
+// FluxExec executes flux with the given arguments and returns
+// its output.
+//
+// Errors from flux are translated into friendly messages by
+// RecogniseFluxError (see errors.go)
+func FluxExec(ctx context.Context, args []string) (string, error) {
+	args = withContext(args)
+	log.Debug("flux exec", "command", "flux "+strings.Join(args, " "))
+	out, _, err := Run(ctx, "flux", args)
+	if notFound(err) {
+		log.Error("unable to find flux in path. is this installed?")
+		return "", &bmx.BmxExecError{
+			Command: "flux " + strings.Join(args, " "),
+			Stdout:  "",
+			Stderr:  err.Error(),
+		}
+	}
+	if err != nil {
+		switch err := err.(type) {
+		case *bmx.BmxExecError:
+			msg := "identified at least one change, exiting with non-zero exit code"
+			if !strings.HasSuffix(err.Stderr, msg) {
 				log.Error("flux exec", "error", err)
-				return ModelErrorMsg{Error: err}
+				return "", RecogniseFluxError(err)
 			}
+			out = err.Stdout
+		default:
+			log.Error("flux exec", "error", err)
+			return "", err
 		}
-
-		log.Debug(args[0], "output", out)
-		return FluxExecMsg{Output: out}
 	}
+
+	log.Debug(args[0], "output", out)
+	return out, nil
 }
 
 // ModelErrorMsg is returned when the UI should enter an error state
@@ -200,11 +236,17 @@ func ModelFatalCmd(err error) tea.Cmd {
 type TabType string
 
 const (
-	TabKustomize TabType = "Kustomization"
-	TabSource    TabType = "Source"
-	TabFluxBuild TabType = "Flux Build"
-	TabFluxDiff  TabType = "Flux Diff"
-	TabGraph     TabType = "Graph"
+	TabKustomize   TabType = "Kustomization"
+	TabSource      TabType = "Source"
+	TabHelmRelease TabType = "Helm Releases"
+	TabFluxBuild   TabType = "Flux Build"
+	TabResources   TabType = "Resources"
+	TabImages      TabType = "Images"
+	TabHealth      TabType = "Health"
+	TabIntervals   TabType = "Intervals"
+	TabFluxDiff    TabType = "Flux Diff"
+	TabRefDiff     TabType = "Ref Diff"
+	TabGraph       TabType = "Graph"
 )
 
 // TabChangedMsg is returned when the tabs change on the
@@ -247,3 +289,73 @@ func (k *KustomizationError) Error() {
 	builder.WriteString(fmt.Sprintf("filepath: %s", k.Filepath))
 	builder.WriteString(fmt.Sprintf("error: %s", k.error.Error()))
 }
+
+// ResourceDiffMsg asks for the diff of the selected kustomization
+// to be limited to a single resource
+type ResourceDiffMsg struct {
+	Kind      string
+	Namespace string
+	Name      string
+}
+
+// ResourceDiffCmd returns a ResourceDiffMsg
+func ResourceDiffCmd(kind, namespace, name string) tea.Cmd {
+	return func() tea.Msg {
+		return ResourceDiffMsg{Kind: kind, Namespace: namespace, Name: name}
+	}
+}
+
+// RecursiveDiffMsg asks for the selected kustomization to be
+// diffed along with every kustomization that depends on it
+type RecursiveDiffMsg struct{}
+
+// RecursiveDiffCmd returns a RecursiveDiffMsg
+func RecursiveDiffCmd() tea.Cmd {
+	return func() tea.Msg {
+		return RecursiveDiffMsg{}
+	}
+}
+
+// DefinitionMsg asks for the source manifest defining a resource
+// in the build of the selected kustomization
+type DefinitionMsg struct {
+	Kind      string
+	Namespace string
+	Name      string
+}
+
+// DefinitionCmd returns a DefinitionMsg
+func DefinitionCmd(kind, namespace, name string) tea.Cmd {
+	return func() tea.Msg {
+		return DefinitionMsg{Kind: kind, Namespace: namespace, Name: name}
+	}
+}
+
+// ShowFileMsg switches to Tab and shows File in it, scrolled to
+// Line. The sidebar has already moved to the tab so no
+// TabChangedMsg follows
+type ShowFileMsg struct {
+	Tab  TabType
+	File File
+	Line int
+}
+
+// ShowFileCmd returns a ShowFileMsg
+func ShowFileCmd(tab TabType, file File, line int) tea.Cmd {
+	return func() tea.Msg {
+		return ShowFileMsg{Tab: tab, File: file, Line: line}
+	}
+}
+
+// DiffFiltersMsg replaces the filters the diff views hide until
+// the user changes the filter, such as when a profile is switched
+type DiffFiltersMsg struct {
+	Filters []string
+}
+
+// DiffFiltersCmd returns a DiffFiltersMsg
+func DiffFiltersCmd(filters []string) tea.Cmd {
+	return func() tea.Msg {
+		return DiffFiltersMsg{Filters: filters}
+	}
+}
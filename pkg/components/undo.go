@@ -0,0 +1,93 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package components
+
+import tea "github.com/charmbracelet/bubbletea"
+
+// MaxUndo is the number of actions kept on the undo stack. The
+// oldest action is dropped once the stack is full
+const MaxUndo = 20
+
+// Action is a reversible change to the view state such as a
+// filter or a toggle. Do applies the change and Undo restores
+// the state from before it was applied
+type Action struct {
+	Name string
+	Do   func() tea.Cmd
+	Undo func() tea.Cmd
+}
+
+// Apply runs the action and records it on the undo stack
+func (a Action) Apply() tea.Cmd {
+	var cmd tea.Cmd
+	if a.Do != nil {
+		cmd = a.Do()
+	}
+	return tea.Batch(cmd, UndoPushCmd(a))
+}
+
+// UndoPushMsg records an action which has been applied so it can
+// be reverted later
+type UndoPushMsg struct {
+	Action Action
+}
+
+// UndoPushCmd returns an UndoPushMsg for the action. Use this
+// directly when the change has already been made
+func UndoPushCmd(a Action) tea.Cmd {
+	return func() tea.Msg {
+		return UndoPushMsg{Action: a}
+	}
+}
+
+// UndoStack holds the most recent actions, newest last
+type UndoStack struct {
+	actions []Action
+}
+
+// Push records an action, dropping the oldest when full
+func (u *UndoStack) Push(a Action) {
+	if len(u.actions) == MaxUndo {
+		u.actions = u.actions[1:]
+	}
+	u.actions = append(u.actions, a)
+}
+
+// Pop removes and returns the newest action. It returns false
+// when there is nothing to undo
+func (u *UndoStack) Pop() (Action, bool) {
+	if len(u.actions) == 0 {
+		return Action{}, false
+	}
+	a := u.actions[len(u.actions)-1]
+	u.actions = u.actions[:len(u.actions)-1]
+	return a, true
+}
+
+// Len returns the number of actions on the stack
+func (u *UndoStack) Len() int {
+	return len(u.actions)
+}
+
+// Clear empties the stack. Actions refer to the models that
+// created them so the stack is cleared when those are replaced
+func (u *UndoStack) Clear() {
+	u.actions = nil
+}
@@ -0,0 +1,75 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package components
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestUndoStack(t *testing.T) {
+	var stack UndoStack
+	value := 0
+	for i := range MaxUndo + 5 {
+		a := Action{
+			Name: "set",
+			Undo: func() tea.Cmd {
+				value = i
+				return nil
+			},
+		}
+		stack.Push(a)
+	}
+	if stack.Len() != MaxUndo {
+		t.Fatalf("expected the stack to be capped at %d, got %d", MaxUndo, stack.Len())
+	}
+
+	a, ok := stack.Pop()
+	if !ok {
+		t.Fatal("expected an action to undo")
+	}
+	a.Undo()
+	if value != MaxUndo+4 {
+		t.Errorf("expected the newest action first, got %d", value)
+	}
+
+	stack.Clear()
+	if _, ok := stack.Pop(); ok {
+		t.Error("expected an empty stack after clear")
+	}
+}
+
+func TestActionApply(t *testing.T) {
+	applied := false
+	cmd := Action{
+		Name: "toggle",
+		Do: func() tea.Cmd {
+			applied = true
+			return nil
+		},
+	}.Apply()
+	if !applied {
+		t.Error("expected Apply to run the action")
+	}
+	if msg, ok := cmd().(UndoPushMsg); !ok || msg.Action.Name != "toggle" {
+		t.Errorf("expected the action to be pushed, got %#v", msg)
+	}
+}
@@ -0,0 +1,71 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package components
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// WatchToggleMsg is sent to start or pause the watch loop
+type WatchToggleMsg struct{}
+
+// WatchTickMsg is delivered on each interval of the watch loop
+//
+// Ticks carry the tag of the loop that scheduled them so ticks
+// from a paused or restarted loop can be discarded
+type WatchTickMsg struct {
+	Time time.Time
+	Tag  int
+}
+
+// WatchTickCmd schedules the next tick of the watch loop
+func WatchTickCmd(interval time.Duration, tag int) tea.Cmd {
+	return tea.Tick(interval, func(t time.Time) tea.Msg {
+		return WatchTickMsg{Time: t, Tag: tag}
+	})
+}
+
+// WatchRefreshMsg is sent ahead of a diff triggered by the
+// watch loop. Views receiving the subsequent output should
+// compare it to the previous result and highlight anything new
+type WatchRefreshMsg struct{}
+
+// WatchRefreshCmd returns a WatchRefreshMsg
+func WatchRefreshCmd() tea.Cmd {
+	return func() tea.Msg {
+		return WatchRefreshMsg{}
+	}
+}
+
+// DriftDetectedMsg is raised when a watch refresh finds drift
+// that was not present in the previous diff
+type DriftDetectedMsg struct {
+	Titles []string
+}
+
+// DriftDetectedCmd returns a DriftDetectedMsg for the given
+// drift entry titles
+func DriftDetectedCmd(titles []string) tea.Cmd {
+	return func() tea.Msg {
+		return DriftDetectedMsg{Titles: titles}
+	}
+}
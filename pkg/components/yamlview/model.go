@@ -26,16 +26,31 @@ import (
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 	"github.com/goccy/go-yaml/lexer"
 	"github.com/goccy/go-yaml/token"
+	"github.com/mproffitt/bmx/pkg/components/overlay"
+	"github.com/mproffitt/bmx/pkg/components/toast"
 	"github.com/mproffitt/bmx/pkg/exec"
 	"github.com/mproffitt/delorian/pkg/components"
+	"github.com/mproffitt/delorian/pkg/components/picker"
 	"github.com/mproffitt/delorian/pkg/components/queryinput"
 	"github.com/mproffitt/delorian/pkg/components/splash"
 	"github.com/mproffitt/delorian/pkg/theme"
 	wrap "github.com/muesli/reflow/wrap"
 )
 
+// MaxContentWidth is the widest the content is drawn, up to the
+// width of the pane. Longer lines are scrolled into view with left
+// and right. Zero draws the content across the whole pane
+var MaxContentWidth int
+
+// TabWidth is the number of columns a tab is expanded to
+var TabWidth = 4
+
+// horizontalStep is how many columns left and right scroll by
+const horizontalStep = 4
+
 const (
 	NoFocus components.FocusType = iota
 	QueryFocus
@@ -45,17 +60,24 @@ const (
 type Model struct {
 	border           bool
 	current          components.File
-	error            error
+	load             components.Load
 	focus            components.FocusType
 	filename         string
 	height           int
+	hovered          string
+	id               string
 	input            string
+	offsets          map[string]position
 	ok               bool
 	output           string
 	query            tea.Model
+	save             save
+	search           search
 	showQuery        bool
 	splash           *splash.Model
 	style            lipgloss.Style
+	substitutions    map[string]string
+	variables        []string
 	viewport         viewport.Model
 	width            int
 	LineNumber       bool
@@ -63,30 +85,44 @@ type Model struct {
 }
 
 func (m *Model) defaultLineNumberFormat(num int) string {
-	number := fmt.Sprintf("%4d │ ", num)
+	number := fmt.Sprintf(theme.Glyph("%4d │ "), num)
 	if m.focus == ViewportFocus {
 		return lipgloss.NewStyle().Foreground(theme.Colours.BrightBlack).Render(number)
 	}
 	return lipgloss.NewStyle().Foreground(theme.Colours.Black).Render(number)
 }
 
+// loadingMessage is shown on the splash while its content loads
+const loadingMessage = "loading kustomizations..."
+
 func New(w, h int, query bool) *Model {
 	m := Model{
 		border: false,
 		style: lipgloss.NewStyle().
 			BorderForeground(theme.Colours.Blue),
 		focus:      NoFocus,
-		splash:     splash.New("loading kustomizations..."),
+		id:         components.NewID(),
+		splash:     splash.New(loadingMessage, splash.Branding{}),
 		showQuery:  query,
 		input:      "",
+		save:       newSave(),
+		search:     newSearch(),
 		viewport:   viewport.New(w, h),
 		LineNumber: true,
 	}
+	m.viewport.SetHorizontalStep(horizontalStep)
 	m.query = queryinput.New(&m.input, w)
+	m.load.Start()
 
 	return &m
 }
 
+// SetBranding brands the splash shown while its content loads
+func (m *Model) SetBranding(branding splash.Branding) *Model {
+	m.splash = splash.New(loadingMessage, branding)
+	return m
+}
+
 func (m *Model) Init() tea.Cmd {
 	return m.splash.Init()
 }
@@ -126,7 +162,9 @@ func (m *Model) PreviousFocus() components.FocusType {
 }
 
 func (m *Model) formatFilename() int {
-	if !m.ok {
+	// Rendered content has no path to display
+	if !m.ok || m.current.GetPath() == "" {
+		m.filename = ""
 		return 0
 	}
 
@@ -156,7 +194,10 @@ func (m *Model) SetSize(w, h int) tea.Model {
 	subtract := (2 * theme.Padding) + 1
 	m.query.(components.Scalable).SetSize(w-subtract, 0)
 	m.viewport.Height = h - l
-	m.viewport.Width = w // + 1) - subtract
+	if m.footer() != "" {
+		m.viewport.Height--
+	}
+	m.viewport.Width = m.contentWidth()
 	return m
 }
 
@@ -164,37 +205,104 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 	switch msg := msg.(type) {
 	case components.TabChangedMsg:
-		m.splash.SetVisible(true)
-		cmd = splash.TickCmd()
+		// The splash replaces the content while loading so the
+		// position is kept before it is shown
+		if m.load.State() == components.StateLoaded {
+			m.saveScroll()
+		}
+		m.load.Start()
+		cmd = m.splash.Follow(&m.load)
+	case components.LoadSkippedMsg:
+		m.load.Skip()
+		cmd = m.splash.Follow(&m.load)
+	case splash.TickMsg:
+		m.splash, cmd = m.splash.Update(msg)
+	case components.ScanStartedMsg:
+		// Only seen while the first scan runs, later scans keep
+		// the content of the previous one on screen
+		message := "scanning for kustomizations..."
+		if msg.FromCache {
+			message = "loading kustomizations from cache..."
+		}
+		m.splash.SetMessage(message)
 	case queryinput.YqErrorMsg:
 		m.output = msg.Error.Error()
 	case components.ModelErrorMsg:
-		m.error = msg.Error
-		m.splash.SetVisible(false)
+		m.load.Fail(msg.Error)
+		m.splash.Follow(&m.load)
 	case queryinput.YqOutputMsg:
 		m.output = msg.Output
 	case components.FileMsg:
+		if m.load.State() == components.StateLoaded {
+			m.saveScroll()
+		}
 		m.current = msg.File
+		m.hovered, m.substitutions = "", nil
+		if s, ok := msg.File.(components.Substituted); ok {
+			m.substitutions = s.Substitutions()
+		}
 		m.SetSize(m.width, m.height)
 		m.ok = msg.Ok
-		m.error = fmt.Errorf("no content")
+		m.load.Fail(fmt.Errorf("no content"))
 		if m.ok {
-			m.error = nil
+			m.load.Done()
 			m.input = msg.Content
 			m.output = m.input
+			m.restoreScroll()
 		}
-		m.splash.SetVisible(false)
+		m.splash.Follow(&m.load)
+	case components.ShowFileMsg:
+		m.Update(components.FileMsg{File: msg.File, Ok: true, Content: msg.File.GetContent()})
+		m.viewport.YOffset = msg.Line
 	case components.FluxExecMsg:
-		m.error = nil
+		if m.load.State() == components.StateLoaded {
+			m.saveScroll()
+		}
+		m.load.Done()
 		m.input = msg.Output
 		m.output = m.input
-		m.splash.SetVisible(false)
+		m.restoreScroll()
+		m.splash.Follow(&m.load)
+	case tea.MouseMsg:
+		if msg.Action == tea.MouseActionMotion {
+			m.hover(msg)
+		}
 	case tea.KeyMsg:
 		switch m.focus {
 		case QueryFocus:
 			m.query, cmd = m.query.Update(msg)
 		case ViewportFocus:
-			m.viewport, cmd = m.viewport.Update(msg)
+			switch {
+			case m.save.typing:
+				cmd = m.updateSave(msg)
+				return m, cmd
+			case m.search.typing:
+				m.search.update(msg)
+				m.find()
+				return m, nil
+			}
+			switch msg.String() {
+			case "c":
+				cmd = picker.CopyDocumentCmd(m.input)
+			case "p":
+				cmd = components.PasteCmd(m.output, "manifest.yaml")
+			case "y":
+				cmd = m.copyOutput()
+			case "w":
+				m.startSave()
+			case "/":
+				m.search.start()
+				m.SetSize(m.width, m.height)
+			case "n", "N":
+				if m.search.active() {
+					m.search.next(msg.String() == "N")
+					m.scrollToMatch()
+					break
+				}
+				m.viewport, cmd = m.viewport.Update(msg)
+			default:
+				m.viewport, cmd = m.viewport.Update(msg)
+			}
 		}
 	}
 	return m, cmd
@@ -205,6 +313,12 @@ func (m *Model) UseBorder() tea.Model {
 	return m
 }
 
+// LoadState reports if the view is waiting for content, showing
+// it or showing the error loading ended in
+func (m *Model) LoadState() components.LoadState {
+	return m.load.State()
+}
+
 func (m *Model) View() string {
 	if m.splash.Visible() {
 		splash := lipgloss.Place(
@@ -218,11 +332,13 @@ func (m *Model) View() string {
 		return m.viewport.View()
 	}
 
-	if m.error != nil {
-		msg := m.error.Error()
-		switch e := m.error.(type) {
+	if err := m.load.Err(); err != nil {
+		msg := err.Error()
+		switch e := err.(type) {
 		case *exec.BmxExecError:
 			msg = e.StyledError(m.width)
+		case *components.FluxError:
+			msg = e.StyledError(m.width)
 		}
 		msg = lipgloss.NewStyle().
 			Foreground(theme.Colours.Red).
@@ -236,8 +352,13 @@ func (m *Model) View() string {
 
 	m.viewport.SetContent(m.print(m.output))
 	view := m.viewport.View()
+	if m.hovered != "" {
+		hover := m.hoverView()
+		view = overlay.PlaceOverlay(max(0, lipgloss.Width(view)-lipgloss.Width(hover)),
+			max(0, lipgloss.Height(view)-1), hover, view, false)
+	}
 	if m.border {
-		m.style = m.style.Border(lipgloss.RoundedBorder(), true)
+		m.style = m.style.Border(theme.RoundedBorder(), true)
 	}
 	switch m.focus {
 	case ViewportFocus:
@@ -246,6 +367,9 @@ func (m *Model) View() string {
 		view = m.style.BorderForeground(theme.Colours.Black).Render(view)
 	}
 
+	if footer := m.footer(); footer != "" {
+		view = lipgloss.JoinVertical(lipgloss.Left, view, footer)
+	}
 	content := lipgloss.JoinVertical(lipgloss.Left, view, m.filename)
 	if m.showQuery {
 		content = lipgloss.JoinVertical(
@@ -256,6 +380,54 @@ func (m *Model) View() string {
 		Render(content)
 }
 
+// footer is the line below the view used by prompts, or empty
+// when there is none
+func (m *Model) footer() string {
+	switch {
+	case m.save.typing:
+		return m.save.View()
+	case m.search.active():
+		return m.search.View()
+	}
+	return ""
+}
+
+// copyOutput copies the content shown, after any yq filter, to
+// the clipboard without styling
+func (m *Model) copyOutput() tea.Cmd {
+	output := ansi.Strip(m.output)
+	if strings.TrimSpace(output) == "" {
+		return toast.NewToastCmd(toast.Warning, "Nothing to copy")
+	}
+	description := "rendered manifest"
+	if m.current != nil && m.current.GetName() != "" {
+		description = m.current.GetName()
+	}
+	return components.CopyCmd(output, description)
+}
+
+// find searches the output as the query is typed, moving to the
+// first match from the top of the view
+func (m *Model) find() {
+	m.search.scan(lexer.Tokenize(m.output))
+	m.search.first(m.viewport.YOffset)
+	m.scrollToMatch()
+	if !m.search.active() {
+		m.SetSize(m.width, m.height)
+	}
+}
+
+// scrollToMatch brings the current match into view
+func (m *Model) scrollToMatch() {
+	if len(m.search.matches) == 0 {
+		return
+	}
+	line := m.search.matches[m.search.current].line
+	if line < m.viewport.YOffset || line >= m.viewport.YOffset+m.viewport.Height {
+		m.viewport.SetYOffset(max(0, line-m.viewport.Height/2))
+	}
+}
+
 func (m *Model) prop(col lipgloss.AdaptiveColor) func(...string) string {
 	return lipgloss.NewStyle().Foreground(col).Render
 }
@@ -289,8 +461,45 @@ func (m *Model) renderer(t *token.Token) func(...string) string {
 	return m.prop(theme.Colours.Black)
 }
 
+// normalise converts CRLF and lone CR line endings to LF and
+// expands tabs to the next multiple of TabWidth. Lines are split
+// and measured by byte so both would otherwise throw out the line
+// numbers and alignment
+func normalise(content string) string {
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+	content = strings.ReplaceAll(content, "\r", "\n")
+	if !strings.Contains(content, "\t") || TabWidth < 1 {
+		return content
+	}
+	lines := strings.Split(content, "\n")
+	for i, line := range lines {
+		var b strings.Builder
+		col := 0
+		for _, r := range line {
+			if r == '\t' {
+				spaces := TabWidth - col%TabWidth
+				b.WriteString(strings.Repeat(" ", spaces))
+				col += spaces
+				continue
+			}
+			b.WriteRune(r)
+			col++
+		}
+		lines[i] = b.String()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// contentWidth is the width of the viewport within the pane
+func (m *Model) contentWidth() int {
+	if MaxContentWidth > 0 {
+		return min(MaxContentWidth, m.width)
+	}
+	return m.width
+}
+
 func (m *Model) print(content string) string {
-	tokens := lexer.Tokenize(content)
+	tokens := lexer.Tokenize(normalise(content))
 	if len(tokens) == 0 {
 		return ""
 	}
@@ -301,48 +510,34 @@ func (m *Model) print(content string) string {
 		}
 	}
 
+	// Matches are found in the output the lines are built from so
+	// their offsets hold against the unstyled text of each line
+	m.search.scan(tokens)
+	m.variables = m.variables[:0]
+
 	texts := []string{}
 	lineNumber := tokens[0].Position.Line
+	col := 0
 	for _, tk := range tokens {
-		lines := strings.Split(tk.Origin, "\n")
-		render := m.renderer(tk)
-		header := ""
-		if m.LineNumber {
-			header = m.LineNumberFormat(lineNumber)
-		}
-		if len(lines) == 1 {
-			line := render(lines[0])
-			if len(texts) == 0 {
-				texts = append(texts, header+line)
-				lineNumber++
-			} else {
-				text := texts[len(texts)-1]
-				texts[len(texts)-1] = text + line
-			}
-		} else {
-			for idx, src := range lines {
+		render := m.placeholders(tk, m.renderer(tk))
+		for idx, src := range strings.Split(tk.Origin, "\n") {
+			if idx > 0 || len(texts) == 0 {
+				header := ""
 				if m.LineNumber {
 					header = m.LineNumberFormat(lineNumber)
 				}
-				line := render(src)
-				if idx == 0 {
-					if len(texts) == 0 {
-						texts = append(texts, header+line)
-						lineNumber++
-					} else {
-						text := texts[len(texts)-1]
-						texts[len(texts)-1] = text + line
-					}
-				} else {
-					texts = append(texts, fmt.Sprintf("%s%s", header, line))
-					lineNumber++
-				}
+				texts = append(texts, header)
+				lineNumber++
+				col = 0
 			}
+			texts[len(texts)-1] += m.search.highlight(len(texts)-1, col, src, render)
+			col += len(src)
 		}
 	}
-	for _, line := range texts {
-		m.viewport.Width = max(m.viewport.Width, len(line))
-		// texts[i] = truncate.String(line, uint(m.viewport.Width))
+	// The viewport never grows to fit a long line as that pushes
+	// the layout off screen
+	if m.width > 0 {
+		m.viewport.Width = m.contentWidth()
 	}
 	return strings.Join(texts, "\n")
 }
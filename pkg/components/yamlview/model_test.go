@@ -0,0 +1,312 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package yamlview
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/mproffitt/bmx/pkg/components/toast"
+	"github.com/mproffitt/delorian/pkg/components"
+	"github.com/mproffitt/delorian/pkg/testutil"
+)
+
+func TestMain(m *testing.M) {
+	testutil.Setup()
+	os.Exit(m.Run())
+}
+
+type file struct {
+	name, path, content string
+}
+
+func (f file) GetName() string    { return f.name }
+func (f file) GetPath() string    { return f.path }
+func (f file) GetContent() string { return f.content }
+
+func TestViewPopulated(t *testing.T) {
+	content, err := os.ReadFile("testdata/kustomization.yaml")
+	if err != nil {
+		t.Fatalf("unable to read fixture: %v", err)
+	}
+	f := file{
+		name:    "podinfo",
+		path:    "/repo/apps/podinfo/kustomization.yaml",
+		content: string(content),
+	}
+	m := testutil.Drive(New(80, 20, false).SetSize(80, 20),
+		components.FileMsg{File: f, Ok: true, Content: f.content})
+	testutil.RequireGolden(t, m.View())
+}
+
+func TestLoadSequence(t *testing.T) {
+	m := New(80, 20, false).SetSize(80, 20).(*Model)
+	f := file{name: "podinfo", content: "kind: ConfigMap\n"}
+
+	tests := []struct {
+		name   string
+		msg    tea.Msg
+		state  components.LoadState
+		splash bool
+		shows  string
+	}{
+		{"tab changed", components.TabChangedMsg{}, components.StateLoading, true, ""},
+		{"failed", components.ModelErrorMsg{Error: errors.New("build failed")},
+			components.StateError, false, "build failed"},
+		{"tab changed after error", components.TabChangedMsg{}, components.StateLoading, true, ""},
+		{"flux output", components.FluxExecMsg{Output: "kind: Namespace\n"},
+			components.StateLoaded, false, "Namespace"},
+		{"tab changed again", components.TabChangedMsg{}, components.StateLoading, true, ""},
+		{"nothing to load", components.LoadSkippedMsg{}, components.StateLoaded, false, "Namespace"},
+		{"missing file", components.FileMsg{File: f}, components.StateError, false, "no content"},
+		{"file", components.FileMsg{File: f, Ok: true, Content: f.content},
+			components.StateLoaded, false, "ConfigMap"},
+	}
+	for _, tt := range tests {
+		testutil.Drive(m, tt.msg)
+		if m.LoadState() != tt.state || m.splash.Visible() != tt.splash {
+			t.Fatalf("%s: expected %s with splash %t, got %s with splash %t", tt.name,
+				tt.state, tt.splash, m.LoadState(), m.splash.Visible())
+		}
+		view := m.View()
+		if tt.shows != "" && !strings.Contains(view, tt.shows) {
+			t.Errorf("%s: expected the view to show %q", tt.name, tt.shows)
+		}
+		if tt.splash && strings.Contains(view, "build failed") {
+			t.Errorf("%s: expected the splash without the error", tt.name)
+		}
+	}
+}
+
+func TestScanStarted(t *testing.T) {
+	// Tall enough for the message beneath the logo
+	m := New(80, 40, false).SetSize(80, 40).(*Model)
+	testutil.Drive(m, components.ScanStartedMsg{})
+	if view := m.View(); !strings.Contains(view, "scanning for kustomizations") {
+		t.Errorf("expected the splash to show a scan, got\n%s", view)
+	}
+	testutil.Drive(m, components.ScanStartedMsg{FromCache: true})
+	if view := m.View(); !strings.Contains(view, "loading kustomizations from cache") {
+		t.Errorf("expected the splash to show loading from the cache, got\n%s", view)
+	}
+}
+
+func TestSearch(t *testing.T) {
+	m := New(80, 5, false).SetSize(80, 5).(*Model)
+	m.focus = ViewportFocus
+	content := "kind: ConfigMap\nmetadata:\n  name: app\n" + strings.Repeat("# filler\n", 20) +
+		"data:\n  Name: other\n"
+	testutil.Drive(m, components.FileMsg{File: file{name: "app"}, Ok: true, Content: content})
+	m.View()
+
+	keys := func(s string) {
+		for _, r := range s {
+			testutil.Drive(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		}
+	}
+	keys("/name")
+	if view := m.View(); !strings.Contains(view, "1/2 matches") {
+		t.Fatalf("expected both matches to be counted ignoring case, got\n%s", view)
+	}
+	testutil.Drive(m, tea.KeyMsg{Type: tea.KeyEnter})
+	keys("n")
+	if view := m.View(); !strings.Contains(view, "2/2 matches") || !strings.Contains(view, "other") {
+		t.Errorf("expected n to scroll to the second match, got\n%s", view)
+	}
+	keys("N")
+	if m.search.current != 0 || m.viewport.YOffset != 0 {
+		t.Errorf("expected N to return to the first match, got %d at offset %d",
+			m.search.current, m.viewport.YOffset)
+	}
+
+	token := func(s ...string) string { return "<" + strings.Join(s, "") + ">" }
+	if line := m.search.highlight(24, 0, "  Name: other", token); line != "<  >Name<: other>" {
+		t.Errorf("expected the match to be rendered apart from the token, got %q", line)
+	}
+}
+
+func TestScrollRestored(t *testing.T) {
+	m := New(80, 10, false).SetSize(80, 10).(*Model)
+	long := file{name: "long", path: "/repo/long.yaml", content: strings.Repeat("key: value\n", 50)}
+	other := file{name: "other", path: "/repo/other.yaml", content: "kind: ConfigMap\n"}
+	show := func(f file) {
+		testutil.Drive(m, components.TabChangedMsg{})
+		m.View()
+		testutil.Drive(m, components.FileMsg{File: f, Ok: true, Content: f.content})
+		m.View()
+	}
+
+	show(long)
+	m.viewport.SetYOffset(20)
+	show(other)
+	if m.viewport.YOffset != 0 {
+		t.Errorf("expected another file to start at the top, got %d", m.viewport.YOffset)
+	}
+	show(long)
+	if m.viewport.YOffset != 20 {
+		t.Errorf("expected the position to be restored, got %d", m.viewport.YOffset)
+	}
+
+	long.content += "extra: line\n"
+	show(long)
+	if m.viewport.YOffset != 0 {
+		t.Errorf("expected changed content to start at the top, got %d", m.viewport.YOffset)
+	}
+}
+
+func TestCopyNothing(t *testing.T) {
+	m := New(80, 10, false).SetSize(80, 10).(*Model)
+	m.focus = ViewportFocus
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+	if msg, ok := cmd().(toast.NewToastMsg); !ok || msg.Type != toast.Warning {
+		t.Errorf("expected a warning when there is nothing to copy, got %#v", cmd())
+	}
+}
+
+type substitutedFile struct {
+	file
+	subs map[string]string
+}
+
+func (f substitutedFile) Substitutions() map[string]string { return f.subs }
+
+func TestPlaceholders(t *testing.T) {
+	m := New(80, 10, false).SetSize(80, 10).(*Model)
+	f := substitutedFile{
+		file: file{name: "apps", content: "metadata:\n  name: ${cluster}-apps\n" +
+			"spec:\n  path: \"./${region:=eu-west-1}/${tier}\"\n"},
+		subs: map[string]string{"cluster": "prod", "tier": ""},
+	}
+	testutil.Drive(m, components.FileMsg{File: f, Ok: true, Content: f.content})
+	m.View()
+
+	want := []string{"${cluster}", "${region:=eu-west-1}", "${tier}"}
+	if !slices.Equal(m.variables, want) {
+		t.Fatalf("expected %v to be marked, got %v", want, m.variables)
+	}
+	for variable, value := range map[string]string{
+		"${cluster}":           "${cluster} = prod",
+		"${region:=eu-west-1}": "${region:=eu-west-1} = eu-west-1 (default)",
+		"${tier}":              "${tier} is not set",
+	} {
+		if got := m.resolve(variable); got != value {
+			t.Errorf("expected %q, got %q", value, got)
+		}
+	}
+
+	m.hovered = "${cluster}"
+	if view := m.View(); !strings.Contains(view, "${cluster} = prod") {
+		t.Errorf("expected the hovered value to be shown, got\n%s", view)
+	}
+}
+
+func TestSave(t *testing.T) {
+	m := New(80, 10, false).SetSize(80, 10).(*Model)
+	m.focus = ViewportFocus
+	f := file{name: "apps", content: "kind: ConfigMap\n"}
+	testutil.Drive(m, components.FileMsg{File: f, Ok: true, Content: f.content})
+
+	press := func(msg tea.KeyMsg) tea.Cmd {
+		_, cmd := m.Update(msg)
+		return cmd
+	}
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'w'}})
+	if got := m.save.input.Value(); got != "apps.yaml" {
+		t.Fatalf("expected the resource name to be suggested, got %q", got)
+	}
+	filename := filepath.Join(t.TempDir(), "apps.yaml")
+	m.save.input.SetValue(filename)
+	cmd := press(tea.KeyMsg{Type: tea.KeyEnter})
+	if msg, ok := cmd().(toast.NewToastMsg); !ok || msg.Type != toast.Success {
+		t.Fatalf("expected the file to be saved, got %#v", msg)
+	}
+	if content, _ := os.ReadFile(filename); string(content) != f.content {
+		t.Errorf("expected the content shown to be written, got %q", content)
+	}
+
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'w'}})
+	m.save.input.SetValue(filename)
+	if cmd := press(tea.KeyMsg{Type: tea.KeyEnter}); cmd != nil || !m.save.confirm {
+		t.Fatal("expected an existing file to need confirming")
+	}
+	if !strings.Contains(m.View(), "enter again to overwrite") {
+		t.Error("expected the overwrite warning to be shown")
+	}
+	if cmd := press(tea.KeyMsg{Type: tea.KeyEnter}); cmd == nil || m.save.typing {
+		t.Error("expected a second enter to overwrite the file")
+	}
+}
+
+func TestLongLine(t *testing.T) {
+	m := New(40, 6, false).SetSize(40, 6).(*Model)
+	m.focus = ViewportFocus
+	content := "data:\n  key: " + strings.Repeat("x", 5000) + "end\n"
+	testutil.Drive(m, components.FileMsg{File: file{name: "app"}, Ok: true, Content: content})
+	if m.viewport.Width != 40 {
+		t.Fatalf("expected the viewport to keep the pane width, got %d", m.viewport.Width)
+	}
+	for _, line := range strings.Split(m.View(), "\n") {
+		if w := lipgloss.Width(line); w > 40 {
+			t.Fatalf("expected lines to fit the pane, got one %d wide", w)
+		}
+	}
+
+	testutil.Drive(m, tea.KeyMsg{Type: tea.KeyRight})
+	if m.viewport.HorizontalScrollPercent() == 0 {
+		t.Errorf("expected right to scroll the long line into view")
+	}
+
+	MaxContentWidth = 20
+	defer func() { MaxContentWidth = 0 }()
+	m.SetSize(40, 6)
+	if m.viewport.Width != 20 {
+		t.Errorf("expected the configured cap, got %d", m.viewport.Width)
+	}
+}
+
+func TestTabsAndCarriageReturns(t *testing.T) {
+	if got := normalise("a:\tb\r\n\tc: d\re"); got != "a:  b\n    c: d\ne" {
+		t.Errorf("unexpected normalised content %q", got)
+	}
+
+	m := New(80, 10, false).SetSize(80, 10).(*Model)
+	content := "kind: ConfigMap\r\ndata:\r\n  a:\tone\r\n  bb:\ttwo # aligned\r\n"
+	testutil.Drive(m, components.FileMsg{File: file{name: "app"}, Ok: true, Content: content})
+	m.View()
+	view := ansi.Strip(m.viewport.View())
+	if strings.ContainsAny(view, "\t\r") {
+		t.Fatalf("expected tabs and carriage returns to be removed, got %q", view)
+	}
+	for _, want := range []string{"   1 │ kind: ConfigMap", "   3 │   a:    one", "   4 │   bb:   two # aligned"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("expected %q in\n%s", want, view)
+		}
+	}
+	if strings.Contains(view, "   6 │") {
+		t.Errorf("expected CRLF not to add lines, got\n%s", view)
+	}
+}
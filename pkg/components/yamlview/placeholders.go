@@ -0,0 +1,109 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package yamlview
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/goccy/go-yaml/token"
+	zone "github.com/lrstanley/bubblezone"
+	"github.com/mproffitt/delorian/pkg/theme"
+)
+
+// placeholder matches a flux postBuild substitution variable
+var placeholder = regexp.MustCompile(`\$\{[^{}]+\}`)
+
+// placeholders wraps the renderer of string tokens so substitution
+// variables in them stand out and can be hovered to see their value
+func (m *Model) placeholders(t *token.Token, render func(...string) string) func(...string) string {
+	switch t.Type {
+	case token.StringType, token.SingleQuoteType, token.DoubleQuoteType:
+	default:
+		return render
+	}
+	style := lipgloss.NewStyle().Foreground(theme.Colours.BrightPurple).Bold(true)
+	return func(s ...string) string {
+		src := strings.Join(s, "")
+		spans := placeholder.FindAllStringIndex(src, -1)
+		if len(spans) == 0 {
+			return render(src)
+		}
+		var b strings.Builder
+		at := 0
+		for _, span := range spans {
+			b.WriteString(render(src[at:span[0]]))
+			b.WriteString(zone.Mark(m.variableID(len(m.variables)), style.Render(src[span[0]:span[1]])))
+			m.variables = append(m.variables, src[span[0]:span[1]])
+			at = span[1]
+		}
+		b.WriteString(render(src[at:]))
+		return b.String()
+	}
+}
+
+// variableID identifies the nth variable rendered
+func (m *Model) variableID(n int) string {
+	return fmt.Sprintf("%s$%d", m.id, n)
+}
+
+// hover finds the variable under the mouse
+func (m *Model) hover(msg tea.MouseMsg) {
+	m.hovered = ""
+	for i, v := range m.variables {
+		if zone.Get(m.variableID(i)).InBounds(msg) {
+			m.hovered = v
+			return
+		}
+	}
+}
+
+// resolve describes the value flux substitutes for variable,
+// falling back to a default written as `${var:=default}` or
+// `${var:-default}` when the variable is unset or empty
+func (m *Model) resolve(variable string) string {
+	name := strings.TrimSuffix(strings.TrimPrefix(variable, "${"), "}")
+	var fallback *string
+	if i := strings.Index(name, ":"); i > 0 && i+1 < len(name) && strings.ContainsAny(name[i+1:i+2], "=-") {
+		d := name[i+2:]
+		name, fallback = name[:i], &d
+	}
+	switch value, ok := m.substitutions[name]; {
+	case ok && value != "":
+		return fmt.Sprintf("%s = %s", variable, value)
+	case fallback != nil:
+		return fmt.Sprintf("%s = %s (default)", variable, *fallback)
+	case m.substitutions == nil:
+		return variable + " has no known substitutions"
+	}
+	return variable + " is not set"
+}
+
+// hoverView shows the value of the hovered variable
+func (m *Model) hoverView() string {
+	return lipgloss.NewStyle().
+		Foreground(theme.Colours.Fg).
+		Background(theme.Colours.SelectionBg).
+		Padding(0, 1).
+		Render(m.resolve(m.hovered))
+}
@@ -0,0 +1,120 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package yamlview
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/mproffitt/bmx/pkg/components/toast"
+	"github.com/mproffitt/delorian/pkg/theme"
+)
+
+// save prompts for the file the content shown is written to.
+// Writing over an existing file needs enter pressing twice
+type save struct {
+	confirm bool
+	input   textinput.Model
+	typing  bool
+}
+
+func newSave() save {
+	input := textinput.New()
+	input.Prompt = "Save to: "
+	return save{input: input}
+}
+
+// start prompts for a filename, suggesting filename
+func (s *save) start(filename string) {
+	s.input.SetValue(filename)
+	s.input.CursorEnd()
+	s.input.Focus()
+	s.typing = true
+	s.confirm = false
+}
+
+// stop closes the prompt
+func (s *save) stop() {
+	s.input.Blur()
+	s.typing = false
+	s.confirm = false
+}
+
+// View shows the prompt, and a warning once the file is found to
+// exist
+func (s *save) View() string {
+	hint := "enter to save, empty to cancel"
+	if s.confirm {
+		hint = "file exists, enter again to overwrite"
+	}
+	return s.input.View() + lipgloss.NewStyle().
+		Foreground(theme.Colours.BrightBlack).
+		Render("  "+hint)
+}
+
+// startSave opens the prompt with the name of the content shown
+func (m *Model) startSave() {
+	name := "manifest"
+	if m.current != nil && m.current.GetName() != "" {
+		name = m.current.GetName()
+	}
+	m.save.start(name + ".yaml")
+	m.SetSize(m.width, m.height)
+}
+
+// updateSave handles keys while the filename is being typed
+func (m *Model) updateSave(msg tea.KeyMsg) tea.Cmd {
+	if msg.Type != tea.KeyEnter {
+		m.save.confirm = false
+		m.save.input, _ = m.save.input.Update(msg)
+		return nil
+	}
+
+	filename := strings.TrimSpace(m.save.input.Value())
+	if filename != "" && !m.save.confirm {
+		if _, err := os.Stat(filename); err == nil {
+			m.save.confirm = true
+			return nil
+		}
+	}
+	m.save.stop()
+	m.SetSize(m.width, m.height)
+	if filename == "" {
+		return nil
+	}
+	return writeCmd(filename, ansi.Strip(m.output))
+}
+
+// writeCmd writes content to filename and reports the result as a
+// toast
+func writeCmd(filename, content string) tea.Cmd {
+	return func() tea.Msg {
+		if err := os.WriteFile(filename, []byte(content), 0o644); err != nil {
+			return toast.NewToastMsg{Type: toast.Error, Message: "Unable to save: " + err.Error()}
+		}
+		return toast.NewToastMsg{Type: toast.Success,
+			Message: fmt.Sprintf("Saved %d lines to %s", strings.Count(content, "\n"), filename)}
+	}
+}
@@ -0,0 +1,73 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package yamlview
+
+import "hash/fnv"
+
+// position is where a view was scrolled to and a hash of the
+// content it was scrolled through
+type position struct {
+	hash   uint64
+	offset int
+}
+
+// contentHash identifies content without holding on to it
+func contentHash(content string) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(content))
+	return h.Sum64()
+}
+
+// scrollKey identifies what the view is showing. Rendered output
+// has no file so shares a key, told apart by its hash
+func (m *Model) scrollKey() string {
+	if m.current == nil {
+		return ""
+	}
+	return m.current.GetPath()
+}
+
+// saveScroll remembers the offset of the content being replaced
+func (m *Model) saveScroll() {
+	if m.input == "" {
+		return
+	}
+	if m.offsets == nil {
+		m.offsets = map[string]position{}
+	}
+	m.offsets[m.scrollKey()] = position{hash: contentHash(m.input), offset: m.viewport.YOffset}
+}
+
+// restoreScroll returns to where the content was left when it is
+// shown again unchanged, or to the top when it has changed
+func (m *Model) restoreScroll() {
+	key := m.scrollKey()
+	saved, ok := m.offsets[key]
+	m.viewport.YOffset = 0
+	switch {
+	case !ok:
+	case saved.hash == contentHash(m.input):
+		// The viewport is filled when the view is drawn, which
+		// keeps the offset within the content
+		m.viewport.YOffset = saved.offset
+	default:
+		delete(m.offsets, key)
+	}
+}
@@ -0,0 +1,178 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package yamlview
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/goccy/go-yaml/token"
+	"github.com/mproffitt/delorian/pkg/theme"
+)
+
+// match is a span of the output matching the search, as byte
+// offsets into a line of the unstyled output
+type match struct {
+	line, start, end int
+}
+
+// search finds text in the viewport. `/` starts typing a query,
+// enter stops and `n` and `N` move between the matches
+type search struct {
+	current int
+	input   textinput.Model
+	matches []match
+	typing  bool
+}
+
+func newSearch() search {
+	input := textinput.New()
+	input.Prompt = "/"
+	return search{input: input}
+}
+
+// active reports if a search is being typed or its matches shown
+func (s *search) active() bool {
+	return s.typing || s.input.Value() != ""
+}
+
+// start clears the previous search and starts typing a new one
+func (s *search) start() {
+	s.input.SetValue("")
+	s.input.Focus()
+	s.typing = true
+	s.matches = nil
+	s.current = 0
+}
+
+// scan finds the query in the output the tokens were lexed from.
+// Matching ignores case
+func (s *search) scan(tokens token.Tokens) {
+	s.matches = s.matches[:0]
+	query := strings.ToLower(s.input.Value())
+	if query == "" {
+		return
+	}
+
+	var b strings.Builder
+	for _, tk := range tokens {
+		b.WriteString(tk.Origin)
+	}
+	for i, line := range strings.Split(b.String(), "\n") {
+		// Lowering can change the length of some runes, in which
+		// case the offsets only hold for an exact match
+		lower := strings.ToLower(line)
+		if len(lower) != len(line) {
+			lower = line
+		}
+		for at := 0; ; {
+			n := strings.Index(lower[at:], query)
+			if n < 0 {
+				break
+			}
+			at += n
+			s.matches = append(s.matches, match{line: i, start: at, end: at + len(query)})
+			at += len(query)
+		}
+	}
+	s.current = min(s.current, max(0, len(s.matches)-1))
+}
+
+// next moves to the match after the current one, or the one
+// before it when back is set, wrapping at either end
+func (s *search) next(back bool) {
+	if len(s.matches) == 0 {
+		return
+	}
+	step := 1
+	if back {
+		step = len(s.matches) - 1
+	}
+	s.current = (s.current + step) % len(s.matches)
+}
+
+// first moves to the first match at or below line
+func (s *search) first(line int) {
+	s.current = 0
+	for i, m := range s.matches {
+		if m.line >= line {
+			s.current = i
+			return
+		}
+	}
+}
+
+// highlight renders src, found at col on a line of the output,
+// with any matches in it standing out from the syntax colours
+func (s *search) highlight(line, col int, src string, render func(...string) string) string {
+	var b strings.Builder
+	at := 0
+	for i, m := range s.matches {
+		if m.line != line || m.end <= col || m.start >= col+len(src) {
+			continue
+		}
+		start, end := max(m.start-col, at), min(m.end-col, len(src))
+		if start >= end {
+			continue
+		}
+		style := lipgloss.NewStyle().
+			Foreground(theme.Colours.Bg).
+			Background(theme.Colours.BrightBlack)
+		if i == s.current {
+			style = style.Background(theme.Colours.Yellow)
+		}
+		b.WriteString(render(src[at:start]))
+		b.WriteString(style.Render(src[start:end]))
+		at = end
+	}
+	if at == 0 {
+		return render(src)
+	}
+	b.WriteString(render(src[at:]))
+	return b.String()
+}
+
+// update handles keys while the query is being typed
+func (s *search) update(msg tea.KeyMsg) {
+	if msg.Type == tea.KeyEnter {
+		s.typing = false
+		s.input.Blur()
+		return
+	}
+	s.input, _ = s.input.Update(msg)
+}
+
+// View shows the query and how many matches it has
+func (s *search) View() string {
+	count := "no matches"
+	if len(s.matches) > 0 {
+		count = fmt.Sprintf("%d/%d matches", s.current+1, len(s.matches))
+	}
+	query := s.input.Prompt + s.input.Value()
+	if s.typing {
+		query = s.input.View()
+	}
+	return query + lipgloss.NewStyle().
+		Foreground(theme.Colours.BrightBlack).
+		Render("  "+count)
+}
@@ -0,0 +1,327 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	appName         = "delorian"
+	configFilename  = "config.yaml"
+	themeFilename   = "theme.yaml"
+	stateFilename   = "state.yaml"
+	historyFilename = ".query_history"
+	notesFilename   = "notes.yaml"
+
+	// DefaultTitle is the title shown when no branding is configured
+	DefaultTitle = appName
+)
+
+type Config struct {
+	Branding Branding  `yaml:"branding"`
+	Flux     Flux      `yaml:"flux"`
+	Layout   Layout    `yaml:"layout"`
+	Paste    Paste     `yaml:"paste"`
+	Profiles []Profile `yaml:"profiles"`
+	Scan     Scan      `yaml:"scan"`
+	State    State     `yaml:"-"`
+	active   string
+	filename string
+}
+
+// State holds choices made in the UI which are remembered between
+// runs. It is kept in its own file next to the config file so the
+// config file is never rewritten
+type State struct {
+	// Compact shows a single pane without the sidebar or tabs
+	Compact bool `yaml:"compact"`
+}
+
+// Profile bundles the settings for a single environment so
+// operators can switch between fully configured setups
+type Profile struct {
+	Name string `yaml:"name"`
+
+	// Context is the kubernetes context flux is run against
+	Context string `yaml:"context"`
+
+	// Root is the repository to scan and Subpath an optional
+	// directory inside it to limit the scan to
+	Root    string `yaml:"root"`
+	Subpath string `yaml:"subpath"`
+
+	// ClusterRegex limits the clusters shown to those with a
+	// matching name
+	ClusterRegex string `yaml:"clusterRegex"`
+
+	// Filters are the diff filters enabled by default
+	Filters []string `yaml:"filters"`
+}
+
+// Path returns the directory scanned for this profile
+func (p Profile) Path() string {
+	root := p.Root
+	// Accept ~\ as well so Windows users can write paths natively
+	if strings.HasPrefix(root, "~/") || strings.HasPrefix(root, `~\`) {
+		if home, err := os.UserHomeDir(); err == nil {
+			root = filepath.Join(home, root[2:])
+		}
+	}
+	if p.Subpath != "" {
+		root = filepath.Join(root, p.Subpath)
+	}
+	return root
+}
+
+// Validate checks the profile can be used
+func (p Profile) Validate() error {
+	if p.Name == "" {
+		return fmt.Errorf("profile has no name")
+	}
+	if p.ClusterRegex != "" {
+		if _, err := regexp.Compile(p.ClusterRegex); err != nil {
+			return fmt.Errorf("profile %q has an invalid clusterRegex: %w", p.Name, err)
+		}
+	}
+	return nil
+}
+
+// Flux holds extra flags appended to the flux commands run by
+// delorian, for setups which need options it does not set itself
+type Flux struct {
+	BuildFlags []string `yaml:"buildFlags"`
+	DiffFlags  []string `yaml:"diffFlags"`
+
+	// KeepComments copies the comments from the source manifests
+	// into the build of a kustomization
+	KeepComments bool `yaml:"keepComments"`
+}
+
+// Layout controls the size of the sidebar and the tabs shown
+type Layout struct {
+	// SidebarMinWidth is the narrowest the sidebar is drawn. When
+	// unset the default minimum width is used
+	SidebarMinWidth int `yaml:"sidebarMinWidth"`
+
+	// SidebarHideBelow hides the sidebar when the terminal is
+	// narrower than this many columns. Zero never hides it
+	SidebarHideBelow int `yaml:"sidebarHideBelow"`
+
+	// FocusFollowsMouse focuses the sidebar or primary view when
+	// the mouse moves over it, without needing tab or a click
+	FocusFollowsMouse bool `yaml:"focusFollowsMouse"`
+
+	// MaxContentWidth is the widest YAML is drawn before long
+	// lines scroll sideways. Zero uses the width of the pane
+	MaxContentWidth int `yaml:"maxContentWidth"`
+
+	// TabWidth is the number of columns tabs in YAML are expanded
+	// to. When unset tabs are four columns wide
+	TabWidth int `yaml:"tabWidth"`
+
+	// Glyphs draws with unicode or plain ASCII, as for the
+	// --glyphs flag. The flag takes precedence when given
+	Glyphs string `yaml:"glyphs"`
+
+	// Tabs lists the tabs to show, in order. Tabs not listed are
+	// hidden. When unset every tab is shown in the default order
+	Tabs []string `yaml:"tabs"`
+}
+
+// Validate checks the layout values are usable
+func (l Layout) Validate() error {
+	if l.SidebarMinWidth < 0 || l.SidebarHideBelow < 0 || l.MaxContentWidth < 0 || l.TabWidth < 0 {
+		return fmt.Errorf("layout widths must not be negative")
+	}
+	if l.Tabs != nil && len(l.Tabs) == 0 {
+		return fmt.Errorf("layout must show at least one tab")
+	}
+	return nil
+}
+
+// Paste configures the service views are shared through. Sharing
+// is disabled until an endpoint is set
+type Paste struct {
+	// Endpoint receives the upload. Use
+	// `https://api.github.com/gists` to create a secret gist, or a
+	// paste service which replies with the URL of the paste
+	Endpoint string `yaml:"endpoint"`
+
+	// Token is sent as a bearer token. Environment variables in
+	// it are expanded so it needn't be written in the file
+	Token string `yaml:"token"`
+
+	// Timeout limits how long an upload may take. When unset the
+	// default is used
+	Timeout time.Duration `yaml:"timeout"`
+}
+
+// Scan controls how the repository is interpreted while walking it
+type Scan struct {
+	// BaseMarkers are directory names which hold shared bases
+	// rather than clusters. Paths containing any of them are not
+	// used for cluster detection. Names may use `*` wildcards
+	BaseMarkers []string `yaml:"baseMarkers"`
+
+	// Labels are the kustomization labels shown in the list,
+	// such as those naming the owning team
+	Labels []string `yaml:"labels"`
+
+	// WarnAfter is how long a scan runs before warning that it is
+	// slow. Limit is how long before offering to stop it. When
+	// unset the defaults are used and a negative value disables them
+	WarnAfter time.Duration `yaml:"warnAfter"`
+	Limit     time.Duration `yaml:"limit"`
+}
+
+// Branding allows the application to be presented under a
+// custom name, for example by platform teams wrapping it as
+// part of their own tooling
+type Branding struct {
+	Title    string `yaml:"title"`
+	Subtitle string `yaml:"subtitle"`
+	Logo     *bool  `yaml:"logo,omitempty"`
+}
+
+// ShowLogo reports if the flux logo should be displayed on
+// splash screens. The logo is shown unless disabled
+func (b Branding) ShowLogo() bool {
+	return b.Logo == nil || *b.Logo
+}
+
+// ThemeFile returns the path to `~/.config/delorian/theme.yaml`,
+// which holds colour overrides
+func ThemeFile() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to find user config directory: %w", err)
+	}
+	return filepath.Join(dir, appName, themeFilename), nil
+}
+
+// New loads the configuration from `~/.config/delorian/config.yaml`
+//
+// If the file does not exist, the default configuration is returned
+func New() (*Config, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to find user config directory: %w", err)
+	}
+	return Load(filepath.Join(dir, appName, configFilename))
+}
+
+// Load reads the configuration from the given file
+//
+// A missing file is not an error and results in the default
+// configuration
+func Load(filename string) (*Config, error) {
+	c := Config{filename: filename}
+	content, err := os.ReadFile(filename)
+	switch {
+	case os.IsNotExist(err):
+	case err != nil:
+		return nil, err
+	default:
+		if err := yaml.Unmarshal(content, &c); err != nil {
+			return nil, fmt.Errorf("failed to parse config file %q: %w", filename, err)
+		}
+	}
+
+	if c.Branding.Title == "" {
+		c.Branding.Title = DefaultTitle
+	}
+	if err := c.Layout.Validate(); err != nil {
+		return nil, err
+	}
+	for _, p := range c.Profiles {
+		if err := p.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	// The state is only a convenience so an unreadable file
+	// falls back to the defaults rather than stopping startup
+	if content, err := os.ReadFile(c.stateFile()); err == nil {
+		_ = yaml.Unmarshal(content, &c.State)
+	}
+	return &c, nil
+}
+
+// stateFile is the file the UI state is remembered in
+func (c *Config) stateFile() string {
+	return filepath.Join(filepath.Dir(c.filename), stateFilename)
+}
+
+// HistoryFile is the file yaml queries are remembered in
+func (c *Config) HistoryFile() string {
+	return filepath.Join(filepath.Dir(c.filename), historyFilename)
+}
+
+// NotesFile is the file notes on kustomizations are kept in
+func (c *Config) NotesFile() string {
+	return filepath.Join(filepath.Dir(c.filename), notesFilename)
+}
+
+// SaveState writes the UI state so it is restored on the next run
+func (c *Config) SaveState() error {
+	content, err := yaml.Marshal(c.State)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(c.stateFile()), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(c.stateFile(), content, 0o600)
+}
+
+// SetProfile selects the named profile as the active profile
+func (c *Config) SetProfile(name string) error {
+	for _, p := range c.Profiles {
+		if p.Name == name {
+			c.active = name
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown profile %q", name)
+}
+
+// ActiveProfile returns the active profile or nil if no
+// profile has been selected
+func (c *Config) ActiveProfile() *Profile {
+	for i := range c.Profiles {
+		if c.Profiles[i].Name == c.active {
+			return &c.Profiles[i]
+		}
+	}
+	return nil
+}
+
+// GetConfigFile returns the full path to the config file
+func (c *Config) GetConfigFile() string {
+	return c.filename
+}
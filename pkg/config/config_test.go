@@ -0,0 +1,130 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadProfiles(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "config.yaml")
+	content := `profiles:
+  - name: staging
+    context: staging-admin
+    root: /src/gitops
+    subpath: clusters/staging
+    clusterRegex: ^staging-
+`
+	if err := os.WriteFile(filename, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load(filename)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.ActiveProfile() != nil {
+		t.Fatalf("expected no active profile before selection")
+	}
+	if err := cfg.SetProfile("production"); err == nil {
+		t.Errorf("expected an error for an unknown profile")
+	}
+	if err := cfg.SetProfile("staging"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	p := cfg.ActiveProfile()
+	if p.Context != "staging-admin" {
+		t.Errorf("context = %q, want %q", p.Context, "staging-admin")
+	}
+	if want := "/src/gitops/clusters/staging"; p.Path() != want {
+		t.Errorf("path = %q, want %q", p.Path(), want)
+	}
+}
+
+func TestLoadInvalidProfile(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "config.yaml")
+	content := "profiles:\n  - name: broken\n    clusterRegex: \"[\"\n"
+	if err := os.WriteFile(filename, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(filename); err == nil {
+		t.Errorf("expected an error for an invalid clusterRegex")
+	}
+}
+
+func TestLoadLayout(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "config.yaml")
+	content := "layout:\n  sidebarMinWidth: 40\n  sidebarHideBelow: 100\n  focusFollowsMouse: true\n"
+	if err := os.WriteFile(filename, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := Load(filename)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Layout.SidebarMinWidth != 40 || cfg.Layout.SidebarHideBelow != 100 ||
+		!cfg.Layout.FocusFollowsMouse {
+		t.Errorf("unexpected layout %+v", cfg.Layout)
+	}
+
+	if err := os.WriteFile(filename, []byte("layout:\n  sidebarMinWidth: -1\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(filename); err == nil {
+		t.Errorf("expected an error for a negative width")
+	}
+
+	if err := os.WriteFile(filename, []byte("layout:\n  tabs: []\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(filename); err == nil {
+		t.Errorf("expected an error when every tab is hidden")
+	}
+}
+
+func TestSaveState(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "delorian", "config.yaml")
+	cfg, err := Load(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.State.Compact {
+		t.Fatal("expected compact mode to be off by default")
+	}
+
+	cfg.State.Compact = true
+	if err := cfg.SaveState(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filename); !os.IsNotExist(err) {
+		t.Errorf("expected the config file not to be written")
+	}
+
+	cfg, err = Load(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cfg.State.Compact {
+		t.Error("expected compact mode to be remembered")
+	}
+}
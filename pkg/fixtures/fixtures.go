@@ -0,0 +1,92 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// Package fixtures replaces the output of flux and kustomize with
+// canned output read from a directory so the interface can be
+// demonstrated and tested without a cluster or either binary.
+//
+// Fixtures are selected by kustomization name:
+//
+//	<dir>/<name>/build.yaml      output of flux build
+//	<dir>/<name>/diff.txt        output of flux diff
+//	<dir>/<name>/kustomize.yaml  output of kustomize build
+//
+// For kustomize the name is that of the directory being built.
+package fixtures
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	BuildFile     = "build.yaml"
+	DiffFile      = "diff.txt"
+	KustomizeFile = "kustomize.yaml"
+)
+
+// Dir is the directory fixtures are read from. Set Runner and
+// Builder in place of the defaults to use them instead of flux
+// and kustomize
+var Dir string
+
+// Enabled reports if fixtures are used in place of flux and kustomize
+func Enabled() bool {
+	return Dir != ""
+}
+
+// Flux returns the fixture for the flux command given in args,
+// which must be a build or diff of a kustomization
+func Flux(args []string) (string, error) {
+	if len(args) < 3 || args[1] != "kustomization" {
+		return "", fmt.Errorf("no fixture for flux %s", strings.Join(args, " "))
+	}
+	var file string
+	switch args[0] {
+	case "build":
+		file = BuildFile
+	case "diff":
+		file = DiffFile
+	default:
+		return "", fmt.Errorf("no fixture for flux %s", args[0])
+	}
+	content, err := read(args[2], file)
+	return string(content), err
+}
+
+// Kustomize returns the fixture for building the kustomization
+// in the directory path
+func Kustomize(path string) ([]byte, error) {
+	return read(filepath.Base(path), KustomizeFile)
+}
+
+// read returns the content of file for the named kustomization
+func read(name, file string) ([]byte, error) {
+	if name == "" || name != filepath.Base(name) || strings.HasPrefix(name, ".") {
+		return nil, fmt.Errorf("no fixture for kustomization %q", name)
+	}
+	path := filepath.Join(Dir, name, file)
+	content, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("no fixture for %s: %w", name, err)
+	}
+	return content, nil
+}
@@ -0,0 +1,115 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package fixtures
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFixtures(t *testing.T) {
+	previous := Dir
+	defer func() { Dir = previous }()
+	Dir = t.TempDir()
+
+	files := map[string]string{
+		"apps/" + BuildFile:      "kind: Deployment\n",
+		"apps/" + DiffFile:       "► Deployment/apps/web drifted\n",
+		"infra/" + KustomizeFile: "kind: Kustomization\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(Dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	out, err := Flux([]string{"build", "kustomization", "apps", "-n", "flux-system"})
+	if err != nil || out != files["apps/"+BuildFile] {
+		t.Errorf("expected build fixture, got %q (%v)", out, err)
+	}
+	out, err = Flux([]string{"diff", "kustomization", "apps"})
+	if err != nil || out != files["apps/"+DiffFile] {
+		t.Errorf("expected diff fixture, got %q (%v)", out, err)
+	}
+	content, err := Kustomize("/repo/clusters/infra")
+	if err != nil || string(content) != files["infra/"+KustomizeFile] {
+		t.Errorf("expected kustomize fixture, got %q (%v)", content, err)
+	}
+
+	for _, args := range [][]string{
+		{"build", "kustomization", "missing"},
+		{"build", "kustomization", "../apps"},
+		{"get", "kustomization", "apps"},
+		{"version"},
+	} {
+		if _, err := Flux(args); err == nil {
+			t.Errorf("expected an error for %v", args)
+		}
+	}
+}
+
+func TestRunner(t *testing.T) {
+	previous := Dir
+	defer func() { Dir = previous }()
+	Dir = t.TempDir()
+	for name, content := range map[string]string{
+		"apps/" + DiffFile:       "► Deployment/apps/web drifted\n",
+		"infra/" + KustomizeFile: "kind: Kustomization\n",
+	} {
+		path := filepath.Join(Dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ctx := context.Background()
+	r := Runner{}
+	if out, _, err := r.Run(ctx, "flux", []string{"diff", "kustomization", "apps", "--context", "dev"}); err != nil ||
+		!strings.Contains(out, "drifted") {
+		t.Errorf("expected the diff fixture, got %q (%v)", out, err)
+	}
+	if _, _, err := r.Run(ctx, "kubectl", []string{"cluster-info", "--request-timeout=3s"}); err != nil {
+		t.Errorf("expected the cluster to be reachable, got %v", err)
+	}
+	if out, _, _ := r.Run(ctx, "kubectl", []string{"config", "current-context"}); out != Context {
+		t.Errorf("expected the %s context, got %q", Context, out)
+	}
+	if _, _, err := r.Run(ctx, "kubectl", []string{"get", "kustomizations"}); err == nil {
+		t.Error("expected kubectl to never reach a real cluster")
+	}
+	if _, err := r.LookPath("flux"); err != nil {
+		t.Errorf("expected flux to be stood in for, got %v", err)
+	}
+
+	content, err := Builder{}.Build(nil, "/repo/clusters/infra")
+	if err != nil || string(content) != "kind: Kustomization\n" {
+		t.Errorf("expected the kustomize fixture, got %q (%v)", content, err)
+	}
+}
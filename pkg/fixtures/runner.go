@@ -0,0 +1,75 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package fixtures
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mproffitt/delorian/pkg/components"
+	"sigs.k8s.io/kustomize/kyaml/filesys"
+)
+
+// Context is the kubernetes context reported while fixtures stand
+// in for the cluster
+const Context = "fixtures"
+
+// Runner answers flux from the fixtures and reports the cluster as
+// reachable, so diffs are shown without a cluster or flux installed.
+// Every other command, such as git, is run as normal
+type Runner struct {
+	components.ExecRunner
+}
+
+func (r Runner) Run(ctx context.Context, name string, args []string) (string, string, error) {
+	switch name {
+	case "flux":
+		if len(args) > 0 && args[0] == "check" {
+			return "", "", nil
+		}
+		out, err := Flux(args)
+		return out, "", err
+	case "kubectl":
+		switch {
+		case len(args) > 0 && args[0] == "cluster-info":
+			return "", "", nil
+		case len(args) > 1 && args[0] == "config" && args[1] == "current-context":
+			return Context, "", nil
+		}
+		return "", "", fmt.Errorf("no fixture for kubectl %s", strings.Join(args, " "))
+	}
+	return r.ExecRunner.Run(ctx, name, args)
+}
+
+// LookPath finds flux and kubectl whether or not they are installed
+func (r Runner) LookPath(name string) (string, error) {
+	if name == "flux" || name == "kubectl" {
+		return name, nil
+	}
+	return r.ExecRunner.LookPath(name)
+}
+
+// Builder builds kustomizations from the fixtures
+type Builder struct{}
+
+func (Builder) Build(_ filesys.FileSystem, path string) ([]byte, error) {
+	return Kustomize(path)
+}
@@ -0,0 +1,114 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package kustomize
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+
+	"sigs.k8s.io/kustomize/kyaml/filesys"
+)
+
+// recordingFs notes every path a build looks at, whether or not it
+// exists. Together these are the inputs of the build: resources,
+// patches, generator files and helm values alike
+type recordingFs struct {
+	filesys.FileSystem
+	sync.Mutex
+	paths map[string]bool
+}
+
+func (r *recordingFs) record(path string) {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	r.Lock()
+	defer r.Unlock()
+	r.paths[path] = true
+}
+
+func (r *recordingFs) Open(path string) (filesys.File, error) {
+	r.record(path)
+	return r.FileSystem.Open(path)
+}
+
+func (r *recordingFs) IsDir(path string) bool {
+	r.record(path)
+	return r.FileSystem.IsDir(path)
+}
+
+func (r *recordingFs) ReadDir(path string) ([]string, error) {
+	r.record(path)
+	return r.FileSystem.ReadDir(path)
+}
+
+func (r *recordingFs) Exists(path string) bool {
+	r.record(path)
+	return r.FileSystem.Exists(path)
+}
+
+func (r *recordingFs) Glob(pattern string) ([]string, error) {
+	r.record(filepath.Dir(pattern))
+	matches, err := r.FileSystem.Glob(pattern)
+	for _, match := range matches {
+		r.record(match)
+	}
+	return matches, err
+}
+
+func (r *recordingFs) ReadFile(path string) ([]byte, error) {
+	r.record(path)
+	return r.FileSystem.ReadFile(path)
+}
+
+func (r *recordingFs) Walk(path string, walkFn filepath.WalkFunc) error {
+	r.record(path)
+	return r.FileSystem.Walk(path, func(path string, info os.FileInfo, err error) error {
+		r.record(path)
+		return walkFn(path, info, err)
+	})
+}
+
+// recorded returns the paths seen, sorted
+func (r *recordingFs) recorded() []string {
+	r.Lock()
+	defer r.Unlock()
+	paths := make([]string, 0, len(r.paths))
+	for path := range r.paths {
+		paths = append(paths, path)
+	}
+	slices.Sort(paths)
+	return paths
+}
+
+// ExecKustomizeInputs builds the kustomization at path the same as
+// ExecKustomize and also returns every file and directory the build
+// read from, so callers can tell when it needs building again
+func ExecKustomizeInputs(path string) ([]byte, []string, error) {
+	fsys := &recordingFs{
+		FileSystem: filesys.MakeFsOnDisk(),
+		paths:      make(map[string]bool),
+	}
+	fsys.record(path)
+	content, err := execKustomize(fsys, path)
+	return content, fsys.recorded(), err
+}
@@ -0,0 +1,64 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package kustomize
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestExecKustomizeInputs(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		"app/kustomization.yaml": "resources:\n  - ../base\n" +
+			"patches:\n  - path: ../patches/colour.yaml\n" +
+			"configMapGenerator:\n  - name: settings\n    files:\n      - ../config/settings.ini\n",
+		"base/kustomization.yaml": "resources:\n  - configmap.yaml\n",
+		"base/configmap.yaml":     "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: app\ndata:\n  colour: blue\n",
+		"patches/colour.yaml":     "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: app\ndata:\n  colour: red\n",
+		"config/settings.ini":     "debug = true\n",
+		"unrelated/notes.yaml":    "kind: ConfigMap\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	_, inputs, err := ExecKustomizeInputs(filepath.Join(root, "app"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"app/kustomization.yaml", "base/configmap.yaml",
+		"patches/colour.yaml", "config/settings.ini"} {
+		if !slices.Contains(inputs, filepath.Join(root, name)) {
+			t.Errorf("expected %s to be an input, got %v", name, inputs)
+		}
+	}
+	if slices.Contains(inputs, filepath.Join(root, "unrelated", "notes.yaml")) {
+		t.Errorf("expected unrelated files not to be inputs, got %v", inputs)
+	}
+}
@@ -20,12 +20,14 @@
 package kustomize
 
 import (
+	"bytes"
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
+	"strings"
 
 	"github.com/charmbracelet/log"
+	"github.com/mproffitt/delorian/pkg/components"
 	"github.com/mproffitt/delorian/pkg/yaml"
 	v3 "gopkg.in/yaml.v3"
 	"sigs.k8s.io/kustomize/api/krusty"
@@ -33,13 +35,96 @@ import (
 	"sigs.k8s.io/kustomize/kyaml/filesys"
 )
 
+const Kustomization = "kustomization"
+
+// Group is the api group of kustomization files read by kustomize
+const Group = "kustomize.config.k8s.io"
+
+var (
+	// LoadRestrictions controls whether kustomizations may read files
+	// outside of their own root.
+	//
+	// This defaults to LoadRestrictionsNone as many flux repositories
+	// reference shared bases in sibling directories. Setting it to
+	// LoadRestrictionsRootOnly is safer when browsing untrusted
+	// repositories as a kustomization could otherwise read any file
+	// the current user has access to.
+	LoadRestrictions = types.LoadRestrictionsNone
+
+	// PluginRestrictions controls which kustomize plugins may be loaded.
+	//
+	// By default only the builtin plugins are available. Relaxing this
+	// to PluginRestrictionsNone enables alpha plugins
+	PluginRestrictions = types.PluginRestrictionsBuiltinsOnly
+
+	// EnableExec allows kustomize function plugins to run arbitrary
+	// executables and access the network.
+	//
+	// Exec plugins run with the privileges of the current user and
+	// should only be enabled for repositories that are fully trusted.
+	// Enabling exec also requires alpha plugins so PluginRestrictions
+	// is relaxed at the same time
+	EnableExec = false
+
+	// Helm controls the helm binary used when inflating helm charts.
+	//
+	// HelmAuto uses helm if it can be found in PATH, HelmOff disables
+	// helm entirely and any other value is treated as the path to
+	// the helm binary to use
+	Helm = HelmAuto
+)
+
 const (
-	Kustomization      = "kustomization"
-	loadRestrictor     = types.LoadRestrictionsNone
-	enableAlphaPlugins = false
+	HelmAuto = "auto"
+	HelmOff  = "off"
 )
 
+// ParseLoadRestrictions converts the name of a load restrictor as
+// accepted by `kustomize build --load-restrictor`
+func ParseLoadRestrictions(s string) (types.LoadRestrictions, error) {
+	switch s {
+	case types.LoadRestrictionsNone.String():
+		return types.LoadRestrictionsNone, nil
+	case types.LoadRestrictionsRootOnly.String():
+		return types.LoadRestrictionsRootOnly, nil
+	}
+	return types.LoadRestrictionsUnknown, fmt.Errorf(
+		"unknown load restrictor %q (must be one of %s, %s)", s,
+		types.LoadRestrictionsRootOnly, types.LoadRestrictionsNone)
+}
+
 func ExecKustomize(path string) ([]byte, error) {
+	return execKustomize(filesys.MakeFsOnDisk(), path)
+}
+
+// Builder renders the kustomization at path, reading its files
+// from fsys. It is replaced to test build flows without krusty
+type Builder interface {
+	Build(fsys filesys.FileSystem, path string) ([]byte, error)
+}
+
+// KrustyBuilder builds kustomizations in process with krusty. It
+// is used unless another builder has been set
+type KrustyBuilder struct{}
+
+var builder Builder = KrustyBuilder{}
+
+// SetBuilder replaces the builder used by ExecKustomize and
+// ExecKustomizeOverlay. A nil builder restores the default
+func SetBuilder(b Builder) {
+	if b == nil {
+		b = KrustyBuilder{}
+	}
+	builder = b
+}
+
+func execKustomize(fsys filesys.FileSystem, path string) ([]byte, error) {
+	return builder.Build(fsys, path)
+}
+
+// Build runs krusty against path with the load, plugin and helm
+// settings configured from the command line
+func (KrustyBuilder) Build(fsys filesys.FileSystem, path string) ([]byte, error) {
 	helm := findHelm()
 	// Kustomize prints deprecation warnings to Stderr that are
 	// not trapped by bubbletea and interfere with the UI.
@@ -53,29 +138,32 @@ func ExecKustomize(path string) ([]byte, error) {
 		os.Stderr = o
 	}()
 	os.Stderr = devNull
+	// Exec plugins write their own diagnostics to Stderr. These
+	// inherit the redirected Stderr above whilst failures are still
+	// returned by Run as an error
 	options := krusty.Options{
 		Reorder:           krusty.ReorderOptionNone,
 		AddManagedbyLabel: false,
-		LoadRestrictions:  loadRestrictor,
+		LoadRestrictions:  LoadRestrictions,
 
 		PluginConfig: &types.PluginConfig{
-			PluginRestrictions: types.PluginRestrictionsBuiltinsOnly,
+			PluginRestrictions: pluginRestrictions(),
 			BpLoadingOptions:   types.BploUseStaticallyLinked,
 			FnpLoadingOptions: types.FnPluginLoadingOptions{ // These are the defaults from the flags to kustomize
-				EnableExec:    false,
-				Network:       false,
+				EnableExec:    EnableExec,
+				Network:       EnableExec,
 				NetworkName:   "bridge",
 				Mounts:        []string{},
 				AsCurrentUser: false,
 			},
-			// Helm is enabled only if it's found in path
+			// Helm is enabled only if it's found in path or
+			// has been explicitly configured
 			HelmConfig: types.HelmConfig{
 				Enabled: helm != "",
 				Command: helm,
 			},
 		},
 	}
-	fsys := filesys.MakeFsOnDisk()
 	k := krusty.MakeKustomizer(&options)
 	m, err := k.Run(fsys, path)
 	if err != nil {
@@ -95,8 +183,14 @@ func FilterKustomization(input []byte, opts ...string) ([]byte, error) {
 
 // FilterGitRepository is a convenience wrapper to filter for targetting GitRepository types
 func FilterGitRepository(input []byte, opts ...string) ([]byte, error) {
+	return FilterSource(input, "GitRepository", opts...)
+}
+
+// FilterSource is a convenience wrapper to filter for targetting
+// sources of the given kind, such as OCIRepository
+func FilterSource(input []byte, kind string, opts ...string) ([]byte, error) {
 	options := []string{
-		".kind", "GitRepository",
+		".kind", kind,
 	}
 	options = append(options, opts...)
 	return yaml.Filter(input, options...)
@@ -117,23 +211,107 @@ func GetKustomization(path string) (string, *types.Kustomization) {
 	if err != nil {
 		return "", nil
 	}
-	var kustomization types.Kustomization
-	err = v3.Unmarshal(content, &kustomization)
-	if err != nil {
-		return "", nil
+	// The file may hold other documents, such as flux
+	// kustomizations, before the one kustomize reads
+	dec := v3.NewDecoder(bytes.NewReader(content))
+	for {
+		var kustomization types.Kustomization
+		if err := dec.Decode(&kustomization); err != nil {
+			return "", nil
+		}
+		if IsKustomization(kustomization.APIVersion, kustomization.Kind) {
+			return sigskustpath, &kustomization
+		}
+	}
+}
+
+// IsKustomization reports if a document with the given apiVersion
+// and kind is read by kustomize. Flux uses the same kind in its own
+// group. kustomization.yaml files often leave out apiVersion and
+// kind altogether
+func IsKustomization(apiVersion, kind string) bool {
+	if apiVersion == "" {
+		return kind == "" || kind == "Kustomization"
 	}
+	group, _, _ := strings.Cut(apiVersion, "/")
+	return group == Group && (kind == "Kustomization" || kind == "Component")
+}
 
-	return sigskustpath, &kustomization
+// pluginRestrictions returns the effective plugin restrictions.
+// Exec plugins are not builtins and cannot be loaded unless the
+// restrictions are lifted
+func pluginRestrictions() types.PluginRestrictions {
+	if EnableExec {
+		return types.PluginRestrictionsNone
+	}
+	return PluginRestrictions
+}
+
+// BuildFlags returns the `kustomize build` flags matching the
+// options delorian builds kustomizations with
+func BuildFlags() []string {
+	flags := []string{"--load-restrictor " + LoadRestrictions.String()}
+	if helm := findHelm(); helm != "" {
+		flags = append(flags, "--enable-helm --helm-command "+helm)
+	}
+	if pluginRestrictions() == types.PluginRestrictionsNone {
+		flags = append(flags, "--enable-alpha-plugins")
+	}
+	if EnableExec {
+		flags = append(flags, "--enable-exec --network")
+	}
+	return flags
+}
+
+// ValidateHelm checks that the given helm setting is either one
+// of HelmAuto or HelmOff, or is the path to an executable file
+func ValidateHelm(helm string) error {
+	switch helm {
+	case HelmAuto, HelmOff:
+		return nil
+	}
+	if _, err := components.LookPath(helm); err != nil {
+		return fmt.Errorf("helm binary %q is not executable: %w", helm, err)
+	}
+	return nil
+}
+
+// HelmDescription describes the effective helm configuration
+func HelmDescription() string {
+	helm := findHelm()
+	switch {
+	case Helm == HelmOff:
+		return "disabled (forced off)"
+	case helm == "":
+		return "disabled (helm not found in PATH)"
+	case Helm == HelmAuto:
+		return fmt.Sprintf("enabled (auto-detected %s)", helm)
+	}
+	return fmt.Sprintf("enabled (%s)", helm)
+}
+
+// HelmAvailable reports if a helm binary will be used to inflate
+// helm charts
+func HelmAvailable() bool {
+	return findHelm() != ""
 }
 
 func findHelm() string {
-	helm, err := exec.LookPath("helm")
+	switch Helm {
+	case HelmOff:
+		return ""
+	case HelmAuto:
+	default:
+		return Helm
+	}
+
+	helm, err := components.LookPath("helm")
 	if err == nil {
 		return helm
 	}
 	// kustomize references this has helmV3 so lets check
 	// that one for safety too
-	helm, err = exec.LookPath("helmV3")
+	helm, err = components.LookPath("helmV3")
 	if err == nil {
 		return helm
 	}
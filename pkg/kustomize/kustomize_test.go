@@ -0,0 +1,168 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package kustomize
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/kustomize/kyaml/filesys"
+)
+
+func TestBuildWithExec(t *testing.T) {
+	previous := EnableExec
+	defer func() { EnableExec = previous }()
+	EnableExec = true
+
+	dir := t.TempDir()
+	content := "resources:\n  - missing.yaml\n"
+	if err := os.WriteFile(filepath.Join(dir, "kustomization.yaml"), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	stderr := os.Stderr
+	if _, err := (KrustyBuilder{}).Build(filesys.MakeFsOnDisk(), dir); err == nil {
+		t.Error("expected the failing build to return its error")
+	}
+	if os.Stderr != stderr {
+		t.Error("expected stderr to be restored after the build")
+	}
+}
+
+func TestParseLoadRestrictions(t *testing.T) {
+	for name, tc := range map[string]struct {
+		value   string
+		want    types.LoadRestrictions
+		wantErr bool
+	}{
+		"root only": {value: "LoadRestrictionsRootOnly", want: types.LoadRestrictionsRootOnly},
+		"none":      {value: "LoadRestrictionsNone", want: types.LoadRestrictionsNone},
+		"empty":     {value: "", want: types.LoadRestrictionsUnknown, wantErr: true},
+		"unknown":   {value: "rootOnly", want: types.LoadRestrictionsUnknown, wantErr: true},
+	} {
+		t.Run(name, func(t *testing.T) {
+			got, err := ParseLoadRestrictions(tc.value)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("expected error %t, got %v", tc.wantErr, err)
+			}
+			if got != tc.want {
+				t.Errorf("expected %s, got %s", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestPluginRestrictions(t *testing.T) {
+	previous, exec := PluginRestrictions, EnableExec
+	defer func() { PluginRestrictions, EnableExec = previous, exec }()
+
+	for name, tc := range map[string]struct {
+		alpha, exec bool
+		want        types.PluginRestrictions
+	}{
+		"builtins only":        {want: types.PluginRestrictionsBuiltinsOnly},
+		"enable alpha plugins": {alpha: true, want: types.PluginRestrictionsNone},
+		"enable exec":          {exec: true, want: types.PluginRestrictionsNone},
+	} {
+		t.Run(name, func(t *testing.T) {
+			// As set by --enable-alpha-plugins and --enable-exec
+			PluginRestrictions = types.PluginRestrictionsBuiltinsOnly
+			if tc.alpha {
+				PluginRestrictions = types.PluginRestrictionsNone
+			}
+			EnableExec = tc.exec
+
+			if got := pluginRestrictions(); got != tc.want {
+				t.Errorf("expected %s, got %s", tc.want, got)
+			}
+			alpha := slices.Contains(BuildFlags(), "--enable-alpha-plugins")
+			if want := tc.want == types.PluginRestrictionsNone; alpha != want {
+				t.Errorf("expected --enable-alpha-plugins in the build flags %t, got %v", want, BuildFlags())
+			}
+		})
+	}
+}
+
+// executable writes an executable named name into dir
+func executable(t *testing.T, dir, name string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestValidateHelm(t *testing.T) {
+	helm := executable(t, t.TempDir(), "helm")
+	for name, tc := range map[string]struct {
+		value   string
+		wantErr bool
+	}{
+		"auto":          {value: HelmAuto},
+		"off":           {value: HelmOff},
+		"explicit path": {value: helm},
+		"missing path":  {value: filepath.Join(t.TempDir(), "helm"), wantErr: true},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if err := ValidateHelm(tc.value); (err != nil) != tc.wantErr {
+				t.Errorf("expected error %t, got %v", tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestFindHelm(t *testing.T) {
+	previous := Helm
+	defer func() { Helm = previous }()
+
+	bin := t.TempDir()
+	found := executable(t, bin, "helm")
+	explicit := executable(t, t.TempDir(), "helm")
+	for name, tc := range map[string]struct {
+		helm, path  string
+		want        string
+		description string
+	}{
+		"off":             {helm: HelmOff, path: bin, description: "disabled (forced off)"},
+		"explicit path":   {helm: explicit, path: t.TempDir(), want: explicit, description: "enabled (" + explicit + ")"},
+		"found in PATH":   {helm: HelmAuto, path: bin, want: found, description: "enabled (auto-detected " + found + ")"},
+		"missing in PATH": {helm: HelmAuto, path: t.TempDir(), description: "disabled (helm not found in PATH)"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv("PATH", tc.path)
+			Helm = tc.helm
+			if got := findHelm(); got != tc.want {
+				t.Errorf("expected helm %q, got %q", tc.want, got)
+			}
+			if got := HelmDescription(); got != tc.description {
+				t.Errorf("expected %q, got %q", tc.description, got)
+			}
+			wantFlag := tc.want != ""
+			if got := strings.Contains(strings.Join(BuildFlags(), " "), "--enable-helm"); got != wantFlag {
+				t.Errorf("expected --enable-helm in the build flags %t, got %v", wantFlag, BuildFlags())
+			}
+		})
+	}
+}
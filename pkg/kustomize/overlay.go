@@ -0,0 +1,85 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package kustomize
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+
+	"sigs.k8s.io/kustomize/kyaml/filesys"
+)
+
+// overlayFs reads the given files from memory and everything else
+// from disk so edits can be built without writing them out
+type overlayFs struct {
+	filesys.FileSystem
+	files map[string][]byte
+}
+
+func (o overlayFs) ReadFile(path string) ([]byte, error) {
+	if abs, err := filepath.Abs(path); err == nil {
+		if content, ok := o.files[abs]; ok {
+			return content, nil
+		}
+	}
+	return o.FileSystem.ReadFile(path)
+}
+
+// ExecKustomizeOverlay builds the kustomization at path using the
+// content in files, keyed by absolute path, in place of those files
+// on disk. Nothing is written to disk
+func ExecKustomizeOverlay(path string, files map[string][]byte) ([]byte, error) {
+	return execKustomize(overlayFs{
+		FileSystem: filesys.MakeFsOnDisk(),
+		files:      files,
+	}, path)
+}
+
+// OverlayFiles returns the kustomization file in dir followed by
+// any local patch files it lists. These are the files that change
+// what an overlay renders
+func OverlayFiles(dir string) []string {
+	file, kust := GetKustomization(filepath.Join(dir, Kustomization))
+	if kust == nil {
+		return nil
+	}
+	files := []string{file}
+	add := func(name string) {
+		if name == "" {
+			return
+		}
+		path := filepath.Join(dir, name)
+		if fi, err := os.Stat(path); err != nil || !fi.Mode().IsRegular() {
+			return
+		}
+		if !slices.Contains(files, path) {
+			files = append(files, path)
+		}
+	}
+	for _, p := range kust.Patches {
+		add(p.Path)
+	}
+	for _, p := range kust.PatchesStrategicMerge {
+		// Inline patches are part of the kustomization file
+		add(string(p))
+	}
+	return files
+}
@@ -0,0 +1,83 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package kustomize
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kustomize/kyaml/filesys"
+)
+
+func TestExecKustomizeOverlay(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"kustomization.yaml": "resources:\n  - configmap.yaml\npatches:\n  - path: patch.yaml\n",
+		"configmap.yaml":     "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: app\ndata:\n  colour: blue\n",
+		"patch.yaml":         "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: app\ndata:\n  size: small\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	overlay := OverlayFiles(dir)
+	if len(overlay) != 2 || filepath.Base(overlay[1]) != "patch.yaml" {
+		t.Fatalf("expected the kustomization and patch, got %v", overlay)
+	}
+
+	patch := filepath.Join(dir, "patch.yaml")
+	edited := strings.ReplaceAll(files["patch.yaml"], "small", "large")
+	out, err := ExecKustomizeOverlay(dir, map[string][]byte{patch: []byte(edited)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(out), "size: large") {
+		t.Errorf("expected the edited patch to be used, got\n%s", out)
+	}
+
+	onDisk, _ := os.ReadFile(patch)
+	if string(onDisk) != files["patch.yaml"] {
+		t.Error("expected the file on disk to be left alone")
+	}
+}
+
+// overlayBuilder reads the kustomization file instead of building
+type overlayBuilder struct{}
+
+func (overlayBuilder) Build(fsys filesys.FileSystem, path string) ([]byte, error) {
+	return fsys.ReadFile(filepath.Join(path, "kustomization.yaml"))
+}
+
+func TestSetBuilder(t *testing.T) {
+	defer SetBuilder(nil)
+	SetBuilder(overlayBuilder{})
+	path := filepath.Join(t.TempDir(), "kustomization.yaml")
+	out, err := ExecKustomizeOverlay(filepath.Dir(path), map[string][]byte{path: []byte("resources: []\n")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(out) != "resources: []\n" {
+		t.Errorf("expected the builder to see the overlay, got %q", out)
+	}
+}
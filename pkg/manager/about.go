@@ -0,0 +1,73 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package manager
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mproffitt/delorian/pkg/kustomize"
+	"github.com/mproffitt/delorian/pkg/repo/flux"
+)
+
+// AboutWidth is the width of the about dialog
+const AboutWidth = 64
+
+// about describes the application and the effective build
+// configuration so differences between machines can be spotted
+func about() string {
+	lines := []string{
+		"delorian - Flux Build and Diff UI",
+		"",
+	}
+	for _, s := range settings() {
+		lines = append(lines, fmt.Sprintf("%-20s %s", s[0]+":", s[1]))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// settings are the effective build settings as label, value pairs
+func settings() [][2]string {
+	return [][2]string{
+		{"Load restrictions", kustomize.LoadRestrictions.String()},
+		{"Plugin restrictions", kustomize.PluginRestrictions.String()},
+		{"Exec plugins", execDescription()},
+		{"Helm", kustomize.HelmDescription()},
+		{"Flux build flags", flagsDescription(flux.BuildFlags)},
+		{"Flux diff flags", flagsDescription(flux.DiffFlags)},
+		{"Base markers", strings.Join(flux.BaseMarkers, " ")},
+	}
+}
+
+// execDescription describes if kustomize exec plugins are enabled
+func execDescription() string {
+	if kustomize.EnableExec {
+		return "enabled (exec and network access allowed)"
+	}
+	return "disabled"
+}
+
+// flagsDescription describes extra flags passed to flux
+func flagsDescription(flags []string) string {
+	if len(flags) == 0 {
+		return "none"
+	}
+	return strings.Join(flags, " ")
+}
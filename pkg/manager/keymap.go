@@ -27,14 +27,24 @@ import (
 )
 
 type keyMap struct {
+	About    key.Binding
+	Compact  key.Binding
 	CtrlN    key.Binding
 	CtrlS    key.Binding
 	Delete   key.Binding
 	Enter    key.Binding
+	Goto     key.Binding
 	Help     key.Binding
+	Profile  key.Binding
 	Quit     key.Binding
+	Recent   key.Binding
+	Report   key.Binding
+	Rescan   key.Binding
 	ShiftTab key.Binding
+	Sidebar  key.Binding
 	Tab      key.Binding
+	Undo     key.Binding
+	Watch    key.Binding
 }
 
 func (k *keyMap) ShortHelp() []key.Binding {
@@ -44,16 +54,20 @@ func (k *keyMap) ShortHelp() []key.Binding {
 func (k *keyMap) FullHelp() [][]key.Binding {
 	return [][]key.Binding{
 		{
-			k.CtrlN, k.CtrlS, k.Delete, k.Enter, k.Help,
+			k.About, k.Compact, k.CtrlN, k.CtrlS, k.Delete, k.Enter, k.Goto, k.Help,
 		},
 		{
-			k.Quit, k.ShiftTab, k.Tab,
+			k.Profile, k.Quit, k.Recent, k.Report, k.Rescan, k.ShiftTab, k.Sidebar, k.Tab, k.Undo, k.Watch,
 		},
 	}
 }
 
 func mapKeys() *keyMap {
 	return &keyMap{
+		About: key.NewBinding(key.WithKeys("f2"),
+			key.WithHelp("f2", "About delorian")),
+		Compact: key.NewBinding(key.WithKeys("ctrl+o"),
+			key.WithHelp("ctrl+o", "Toggle compact single pane mode")),
 		CtrlN: key.NewBinding(key.WithKeys("ctrl+n"),
 			key.WithHelp("ctrl+n", "Create new session")),
 		CtrlS: key.NewBinding(key.WithKeys("ctrl+s"),
@@ -62,15 +76,30 @@ func mapKeys() *keyMap {
 			key.WithHelp("del/x", "Delete current item")),
 		Enter: key.NewBinding(key.WithKeys("enter"),
 			key.WithHelp(icons.Enter, "Select current item")),
+		Goto: key.NewBinding(key.WithKeys("ctrl+g"),
+			key.WithHelp("ctrl+g", "Go to a kustomization")),
 		Help: key.NewBinding(key.WithKeys("?", "f1"),
 			key.WithHelp("?", "Help")),
-
+		Profile: key.NewBinding(key.WithKeys("ctrl+p"),
+			key.WithHelp("ctrl+p", "Switch profile")),
 		Quit: key.NewBinding(key.WithKeys("ctrl+c", "esc"),
 			key.WithHelp("esc", "Close overlays or Quit")),
+		Recent: key.NewBinding(key.WithKeys("ctrl+t"),
+			key.WithHelp("ctrl+t", "Switch to a recently viewed kustomization")),
+		Report: key.NewBinding(key.WithKeys("ctrl+e"),
+			key.WithHelp("ctrl+e", "Export a bug report")),
+		Rescan: key.NewBinding(key.WithKeys("ctrl+r"),
+			key.WithHelp("ctrl+r", "Rescan the repository")),
 		ShiftTab: key.NewBinding(key.WithKeys("shift+tab"),
 			key.WithHelp(icons.ShiftTab, "Previous pane")),
+		Sidebar: key.NewBinding(key.WithKeys("ctrl+b"),
+			key.WithHelp("ctrl+b", "Reveal the sidebar on narrow terminals")),
 		Tab: key.NewBinding(key.WithKeys("tab"),
 			key.WithHelp(icons.Tab, "Next pane")),
+		Undo: key.NewBinding(key.WithKeys("ctrl+z"),
+			key.WithHelp("ctrl+z", "Undo the last filter or toggle")),
+		Watch: key.NewBinding(key.WithKeys("ctrl+w"),
+			key.WithHelp("ctrl+w", "Start / pause watching for drift")),
 	}
 }
 
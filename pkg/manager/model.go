@@ -20,7 +20,11 @@
 package manager
 
 import (
+	"io"
 	"os"
+	"path/filepath"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/charmbracelet/bubbles/key"
@@ -29,11 +33,17 @@ import (
 	"github.com/charmbracelet/lipgloss"
 	"github.com/charmbracelet/log"
 	zone "github.com/lrstanley/bubblezone"
+	"github.com/mproffitt/bmx/pkg/components/dialog"
 	"github.com/mproffitt/bmx/pkg/components/overlay"
 	"github.com/mproffitt/bmx/pkg/components/toast"
 	"github.com/mproffitt/delorian/pkg/components"
+	"github.com/mproffitt/delorian/pkg/components/splash"
+	"github.com/mproffitt/delorian/pkg/components/statusbar"
 	"github.com/mproffitt/delorian/pkg/components/tabview"
+	"github.com/mproffitt/delorian/pkg/components/treeview"
 	"github.com/mproffitt/delorian/pkg/components/yamlview"
+	"github.com/mproffitt/delorian/pkg/config"
+	"github.com/mproffitt/delorian/pkg/notify"
 	fluxrepo "github.com/mproffitt/delorian/pkg/repo/flux"
 	"github.com/mproffitt/delorian/pkg/theme"
 )
@@ -46,42 +56,92 @@ const (
 )
 
 type Model struct {
-	height int
-	keymap *keyMap
-	layout layout
-	width  int
-	focus  Focus
+	config   *config.Config
+	height   int
+	keymap   *keyMap
+	layout   layout
+	notifier *notify.Notifier
+	roots    []string
+	undo     components.UndoStack
+	width    int
+	focus    Focus
+
+	// profileErr holds why the profile active at startup
+	// couldn't be scanned, reported once the program starts
+	profileErr error
 }
 
 type layout struct {
-	sidebar tea.Model
-	primary tea.Model
-	toasts  []*toast.Model
-	fatal   *toast.Model
+	compact   bool
+	dialog    tea.Model
+	profiles  *profilePicker
+	reveal    bool
+	sidebar   tea.Model
+	sideWidth int
+	primary   tea.Model
+	statusbar tea.Model
+	toasts    []*toast.Model
+	fatal     *toast.Model
 }
 
 // The maximum number of toast messages
 // we display at any given time
 const MaxToasts = 10
 
-func New() *Model {
-	rootPath, _ := os.Getwd()
+// New creates the manager for the given repository roots.
+// If no roots are provided, the current directory is used
+func New(cfg *config.Config, roots ...string) *Model {
+	if len(roots) == 0 {
+		rootPath, _ := os.Getwd()
+		roots = []string{rootPath}
+	}
+	// Normalised in a copy so the caller's slice is left alone
+	roots = slices.Clone(roots)
+	for i := range roots {
+		if abs, err := filepath.Abs(roots[i]); err == nil {
+			roots[i] = abs
+		}
+	}
+	repo, err := newSidebar(cfg, roots)
+	if err != nil {
+		repo = fluxrepo.New(roots...)
+	}
 	m := Model{
-		keymap: mapKeys(),
+		config:     cfg,
+		profileErr: err,
+		keymap:     mapKeys(),
+		notifier:   notify.New(notify.DefaultMethod),
+		roots:      roots,
 		layout: layout{
-			sidebar: fluxrepo.New(rootPath),
-			primary: tabview.New(),
-			toasts:  make([]*toast.Model, 0, MaxToasts),
+			sidebar: repo,
+			primary: tabview.New(splash.Branding{
+				Title:    cfg.Branding.Title,
+				Subtitle: cfg.Branding.Subtitle,
+				HideLogo: !cfg.Branding.ShowLogo(),
+			}),
+			statusbar: statusbar.New(cfg.Branding.Title).
+				SetSubtitle(cfg.Branding.Subtitle),
+			toasts: make([]*toast.Model, 0, MaxToasts),
 		},
 	}
+	m.setCompact(cfg.State.Compact)
 	return &m
 }
 
 func (m *Model) Init() tea.Cmd {
-	return tea.Batch(
+	cmds := []tea.Cmd{
 		m.layout.sidebar.Init(),
 		m.layout.primary.Init(),
-	)
+		m.layout.statusbar.Init(),
+		components.ClusterCheckCmd(),
+		profileStatusCmd(m.config),
+		profileFiltersCmd(m.config),
+		preflightCmd(),
+	}
+	if m.profileErr != nil {
+		cmds = append(cmds, components.ModelErrorCmd(m.profileErr))
+	}
+	return tea.Batch(cmds...)
 }
 
 func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -95,11 +155,15 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		log.Error("model", "error", msg.Error)
 		// forward the error to the primary view
 		m.layout.primary, _ = m.layout.primary.Update(msg)
-		cmd = toast.NewToastCmd(toast.Error, msg.Error.Error())
+		message := msg.Error.Error()
+		if e, ok := msg.Error.(*components.FluxError); ok {
+			message = e.Summary
+		}
+		cmd = toast.NewToastCmd(toast.Error, message)
 	case components.ModelFatalMsg:
 		m.layout.fatal = toast.New(toast.Error, msg.Error.Error()).
 			SetTickDuration(45 * time.Millisecond).
-			SetCompletionCommand(tea.Quit)
+			SetCompletionCommand(m.quit)
 		cmd = m.layout.fatal.Init()
 	case tea.WindowSizeMsg:
 		cmd = m.resize(msg)
@@ -144,14 +208,58 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.layout.toasts = newToasts
 		cmd = tea.Batch(cmds...)
 	case tea.MouseMsg:
-		switch m.focus {
-		case sidebar:
-			m.layout.sidebar, cmd = m.layout.sidebar.Update(msg)
-		case primary:
+		m.followMouse(msg)
+		switch {
+		case m.focus == sidebar, msg.Action == tea.MouseActionMotion:
+			// The sidebar previews whatever the mouse rests on
+			// wherever the focus is
+			var sc, pc tea.Cmd
+			m.layout.sidebar, sc = m.layout.sidebar.Update(msg)
+			if m.focus == primary {
+				m.layout.primary, pc = m.layout.primary.Update(msg)
+			}
+			cmd = tea.Batch(sc, pc)
+		case m.focus == primary:
 			m.layout.primary, cmd = m.layout.primary.Update(msg)
 		}
 
-	case components.TabChangedMsg:
+	case components.ClusterStatusMsg:
+		// The cluster status affects every part of the UI
+		var sc, pc tea.Cmd
+		m.layout.statusbar, _ = m.layout.statusbar.Update(msg)
+		m.layout.sidebar, sc = m.layout.sidebar.Update(msg)
+		m.layout.primary, pc = m.layout.primary.Update(msg)
+		cmd = tea.Batch(sc, pc)
+	case dialog.DialogStatusMsg:
+		if msg.Done {
+			m.layout.dialog = nil
+		}
+	case components.OpenDialogMsg:
+		m.layout.dialog = msg.Dialog
+		cmd = m.layout.dialog.Init()
+	case components.StatusMsg:
+		m.layout.statusbar, cmd = m.layout.statusbar.Update(msg)
+	case components.UndoPushMsg:
+		m.undo.Push(msg.Action)
+	case components.RescanMsg:
+		// Actions refer to kustomizations the rescan replaces
+		m.undo.Clear()
+		m.layout.sidebar, cmd = m.layout.sidebar.Update(msg)
+	case components.WatchTickMsg, components.ImageScopeMsg,
+		components.SubstitutionsMsg, components.OverlayEditMsg,
+		components.ResourceDiffMsg, components.RecursiveDiffMsg,
+		components.DefinitionMsg, components.NoteMsg,
+		components.PollMsg, components.FilesChangedMsg,
+		fluxrepo.SelectMsg,
+		treeview.SelectMsg, fluxrepo.LabelMsg, fluxrepo.PeekMsg,
+		fluxrepo.ScanDoneMsg, fluxrepo.ScanCheckMsg, fluxrepo.ScanStopMsg,
+		fluxrepo.LiveMsg:
+		m.layout.sidebar, cmd = m.layout.sidebar.Update(msg)
+	case components.DriftDetectedMsg:
+		cmd = m.notifier.NotifyCmd("delorian: new drift detected",
+			strings.Join(msg.Titles, "\n"))
+
+	case components.TabChangedMsg, components.ShowFileMsg:
 		// These messages need to go to both the sidebar and
 		// the primary view
 		var sc, pc tea.Cmd
@@ -160,8 +268,14 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		cmd = tea.Batch(sc, pc)
 
 	default:
-		// Everything else, send to the primary view
+		// Everything else, send to the primary view. An open dialog
+		// also receives these so forms can process their own commands
+		var dc tea.Cmd
+		if m.layout.dialog != nil {
+			m.layout.dialog, dc = m.layout.dialog.Update(msg)
+		}
 		m.layout.primary, cmd = m.layout.primary.Update(msg)
+		cmd = tea.Batch(cmd, dc)
 	}
 	return m, cmd
 }
@@ -172,13 +286,38 @@ func (m *Model) View() string {
 		view = lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, view)
 		return view
 	}
-	view := viewport.New(m.width-theme.Padding, m.height)
+	view := viewport.New(m.width-theme.Padding, m.height-statusbar.Height)
 	sidebar := m.layout.sidebar.View()
 	primary := m.layout.primary.View()
 
 	content := lipgloss.JoinHorizontal(lipgloss.Top, sidebar, primary)
+	if m.sidebarHidden() {
+		content = primary
+		if m.layout.reveal {
+			content = overlay.PlaceOverlay(0, 0, sidebar, content, false)
+		}
+	}
 	view.SetContent(content)
-	content = view.View()
+	content = lipgloss.JoinVertical(lipgloss.Left,
+		view.View(), m.layout.statusbar.View())
+	if s, ok := m.layout.sidebar.(*fluxrepo.Model); ok && !m.sidebarHidden() {
+		if peek, y, ok := s.Peek(); ok {
+			y = max(0, min(y, m.height-statusbar.Height-lipgloss.Height(peek)))
+			content = overlay.PlaceOverlay(lipgloss.Width(sidebar), y, peek, content, false)
+		}
+	}
+	if m.layout.dialog != nil {
+		d := m.layout.dialog.View()
+		x := max(0, (m.width-lipgloss.Width(d))/2)
+		y := max(0, (m.height-lipgloss.Height(d))/2)
+		content = overlay.PlaceOverlay(x, y, d, content, false)
+	}
+	if m.layout.profiles != nil {
+		p := m.layout.profiles.view()
+		x := max(0, (m.width-lipgloss.Width(p))/2)
+		y := max(0, (m.height-lipgloss.Height(p))/2)
+		content = overlay.PlaceOverlay(x, y, p, content, false)
+	}
 	if len(m.layout.toasts) > 0 {
 		lastheight := m.height
 		for _, toast := range m.layout.toasts {
@@ -197,11 +336,23 @@ func (m *Model) resize(msg tea.WindowSizeMsg) tea.Cmd {
 	m.width = msg.Width + theme.Padding
 
 	var sidebarWidth, sidebarHeight, primaryWidth, primaryHeight int
-	sidebarWidth = max(fluxrepo.MinListWidth, int(float64(m.width)*.15)) + theme.Padding
-	sidebarHeight = m.height
+	sidebarWidth = max(m.sidebarMinWidth(), int(float64(m.width)*.15)) + theme.Padding
+	sidebarHeight = m.height - statusbar.Height
 	primaryWidth = (m.width - sidebarWidth) - theme.Padding
-	primaryHeight = m.height
+	primaryHeight = m.height - statusbar.Height
+
+	// On narrow terminals the primary view takes the full width
+	// and the sidebar is only shown on request as an overlay
+	if m.sidebarHidden() {
+		primaryWidth = m.width - theme.Padding
+		if !m.layout.reveal {
+			m.focusPrimary()
+		}
+	} else {
+		m.layout.reveal = false
+	}
 
+	m.layout.sideWidth = sidebarWidth
 	if s, ok := m.layout.sidebar.(components.Scalable); ok {
 		m.layout.sidebar = s.SetSize(sidebarWidth, sidebarHeight)
 	}
@@ -209,34 +360,113 @@ func (m *Model) resize(msg tea.WindowSizeMsg) tea.Cmd {
 	if p, ok := m.layout.primary.(components.Scalable); ok {
 		m.layout.primary = p.SetSize(primaryWidth, primaryHeight)
 	}
+
+	if s, ok := m.layout.statusbar.(components.Scalable); ok {
+		m.layout.statusbar = s.SetSize(m.width-theme.Padding, statusbar.Height)
+	}
 	return nil
 }
 
+// quit releases anything the sidebar holds open, such as the file
+// watcher, before the program exits
+func (m *Model) quit() tea.Msg {
+	m.closeSidebar()
+	return tea.Quit()
+}
+
+// closeSidebar releases the resources of the sidebar when it is
+// about to be discarded
+func (m *Model) closeSidebar() {
+	if c, ok := m.layout.sidebar.(io.Closer); ok {
+		if err := c.Close(); err != nil {
+			log.Warn("closing sidebar", "error", err)
+		}
+	}
+}
+
+// undoLast reverts the most recent reversible action
+func (m *Model) undoLast() tea.Cmd {
+	action, ok := m.undo.Pop()
+	if !ok {
+		return toast.NewToastCmd(toast.Info, "Nothing to undo")
+	}
+	return tea.Batch(action.Undo(), toast.NewToastCmd(toast.Info, "Undone: "+action.Name))
+}
+
 func (m *Model) updateKeyMsg(msg tea.KeyMsg) (*Model, tea.Cmd) {
 	var cmd tea.Cmd
+	if m.layout.dialog != nil {
+		m.layout.dialog, cmd = m.layout.dialog.Update(msg)
+		return m, cmd
+	}
+	if m.layout.profiles != nil {
+		if done, name := m.layout.profiles.update(msg); done {
+			m.layout.profiles = nil
+			if name != "" {
+				cmd = m.switchProfile(name)
+			}
+		}
+		return m, cmd
+	}
 	switch {
+	case key.Matches(msg, m.keymap.About):
+		m.layout.dialog = dialog.NewOKDialog(about(), AboutWidth)
+	case key.Matches(msg, m.keymap.Profile):
+		if len(m.config.Profiles) > 0 {
+			m.layout.profiles = newProfilePicker(m.config)
+		}
 	case key.Matches(msg, m.keymap.Quit):
-		cmd = tea.Quit
+		cmd = m.quit
 	case key.Matches(msg, m.keymap.Tab):
 		switch m.focus {
 		case sidebar:
 			m.focus = primary
+			m.layout.reveal = false
 			m.layout.primary.(components.Focus).NextFocus()
 			m.layout.sidebar.(components.Focusable).Blur()
 		case primary:
 			if m.layout.primary.(components.Focus).NextFocus() == yamlview.NoFocus {
+				if !m.sidebarVisible() {
+					m.layout.primary.(components.Focus).NextFocus()
+					break
+				}
 				m.focus = sidebar
 				m.layout.sidebar.(components.Focusable).Focus()
 			}
 		}
+	case key.Matches(msg, m.keymap.Rescan):
+		cmd = tea.Sequence(fluxrepo.ScanningStatusCmd(), components.RescanCmd())
+	case key.Matches(msg, m.keymap.Sidebar):
+		m.toggleSidebar()
+	case key.Matches(msg, m.keymap.Compact):
+		cmd = m.toggleCompact()
+	case key.Matches(msg, m.keymap.Goto):
+		if s, ok := m.layout.sidebar.(*fluxrepo.Model); ok {
+			cmd = s.PickerCmd()
+		}
+	case key.Matches(msg, m.keymap.Recent):
+		if s, ok := m.layout.sidebar.(*fluxrepo.Model); ok {
+			cmd = s.RecentCmd()
+		}
+	case key.Matches(msg, m.keymap.Watch):
+		m.layout.sidebar, cmd = m.layout.sidebar.Update(components.WatchToggleMsg{})
+	case key.Matches(msg, m.keymap.Report):
+		cmd = m.reportCmd()
+	case key.Matches(msg, m.keymap.Undo):
+		cmd = m.undoLast()
 	case key.Matches(msg, m.keymap.ShiftTab):
 		switch m.focus {
 		case sidebar:
 			m.focus = primary
+			m.layout.reveal = false
 			m.layout.primary.(components.Focus).PreviousFocus()
 			m.layout.sidebar.(components.Focusable).Blur()
 		case primary:
 			if m.layout.primary.(components.Focus).PreviousFocus() == yamlview.NoFocus {
+				if !m.sidebarVisible() {
+					m.layout.primary.(components.Focus).PreviousFocus()
+					break
+				}
 				m.focus = sidebar
 				m.layout.sidebar.(components.Focusable).Focus()
 			}
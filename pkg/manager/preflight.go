@@ -0,0 +1,47 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package manager
+
+import (
+	"errors"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mproffitt/bmx/pkg/components/toast"
+	"github.com/mproffitt/delorian/pkg/components"
+	"github.com/mproffitt/delorian/pkg/kustomize"
+)
+
+// preflightCmd checks the binaries delorian runs are installed
+// before they are first needed. flux builds and diffs every
+// kustomization so stops delorian when missing, whereas helm is
+// only needed to inflate charts and is warned about. kustomize is
+// built in so needs no binary
+func preflightCmd() tea.Cmd {
+	if _, err := components.LookPath("flux"); err != nil {
+		return components.ModelFatalCmd(errors.New(
+			"flux was not found in PATH\n" +
+				"install the flux CLI from https://fluxcd.io/flux/installation/"))
+	}
+	if kustomize.Helm == kustomize.HelmAuto && !kustomize.HelmAvailable() {
+		return toast.NewToastCmd(toast.Warning,
+			"helm was not found in PATH\nhelm charts won't be inflated")
+	}
+	return nil
+}
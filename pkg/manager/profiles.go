@@ -0,0 +1,207 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package manager
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mproffitt/delorian/pkg/components"
+	"github.com/mproffitt/delorian/pkg/components/diffview"
+	"github.com/mproffitt/delorian/pkg/config"
+	fluxrepo "github.com/mproffitt/delorian/pkg/repo/flux"
+	"github.com/mproffitt/delorian/pkg/theme"
+)
+
+// profilePicker is the overlay used to switch between profiles
+type profilePicker struct {
+	cursor int
+	names  []string
+	active string
+}
+
+func newProfilePicker(cfg *config.Config) *profilePicker {
+	p := profilePicker{names: make([]string, 0, len(cfg.Profiles))}
+	if active := cfg.ActiveProfile(); active != nil {
+		p.active = active.Name
+	}
+	for i, profile := range cfg.Profiles {
+		p.names = append(p.names, profile.Name)
+		if profile.Name == p.active {
+			p.cursor = i
+		}
+	}
+	return &p
+}
+
+// update handles keys for the picker. When the picker is closed
+// done is true and selected holds the chosen profile, which is
+// empty if the picker was cancelled
+func (p *profilePicker) update(msg tea.KeyMsg) (done bool, selected string) {
+	switch msg.String() {
+	case "up", "k":
+		p.cursor = max(0, p.cursor-1)
+	case "down", "j":
+		p.cursor = min(len(p.names)-1, p.cursor+1)
+	case "enter":
+		return true, p.names[p.cursor]
+	case "esc", "ctrl+c":
+		return true, ""
+	}
+	return false, ""
+}
+
+func (p *profilePicker) view() string {
+	lines := []string{
+		lipgloss.NewStyle().Foreground(theme.Colours.BrightYellow).
+			MarginBottom(1).Render("Switch profile"),
+	}
+	for i, name := range p.names {
+		prefix := "  "
+		if name == p.active {
+			prefix = theme.Glyph("● ")
+		}
+		style := lipgloss.NewStyle().Foreground(theme.Colours.Fg)
+		if i == p.cursor {
+			style = style.Foreground(theme.Colours.BrightWhite).
+				Background(theme.Colours.SelectionBg)
+		}
+		lines = append(lines, style.Render(prefix+name))
+	}
+	return lipgloss.NewStyle().
+		Border(theme.RoundedBorder(), true).
+		BorderForeground(theme.Colours.Blue).
+		Padding(1, 2).
+		Width(max(30, longest(p.names)+8)).
+		Render(strings.Join(lines, "\n"))
+}
+
+func longest(names []string) int {
+	l := 0
+	for _, n := range names {
+		l = max(l, lipgloss.Width(n))
+	}
+	return l
+}
+
+// newSidebar creates the repository model for the active profile,
+// applying the profile's context. A profile path which can't be
+// scanned is returned as an error without creating the model
+func newSidebar(cfg *config.Config, roots []string) (*fluxrepo.Model, error) {
+	profile := cfg.ActiveProfile()
+	if profile == nil {
+		return fluxrepo.New(roots...), nil
+	}
+
+	if profile.Root != "" || profile.Subpath != "" {
+		root, err := profileRoot(profile)
+		if err != nil {
+			return nil, err
+		}
+		roots = []string{root}
+	}
+	components.SetKubeContext(profile.Context)
+
+	sidebar := fluxrepo.New(roots...)
+	if profile.ClusterRegex != "" {
+		// validated when the config was loaded
+		sidebar.SetClusterFilter(regexp.MustCompile(profile.ClusterRegex))
+	}
+	return sidebar, nil
+}
+
+// profileRoot resolves the path of the profile to an absolute path,
+// checking it is a directory
+func profileRoot(profile *config.Profile) (string, error) {
+	path := profile.Path()
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", fmt.Errorf("profile %s: cannot resolve %s: %w", profile.Name, path, err)
+	}
+	fi, err := os.Stat(abs)
+	switch {
+	case os.IsNotExist(err):
+		return "", fmt.Errorf("profile %s: %s does not exist", profile.Name, path)
+	case err != nil:
+		return "", fmt.Errorf("profile %s: cannot scan %s: %w", profile.Name, path, err)
+	case !fi.IsDir():
+		return "", fmt.Errorf("profile %s: %s is not a directory", profile.Name, path)
+	}
+	return abs, nil
+}
+
+// switchProfile activates the named profile, rescanning the
+// repository and re-checking the cluster for its context. When
+// the profile can't be scanned the previous profile stays active
+func (m *Model) switchProfile(name string) tea.Cmd {
+	var previous string
+	if active := m.config.ActiveProfile(); active != nil {
+		previous = active.Name
+	}
+	if err := m.config.SetProfile(name); err != nil {
+		return components.ModelErrorCmd(err)
+	}
+
+	s, err := newSidebar(m.config, m.roots)
+	if err != nil {
+		if previous != "" {
+			_ = m.config.SetProfile(previous)
+		}
+		return components.ModelErrorCmd(err)
+	}
+
+	m.closeSidebar()
+	m.layout.sidebar = s
+	m.undo.Clear()
+	cmd := m.layout.sidebar.Init()
+	_ = m.resize(tea.WindowSizeMsg{Width: m.width - theme.Padding, Height: m.height})
+	if m.focus == sidebar {
+		m.layout.sidebar.(components.Focusable).Focus()
+	} else {
+		m.layout.sidebar.(components.Focusable).Blur()
+	}
+	return tea.Batch(cmd, components.ClusterCheckCmd(),
+		profileStatusCmd(m.config), profileFiltersCmd(m.config))
+}
+
+// profileFiltersCmd sets the filters the diff views hide to the
+// defaults along with those of the active profile
+func profileFiltersCmd(cfg *config.Config) tea.Cmd {
+	filters := slices.Clone(diffview.DefaultFilters)
+	if profile := cfg.ActiveProfile(); profile != nil {
+		filters = append(filters, profile.Filters...)
+	}
+	return components.DiffFiltersCmd(filters)
+}
+
+// profileStatusCmd shows the active profile in the status bar
+func profileStatusCmd(cfg *config.Config) tea.Cmd {
+	profile := cfg.ActiveProfile()
+	if profile == nil {
+		return nil
+	}
+	return components.StatusCmd("profile", "profile: "+profile.Name)
+}
@@ -0,0 +1,54 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package manager
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mproffitt/bmx/pkg/components/toast"
+	fluxrepo "github.com/mproffitt/delorian/pkg/repo/flux"
+	"github.com/mproffitt/delorian/pkg/report"
+)
+
+// reportCmd exports the state of the application for a bug report.
+// The state is gathered straight away so it matches what is on
+// screen and the tool versions are detected in the background
+func (m *Model) reportCmd() tea.Cmd {
+	r := report.New(m.config)
+	for _, s := range settings() {
+		r.Settings[s[0]] = s[1]
+	}
+	if s, ok := m.layout.sidebar.(*fluxrepo.Model); ok {
+		s.Report(r)
+	}
+	roots := m.roots
+
+	return func() tea.Msg {
+		r.DetectTools()
+		if report.Redact {
+			r.Redact(roots...)
+		}
+		if err := r.Write(report.Filename); err != nil {
+			return toast.NewToastMsg{Type: toast.Error,
+				Message: "Unable to write report: " + err.Error()}
+		}
+		return toast.NewToastMsg{Type: toast.Success,
+			Message: "Bug report written to " + report.Filename}
+	}
+}
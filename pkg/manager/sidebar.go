@@ -0,0 +1,144 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package manager
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mproffitt/bmx/pkg/components/toast"
+	"github.com/mproffitt/delorian/pkg/components"
+	"github.com/mproffitt/delorian/pkg/components/statusbar"
+	"github.com/mproffitt/delorian/pkg/components/tabview"
+	"github.com/mproffitt/delorian/pkg/components/yamlview"
+	fluxrepo "github.com/mproffitt/delorian/pkg/repo/flux"
+	"github.com/mproffitt/delorian/pkg/theme"
+)
+
+// sidebarMinWidth is the narrowest the sidebar is drawn
+func (m *Model) sidebarMinWidth() int {
+	if m.config.Layout.SidebarMinWidth > 0 {
+		return m.config.Layout.SidebarMinWidth
+	}
+	return fluxrepo.MinListWidth
+}
+
+// sidebarHidden reports if the terminal is too narrow to show
+// the sidebar alongside the primary view, or compact mode is on
+func (m *Model) sidebarHidden() bool {
+	if m.layout.compact {
+		return true
+	}
+	hideBelow := m.config.Layout.SidebarHideBelow
+	return hideBelow > 0 && m.width-theme.Padding < hideBelow
+}
+
+// sidebarVisible reports if the sidebar is on screen, either
+// alongside the primary view or revealed as an overlay
+func (m *Model) sidebarVisible() bool {
+	return !m.sidebarHidden() || m.layout.reveal
+}
+
+// toggleSidebar reveals or hides the sidebar as an overlay when
+// it has been hidden because the terminal is too narrow
+func (m *Model) toggleSidebar() {
+	if !m.sidebarHidden() {
+		return
+	}
+	m.layout.reveal = !m.layout.reveal
+	if m.layout.reveal {
+		m.focus = sidebar
+		m.layout.sidebar.(components.Focusable).Focus()
+		return
+	}
+	m.focusPrimary()
+}
+
+// focusPrimary moves focus from the sidebar to the primary view
+func (m *Model) focusPrimary() {
+	if m.focus == primary {
+		return
+	}
+	m.focus = primary
+	m.layout.primary.(components.Focus).NextFocus()
+	m.layout.sidebar.(components.Focusable).Blur()
+}
+
+// focusSidebar moves focus from the primary view to the sidebar,
+// stepping the primary view through its focus cycle until nothing
+// inside it holds focus
+func (m *Model) focusSidebar() {
+	if m.focus == sidebar {
+		return
+	}
+	if f, ok := m.layout.primary.(components.Focus); ok {
+		for range maxFocusSteps {
+			if f.NextFocus() == yamlview.NoFocus {
+				break
+			}
+		}
+	}
+	m.focus = sidebar
+	m.layout.sidebar.(components.Focusable).Focus()
+}
+
+// maxFocusSteps bounds the walk through the primary view focus
+// cycle in case a view never gives focus up
+const maxFocusSteps = 8
+
+// followMouse focuses the pane under the mouse when the layout
+// asks for focus to follow the mouse. Motion and clicks both count
+func (m *Model) followMouse(msg tea.MouseMsg) {
+	if !m.config.Layout.FocusFollowsMouse || m.layout.dialog != nil || m.layout.profiles != nil {
+		return
+	}
+	if msg.Action != tea.MouseActionMotion && msg.Button != tea.MouseButtonLeft {
+		return
+	}
+	if msg.Y >= m.height-statusbar.Height {
+		return
+	}
+	if m.sidebarVisible() && msg.X < m.layout.sideWidth {
+		m.focusSidebar()
+		return
+	}
+	m.focusPrimary()
+}
+
+// setCompact shows only the primary view, without the sidebar or
+// the row of tabs
+func (m *Model) setCompact(compact bool) {
+	m.layout.compact = compact
+	if t, ok := m.layout.primary.(*tabview.Model); ok {
+		t.SetCompact(compact)
+	}
+}
+
+// toggleCompact switches compact mode and remembers the choice
+// for the next run
+func (m *Model) toggleCompact() tea.Cmd {
+	m.setCompact(!m.layout.compact)
+	m.config.State.Compact = m.layout.compact
+	if m.width > 0 {
+		m.resize(tea.WindowSizeMsg{Width: m.width - theme.Padding, Height: m.height})
+	}
+	if err := m.config.SaveState(); err != nil {
+		return toast.NewToastCmd(toast.Warning, "Unable to remember compact mode: "+err.Error())
+	}
+	return nil
+}
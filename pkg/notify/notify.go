@@ -0,0 +1,126 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package notify
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/log"
+	"github.com/mproffitt/delorian/pkg/components"
+)
+
+// Method is the means by which the user is alerted to new drift
+type Method string
+
+const (
+	None   Method = "none"
+	Bell   Method = "bell"
+	Notify Method = "notify"
+)
+
+// Methods lists all supported notification methods
+var Methods = []Method{None, Bell, Notify}
+
+// DefaultMethod is the method used by notifiers created by the
+// manager. This is set from the command line
+var DefaultMethod = None
+
+// Debounce is the period during which an identical notification
+// will not be repeated
+var Debounce = 10 * time.Minute
+
+// ParseMethod converts a string into a notification method
+func ParseMethod(s string) (Method, error) {
+	method := Method(strings.ToLower(strings.TrimSpace(s)))
+	if !slices.Contains(Methods, method) {
+		return None, fmt.Errorf("unknown notification method %q (must be one of %v)", s, Methods)
+	}
+	return method, nil
+}
+
+// Notifier sends alerts using the configured method, suppressing
+// repeats of the same alert inside the debounce period
+type Notifier struct {
+	sync.Mutex
+	method Method
+	sent   map[string]time.Time
+}
+
+// New creates a new notifier for the given method
+func New(method Method) *Notifier {
+	return &Notifier{
+		method: method,
+		sent:   make(map[string]time.Time),
+	}
+}
+
+// NotifyCmd alerts the user with the given title and message.
+//
+// Alerts are keyed on their message so identical drift reported
+// by consecutive diffs only produces a single notification
+func (n *Notifier) NotifyCmd(title, message string) tea.Cmd {
+	if n == nil || n.method == None {
+		return nil
+	}
+
+	n.Lock()
+	defer n.Unlock()
+	now := time.Now()
+	if last, ok := n.sent[message]; ok && now.Sub(last) < Debounce {
+		return nil
+	}
+	n.sent[message] = now
+
+	method := n.method
+	return func() tea.Msg {
+		var err error
+		switch method {
+		case Bell:
+			_, err = fmt.Fprint(os.Stdout, "\a")
+		case Notify:
+			err = desktop(title, message)
+		}
+		if err != nil {
+			log.Error("notify", "method", method, "error", err)
+		}
+		return nil
+	}
+}
+
+// desktop raises an OS level notification
+func desktop(title, message string) error {
+	var err error
+	switch runtime.GOOS {
+	case "darwin":
+		script := fmt.Sprintf("display notification %q with title %q", message, title)
+		_, _, err = components.Run(context.Background(), "osascript", []string{"-e", script})
+	default:
+		_, _, err = components.Run(context.Background(), "notify-send", []string{title, message})
+	}
+	return err
+}
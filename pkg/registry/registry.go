@@ -0,0 +1,110 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package registry
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/blang/semver/v4"
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+)
+
+// Enabled controls if registries are queried for newer tags.
+//
+// Querying requires network access to every registry referenced
+// by the rendered manifests and uses the credentials from the
+// local docker configuration, so this is off by default
+var Enabled = false
+
+// Timeout is the maximum time spent listing a single repository
+var Timeout = 10 * time.Second
+
+// cache holds the tags already listed for a repository
+var cache sync.Map
+
+// ListTags returns the tags for the given repository
+var ListTags = func(repository string) ([]string, error) {
+	if tags, ok := cache.Load(repository); ok {
+		return tags.([]string), nil
+	}
+	repo, err := name.NewRepository(repository)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), Timeout)
+	defer cancel()
+
+	tags, err := remote.List(repo,
+		remote.WithAuthFromKeychain(authn.DefaultKeychain),
+		remote.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	cache.Store(repository, tags)
+	return tags, nil
+}
+
+// Latest finds the newest released semantic version in tags.
+//
+// Pre-release and non semver tags are ignored. If current is
+// prefixed with "v" only tags with the same prefix are considered
+func Latest(current string, tags []string) (string, bool) {
+	prefixed := len(current) > 0 && current[0] == 'v'
+
+	var latest string
+	var newest semver.Version
+	for _, tag := range tags {
+		if (len(tag) > 0 && tag[0] == 'v') != prefixed {
+			continue
+		}
+		v, err := semver.ParseTolerant(tag)
+		if err != nil || len(v.Pre) > 0 {
+			continue
+		}
+		if latest == "" || v.GT(newest) {
+			latest, newest = tag, v
+		}
+	}
+	return latest, latest != ""
+}
+
+// Outdated reports the newest tag in the repository and whether
+// the current tag is behind it
+func Outdated(repository, current string) (string, bool, error) {
+	tags, err := ListTags(repository)
+	if err != nil {
+		return "", false, err
+	}
+	latest, ok := Latest(current, tags)
+	if !ok {
+		return "", false, nil
+	}
+	cur, err := semver.ParseTolerant(current)
+	if err != nil {
+		// Tags such as "latest" can't be compared
+		return latest, false, nil
+	}
+	l, _ := semver.ParseTolerant(latest)
+	return latest, l.GT(cur), nil
+}
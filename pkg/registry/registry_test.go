@@ -0,0 +1,51 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package registry
+
+import "testing"
+
+func TestOutdated(t *testing.T) {
+	ListTags = func(repository string) ([]string, error) {
+		return []string{"1.0.0", "1.2.0", "1.10.0", "2.0.0-rc.1", "latest", "v3.0.0"}, nil
+	}
+
+	tests := []struct {
+		tag      string
+		latest   string
+		outdated bool
+	}{
+		{"1.2.0", "1.10.0", true},
+		{"1.10.0", "1.10.0", false},
+		{"latest", "1.10.0", false},
+		{"v2.0.0", "v3.0.0", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			latest, outdated, err := Outdated("example/app", tt.tag)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if latest != tt.latest || outdated != tt.outdated {
+				t.Errorf("Outdated(%q) = %q, %v, want %q, %v",
+					tt.tag, latest, outdated, tt.latest, tt.outdated)
+			}
+		})
+	}
+}
@@ -26,42 +26,113 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	zone "github.com/lrstanley/bubblezone"
-	"github.com/mproffitt/delorian/pkg/components"
 	"github.com/mproffitt/delorian/pkg/kustomize"
+	"github.com/mproffitt/delorian/pkg/theme"
 )
 
 func (s *shortApi) Build() tea.Cmd {
-	args := []string{
+	if len(s.edits) > 0 {
+		return s.previewCmd()
+	}
+	if KeepComments {
+		return s.commentedBuildCmd()
+	}
+	return s.fluxExecCmd(s.buildArgs)
+}
+
+// buildArgs are the arguments passed to flux to build
+// this kustomization from the given kustomization file
+func (s *shortApi) buildArgs(file string) []string {
+	return append([]string{
 		"build", "kustomization", s.GetName(),
 		"-n", s.GetNamespace(),
 		"--path", s.GetAbsoluteSpecPath(),
-		"--kustomization-file", s.GetPath(),
+		"--kustomization-file", file,
 		"--dry-run", "--strict-substitute",
-	}
-	return components.FluxExecCmd(args)
+	}, BuildFlags...)
 }
 
 func (s *shortApi) Diff() tea.Cmd {
-	args := []string{
+	return s.fluxExecCmd(s.diffArgs)
+}
+
+// diffArgs are the arguments passed to flux to diff
+// this kustomization from the given kustomization file
+func (s *shortApi) diffArgs(file string) []string {
+	return append([]string{
 		"diff", "kustomization", s.GetName(),
 		"-n", s.GetNamespace(),
 		"--path", s.GetAbsoluteSpecPath(),
-		"--kustomization-file", s.GetPath(),
+		"--kustomization-file", file,
 		"--strict-substitute",
 		"--progress-bar=false",
-	}
-	return components.FluxExecCmd(args)
+	}, DiffFlags...)
 }
 
 func (s *shortApi) Title() string {
-	return zone.Mark(s.id, s.GetName())
+	return zone.Mark(s.id, s.titleText())
+}
+
+// titleText is the unmarked title including status indicators
+func (s *shortApi) titleText() string {
+	title := s.GetName()
+	if len(s.overrides) > 0 || len(s.edits) > 0 {
+		title = theme.Glyph("✎ ") + title
+	}
+	if s.note() != "" {
+		title = theme.Glyph("¶ ") + title
+	}
+	if len(s.issues) > 0 {
+		title = theme.Glyph("⚠ ") + title
+	}
+	if live, ok := s.liveStatus(); ok {
+		title = live.badge() + " " + title
+	}
+	return title
 }
 
 func (s *shortApi) Description() string {
 	desc := fmt.Sprintf("%s (%d)", s.GetNamespace(), len(s.children))
+	if s.multiroot {
+		desc = fmt.Sprintf(theme.Glyph("%s · %s"), desc, rootName(s.root))
+	}
+	// Flux builds kustomizations without a path from the source root
+	if s.Spec.Path == nil && s.Spec.Source != nil {
+		desc = fmt.Sprintf(theme.Glyph("%s · source root"), desc)
+	}
+	if labels := s.keyLabels(); len(labels) > 0 {
+		desc = fmt.Sprintf(theme.Glyph("%s · %s"), desc, strings.Join(labels, " "))
+	}
+	if s.unmet > 0 {
+		desc = fmt.Sprintf(theme.Glyph("%s · ⇢ %d unmet"), desc, s.unmet)
+	}
+	if s.reconcileDisabled() {
+		desc = fmt.Sprintf(theme.Glyph("%s · reconcile disabled"), desc)
+	}
+	if live, ok := s.liveStatus(); ok {
+		desc = fmt.Sprintf(theme.Glyph("%s · %s"), desc, live)
+	}
+	switch {
+	case s.ftype == Base && s.parent == nil:
+		desc = fmt.Sprintf(theme.Glyph("%s · unlinked %s"), desc, s.ftype)
+	case s.ftype != Complete:
+		desc = fmt.Sprintf(theme.Glyph("%s · %s"), desc, s.ftype)
+	}
 	return desc
 }
 
+// identity names the kustomization independently of the scan
+// which found it, unlike its id
+func (s *shortApi) identity() string {
+	return s.root + "|" + s.GetNamespace() + "/" + s.rawName
+}
+
+// GetRoot returns the repository root this kustomization
+// was discovered in
+func (s *shortApi) GetRoot() string {
+	return s.root
+}
+
 func (s *shortApi) FilterValue() string {
 	return zone.Mark(s.id, s.GetName())
 }
@@ -75,15 +146,11 @@ func (s *shortApi) GetContent() string {
 		options = append(options, "metadata.namespace", s.GetNamespace())
 	}
 
-	if s.ftype == Complete {
+	// Bases are shown as they are written in the repository
+	if s.ftype == Complete || s.ftype == Base {
 		return readFile(s.GetPath(), options...)
 	}
-
-	// We should not be seeing bases in the final view
-	if s.ftype == Base {
-		return ""
-	}
-	content, err := kustomize.ExecKustomize(filepath.Dir(s.kustomize))
+	content, err := builds.build(filepath.Dir(s.kustomize))
 	if err != nil {
 		return err.Error()
 	}
@@ -110,11 +177,16 @@ func (s *shortApi) GetPath() string {
 	return path
 }
 
+// GetAbsoluteSpecPath returns the spec path as resolved with the
+// substitutions of the parent kustomization, as flux substitutes it.
+// The kustomization's own postBuild never applies to its path.
+// Without a spec path flux builds from the root of the source
 func (s *shortApi) GetAbsoluteSpecPath() string {
-	path := ""
-	if s.Spec.Path != nil {
-		path, _ = filepath.Abs(filepath.Join(s.root, *s.Spec.Path))
+	if s.Spec.Path == nil {
+		path, _ := filepath.Abs(s.root)
+		return path
 	}
+	path, _ := filepath.Abs(filepath.Join(s.root, *s.Spec.Path))
 	return path
 }
 
@@ -0,0 +1,63 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package flux
+
+import (
+	"strings"
+
+	"github.com/mproffitt/delorian/pkg/kustomize"
+)
+
+// docClass is what a YAML document means to the traversal
+type docClass int
+
+const (
+	otherDoc docClass = iota
+	fluxKustomizationDoc
+	sourceDoc
+	kustomizeDoc
+	configDoc
+	helmReleaseDoc
+)
+
+// classify decides what a document is from its full apiVersion
+// and kind rather than the name of the file holding it. Both
+// flux and kustomize use the kind Kustomization so the group is
+// what tells them apart
+func classify(apiVersion, kind string) docClass {
+	group, _, grouped := strings.Cut(apiVersion, "/")
+	switch {
+	case kustomize.IsKustomization(apiVersion, kind):
+		return kustomizeDoc
+	case !grouped && (kind == "ConfigMap" || kind == "Secret"):
+		// read for spec.postBuild.substituteFrom
+		return configDoc
+	case !grouped:
+		// core kubernetes types such as v1 have no group
+		return otherDoc
+	case group == kustomizationApi && kind == "Kustomization":
+		return fluxKustomizationDoc
+	case group == sourceApi:
+		return sourceDoc
+	case group == helmApi && kind == "HelmRelease":
+		return helmReleaseDoc
+	}
+	return otherDoc
+}
@@ -0,0 +1,104 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package flux
+
+import (
+	"testing"
+
+	"github.com/mproffitt/delorian/pkg/testutil"
+)
+
+func TestClassifyApiVersion(t *testing.T) {
+	tests := []struct {
+		apiVersion, kind string
+		expect           docClass
+	}{
+		{"kustomize.toolkit.fluxcd.io/v1", "Kustomization", fluxKustomizationDoc},
+		{"kustomize.toolkit.fluxcd.io/v1beta2", "Kustomization", fluxKustomizationDoc},
+		{"kustomize.config.k8s.io/v1beta1", "Kustomization", kustomizeDoc},
+		{"kustomize.config.k8s.io/v1alpha1", "Component", kustomizeDoc},
+		{"", "", kustomizeDoc},
+		{"", "Kustomization", kustomizeDoc},
+		{"source.toolkit.fluxcd.io/v1", "GitRepository", sourceDoc},
+		{"v1", "ConfigMap", configDoc},
+		{"v1", "Secret", configDoc},
+		{"v1", "Namespace", otherDoc},
+		{"helm.toolkit.fluxcd.io/v2", "HelmRelease", helmReleaseDoc},
+		{"helm.toolkit.fluxcd.io/v2", "Kustomization", otherDoc},
+		{"kustomize.toolkit.fluxcd.io", "Kustomization", otherDoc},
+		{"example.com/v1", "Kustomization", otherDoc},
+	}
+	for _, tt := range tests {
+		if got := classify(tt.apiVersion, tt.kind); got != tt.expect {
+			t.Errorf("%s %s: expected %d, got %d", tt.apiVersion, tt.kind, tt.expect, got)
+		}
+	}
+}
+
+// TestMixedKustomizationFile keeps flux kustomizations in the same
+// kustomization.yaml as the kustomize document, in either order
+func TestMixedKustomizationFile(t *testing.T) {
+	testutil.Setup()
+	root := t.TempDir()
+	kust := func(name, path string) string {
+		return "apiVersion: kustomize.toolkit.fluxcd.io/v1\nkind: Kustomization\n" +
+			"metadata:\n  name: " + name + "\n  namespace: flux-system\n" +
+			"spec:\n  path: " + path + "\n"
+	}
+
+	testutil.WriteFile(t, root, "clusters/prod/root.yaml", kust("root", "./clusters/prod/flux-system"))
+	testutil.WriteFile(t, root, "clusters/prod/flux-system/kustomization.yaml",
+		kust("infra", "./infra")+"---\n"+
+			"apiVersion: kustomize.config.k8s.io/v1beta1\nkind: Kustomization\n"+
+			"resources:\n- ../apps.yaml\n")
+	testutil.WriteFile(t, root, "clusters/prod/apps.yaml", kust("apps", "./apps"))
+	testutil.WriteFile(t, root, "apps/kustomization.yaml",
+		"apiVersion: kustomize.config.k8s.io/v1beta1\nkind: Kustomization\n"+
+			"resources:\n- podinfo.yaml\n---\n"+kust("podinfo", "./apps/podinfo"))
+	testutil.WriteFile(t, root, "apps/podinfo.yaml", "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: podinfo\n")
+	testutil.WriteFile(t, root, "infra/namespace.yaml", "apiVersion: v1\nkind: Namespace\nmetadata:\n  name: infra\n")
+
+	m := New(root)
+	m.walk()
+	if len(m.kustomizations) != 4 {
+		t.Fatalf("expected 4 flux kustomizations, got %d", len(m.kustomizations))
+	}
+
+	expect := map[string]struct {
+		parent string
+		ftype  FluxFileType
+	}{
+		"root":    {"", Complete},
+		"infra":   {"root", Complete},
+		"apps":    {"root", Complete},
+		"podinfo": {"apps", Complete},
+	}
+	for _, k := range m.kustomizations {
+		want := expect[k.GetName()]
+		parent := ""
+		if k.parent != nil {
+			parent = k.parent.GetName()
+		}
+		if parent != want.parent || k.ftype != want.ftype {
+			t.Errorf("%s: expected parent %q and type %s, got %q and %s",
+				k.GetName(), want.parent, want.ftype, parent, k.ftype)
+		}
+	}
+}
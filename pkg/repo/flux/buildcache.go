@@ -0,0 +1,86 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package flux
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/mproffitt/delorian/pkg/kustomize"
+)
+
+// buildCache keeps the kustomize build shown for each kustomize
+// kustomization so selecting one again doesn't build it again.
+// Builds are keyed by directory and redone once any path they read
+// from has changed
+type buildCache struct {
+	sync.Mutex
+	entries map[string]builtContent
+}
+
+type builtContent struct {
+	inputs  []string
+	stamp   string
+	content []byte
+}
+
+var builds buildCache
+
+// build returns the kustomize build of dir, reusing the last build
+// while its inputs are unchanged. Failed builds are not kept
+func (c *buildCache) build(dir string) ([]byte, error) {
+	c.Lock()
+	entry, ok := c.entries[dir]
+	c.Unlock()
+	if ok && entry.stamp == inputStamp(entry.inputs) {
+		return entry.content, nil
+	}
+
+	content, inputs, err := kustomize.ExecKustomizeInputs(dir)
+	if err != nil {
+		return nil, err
+	}
+	c.Lock()
+	defer c.Unlock()
+	if c.entries == nil {
+		c.entries = make(map[string]builtContent)
+	}
+	c.entries[dir] = builtContent{inputs: inputs, stamp: inputStamp(inputs), content: content}
+	return content, nil
+}
+
+// inputStamp summarises the modification time and size of every path
+// a build read from. Paths which didn't exist are stamped as missing
+// so creating them changes the stamp too
+func inputStamp(paths []string) string {
+	hash := sha256.New()
+	for _, path := range paths {
+		fi, err := os.Stat(path)
+		if err != nil {
+			fmt.Fprintf(hash, "%s\x00missing\n", path)
+			continue
+		}
+		fmt.Fprintf(hash, "%s\x00%d\x00%d\n", path, fi.ModTime().UnixNano(), fi.Size())
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
@@ -0,0 +1,107 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package flux
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mproffitt/delorian/pkg/kustomize"
+	"github.com/mproffitt/delorian/pkg/testutil"
+	"sigs.k8s.io/kustomize/kyaml/filesys"
+)
+
+// countingBuilder records how many builds were run
+type countingBuilder struct {
+	builds *int
+}
+
+func (b countingBuilder) Build(fsys filesys.FileSystem, path string) ([]byte, error) {
+	*b.builds++
+	return kustomize.KrustyBuilder{}.Build(fsys, path)
+}
+
+func TestBuildCache(t *testing.T) {
+	count := 0
+	kustomize.SetBuilder(countingBuilder{builds: &count})
+	builds = buildCache{}
+	t.Cleanup(func() {
+		kustomize.SetBuilder(nil)
+		builds = buildCache{}
+	})
+
+	root := t.TempDir()
+	testutil.WriteFile(t, root, "app/kustomization.yaml",
+		"resources:\n  - ../base\npatches:\n  - path: ../patches/replicas.yaml\n")
+	testutil.WriteFile(t, root, "base/kustomization.yaml", "resources:\n  - deployment.yaml\n")
+	testutil.WriteFile(t, root, "base/deployment.yaml",
+		"apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: app\nspec:\n  replicas: 1\n")
+	patch := testutil.WriteFile(t, root, "patches/replicas.yaml",
+		"apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: app\nspec:\n  replicas: 2\n")
+	dir := filepath.Join(root, "app")
+
+	for range 3 {
+		if _, err := builds.build(dir); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if count != 1 {
+		t.Errorf("expected a single build while nothing changed, got %d", count)
+	}
+
+	// Changing a file in a base the kustomization pulls in rebuilds
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(filepath.Join(root, "base", "deployment.yaml"), later, later); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := builds.build(dir); err != nil {
+		t.Fatal(err)
+	}
+	if count != 2 {
+		t.Errorf("expected a rebuild after a base changed, got %d builds", count)
+	}
+
+	// So does a patch outside both directories
+	testutil.WriteFile(t, root, "patches/replicas.yaml",
+		"apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: app\nspec:\n  replicas: 3\n")
+	later = later.Add(time.Minute)
+	if err := os.Chtimes(patch, later, later); err != nil {
+		t.Fatal(err)
+	}
+	content, err := builds.build(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 3 || !strings.Contains(string(content), "replicas: 3") {
+		t.Errorf("expected a rebuild with the edited patch, got %d builds\n%s", count, content)
+	}
+
+	// Unrelated directories are not inputs
+	testutil.WriteFile(t, root, "other/unrelated.yaml", "kind: ConfigMap\n")
+	if _, err := builds.build(dir); err != nil {
+		t.Fatal(err)
+	}
+	if count != 3 {
+		t.Errorf("expected no rebuild for an unrelated file, got %d builds", count)
+	}
+}
@@ -0,0 +1,240 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package flux
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/charmbracelet/log"
+)
+
+// NoCache disables the scan cache so every file is parsed on
+// each scan and nothing is written to the cache directory
+var NoCache = false
+
+// cacheVersion must be increased whenever the exported fields
+// of shortApi change so caches written before are discarded
+const cacheVersion = 4
+
+// scanCache keeps the documents decoded from each yaml file in
+// the repository between runs. Files are parsed again when their
+// modification time or size changes
+type scanCache struct {
+	sync.Mutex
+	path string
+
+	// files are those read from disk or kept from the last scan
+	// and seen those found by the scan in progress
+	files map[string]cachedFile
+	seen  map[string]cachedFile
+
+	hits  int
+	dirty bool
+}
+
+type cachedFile struct {
+	ModTime int64 `json:"modTime"`
+	Size    int64 `json:"size"`
+
+	// Docs are stored encoded so every lookup decodes a fresh
+	// copy which the scan is free to modify
+	Docs json.RawMessage `json:"docs"`
+}
+
+type cacheContent struct {
+	Version int                   `json:"version"`
+	Roots   []string              `json:"roots"`
+	Files   map[string]cachedFile `json:"files"`
+}
+
+// cachePath returns the cache file for the given repository roots
+// under the user cache directory ($XDG_CACHE_HOME on linux)
+func cachePath(roots []string) (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256([]byte(strings.Join(roots, "\n")))
+	return filepath.Join(dir, "delorian", hex.EncodeToString(sum[:8])+".json"), nil
+}
+
+// loadCache reads the cache for roots. A missing, unreadable or
+// outdated cache gives an empty one which is filled by the scan
+func loadCache(roots []string) *scanCache {
+	c := scanCache{
+		files: make(map[string]cachedFile),
+		seen:  make(map[string]cachedFile),
+	}
+	path, err := cachePath(roots)
+	if err != nil {
+		log.Debug("scan cache disabled", "error", err)
+		return &c
+	}
+	c.path = path
+
+	content, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return &c
+	}
+	var cached cacheContent
+	if err := json.Unmarshal(content, &cached); err != nil || cached.Version != cacheVersion {
+		log.Debug("discarding scan cache", "path", path, "error", err)
+		return &c
+	}
+	if cached.Files != nil {
+		c.files = cached.Files
+	}
+	return &c
+}
+
+// view returns a cache for a single scan. It reads the files kept
+// by c but records what the scan sees apart from c, so a scan which
+// is abandoned part way leaves c as it was
+func (c *scanCache) view() *scanCache {
+	if c == nil {
+		return nil
+	}
+	c.Lock()
+	defer c.Unlock()
+	return &scanCache{
+		path:  c.path,
+		files: c.files,
+		seen:  make(map[string]cachedFile),
+	}
+}
+
+// empty reports if the cache holds no files to load from
+func (c *scanCache) empty() bool {
+	if c == nil {
+		return true
+	}
+	c.Lock()
+	defer c.Unlock()
+	return len(c.files) == 0
+}
+
+// lookup returns the documents cached for path if the file is
+// unchanged since they were decoded
+func (c *scanCache) lookup(path string, fi fs.FileInfo) ([]decodedDoc, bool) {
+	c.Lock()
+	defer c.Unlock()
+	f, ok := c.files[path]
+	if !ok || f.ModTime != fi.ModTime().UnixNano() || f.Size != fi.Size() {
+		return nil, false
+	}
+	var docs []decodedDoc
+	if err := json.Unmarshal(f.Docs, &docs); err != nil {
+		return nil, false
+	}
+	c.seen[path] = f
+	c.hits++
+	return docs, true
+}
+
+// store records the documents decoded from path
+func (c *scanCache) store(path string, fi fs.FileInfo, docs []decodedDoc) {
+	encoded, err := json.Marshal(docs)
+	if err != nil {
+		return
+	}
+	c.Lock()
+	defer c.Unlock()
+	c.seen[path] = cachedFile{
+		ModTime: fi.ModTime().UnixNano(),
+		Size:    fi.Size(),
+		Docs:    encoded,
+	}
+	c.dirty = true
+}
+
+// finish ends a scan, dropping files which were not seen and
+// writing the cache if anything changed. It returns the number
+// of files read from the cache
+func (c *scanCache) finish(roots []string) int {
+	c.Lock()
+	defer c.Unlock()
+	hits := c.hits
+	changed := c.dirty || len(c.seen) != len(c.files)
+	c.files, c.seen = c.seen, make(map[string]cachedFile)
+	c.hits, c.dirty = 0, false
+	if !changed || c.path == "" {
+		return hits
+	}
+
+	content, err := json.Marshal(cacheContent{
+		Version: cacheVersion,
+		Roots:   roots,
+		Files:   c.files,
+	})
+	if err == nil {
+		err = writeCache(c.path, content)
+	}
+	if err != nil {
+		log.Warn("unable to write scan cache", "path", c.path, "error", err)
+	}
+	return hits
+}
+
+// writeCache replaces the cache file so a reader never sees a
+// partly written cache
+func writeCache(path string, content []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(content); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+// parseFile parses the yaml file at path, reusing the documents
+// decoded by an earlier scan when the file is unchanged
+func (m *Model) parseFile(root, path string, fi fs.FileInfo) ([]shortApi, []shortSource, []shortRelease, []shortConfig) {
+	if m.cache == nil {
+		return parseYamlFromFile(root, path)
+	}
+	docs, ok := m.cache.lookup(path, fi)
+	if !ok {
+		content, err := os.ReadFile(filepath.Clean(path))
+		if err != nil {
+			return nil, nil, nil, nil
+		}
+		docs = decodeYaml(content)
+		m.cache.store(path, fi, docs)
+	}
+	return fromDocuments(docs, root, path)
+}
@@ -0,0 +1,90 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package flux
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mproffitt/delorian/pkg/testutil"
+)
+
+func TestMain(m *testing.M) {
+	// Tests must not read or write the cache of the current user
+	NoCache = true
+	os.Exit(m.Run())
+}
+
+func TestScanCache(t *testing.T) {
+	NoCache = false
+	defer func() { NoCache = true }()
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+
+	root := t.TempDir()
+	path := testutil.WriteFile(t, root, filepath.Join("clusters", "apps.yaml"), multiDoc)
+	testutil.WriteFile(t, root, filepath.Join("clusters", "other.yaml"),
+		"apiVersion: v1\nkind: Namespace\nmetadata:\n  name: apps\n")
+
+	m := New(root)
+	m.walk()
+	if m.cached != 0 {
+		t.Errorf("expected nothing from the cache on the first scan, got %d", m.cached)
+	}
+	cache, err := cachePath(m.roots)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(cache); err != nil {
+		t.Fatalf("expected the cache to be written: %v", err)
+	}
+
+	// A new model reads the cache written by the first
+	m = New(root)
+	m.walk()
+	if m.cached != 2 {
+		t.Errorf("expected both files from the cache, got %d", m.cached)
+	}
+	if len(m.kustomizations) != 1 || len(m.sources) != 1 {
+		t.Fatalf("expected 1 kustomization and 1 source from the cache, got %d and %d",
+			len(m.kustomizations), len(m.sources))
+	}
+	if m.kustomizations[0].GetName() != "apps" || m.kustomizations[0].GetSource() == nil {
+		t.Errorf("expected apps linked to its source, got %+v", m.kustomizations[0])
+	}
+
+	// Changed files are parsed again
+	renamed := strings.Replace(multiDoc, "name: apps", "name: web", 1)
+	testutil.WriteFile(t, root, filepath.Join("clusters", "apps.yaml"), renamed)
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(path, later, later); err != nil {
+		t.Fatal(err)
+	}
+	m.walk()
+	if m.cached != 1 {
+		t.Errorf("expected only the unchanged file from the cache, got %d", m.cached)
+	}
+	if len(m.kustomizations) != 1 || m.kustomizations[0].GetName() != "web" {
+		t.Errorf("expected the renamed kustomization, got %+v", m.kustomizations)
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package flux
+
+import (
+	"time"
+
+	"github.com/mproffitt/delorian/pkg/components"
+)
+
+var (
+	// MinInterval is the interval below which reconciles are
+	// flagged as unusually frequent
+	MinInterval = time.Minute
+
+	// MaxInterval is the interval above which reconciles are
+	// flagged as unusually infrequent
+	MaxInterval = 12 * time.Hour
+)
+
+// cadence collects the reconcile interval and timeout of every
+// kustomization and source in the repository
+func (m *Model) cadence() []components.Cadence {
+	entries := make([]components.Cadence, 0, len(m.kustomizations)+len(m.sources))
+	for i := range m.kustomizations {
+		k := &m.kustomizations[i]
+		if k.ftype == Base {
+			continue
+		}
+		entries = append(entries, newCadence(components.Cadence{
+			Kind:      k.Kind,
+			Name:      k.GetName(),
+			Namespace: k.GetNamespace(),
+			Filepath:  k.GetPath(),
+			Interval:  k.Spec.Interval,
+			Timeout:   k.Spec.Timeout,
+		}))
+	}
+	for i := range m.sources {
+		s := &m.sources[i]
+		entries = append(entries, newCadence(components.Cadence{
+			Kind:      s.Kind,
+			Name:      s.GetName(),
+			Namespace: s.GetNamespace(),
+			Filepath:  s.GetPath(),
+			Interval:  s.interval,
+			Timeout:   s.timeout,
+		}))
+	}
+	return entries
+}
+
+// newCadence parses the interval and timeout of the entry and
+// flags intervals outside MinInterval and MaxInterval
+func newCadence(c components.Cadence) components.Cadence {
+	every, err := time.ParseDuration(c.Interval)
+	if err != nil || every <= 0 {
+		c.Flag = components.CadenceUnset
+		return c
+	}
+	c.Every = every
+	switch {
+	case every < MinInterval:
+		c.Flag = components.CadenceShort
+	case every > MaxInterval:
+		c.Flag = components.CadenceLong
+	}
+	if timeout, err := time.ParseDuration(c.Timeout); err == nil && timeout > every {
+		c.TimeoutExceeds = true
+	}
+	return c
+}
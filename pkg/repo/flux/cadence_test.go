@@ -0,0 +1,104 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package flux
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mproffitt/delorian/pkg/components"
+)
+
+const cadenceDocs = `apiVersion: kustomize.toolkit.fluxcd.io/v1
+kind: Kustomization
+metadata:
+  name: apps
+  namespace: flux-system
+spec:
+  interval: 10m
+  timeout: 30m
+  path: ./apps
+  sourceRef:
+    kind: GitRepository
+    name: flux-system
+---
+apiVersion: kustomize.toolkit.fluxcd.io/v1
+kind: Kustomization
+metadata:
+  name: infra
+  namespace: flux-system
+spec:
+  interval: ${INTERVAL}
+  path: ./infra
+---
+apiVersion: source.toolkit.fluxcd.io/v1
+kind: GitRepository
+metadata:
+  name: flux-system
+  namespace: flux-system
+spec:
+  interval: 30s
+  timeout: 20s
+  url: https://github.com/org/repo
+---
+apiVersion: source.toolkit.fluxcd.io/v1
+kind: HelmRepository
+metadata:
+  name: charts
+  namespace: flux-system
+spec:
+  interval: 24h
+`
+
+func TestCadence(t *testing.T) {
+	root := t.TempDir()
+	m := New(root)
+	m.kustomizations, m.sources, _, _ = parseYaml([]byte(cadenceDocs), root, filepath.Join(root, "clusters", "flux.yaml"))
+	for i := range m.kustomizations {
+		m.kustomizations[i].ftype = Complete
+	}
+
+	entries := m.cadence()
+	if len(entries) != 4 {
+		t.Fatalf("expected 4 entries, got %d: %v", len(entries), entries)
+	}
+	expected := map[string]struct {
+		every   time.Duration
+		flag    components.CadenceFlag
+		exceeds bool
+	}{
+		"apps":        {10 * time.Minute, components.CadenceNormal, true},
+		"infra":       {0, components.CadenceUnset, false},
+		"flux-system": {30 * time.Second, components.CadenceShort, false},
+		"charts":      {24 * time.Hour, components.CadenceLong, false},
+	}
+	for _, e := range entries {
+		want, ok := expected[e.Name]
+		if !ok {
+			t.Errorf("unexpected entry %s", e.Object())
+			continue
+		}
+		if e.Every != want.every || e.Flag != want.flag || e.TimeoutExceeds != want.exceeds {
+			t.Errorf("%s: expected %s %q exceeds=%t, got %s %q exceeds=%t", e.Object(),
+				want.every, want.flag, want.exceeds, e.Every, e.Flag, e.TimeoutExceeds)
+		}
+	}
+}
@@ -29,6 +29,7 @@ import (
 
 	"github.com/charmbracelet/lipgloss/tree"
 	"github.com/charmbracelet/log"
+	"github.com/mproffitt/delorian/pkg/components/treeview"
 )
 
 var commonNamespaces = []string{
@@ -51,6 +52,7 @@ func (c *cluster) Add(entries []string, path string) *cluster {
 				child := &cluster{
 					name:     entries[0],
 					filepath: path,
+					root:     c.root,
 					children: make([]*cluster, 0),
 				}
 				log.Debug("Adding child", "cluster", entries[0], "parent", c.name, "path", path)
@@ -64,7 +66,7 @@ func (c *cluster) Add(entries []string, path string) *cluster {
 
 func (c *cluster) Tree() *tree.Tree {
 	tree := tree.New().
-		Root(c.Name())
+		Root(c.label())
 	sort.SliceStable(c.children, func(i, j int) bool {
 		return c.children[i].name < c.children[j].name
 	})
@@ -72,12 +74,26 @@ func (c *cluster) Tree() *tree.Tree {
 		if len(v.children) > 0 {
 			tree = tree.Child(c.children[i].Tree())
 		} else {
-			tree = tree.Child(c.children[i].Name())
+			tree = tree.Child(c.children[i].label())
 		}
 	}
 	return tree
 }
 
+// label is the name shown in the tree, highlighted when this is
+// the deepest selected cluster
+func (c *cluster) label() string {
+	if !c.selected {
+		return c.Name()
+	}
+	for _, child := range c.children {
+		if child.selected {
+			return c.Name()
+		}
+	}
+	return treeview.Highlight(c.Name())
+}
+
 func (c *cluster) Len() int {
 	l := len(c.children)
 	for _, child := range c.children {
@@ -87,7 +103,7 @@ func (c *cluster) Len() int {
 }
 
 func (c *cluster) Matches(entry string) bool {
-	dirs := strings.Split(c.filepath, string(filepath.Separator))
+	dirs := strings.Split(c.filepath, separator)
 	return entry == dirs[len(dirs)-1]
 }
 
@@ -95,21 +111,16 @@ func (c *cluster) Name() string {
 	return c.name
 }
 
+// Select marks the clusters along branch as selected and clears
+// the selection from every other cluster
 func (c *cluster) Select(branch []string) {
-	switch len(branch) {
-	case 0:
-		return
-	default:
-		switch branch[0] {
-		case c.name:
-			c.selected = true
-			if len(branch) > 1 {
-				branch = branch[1:]
-			}
-			for i := range c.children {
-				c.children[i].Select(branch)
-			}
-		}
+	c.selected = len(branch) > 0 && branch[0] == c.name
+	var rest []string
+	if c.selected {
+		rest = branch[1:]
+	}
+	for i := range c.children {
+		c.children[i].Select(rest)
 	}
 }
 
@@ -117,16 +128,18 @@ func (c *cluster) Selected() bool {
 	return c.selected
 }
 
-func (m *Model) checkClusterPath(path string) {
+func (m *Model) checkClusterPath(root, path string) {
 	// We should have already tested that this is a valid
 	// location so no need to try again, just validate the
 	// path and update clusters, then move on.
-	path = strings.TrimRight(path, string(filepath.Separator))
-	if strings.Contains(path, "/.") || strings.Contains(path, "bases/") {
-		// ignore hidden paths and bases
+	path = strings.TrimRight(path, separator)
+	testPath := slash(strings.TrimPrefix(path, root+separator))
+	if hidden(path) || inBase(testPath) {
+		// ignore hidden paths and bases. Bases are only looked
+		// for inside the root so the location of the checkout
+		// can't hide every cluster
 		return
 	}
-	testPath := strings.TrimPrefix(path, m.root+string(filepath.Separator))
 	// We accept any of
 	// *clusters
 	// *hub
@@ -157,7 +170,7 @@ func (m *Model) checkClusterPath(path string) {
 	foundParent := false
 	m.Lock()
 	for i, c := range m.clusters {
-		if c.name == clusters[0] {
+		if c.name == clusters[0] && c.root == root {
 			foundParent = true
 			m.clusters[i].Add(clusters, path)
 		}
@@ -167,6 +180,7 @@ func (m *Model) checkClusterPath(path string) {
 			children: make([]*cluster, 0),
 			name:     clusters[0],
 			filepath: path,
+			root:     root,
 		}
 		log.Debug("Adding cluster", "clusterName", clusters[0], "parent", nil, "filepath", path)
 		m.clusters = append(m.clusters, &newCluster)
@@ -186,7 +200,7 @@ func (m *Model) reparentClusters() {
 		}
 
 		for j := range m.clusters {
-			if j == i || m.clusters[j] == nil {
+			if j == i || m.clusters[j] == nil || m.clusters[i].root != m.clusters[j].root {
 				continue
 			}
 			fname := filepath.Join(m.clusters[i].filepath, m.clusters[j].name) + ".yaml"
@@ -196,6 +210,7 @@ func (m *Model) reparentClusters() {
 					children: make([]*cluster, len(m.clusters[j].children)),
 					name:     m.clusters[j].name,
 					filepath: m.clusters[j].filepath,
+					root:     m.clusters[j].root,
 				}
 				c.children = append(c.children, m.clusters[j].children...)
 				m.clusters[i].children = append(m.clusters[i].children, &c)
@@ -218,3 +233,75 @@ func (m *Model) reparentClusters() {
 
 	m.clusters = newclusters
 }
+
+// filter returns a copy of the cluster pruned to the branches
+// matching re. A matching cluster keeps all of its children
+func (c *cluster) filter(re *regexp.Regexp) *cluster {
+	if re.MatchString(c.name) {
+		return c
+	}
+	pruned := cluster{
+		name:     c.name,
+		filepath: c.filepath,
+		root:     c.root,
+		children: make([]*cluster, 0),
+	}
+	for _, child := range c.children {
+		if f := child.filter(re); f != nil {
+			pruned.children = append(pruned.children, f)
+		}
+	}
+	if len(pruned.children) == 0 {
+		return nil
+	}
+	return &pruned
+}
+
+// dirs returns the directories of this cluster and its children
+func (c *cluster) dirs() []string {
+	dirs := []string{c.filepath}
+	for _, child := range c.children {
+		dirs = append(dirs, child.dirs()...)
+	}
+	return dirs
+}
+
+// SetClusterFilter limits the clusters shown to those with a
+// name matching re. A nil filter shows all clusters
+func (m *Model) SetClusterFilter(re *regexp.Regexp) *Model {
+	m.clusterFilter = re
+	return m
+}
+
+// visibleClusters returns the clusters matching the cluster filter
+func (m *Model) visibleClusters() []*cluster {
+	if m.clusterFilter == nil {
+		return m.clusters
+	}
+	visible := make([]*cluster, 0)
+	for _, c := range m.clusters {
+		if f := c.filter(m.clusterFilter); f != nil {
+			visible = append(visible, f)
+		}
+	}
+	return visible
+}
+
+// inFilteredCluster reports if path is inside the directory of a
+// cluster that has been excluded by the cluster filter
+func (m *Model) inFilteredCluster(path string) bool {
+	if m.clusterFilter == nil {
+		return false
+	}
+	under := func(clusters []*cluster) bool {
+		for _, c := range clusters {
+			for _, dir := range c.dirs() {
+				if strings.HasPrefix(path, dir+separator) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	return under(m.clusters) && !under(m.visibleClusters())
+}
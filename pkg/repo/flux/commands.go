@@ -0,0 +1,127 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package flux
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/mproffitt/delorian/pkg/kustomize"
+)
+
+// CopyCommands copies the flux CLI commands for the selected
+// kustomization to the clipboard
+var CopyCommands = key.NewBinding(key.WithKeys("y"),
+	key.WithHelp("y", "Copy as flux commands"))
+
+// CopyBuildCommand copies the kustomize build command for the
+// spec path of the selected kustomization to the clipboard
+var CopyBuildCommand = key.NewBinding(key.WithKeys("Y"),
+	key.WithHelp("Y", "Copy as kustomize build"))
+
+// fluxCommands generates the flux CLI commands used to create and
+// manage this kustomization, suitable for runbooks and documentation
+func (s *shortApi) fluxCommands() string {
+	name, namespace := s.GetName(), s.GetNamespace()
+	if namespace == "" {
+		namespace = "flux-system"
+	}
+
+	create := []string{
+		"flux create kustomization " + quote(name),
+		"--namespace " + quote(namespace),
+	}
+	if s.Spec.Source != nil {
+		source := fmt.Sprintf("%s/%s", s.Spec.Source.Kind, s.Spec.Source.Name)
+		if s.Spec.Source.Namespace != nil && *s.Spec.Source.Namespace != namespace {
+			source = fmt.Sprintf("%s.%s", source, *s.Spec.Source.Namespace)
+		}
+		create = append(create, "--source "+quote(source))
+	}
+	if s.Spec.Path != nil {
+		create = append(create, "--path "+quote(*s.Spec.Path))
+	}
+	for _, d := range s.Spec.DependsOn {
+		dependency := d.Name
+		if d.Namespace != "" {
+			dependency = d.Namespace + "/" + d.Name
+		}
+		create = append(create, "--depends-on "+quote(dependency))
+	}
+
+	lines := []string{
+		fmt.Sprintf("# %s/%s", namespace, name),
+		strings.Join(create, " \\\n  "),
+	}
+
+	// flux create has no flag for substitutions so they are listed
+	// for adding to spec.postBuild.substitute of the exported object
+	if s.Spec.PostBuild != nil && len(s.Spec.PostBuild.Substitute) > 0 {
+		keys := make([]string, 0, len(s.Spec.PostBuild.Substitute))
+		for k := range s.Spec.PostBuild.Substitute {
+			keys = append(keys, k)
+		}
+		slices.Sort(keys)
+		lines = append(lines, "# spec.postBuild.substitute:")
+		for _, k := range keys {
+			lines = append(lines, fmt.Sprintf("#   %s: %s", k, s.Spec.PostBuild.Substitute[k]))
+		}
+	}
+
+	target := fmt.Sprintf("kustomization %s --namespace %s", quote(name), quote(namespace))
+	lines = append(lines,
+		"",
+		"flux reconcile "+target+" --with-source",
+		"flux suspend "+target,
+		"flux resume "+target,
+	)
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// buildCommand generates the kustomize and kubectl commands which
+// build the spec path of this kustomization outside of delorian.
+// Flags delorian builds with are listed as comments as they depend
+// on the tools installed where the command is run
+func (s *shortApi) buildCommand() string {
+	path := quote(s.GetAbsoluteSpecPath())
+	lines := []string{
+		fmt.Sprintf("# %s/%s", s.GetNamespace(), s.GetName()),
+		"# delorian builds with:",
+	}
+	for _, flag := range kustomize.BuildFlags() {
+		lines = append(lines, "#   "+flag)
+	}
+	lines = append(lines,
+		"kustomize build "+path,
+		"# or: kubectl kustomize "+path,
+	)
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// quote wraps values containing shell meta characters in
+// single quotes
+func quote(value string) string {
+	if value != "" && !strings.ContainsAny(value, " \t\n'\"$`\\|&;<>()*?[]{}~!#") {
+		return value
+	}
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
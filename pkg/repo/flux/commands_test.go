@@ -0,0 +1,92 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package flux
+
+import (
+	"testing"
+
+	"github.com/mproffitt/delorian/pkg/kustomize"
+	"sigs.k8s.io/kustomize/api/types"
+)
+
+func TestFluxCommands(t *testing.T) {
+	doc := `apiVersion: kustomize.toolkit.fluxcd.io/v1
+kind: Kustomization
+metadata:
+  name: apps
+  namespace: flux-system
+spec:
+  path: ./apps/${cluster_name}
+  dependsOn:
+    - name: infra
+  sourceRef:
+    kind: GitRepository
+    name: platform
+    namespace: sources
+  postBuild:
+    substitute:
+      region: eu-west-1
+      cluster_name: prod
+`
+	k, _, _, _ := parseYaml([]byte(doc), "/repo", "/repo/clusters/apps.yaml")
+	if len(k) != 1 {
+		t.Fatalf("expected 1 kustomization, got %d", len(k))
+	}
+
+	want := `# flux-system/apps
+flux create kustomization apps \
+  --namespace flux-system \
+  --source GitRepository/platform.sources \
+  --path './apps/${cluster_name}' \
+  --depends-on infra
+# spec.postBuild.substitute:
+#   cluster_name: prod
+#   region: eu-west-1
+
+flux reconcile kustomization apps --namespace flux-system --with-source
+flux suspend kustomization apps --namespace flux-system
+flux resume kustomization apps --namespace flux-system
+`
+	if got := k[0].fluxCommands(); got != want {
+		t.Errorf("unexpected commands\ngot:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestBuildCommand(t *testing.T) {
+	helm, restrictions := kustomize.Helm, kustomize.LoadRestrictions
+	defer func() { kustomize.Helm, kustomize.LoadRestrictions = helm, restrictions }()
+	kustomize.Helm = kustomize.HelmOff
+	kustomize.LoadRestrictions = types.LoadRestrictionsRootOnly
+
+	k, _, _, _ := parseYaml([]byte(multiDoc), "/my repo", "/my repo/clusters/apps.yaml")
+	if len(k) != 1 {
+		t.Fatalf("expected 1 kustomization, got %d", len(k))
+	}
+
+	want := `# flux-system/apps
+# delorian builds with:
+#   --load-restrictor LoadRestrictionsRootOnly
+kustomize build '/my repo/apps'
+# or: kubectl kustomize '/my repo/apps'
+`
+	if got := k[0].buildCommand(); got != want {
+		t.Errorf("unexpected command\ngot:\n%s\nwant:\n%s", got, want)
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package flux
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mproffitt/delorian/pkg/components"
+	"github.com/mproffitt/delorian/pkg/yaml"
+)
+
+// KeepComments copies the comments from the source manifests
+// into the build, which kustomize otherwise strips
+var KeepComments bool
+
+// commentedBuildCmd builds the kustomization and annotates each
+// resource with the comments from the manifest defining it
+func (s *shortApi) commentedBuildCmd() tea.Cmd {
+	dir := s.GetAbsoluteSpecPath()
+	return func() tea.Msg {
+		out, err := s.fluxExec(context.Background(), s.buildArgs)
+		if err != nil {
+			return components.ModelErrorMsg{Error: err}
+		}
+		return components.FluxExecMsg{Output: annotate(out, s.root, manifests(dir))}
+	}
+}
+
+// annotate copies the comments from the manifests in files onto the
+// documents they define in built, headed by a comment naming the
+// file relative to root. Documents defined nowhere are left as built
+func annotate(built, root string, files []string) string {
+	documents := yaml.Documents(built)
+	if len(documents) == 0 {
+		return built
+	}
+	sources := make([]yaml.Document, 0)
+	paths := make([]string, 0)
+	for _, path := range files {
+		content, err := os.ReadFile(filepath.Clean(path))
+		if err != nil {
+			continue
+		}
+		for _, d := range yaml.Documents(string(content)) {
+			sources = append(sources, d)
+			paths = append(paths, path)
+		}
+	}
+
+	out := make([]string, 0, len(documents))
+	for _, d := range documents {
+		i := definedIn(sources, d.Kind, d.Namespace, d.Name)
+		if i < 0 {
+			out = append(out, d.Raw)
+			continue
+		}
+		path, err := filepath.Rel(root, paths[i])
+		if err != nil {
+			path = paths[i]
+		}
+		out = append(out, "# source: "+filepath.ToSlash(path)+"\n"+yaml.Comments(d.Raw, sources[i].Raw))
+	}
+	return strings.Join(out, "---\n")
+}
@@ -0,0 +1,49 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package flux
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mproffitt/delorian/pkg/testutil"
+)
+
+func TestAnnotate(t *testing.T) {
+	root := t.TempDir()
+	source := "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: settings\ndata:\n  # debug is only for staging\n  LOG_LEVEL: debug\n"
+	path := testutil.WriteFile(t, root, filepath.Join("apps", "settings.yaml"), source)
+
+	built := "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: settings\n  namespace: apps\ndata:\n  LOG_LEVEL: debug\n" +
+		"---\napiVersion: v1\nkind: Namespace\nmetadata:\n  name: apps\n"
+	got := annotate(built, root, []string{path})
+	docs := strings.Split(got, "---\n")
+	if len(docs) != 2 {
+		t.Fatalf("expected two documents, got\n%s", got)
+	}
+	if !strings.HasPrefix(docs[0], "# source: apps/settings.yaml\n") ||
+		!strings.Contains(docs[0], "# debug is only for staging\n  LOG_LEVEL: debug") {
+		t.Errorf("expected the config map annotated from its source, got\n%s", docs[0])
+	}
+	if docs[1] != "apiVersion: v1\nkind: Namespace\nmetadata:\n  name: apps\n" {
+		t.Errorf("expected the namespace left as built, got\n%s", docs[1])
+	}
+}
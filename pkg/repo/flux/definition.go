@@ -0,0 +1,163 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package flux
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mproffitt/bmx/pkg/components/toast"
+	"github.com/mproffitt/delorian/pkg/components"
+	"github.com/mproffitt/delorian/pkg/kustomize"
+	"github.com/mproffitt/delorian/pkg/yaml"
+)
+
+// definition is a source manifest shown in place of the
+// kustomization when jumping from the diff
+type definition struct {
+	path    string
+	content string
+}
+
+// GetName returns the name of the manifest file
+func (d *definition) GetName() string {
+	return filepath.Base(d.path)
+}
+
+// GetPath returns the absolute path to the manifest
+func (d *definition) GetPath() string {
+	return d.path
+}
+
+// GetContent returns the manifest as written
+func (d *definition) GetContent() string {
+	return d.content
+}
+
+// definitionCmd finds the manifest under the spec path of the
+// kustomization which defines the resource named in msg, and
+// shows it on the kustomization tab at the document defining it
+func (s *shortApi) definitionCmd(msg components.DefinitionMsg) tea.Cmd {
+	dir := s.GetAbsoluteSpecPath()
+	return func() tea.Msg {
+		for _, path := range manifests(dir) {
+			content, err := os.ReadFile(filepath.Clean(path))
+			if err != nil {
+				continue
+			}
+			if line, ok := findDefinition(string(content), msg); ok {
+				return components.ShowFileMsg{
+					Tab:  components.TabKustomize,
+					File: &definition{path: path, content: string(content)},
+					Line: line,
+				}
+			}
+		}
+		title := yaml.Document{Kind: msg.Kind, Namespace: msg.Namespace, Name: msg.Name}.Title()
+		return toast.NewToastMsg{
+			Type:    toast.Warning,
+			Message: "No manifest under " + filepath.Base(dir) + " defines " + title,
+		}
+	}
+}
+
+// findDefinition returns the line of the document in content
+// defining the resource
+func findDefinition(content string, msg components.DefinitionMsg) (int, bool) {
+	docs := yaml.Documents(content)
+	i := definedIn(docs, msg.Kind, msg.Namespace, msg.Name)
+	if i < 0 {
+		return 0, false
+	}
+	return docs[i].Line, true
+}
+
+// definedIn returns the index of the document in docs defining
+// the resource, or -1 when there is none. Manifests often leave
+// the namespace to the kustomization so one without a namespace
+// also matches
+func definedIn(docs []yaml.Document, kind, namespace, name string) int {
+	found := -1
+	for i, d := range docs {
+		if d.Kind != kind || d.Name != name {
+			continue
+		}
+		switch d.Namespace {
+		case namespace:
+			return i
+		case "":
+			if found < 0 {
+				found = i
+			}
+		}
+	}
+	return found
+}
+
+// manifests returns the files kustomize reads resources from when
+// building dir, following directory resources and components into
+// their own kustomization. Without a kustomization file flux
+// generates one listing every manifest beneath dir
+func manifests(dir string) []string {
+	files := make([]string, 0)
+	collectManifests(dir, make(map[string]bool), 0, &files)
+	return files
+}
+
+func collectManifests(dir string, visited map[string]bool, depth int, files *[]string) {
+	dir, err := filepath.Abs(dir)
+	if err != nil || visited[dir] || depth > maxKustomizationDepth {
+		return
+	}
+	visited[dir] = true
+
+	_, kust := kustomize.GetKustomization(filepath.Join(dir, kustomize.Kustomization))
+	if kust == nil {
+		_ = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+			if err == nil && !d.IsDir() && isManifest(path) {
+				*files = append(*files, path)
+			}
+			return nil
+		})
+		return
+	}
+	for _, resource := range slices.Concat(kust.Resources, kust.Components) {
+		// Remote resources fail to stat and are skipped
+		path := filepath.Join(dir, resource)
+		fi, err := os.Stat(path)
+		switch {
+		case err != nil:
+		case fi.IsDir():
+			collectManifests(path, visited, depth+1, files)
+		case !slices.Contains(*files, path):
+			*files = append(*files, path)
+		}
+	}
+}
+
+// isManifest reports if path has a YAML extension
+func isManifest(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	return ext == ".yaml" || ext == ".yml"
+}
@@ -0,0 +1,83 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package flux
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/mproffitt/delorian/pkg/components"
+	"github.com/mproffitt/delorian/pkg/testutil"
+)
+
+func TestFindDefinition(t *testing.T) {
+	content := `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings
+  namespace: apps
+`
+	msg := components.DefinitionMsg{Kind: "ConfigMap", Namespace: "apps", Name: "settings"}
+	if line, ok := findDefinition(content, msg); !ok || line != 5 {
+		t.Errorf("expected the namespaced document on line 5, got %d %v", line, ok)
+	}
+	msg.Namespace = "other"
+	if line, ok := findDefinition(content, msg); !ok || line != 0 {
+		t.Errorf("expected the document without a namespace on line 0, got %d %v", line, ok)
+	}
+	msg.Kind = "Secret"
+	if _, ok := findDefinition(content, msg); ok {
+		t.Error("expected no definition for another kind")
+	}
+}
+
+func TestDefinitionFollowsResources(t *testing.T) {
+	root := t.TempDir()
+	testutil.WriteFile(t, root, "apps/prod/kustomization.yaml", "resources:\n- ../base\n- https://example.com/remote.yaml\n")
+	testutil.WriteFile(t, root, "apps/base/kustomization.yaml", "resources:\n- deployment.yaml\n")
+	testutil.WriteFile(t, root, "apps/base/deployment.yaml", "# podinfo\n---\napiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: podinfo\n")
+	testutil.WriteFile(t, root, "apps/base/unused.yaml", "apiVersion: v1\nkind: Service\nmetadata:\n  name: podinfo\n")
+
+	files := manifests(filepath.Join(root, "apps/prod"))
+	if want := []string{filepath.Join(root, "apps/base/deployment.yaml")}; !slices.Equal(files, want) {
+		t.Fatalf("expected %v, got %v", want, files)
+	}
+
+	path := "./apps/prod"
+	api := &shortApi{root: root, Spec: shortSpec{Path: &path}}
+	msg := api.definitionCmd(components.DefinitionMsg{Kind: "Deployment", Namespace: "apps", Name: "podinfo"})()
+	show, ok := msg.(components.ShowFileMsg)
+	if !ok {
+		t.Fatalf("expected the manifest to be shown, got %T", msg)
+	}
+	if show.Tab != components.TabKustomize || show.File.GetName() != "deployment.yaml" || show.Line != 2 {
+		t.Errorf("unexpected definition %v %s %d", show.Tab, show.File.GetPath(), show.Line)
+	}
+
+	if _, ok := api.definitionCmd(components.DefinitionMsg{Kind: "Service", Name: "podinfo"})().(components.ShowFileMsg); ok {
+		t.Error("expected files outside the resources to be skipped")
+	}
+}
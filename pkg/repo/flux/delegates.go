@@ -20,11 +20,75 @@
 package flux
 
 import (
+	"io"
+
 	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	zone "github.com/lrstanley/bubblezone"
+	"github.com/mproffitt/delorian/pkg/components"
 	"github.com/mproffitt/delorian/pkg/theme"
 )
 
-func (m *Model) createListNormalDelegate() list.DefaultDelegate {
+// Ellipsis marks a title or description that has been truncated
+const Ellipsis = "…"
+
+// truncatingDelegate truncates titles and descriptions to the
+// width of the list before they are marked as zones. Truncating
+// after marking can cut off the end of the zone and hides that
+// text was lost
+type truncatingDelegate struct {
+	list.DefaultDelegate
+}
+
+// truncatedItem is a list item with its title and description
+// cut to fit the list
+type truncatedItem struct {
+	*shortApi
+	title       string
+	description string
+}
+
+func (t truncatedItem) Title() string {
+	return zone.Mark(t.id, t.title)
+}
+
+func (t truncatedItem) Description() string {
+	return t.description
+}
+
+func (d truncatingDelegate) Render(w io.Writer, m list.Model, index int, item list.Item) {
+	api, ok := item.(*shortApi)
+	if !ok {
+		d.DefaultDelegate.Render(w, m, index, item)
+		return
+	}
+	width := textWidth(d.DefaultDelegate, m.Width())
+	d.DefaultDelegate.Render(w, m, index, truncatedItem{
+		shortApi:    api,
+		title:       ansi.Truncate(api.titleText(), width, theme.Glyph(Ellipsis)),
+		description: ansi.Truncate(api.Description(), width, theme.Glyph(Ellipsis)),
+	})
+}
+
+// textWidth is the space available to item text in a list of
+// the given width
+func textWidth(d list.DefaultDelegate, width int) int {
+	return width - d.Styles.NormalTitle.GetPaddingLeft() - d.Styles.NormalTitle.GetPaddingRight()
+}
+
+// selectedStatusCmd shows the full title of the selected item in
+// the status bar when it is too wide to fit in the list
+func (m *Model) selectedStatusCmd() tea.Cmd {
+	api, ok := m.selectedKustomization()
+	width := textWidth(m.delegates.normal.DefaultDelegate, m.list.Width())
+	if !ok || width <= 0 || ansi.StringWidth(api.titleText()) <= width {
+		return components.StatusCmd("selected", "")
+	}
+	return components.StatusCmd("selected", api.titleText())
+}
+
+func (m *Model) createListNormalDelegate() truncatingDelegate {
 	delegate := list.NewDefaultDelegate()
 	delegate.Styles.NormalTitle = delegate.Styles.NormalTitle.
 		Foreground(theme.Colours.Purple)
@@ -35,10 +99,10 @@ func (m *Model) createListNormalDelegate() list.DefaultDelegate {
 		Foreground(theme.Colours.BrightBlue)
 	delegate.Styles.SelectedDesc = delegate.Styles.SelectedDesc.
 		Foreground(theme.Colours.BrightWhite)
-	return delegate
+	return truncatingDelegate{delegate}
 }
 
-func (m *Model) createListShadedDelegate() list.DefaultDelegate {
+func (m *Model) createListShadedDelegate() truncatingDelegate {
 	delegate := list.NewDefaultDelegate()
 	delegate.Styles.NormalTitle = delegate.Styles.NormalTitle.
 		Foreground(theme.Colours.BrightBlack)
@@ -48,5 +112,5 @@ func (m *Model) createListShadedDelegate() list.DefaultDelegate {
 		Foreground(theme.Colours.BrightBlack)
 	delegate.Styles.SelectedDesc = delegate.Styles.SelectedDesc.
 		Foreground(theme.Colours.BrightBlack)
-	return delegate
+	return truncatingDelegate{delegate}
 }
@@ -0,0 +1,52 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package flux
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/mproffitt/delorian/pkg/testutil"
+)
+
+func TestTruncatingDelegate(t *testing.T) {
+	testutil.Setup()
+	m := New("/repo")
+	k, _, _, _ := parseYaml([]byte(multiDoc), "/repo", "/repo/clusters/apps.yaml")
+	k[0].Metadata.Name = "アプリケーション-platform-apps"
+
+	width := 16
+	l := list.New([]list.Item{&k[0]}, m.delegates.normal, width, 10)
+	var out bytes.Buffer
+	m.delegates.normal.Render(&out, l, 1, &k[0])
+
+	lines := strings.Split(out.String(), "\n")
+	if !strings.Contains(lines[0], Ellipsis) {
+		t.Errorf("expected truncated title to end with %q, got %q", Ellipsis, lines[0])
+	}
+	for _, line := range lines {
+		if w := ansi.StringWidth(line); w > width {
+			t.Errorf("line %q is %d cells wide, want at most %d", line, w, width)
+		}
+	}
+}
@@ -0,0 +1,209 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package flux
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mproffitt/bmx/pkg/components/toast"
+)
+
+// ExportGraph writes the relationships found by the scan to
+// GraphFilename
+var ExportGraph = key.NewBinding(key.WithKeys("J"),
+	key.WithHelp("J", "Export relationships as JSON"))
+
+// GraphFilename is the file the relationships are exported to
+var GraphFilename = "graph.json"
+
+// Graph is every relationship found while scanning the
+// repositories, for use by other tools
+type Graph struct {
+	Roots          []string             `json:"roots"`
+	Clusters       []GraphCluster       `json:"clusters"`
+	Kustomizations []GraphKustomization `json:"kustomizations"`
+	Sources        []GraphSource        `json:"sources"`
+}
+
+// GraphCluster is a cluster directory and the clusters nested
+// beneath it
+type GraphCluster struct {
+	Name     string         `json:"name"`
+	Root     string         `json:"root"`
+	Path     string         `json:"path"`
+	Children []GraphCluster `json:"children,omitempty"`
+}
+
+// GraphRef names another object in the graph. Kind is only set
+// for sources
+type GraphRef struct {
+	Kind      string `json:"kind,omitempty"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+}
+
+// GraphKustomization is a flux Kustomization and the objects it
+// is linked to
+type GraphKustomization struct {
+	Name      string     `json:"name"`
+	Namespace string     `json:"namespace"`
+	Type      string     `json:"type"`
+	Root      string     `json:"root"`
+	File      string     `json:"file"`
+	Path      string     `json:"path,omitempty"`
+	Clusters  []string   `json:"clusters,omitempty"`
+	Source    *GraphRef  `json:"source,omitempty"`
+	Parent    *GraphRef  `json:"parent,omitempty"`
+	Children  []GraphRef `json:"children,omitempty"`
+	DependsOn []GraphRef `json:"dependsOn,omitempty"`
+}
+
+// GraphSource is a source and the kustomizations built from it
+type GraphSource struct {
+	Kind           string     `json:"kind"`
+	Name           string     `json:"name"`
+	Namespace      string     `json:"namespace"`
+	Root           string     `json:"root"`
+	File           string     `json:"file"`
+	URL            string     `json:"url,omitempty"`
+	DefinedBy      *GraphRef  `json:"definedBy,omitempty"`
+	Kustomizations []GraphRef `json:"kustomizations,omitempty"`
+}
+
+// Graph collects the relationships from the last scan. Every
+// cluster is included whatever the cluster filter
+func (m *Model) Graph() Graph {
+	g := Graph{
+		Roots:          slices.Clone(m.roots),
+		Clusters:       graphClusters(m.clusters),
+		Kustomizations: make([]GraphKustomization, 0, len(m.kustomizations)),
+		Sources:        make([]GraphSource, 0, len(m.sources)),
+	}
+
+	for i := range m.kustomizations {
+		k := &m.kustomizations[i]
+		gk := GraphKustomization{
+			Name:      k.GetName(),
+			Namespace: k.GetNamespace(),
+			Type:      k.ftype.String(),
+			Root:      k.root,
+			File:      filepath.ToSlash(k.filepath),
+			Clusters:  branch(m.clusters, k.GetPath()),
+			Parent:    kustomizationRef(k.parent),
+		}
+		if k.Spec.Path != nil {
+			gk.Path = *k.Spec.Path
+		}
+		if k.source != nil {
+			gk.Source = &GraphRef{Kind: k.source.Kind,
+				Namespace: k.source.GetNamespace(), Name: k.source.GetName()}
+		}
+		for _, child := range k.children {
+			gk.Children = append(gk.Children, *kustomizationRef(child))
+		}
+		for _, dep := range k.Spec.DependsOn {
+			gk.DependsOn = append(gk.DependsOn,
+				GraphRef{Namespace: cmp.Or(dep.Namespace, k.GetNamespace()), Name: dep.Name})
+		}
+		g.Kustomizations = append(g.Kustomizations, gk)
+	}
+
+	for i := range m.sources {
+		s := &m.sources[i]
+		gs := GraphSource{
+			Kind:      s.Kind,
+			Name:      s.GetName(),
+			Namespace: s.GetNamespace(),
+			Root:      s.root,
+			File:      filepath.ToSlash(s.filepath),
+			URL:       s.url,
+			DefinedBy: kustomizationRef(s.parent),
+		}
+		for _, child := range s.children {
+			gs.Kustomizations = append(gs.Kustomizations, *kustomizationRef(child))
+		}
+		g.Sources = append(g.Sources, gs)
+	}
+
+	// Sorted so exports of the same repository can be compared
+	slices.SortStableFunc(g.Kustomizations, func(a, b GraphKustomization) int {
+		return cmp.Or(cmp.Compare(a.Namespace, b.Namespace),
+			cmp.Compare(a.Name, b.Name), cmp.Compare(a.File, b.File))
+	})
+	slices.SortStableFunc(g.Sources, func(a, b GraphSource) int {
+		return cmp.Or(cmp.Compare(a.Namespace, b.Namespace),
+			cmp.Compare(a.Name, b.Name), cmp.Compare(a.Kind, b.Kind))
+	})
+	return g
+}
+
+// kustomizationRef names k, or returns nil when there is none
+func kustomizationRef(k *shortApi) *GraphRef {
+	if k == nil {
+		return nil
+	}
+	return &GraphRef{Namespace: k.GetNamespace(), Name: k.GetName()}
+}
+
+// graphClusters converts the cluster tree, sorted by name
+func graphClusters(clusters []*cluster) []GraphCluster {
+	graph := make([]GraphCluster, 0, len(clusters))
+	for _, c := range clusters {
+		path, err := filepath.Rel(c.root, c.filepath)
+		if err != nil {
+			path = c.filepath
+		}
+		gc := GraphCluster{Name: c.name, Root: c.root, Path: filepath.ToSlash(path)}
+		if len(c.children) > 0 {
+			gc.Children = graphClusters(c.children)
+		}
+		graph = append(graph, gc)
+	}
+	slices.SortStableFunc(graph, func(a, b GraphCluster) int {
+		return cmp.Compare(a.Name, b.Name)
+	})
+	return graph
+}
+
+// exportGraphCmd writes the graph to GraphFilename
+func (m *Model) exportGraphCmd() tea.Cmd {
+	m.Lock()
+	g := m.Graph()
+	m.Unlock()
+	return func() tea.Msg {
+		content, err := json.MarshalIndent(g, "", "  ")
+		if err == nil {
+			err = os.WriteFile(GraphFilename, append(content, '\n'), 0o644)
+		}
+		if err != nil {
+			return toast.NewToastMsg{Type: toast.Error,
+				Message: "Unable to export relationships: " + err.Error()}
+		}
+		return toast.NewToastMsg{Type: toast.Success, Message: fmt.Sprintf(
+			"Exported %d kustomizations to %s", len(g.Kustomizations), GraphFilename)}
+	}
+}
@@ -0,0 +1,74 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package flux
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestGraph(t *testing.T) {
+	ns := "flux-system"
+	apps := "./apps"
+	m := &Model{
+		roots: []string{"/repo"},
+		clusters: []*cluster{{name: "clusters", root: "/repo", filepath: "/repo/clusters",
+			children: []*cluster{{name: "prod", root: "/repo", filepath: "/repo/clusters/prod"}}}},
+		kustomizations: []shortApi{
+			{Metadata: shortMeta{Name: "infra", Namespace: &ns}, ftype: Complete, root: "/repo",
+				filepath: "clusters/prod/infra.yaml"},
+			{Metadata: shortMeta{Name: "apps", Namespace: &ns}, ftype: Complete, root: "/repo",
+				filepath: "clusters/prod/apps.yaml",
+				Spec:     shortSpec{Path: &apps, DependsOn: []dependency{{Name: "infra"}}}},
+		},
+		sources: []shortSource{{Kind: "GitRepository", shortMeta: shortMeta{Name: "fleet", Namespace: &ns},
+			root: "/repo", filepath: "clusters/prod/source.yaml", url: "https://example.com/fleet"}},
+	}
+	infra, app, source := &m.kustomizations[0], &m.kustomizations[1], &m.sources[0]
+	infra.children = []*shortApi{app}
+	app.parent = infra
+	app.source = source
+	source.parent = infra
+	source.children = []*shortApi{app}
+
+	content, err := json.Marshal(m.Graph())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"roots":["/repo"],` +
+		`"clusters":[{"name":"clusters","root":"/repo","path":"clusters",` +
+		`"children":[{"name":"prod","root":"/repo","path":"clusters/prod"}]}],` +
+		`"kustomizations":[` +
+		`{"name":"apps","namespace":"flux-system","type":"complete","root":"/repo",` +
+		`"file":"clusters/prod/apps.yaml","path":"./apps","clusters":["clusters","prod"],` +
+		`"source":{"kind":"GitRepository","namespace":"flux-system","name":"fleet"},` +
+		`"parent":{"namespace":"flux-system","name":"infra"},` +
+		`"dependsOn":[{"namespace":"flux-system","name":"infra"}]},` +
+		`{"name":"infra","namespace":"flux-system","type":"complete","root":"/repo",` +
+		`"file":"clusters/prod/infra.yaml","clusters":["clusters","prod"],` +
+		`"children":[{"namespace":"flux-system","name":"apps"}]}],` +
+		`"sources":[{"kind":"GitRepository","name":"fleet","namespace":"flux-system","root":"/repo",` +
+		`"file":"clusters/prod/source.yaml","url":"https://example.com/fleet",` +
+		`"definedBy":{"namespace":"flux-system","name":"infra"},` +
+		`"kustomizations":[{"namespace":"flux-system","name":"apps"}]}]}`
+	if string(content) != want {
+		t.Errorf("unexpected graph\n got: %s\nwant: %s", content, want)
+	}
+}
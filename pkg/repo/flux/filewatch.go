@@ -0,0 +1,221 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package flux
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/log"
+	"github.com/fsnotify/fsnotify"
+	"github.com/mproffitt/delorian/pkg/components"
+	"github.com/mproffitt/delorian/pkg/theme"
+)
+
+// FileWatch rescans the repository as soon as a YAML file in it
+// changes, rather than waiting for PollInterval
+var FileWatch bool
+
+// FileWatchFallback is how often files are polled instead when
+// the file watcher cannot start, for example when the inotify
+// limits are reached, and no PollInterval was given
+var FileWatchFallback = 2 * time.Second
+
+// FileWatchDebounce is how long changes must stop for before the
+// repository is scanned again. Editors often write a file more
+// than once when saving
+var FileWatchDebounce = 500 * time.Millisecond
+
+// fileWatcher notices changes to YAML files beneath the roots.
+// fsnotify does not watch recursively so every directory is
+// added, including any created later
+type fileWatcher struct {
+	roots   []string
+	watcher *fsnotify.Watcher
+	changes chan struct{}
+	done    chan struct{}
+	once    sync.Once
+}
+
+// newFileWatcher starts watching every directory beneath roots.
+// An error is returned if any of them cannot be watched
+func newFileWatcher(roots []string) (*fileWatcher, error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	f := &fileWatcher{
+		roots:   roots,
+		watcher: watcher,
+		changes: make(chan struct{}, 1),
+		done:    make(chan struct{}),
+	}
+	for _, root := range roots {
+		if err := f.add(root); err != nil {
+			_ = watcher.Close()
+			return nil, err
+		}
+	}
+	go f.run()
+	return f, nil
+}
+
+// add watches dir and every directory beneath it, stopping at
+// the first directory which cannot be watched
+func (f *fileWatcher) add(dir string) error {
+	return filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil && path == dir {
+			return err
+		}
+		if err != nil || !d.IsDir() {
+			return nil
+		}
+		if f.ignored(path) {
+			return filepath.SkipDir
+		}
+		if err := f.watcher.Add(path); err != nil {
+			return fmt.Errorf("watching %s: %w", path, err)
+		}
+		return nil
+	})
+}
+
+// ignored reports if path is hidden within its root, such as
+// anything beneath .git
+func (f *fileWatcher) ignored(path string) bool {
+	for _, root := range f.roots {
+		if rel, err := filepath.Rel(root, path); err == nil && !strings.HasPrefix(rel, "..") {
+			return rel != "." && hidden(separator+rel)
+		}
+	}
+	return hidden(path)
+}
+
+// relevant reports if an event could change what the scan finds
+func (f *fileWatcher) relevant(event fsnotify.Event) bool {
+	if event.Op == fsnotify.Chmod || f.ignored(event.Name) {
+		return false
+	}
+	if event.Has(fsnotify.Create) {
+		if fi, err := os.Stat(event.Name); err == nil && fi.IsDir() {
+			if err := f.add(event.Name); err != nil {
+				log.Warn("file watcher", "error", err)
+			}
+			return true
+		}
+	}
+	ext := strings.ToLower(filepath.Ext(event.Name))
+	return ext == ".yaml" || ext == ".yml"
+}
+
+// run debounces relevant events into a single change
+func (f *fileWatcher) run() {
+	var timer *time.Timer
+	notify := func() {
+		select {
+		case f.changes <- struct{}{}:
+		default:
+		}
+	}
+	for {
+		select {
+		case event, ok := <-f.watcher.Events:
+			if !ok {
+				return
+			}
+			if !f.relevant(event) {
+				continue
+			}
+			log.Debug("file watcher", "event", event)
+			if timer != nil {
+				timer.Stop()
+			}
+			timer = time.AfterFunc(FileWatchDebounce, notify)
+		case err, ok := <-f.watcher.Errors:
+			if !ok {
+				return
+			}
+			log.Warn("file watcher", "error", err)
+		}
+	}
+}
+
+// waitCmd blocks until the files have changed. Nothing is
+// returned once the watcher is closed
+func (f *fileWatcher) waitCmd() tea.Cmd {
+	return func() tea.Msg {
+		select {
+		case <-f.changes:
+			return components.FilesChangedMsg{}
+		case <-f.done:
+			return nil
+		}
+	}
+}
+
+// Close stops watching. It is safe to call more than once
+func (f *fileWatcher) Close() error {
+	var err error
+	f.once.Do(func() {
+		close(f.done)
+		err = f.watcher.Close()
+	})
+	return err
+}
+
+// fileWatchCmd starts the file watcher the first time the model
+// is initialised. Rescans keep the watcher already running.
+//
+// When the watcher cannot start the files are polled instead
+func (m *Model) fileWatchCmd() tea.Cmd {
+	if !FileWatch || m.files != nil || m.poll.fallback {
+		return nil
+	}
+	files, err := newFileWatcher(m.roots)
+	if err != nil {
+		log.Warn("file watcher unavailable, polling instead", "error", err)
+		m.poll.fallback = true
+		return components.StatusCmd("watch", theme.Glyph("⚠ polling for changes"))
+	}
+	m.files = files
+	return m.files.waitCmd()
+}
+
+// filesChanged scans the repository again. Links between files
+// mean a change to one can alter kustomizations defined in any
+// other so the whole walk is repeated
+func (m *Model) filesChanged() tea.Cmd {
+	return tea.Batch(m.files.waitCmd(),
+		tea.Sequence(ScanningStatusCmd(), components.RescanCmd()))
+}
+
+// Close releases the file watcher
+func (m *Model) Close() error {
+	if m.files == nil {
+		return nil
+	}
+	return m.files.Close()
+}
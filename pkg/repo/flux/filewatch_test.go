@@ -0,0 +1,121 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package flux
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mproffitt/delorian/pkg/components"
+	"github.com/mproffitt/delorian/pkg/testutil"
+)
+
+// await runs cmd and waits for its message
+func await(t *testing.T, cmd tea.Cmd) tea.Msg {
+	t.Helper()
+	done := make(chan tea.Msg, 1)
+	go func() { done <- cmd() }()
+	select {
+	case msg := <-done:
+		return msg
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the file watcher")
+	}
+	return nil
+}
+
+func TestFileWatcher(t *testing.T) {
+	previous := FileWatchDebounce
+	defer func() { FileWatchDebounce = previous }()
+	FileWatchDebounce = 10 * time.Millisecond
+
+	root := t.TempDir()
+	testutil.MkdirAll(t, root, ".git")
+	f, err := newFileWatcher([]string{root})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	// Files which aren't YAML or are hidden are ignored
+	for _, name := range []string{"README.md", filepath.Join(".git", "index.yaml")} {
+		testutil.WriteFile(t, root, name, "x")
+	}
+	time.Sleep(5 * FileWatchDebounce)
+	select {
+	case <-f.changes:
+		t.Fatal("expected ignored files not to trigger a change")
+	default:
+	}
+
+	// Directories created after the watcher started are watched
+	dir := testutil.MkdirAll(t, root, "apps")
+	if _, ok := await(t, f.waitCmd()).(components.FilesChangedMsg); !ok {
+		t.Fatal("expected the new directory to be noticed")
+	}
+	testutil.WriteFile(t, dir, "kustomization.yaml", "resources: []\n")
+	if _, ok := await(t, f.waitCmd()).(components.FilesChangedMsg); !ok {
+		t.Fatal("expected the yaml file to be noticed")
+	}
+
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if msg := await(t, f.waitCmd()); msg != nil {
+		t.Errorf("expected nothing once closed, got %T", msg)
+	}
+}
+
+func TestFileWatchFallback(t *testing.T) {
+	previous, interval := FileWatch, PollInterval
+	defer func() { FileWatch, PollInterval = previous, interval }()
+	FileWatch, PollInterval = true, 0
+
+	// The watcher cannot start on a root which doesn't exist
+	m := New(filepath.Join(t.TempDir(), "missing"))
+	if cmd := m.fileWatchCmd(); cmd == nil {
+		t.Fatal("expected a status when the watcher cannot start")
+	}
+	if m.files != nil || !m.poll.fallback {
+		t.Fatal("expected the model to fall back to polling")
+	}
+	if got := m.pollInterval(); got != FileWatchFallback {
+		t.Errorf("expected to poll every %s, got %s", FileWatchFallback, got)
+	}
+	PollInterval = time.Second
+	if got := m.pollInterval(); got != time.Second {
+		t.Errorf("expected --poll-interval to be kept, got %s", got)
+	}
+
+	// A running watcher replaces polling so changes aren't scanned twice
+	m = New(t.TempDir())
+	if cmd := m.fileWatchCmd(); cmd == nil || m.files == nil {
+		t.Fatal("expected the watcher to start")
+	}
+	defer m.Close()
+	if got := m.pollInterval(); got != 0 {
+		t.Errorf("expected no polling while watching, got %s", got)
+	}
+	if cmd := m.pollCmd(); cmd != nil {
+		t.Error("expected no poll command while watching")
+	}
+}
@@ -0,0 +1,57 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package flux
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/mproffitt/delorian/pkg/components"
+	"github.com/mproffitt/delorian/pkg/fixtures"
+	"github.com/mproffitt/delorian/pkg/testutil"
+)
+
+func TestBuildFromFixtures(t *testing.T) {
+	previous := fixtures.Dir
+	t.Cleanup(func() {
+		fixtures.Dir = previous
+		components.SetRunner(nil)
+	})
+	fixtures.Dir = t.TempDir()
+	components.SetRunner(fixtures.Runner{})
+	build := "apiVersion: apps/v1\nkind: Deployment\n"
+	testutil.WriteFile(t, fixtures.Dir, filepath.Join("apps", fixtures.BuildFile), build)
+
+	root := t.TempDir()
+	testutil.MkdirAll(t, root, "apps")
+	k, _, _, _ := parseYaml([]byte(multiDoc), root, filepath.Join(root, "clusters", "apps.yaml"))
+	msg := k[0].Build()()
+	exec, ok := msg.(components.FluxExecMsg)
+	if !ok {
+		t.Fatalf("expected FluxExecMsg, got %T %v", msg, msg)
+	}
+	if exec.Output != build {
+		t.Errorf("expected fixture output %q, got %q", build, exec.Output)
+	}
+
+	if _, ok := k[0].Diff()().(components.ModelErrorMsg); !ok {
+		t.Error("expected an error when there is no diff fixture")
+	}
+}
@@ -0,0 +1,74 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package flux
+
+import (
+	"fmt"
+	"strings"
+)
+
+var (
+	// BuildFlags are extra flags appended to `flux build`
+	BuildFlags []string
+
+	// DiffFlags are extra flags appended to `flux diff`
+	DiffFlags []string
+)
+
+// reservedFlags are set by delorian on every build and diff and
+// cannot be overridden
+var reservedFlags = []string{
+	"-n", "--namespace",
+	"--path",
+	"--kustomization-file",
+	"--dry-run",
+	"--strict-substitute",
+	"--progress-bar",
+	"--context",
+}
+
+// SetExtraFlags validates and sets the extra flags passed to
+// flux build and flux diff
+func SetExtraFlags(build, diff []string) error {
+	if err := validateFlags("build", build); err != nil {
+		return err
+	}
+	if err := validateFlags("diff", diff); err != nil {
+		return err
+	}
+	BuildFlags, DiffFlags = build, diff
+	return nil
+}
+
+func validateFlags(command string, flags []string) error {
+	for _, flag := range flags {
+		if !strings.HasPrefix(flag, "-") {
+			continue
+		}
+		name, _, _ := strings.Cut(flag, "=")
+		for _, reserved := range reservedFlags {
+			if name == reserved {
+				return fmt.Errorf("flux %s flag %q is set by delorian and cannot be overridden",
+					command, name)
+			}
+		}
+	}
+	return nil
+}
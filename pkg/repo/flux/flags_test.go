@@ -0,0 +1,46 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package flux
+
+import "testing"
+
+func TestSetExtraFlags(t *testing.T) {
+	defer func() { BuildFlags, DiffFlags = nil, nil }()
+
+	tests := []struct {
+		name  string
+		build []string
+		diff  []string
+		valid bool
+	}{
+		{"passthrough", []string{"--recursive", "--local-sources", "GitRepository/flux-system/flux-system=./"}, []string{"--timeout=5m"}, true},
+		{"reserved", []string{"--path", "./other"}, nil, false},
+		{"reserved with value", nil, []string{"--progress-bar=true"}, false},
+		{"short reserved", []string{"-n", "default"}, nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := SetExtraFlags(tt.build, tt.diff)
+			if (err == nil) != tt.valid {
+				t.Errorf("SetExtraFlags() error = %v, valid %v", err, tt.valid)
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package flux
+
+import (
+	"cmp"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mproffitt/delorian/pkg/components"
+	"github.com/mproffitt/delorian/pkg/theme"
+)
+
+// graphCmd sends the relationships of the kustomization to the
+// graph tab
+func (m *Model) graphCmd(api *shortApi) tea.Cmd {
+	m.Lock()
+	defer m.Unlock()
+	var parent *components.GraphNode
+	if api.parent != nil {
+		parent = &components.GraphNode{
+			Kind:      api.parent.Kind,
+			Namespace: api.parent.GetNamespace(),
+			Name:      api.parent.GetName(),
+		}
+	}
+	return components.GraphCmd(m.graphNode(api, make(map[*shortApi]bool), 0), parent)
+}
+
+// graphNode converts the kustomization and those it builds, with
+// the source it defines as the last child. Kustomizations already
+// drawn higher up the branch are not followed again. Dependencies
+// built from substitutions can't be looked up so are never missing
+func (m *Model) graphNode(k *shortApi, visited map[*shortApi]bool, depth int) components.GraphNode {
+	node := components.GraphNode{
+		Kind:      cmp.Or(k.Kind, "Kustomization"),
+		Namespace: k.GetNamespace(),
+		Name:      k.GetName(),
+		Note:      k.ftype.String(),
+	}
+	if k.Spec.Path != nil {
+		node.Note += theme.Glyph(" · ") + *k.Spec.Path
+	}
+	for _, dep := range k.Spec.DependsOn {
+		namespace := cmp.Or(dep.Namespace, k.GetNamespace())
+		node.DependsOn = append(node.DependsOn, components.GraphDependency{
+			Namespace: namespace,
+			Name:      dep.Name,
+			Missing: !strings.Contains(namespace+dep.Name, "${") &&
+				!m.defined(namespace, dep.Name),
+		})
+	}
+	if visited[k] || depth > maxKustomizationDepth {
+		node.Note += theme.Glyph(" · already shown")
+		return node
+	}
+	visited[k] = true
+	defer delete(visited, k)
+
+	for _, child := range k.children {
+		node.Children = append(node.Children, m.graphNode(child, visited, depth+1))
+	}
+	if s := k.source; s != nil {
+		node.Children = append(node.Children, components.GraphNode{
+			Kind:      s.Kind,
+			Namespace: s.GetNamespace(),
+			Name:      s.GetName(),
+			Note:      cmp.Or(s.url, s.endpoint, "source"),
+		})
+	}
+	return node
+}
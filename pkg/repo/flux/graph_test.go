@@ -0,0 +1,67 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package flux
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/mproffitt/delorian/pkg/components"
+)
+
+func TestGraphNode(t *testing.T) {
+	ns := "flux-system"
+	path := "./apps"
+	root := &shortApi{Kind: "Kustomization", Metadata: shortMeta{Name: "root", Namespace: &ns}, ftype: Complete}
+	apps := &shortApi{Kind: "Kustomization", Metadata: shortMeta{Name: "apps", Namespace: &ns},
+		Spec: shortSpec{Path: &path}, ftype: Base, parent: root}
+	root.children = []*shortApi{apps}
+	root.source = &shortSource{shortMeta: shortMeta{Name: "repo", Namespace: &ns},
+		Kind: "GitRepository", url: "https://example.com/repo.git"}
+	// A kustomization building its own parent must not recurse
+	apps.children = []*shortApi{root}
+	apps.Spec.DependsOn = []dependency{{Name: "root"}, {Name: "infra", Namespace: "infra"}}
+
+	m := &Model{kustomizations: []shortApi{*root, *apps}}
+	node := m.graphNode(root, make(map[*shortApi]bool), 0)
+	if len(node.Children) != 2 {
+		t.Fatalf("expected the child and the source, got %+v", node.Children)
+	}
+	child, source := node.Children[0], node.Children[1]
+	if child.Name != "apps" || child.Note != "base · ./apps" {
+		t.Errorf("unexpected child %+v", child)
+	}
+	deps := []components.GraphDependency{
+		{Namespace: ns, Name: "root"},
+		{Namespace: "infra", Name: "infra", Missing: true},
+	}
+	if !slices.Equal(child.DependsOn, deps) {
+		t.Errorf("expected %+v, got %+v", deps, child.DependsOn)
+	}
+	if len(child.Children) != 1 || child.Children[0].Note != "complete · already shown" ||
+		len(child.Children[0].Children) != 0 {
+		t.Errorf("expected the cycle to stop at the root, got %+v", child.Children)
+	}
+	want := components.GraphNode{Kind: "GitRepository", Namespace: ns, Name: "repo",
+		Note: "https://example.com/repo.git"}
+	if source.Kind != want.Kind || source.Name != want.Name || source.Note != want.Note {
+		t.Errorf("expected %+v, got %+v", want, source)
+	}
+}
@@ -0,0 +1,184 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package flux
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/mproffitt/delorian/pkg/components"
+	"github.com/mproffitt/delorian/pkg/kustomize"
+)
+
+// validate runs the health checks against the scanned repository,
+// recording issues against the kustomizations they were found on
+func (m *Model) validate() []components.HealthIssue {
+	issues := make([]components.HealthIssue, 0)
+	for i := range m.kustomizations {
+		k := &m.kustomizations[i]
+		if k.ftype == Base {
+			continue
+		}
+		k.issues = m.validateSource(k)
+		unmet := m.validateDependsOn(k)
+		k.unmet = len(unmet)
+		k.issues = append(k.issues, unmet...)
+		k.issues = append(k.issues, validateSpecPath(k)...)
+		k.issues = append(k.issues, validateResources(k)...)
+		issues = append(issues, k.issues...)
+	}
+	return issues
+}
+
+// UnlinkedGroup is the heading orphans are listed under
+const UnlinkedGroup = "Unlinked kustomizations"
+
+// orphans reports bases that nothing links to. These are usually
+// applied directly by something outside the repository, or were
+// missed because their layout isn't one the scan recognises
+func (m *Model) orphans() []components.HealthIssue {
+	issues := make([]components.HealthIssue, 0)
+	for i := range m.kustomizations {
+		k := &m.kustomizations[i]
+		if k.ftype != Base || k.parent != nil {
+			continue
+		}
+		issue := k.issue(components.SeverityInfo, fmt.Sprintf(
+			"not listed in the resources or patches of %s and not found under the spec.path "+
+				"of another kustomization. Press B to list it", filepath.Base(k.kustomize)))
+		issue.Group = UnlinkedGroup
+		issues = append(issues, issue)
+	}
+	return issues
+}
+
+// validateSource reports a sourceRef which does not match any
+// source defined in the same repository. setSource has already
+// linked every source that could be found
+func (m *Model) validateSource(k *shortApi) []components.HealthIssue {
+	ref := k.Spec.Source
+	if ref == nil || k.source != nil {
+		return nil
+	}
+	// Names built from substitutions cannot be resolved locally
+	if strings.Contains(ref.GetName(), "${") || strings.Contains(ref.GetNamespace(), "${") {
+		return nil
+	}
+	return []components.HealthIssue{
+		k.issue(components.SeverityWarning, fmt.Sprintf(
+			"sourceRef %s %s/%s is not defined in %s",
+			ref.Kind, ref.GetNamespace(), ref.GetName(), filepath.Base(k.root))),
+	}
+}
+
+// validateDependsOn reports dependencies on kustomizations which
+// are not defined in any of the scanned repositories
+func (m *Model) validateDependsOn(k *shortApi) []components.HealthIssue {
+	issues := make([]components.HealthIssue, 0)
+	for _, dep := range k.Spec.DependsOn {
+		namespace := dep.Namespace
+		if namespace == "" {
+			namespace = k.GetNamespace()
+		}
+		if strings.Contains(dep.Name, "${") || strings.Contains(namespace, "${") {
+			continue
+		}
+		if !m.defined(namespace, dep.Name) {
+			issues = append(issues, k.issue(components.SeverityWarning, fmt.Sprintf(
+				"dependsOn %s/%s does not match any kustomization", namespace, dep.Name)))
+		}
+	}
+	return issues
+}
+
+// defined reports if a kustomization with the namespace and name
+// was found in any of the scanned repositories
+func (m *Model) defined(namespace, name string) bool {
+	return slices.ContainsFunc(m.kustomizations, func(o shortApi) bool {
+		return o.GetName() == name && o.GetNamespace() == namespace
+	})
+}
+
+// validateResources reports entries in kustomization.yaml files
+// reachable from the spec.path of k that point at files or
+// directories which do not exist
+func validateResources(k *shortApi) []components.HealthIssue {
+	if k.Spec.Path == nil {
+		return nil
+	}
+	issues := make([]components.HealthIssue, 0)
+	visited := make(map[string]bool)
+
+	var follow func(dir string)
+	follow = func(dir string) {
+		if visited[dir] {
+			return
+		}
+		visited[dir] = true
+
+		file, kust := kustomize.GetKustomization(filepath.Join(dir, kustomize.Kustomization))
+		if kust == nil {
+			return
+		}
+		for _, resource := range append(kust.Resources, kust.Components...) {
+			if isRemote(resource) || strings.Contains(resource, "${") {
+				continue
+			}
+			path := filepath.Join(dir, resource)
+			fi, err := os.Stat(path)
+			if err != nil {
+				issue := k.issue(components.SeverityError, fmt.Sprintf(
+					"resource %q in %s does not exist", resource, file))
+				issue.Filepath = file
+				issues = append(issues, issue)
+				continue
+			}
+			if fi.IsDir() {
+				follow(path)
+			}
+		}
+	}
+	follow(k.GetAbsoluteSpecPath())
+	return issues
+}
+
+// isRemote reports if a kustomization resource is fetched from
+// a remote location rather than the local filesystem
+func isRemote(resource string) bool {
+	return strings.Contains(resource, "://") ||
+		strings.Contains(resource, "?ref=") ||
+		strings.HasPrefix(resource, "github.com/") ||
+		strings.HasPrefix(resource, "git@")
+}
+
+// issue creates a health issue for this kustomization
+func (s *shortApi) issue(severity components.Severity, message string) components.HealthIssue {
+	return components.HealthIssue{
+		Severity:  severity,
+		Kind:      s.Kind,
+		Name:      s.GetName(),
+		Namespace: s.GetNamespace(),
+		Filepath:  s.GetPath(),
+		Message:   message,
+	}
+}
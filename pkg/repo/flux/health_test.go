@@ -0,0 +1,160 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package flux
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mproffitt/delorian/pkg/components"
+	"github.com/mproffitt/delorian/pkg/testutil"
+)
+
+const missingSource = `apiVersion: kustomize.toolkit.fluxcd.io/v1
+kind: Kustomization
+metadata:
+  name: infra
+  namespace: flux-system
+spec:
+  path: ./infra
+  sourceRef:
+    kind: GitRepository
+    name: platform
+`
+
+func TestValidateMissingSource(t *testing.T) {
+	root := t.TempDir()
+	testutil.MkdirAll(t, root, "apps")
+	m := New(root)
+	k, s, _, _ := parseYaml([]byte(multiDoc+"---\n"+missingSource), root, filepath.Join(root, "clusters", "apps.yaml"))
+	m.kustomizations, m.sources = k, s
+	for i := range m.kustomizations {
+		m.kustomizations[i].ftype = Complete
+		m.setSource(i)
+	}
+
+	issues := m.validate()
+	if len(issues) != 1 {
+		t.Fatalf("expected 1 issue, got %d: %v", len(issues), issues)
+	}
+	if issues[0].Name != "infra" {
+		t.Errorf("expected issue on infra, got %s", issues[0].Name)
+	}
+	want := "sourceRef GitRepository flux-system/platform"
+	if !strings.Contains(issues[0].Message, want) {
+		t.Errorf("expected message to contain %q, got %q", want, issues[0].Message)
+	}
+}
+
+func TestValidateDanglingReferences(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		"apps/kustomization.yaml":      "resources:\n  - base\n  - missing.yaml\n  - github.com/org/repo//deploy?ref=main\n",
+		"apps/base/kustomization.yaml": "resources:\n  - ../../nowhere\n",
+	}
+	for name, content := range files {
+		testutil.WriteFile(t, root, name, content)
+	}
+
+	doc := `apiVersion: kustomize.toolkit.fluxcd.io/v1
+kind: Kustomization
+metadata:
+  name: apps
+  namespace: flux-system
+spec:
+  path: ./apps
+  dependsOn:
+    - name: infra
+    - name: apps
+`
+	m := New(root)
+	m.kustomizations, m.sources, _, _ = parseYaml([]byte(doc), root, filepath.Join(root, "apps.yaml"))
+	m.kustomizations[0].ftype = Complete
+
+	messages := make([]string, 0)
+	for _, issue := range m.validate() {
+		messages = append(messages, issue.Message)
+	}
+	got := strings.Join(messages, "\n")
+	for _, want := range []string{
+		"dependsOn flux-system/infra does not match any kustomization",
+		`resource "missing.yaml"`,
+		`resource "../../nowhere"`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected an issue containing %q, got\n%s", want, got)
+		}
+	}
+	if len(messages) != 3 {
+		t.Errorf("expected 3 issues, got %d\n%s", len(messages), got)
+	}
+	if desc := m.kustomizations[0].Description(); !strings.Contains(desc, "⇢ 1 unmet") {
+		t.Errorf("expected the unmet dependency in the description, got %q", desc)
+	}
+}
+
+func TestValidateSpecPathOutsideRepo(t *testing.T) {
+	root := t.TempDir()
+	m := New(root)
+	k, s, _, _ := parseYaml([]byte(multiDoc), root, filepath.Join(root, "clusters", "apps.yaml"))
+	m.kustomizations, m.sources = k, s
+	m.kustomizations[0].ftype = Complete
+	m.setSource(0)
+
+	issues := m.validate()
+	if len(issues) != 1 {
+		t.Fatalf("expected 1 issue, got %d: %v", len(issues), issues)
+	}
+	want := "spec.path ./apps does not exist"
+	if !strings.Contains(issues[0].Message, want) {
+		t.Errorf("expected message to contain %q, got %q", want, issues[0].Message)
+	}
+
+	_, err := m.kustomizations[0].fluxExec(context.Background(), m.kustomizations[0].buildArgs)
+	if err == nil || !strings.Contains(err.Error(), "not available in the scanned repository") {
+		t.Errorf("expected a missing source error, got %v", err)
+	}
+}
+
+func TestOrphans(t *testing.T) {
+	m := &Model{kustomizations: []shortApi{
+		{ftype: Complete, Kind: "Kustomization", kustomize: "/repo/clusters/kustomization.yaml"},
+		{ftype: Base, Kind: "Kustomization", kustomize: "/repo/clusters/kustomization.yaml"},
+		{ftype: Base, Kind: "Kustomization", kustomize: "/repo/clusters/kustomization.yaml"},
+	}}
+	m.kustomizations[0].Metadata.Name = "linked"
+	m.kustomizations[1].Metadata.Name = "orphan"
+	m.kustomizations[2].Metadata.Name = "base"
+	m.kustomizations[2].parent = &m.kustomizations[0]
+
+	issues := m.orphans()
+	if len(issues) != 1 {
+		t.Fatalf("expected 1 orphan, got %d: %v", len(issues), issues)
+	}
+	if issues[0].Name != "orphan" || issues[0].Severity != components.SeverityInfo ||
+		issues[0].Group != UnlinkedGroup {
+		t.Errorf("expected an unlinked info issue on orphan, got %v", issues[0])
+	}
+	if !strings.Contains(issues[0].Message, "kustomization.yaml") {
+		t.Errorf("expected the hint to name the kustomization file, got %q", issues[0].Message)
+	}
+}
@@ -0,0 +1,170 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package flux
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/mproffitt/bmx/pkg/components/dialog"
+	"github.com/mproffitt/bmx/pkg/components/toast"
+	"github.com/mproffitt/delorian/pkg/components"
+	"github.com/mproffitt/delorian/pkg/theme"
+	"github.com/mproffitt/delorian/pkg/yaml"
+)
+
+// CompareEnvironments builds the selected kustomization in each
+// cluster defining it and shows the fields which differ
+var CompareEnvironments = key.NewBinding(key.WithKeys("H"),
+	key.WithHelp("H", "Compare across environments"))
+
+// HeatmapEnvironments is the most environments compared at once,
+// starting with the one selected
+var HeatmapEnvironments = 2
+
+// HeatmapRows is the most fields listed before the rest are
+// summarised
+var HeatmapRows = 30
+
+// heatmapWidth is the width of the comparison dialog
+const heatmapWidth = 100
+
+// environment is a kustomization as defined for one cluster
+type environment struct {
+	name string
+	api  *shortApi
+}
+
+// environments finds k and the kustomizations with the same name
+// and namespace in other clusters, one per cluster
+func (m *Model) environments(k *shortApi) []environment {
+	name := func(api *shortApi) string {
+		if b := branch(m.clusters, api.GetPath()); len(b) > 0 {
+			return strings.Join(b, "/")
+		}
+		return filepath.Base(api.root)
+	}
+	envs := []environment{{name: name(k), api: k}}
+	seen := map[string]bool{envs[0].name: true}
+	for i := range m.kustomizations {
+		other := &m.kustomizations[i]
+		if other == k || other.ftype == Patch || other.GetName() != k.GetName() ||
+			other.GetNamespace() != k.GetNamespace() {
+			continue
+		}
+		env := environment{name: name(other), api: other}
+		if seen[env.name] {
+			continue
+		}
+		seen[env.name] = true
+		envs = append(envs, env)
+		if len(envs) == HeatmapEnvironments {
+			break
+		}
+	}
+	return envs
+}
+
+// compareEnvironmentsCmd builds k in each environment and opens
+// the fields which differ as a table
+func (m *Model) compareEnvironmentsCmd(k *shortApi) tea.Cmd {
+	m.Lock()
+	envs := m.environments(k)
+	m.Unlock()
+	if len(envs) < 2 {
+		return toast.NewToastCmd(toast.Info, "No other cluster defines "+k.GetName())
+	}
+	return func() tea.Msg {
+		names := make([]string, 0, len(envs))
+		streams := make([][]byte, 0, len(envs))
+		for _, env := range envs {
+			out, err := env.api.fluxExec(context.Background(), env.api.buildArgs)
+			if err != nil {
+				return toast.NewToastMsg{Type: toast.Error,
+					Message: fmt.Sprintf("Unable to build %s for %s: %v", k.GetName(), env.name, err)}
+			}
+			names = append(names, env.name)
+			streams = append(streams, []byte(out))
+		}
+		fields, err := yaml.Matrix(streams...)
+		if err != nil {
+			return toast.NewToastMsg{Type: toast.Error, Message: "Unable to compare environments: " + err.Error()}
+		}
+		if len(fields) == 0 {
+			return toast.NewToastMsg{Type: toast.Info,
+				Message: fmt.Sprintf("%s renders the same in %s", k.GetName(), strings.Join(names, " and "))}
+		}
+		return components.OpenDialogMsg{
+			Dialog: dialog.NewOKDialog(heatmap(names, fields, heatmapWidth), heatmapWidth)}
+	}
+}
+
+// heatmap draws the fields as a table with a column for each
+// environment. Values matching the first environment are dimmed,
+// those which differ from it highlighted and missing ones marked
+func heatmap(names []string, fields []yaml.Field, width int) string {
+	column := max(8, (width-theme.Padding)/(len(names)+2))
+	path := width - theme.Padding - column*len(names)
+	cell := func(value string, w int, style lipgloss.Style) string {
+		return style.Width(w).MaxWidth(w).Render(ansi.Truncate(value, w-1, theme.Glyph("…")))
+	}
+	bold := lipgloss.NewStyle().Bold(true)
+	same := lipgloss.NewStyle().Foreground(theme.Colours.BrightBlack)
+	changed := lipgloss.NewStyle().Foreground(theme.Colours.Black).Background(theme.Colours.Yellow)
+	missing := lipgloss.NewStyle().Foreground(theme.Colours.Red)
+	object := lipgloss.NewStyle().Foreground(theme.Colours.Blue).Bold(true)
+
+	header := []string{cell("field", path, bold)}
+	for _, name := range names {
+		header = append(header, cell(name, column, bold))
+	}
+	rows := []string{lipgloss.JoinHorizontal(lipgloss.Top, header...)}
+
+	current := ""
+	for i, field := range fields {
+		if i == HeatmapRows {
+			rows = append(rows, same.Render(fmt.Sprintf(theme.Glyph("… %d more fields"), len(fields)-i)))
+			break
+		}
+		if field.Object != current {
+			current = field.Object
+			rows = append(rows, object.Render(current))
+		}
+		row := []string{cell("  "+field.Path, path, lipgloss.NewStyle())}
+		for j := range names {
+			switch {
+			case !field.Present[j]:
+				row = append(row, cell(theme.Glyph("∅ unset"), column, missing))
+			case j > 0 && (!field.Present[0] || field.Values[j] != field.Values[0]):
+				row = append(row, cell(field.Values[j], column, changed))
+			default:
+				row = append(row, cell(field.Values[j], column, same))
+			}
+		}
+		rows = append(rows, lipgloss.JoinHorizontal(lipgloss.Top, row...))
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, rows...)
+}
@@ -0,0 +1,76 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package flux
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mproffitt/delorian/pkg/yaml"
+)
+
+func TestEnvironments(t *testing.T) {
+	root := t.TempDir()
+	m := New(root)
+	m.clusters = []*cluster{
+		{name: "dev", root: root, filepath: filepath.Join(root, "clusters", "dev")},
+		{name: "prod", root: root, filepath: filepath.Join(root, "clusters", "prod")},
+	}
+	k := func(name, cluster string) shortApi {
+		return shortApi{Metadata: shortMeta{Name: name}, ftype: Complete,
+			root: root, filepath: filepath.Join("clusters", cluster, name+".yaml")}
+	}
+	m.kustomizations = []shortApi{
+		k("apps", "dev"), k("infra", "dev"), k("apps", "prod"), k("apps", "prod"),
+	}
+	envs := m.environments(&m.kustomizations[0])
+	if len(envs) != 2 || envs[0].name != "dev" || envs[1].name != "prod" ||
+		envs[1].api != &m.kustomizations[2] {
+		t.Errorf("expected apps in dev and prod, got %+v", envs)
+	}
+	if envs := m.environments(&m.kustomizations[1]); len(envs) != 1 {
+		t.Errorf("expected infra to only be found in dev, got %+v", envs)
+	}
+}
+
+func TestHeatmap(t *testing.T) {
+	fields := []yaml.Field{
+		{Object: "Deployment/apps/podinfo", Path: "spec.replicas",
+			Values: []string{"1", "3"}, Present: []bool{true, true}},
+		{Object: "Deployment/apps/podinfo", Path: "spec.paused",
+			Values: []string{"true", ""}, Present: []bool{true, false}},
+	}
+	view := heatmap([]string{"dev", "prod"}, fields, 80)
+	lines := strings.Split(view, "\n")
+	if len(lines) != 4 {
+		t.Fatalf("expected a header, the object and two fields, got\n%s", view)
+	}
+	for i, want := range [][]string{
+		{"field", "dev", "prod"},
+		{"Deployment/apps/podinfo"},
+		{"spec.replicas", "1", "3"},
+		{"spec.paused", "true", "∅ unset"},
+	} {
+		if got := strings.Fields(lines[i]); strings.Join(got, " ") != strings.Join(want, " ") {
+			t.Errorf("line %d: expected %q, got %q", i, want, got)
+		}
+	}
+}
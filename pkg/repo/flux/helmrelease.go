@@ -0,0 +1,105 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package flux
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mproffitt/delorian/pkg/components"
+	"github.com/mproffitt/delorian/pkg/yaml"
+)
+
+// releaseFile presents the helm releases found under the spec
+// path of a kustomization as a single file
+type releaseFile struct {
+	name     string
+	releases []shortRelease
+}
+
+// GetName returns the name of the kustomization the releases
+// belong to
+func (r *releaseFile) GetName() string {
+	return r.name
+}
+
+// GetPath is empty as the content is gathered from many files
+func (r *releaseFile) GetPath() string {
+	return ""
+}
+
+// GetContent returns each helm release as written, headed by a
+// comment naming the file it came from
+func (r *releaseFile) GetContent() string {
+	documents := make([]string, 0, len(r.releases))
+	for _, release := range r.releases {
+		documents = append(documents, release.content())
+	}
+	return strings.Join(documents, "---\n")
+}
+
+// content reads the helm release from its file
+func (r *shortRelease) content() string {
+	options := []string{"kind", "HelmRelease", "metadata.name", r.Name}
+	if r.Namespace != nil {
+		options = append(options, "metadata.namespace", *r.Namespace)
+	}
+	header := "# " + strings.TrimPrefix(r.filepath, r.root+separator) + "\n"
+
+	content, err := os.ReadFile(filepath.Clean(r.filepath))
+	if err != nil {
+		return header + fmt.Sprintf("# %s\n", err)
+	}
+	content, err = yaml.Filter(content, options...)
+	if err != nil {
+		return header + fmt.Sprintf("# %s\n", err)
+	}
+	return header + string(content)
+}
+
+// releasesOf finds the helm releases defined under the spec path
+// of the kustomization
+func (m *Model) releasesOf(k *shortApi) []shortRelease {
+	path := k.GetAbsoluteSpecPath()
+	releases := make([]shortRelease, 0)
+	for _, release := range m.releases {
+		if release.root != k.root {
+			continue
+		}
+		file, _ := filepath.Abs(release.filepath)
+		if strings.HasPrefix(file, path+separator) {
+			releases = append(releases, release)
+		}
+	}
+	return releases
+}
+
+// releasesCmd shows the helm releases of the kustomization on
+// the HelmRelease tab
+func (m *Model) releasesCmd(k *shortApi) tea.Cmd {
+	releases := m.releasesOf(k)
+	return components.FileCmd(&releaseFile{
+		name:     k.GetName(),
+		releases: releases,
+	}, len(releases) > 0)
+}
@@ -0,0 +1,81 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package flux
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mproffitt/delorian/pkg/components"
+	"github.com/mproffitt/delorian/pkg/testutil"
+)
+
+const release = `apiVersion: helm.toolkit.fluxcd.io/v2
+kind: HelmRelease
+metadata:
+  name: %s
+  namespace: apps
+spec:
+  chart:
+    spec:
+      chart: %s
+      sourceRef:
+        kind: HelmRepository
+        name: charts
+  values:
+    replicaCount: 2
+`
+
+func TestHelmReleases(t *testing.T) {
+	testutil.Setup()
+	root := t.TempDir()
+	named := func(name string) string {
+		return strings.ReplaceAll(release, "%s", name)
+	}
+
+	testutil.WriteFile(t, root, "clusters/apps.yaml", multiDoc)
+	testutil.WriteFile(t, root, "apps/podinfo/release.yaml", named("podinfo")+
+		"---\napiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: podinfo-values\n")
+	testutil.WriteFile(t, root, "infra/release.yaml", named("ingress"))
+
+	m := New(root)
+	m.walk()
+	if len(m.releases) != 2 {
+		t.Fatalf("expected 2 helm releases, got %d", len(m.releases))
+	}
+
+	releases := m.releasesOf(&m.kustomizations[0])
+	if len(releases) != 1 || releases[0].Name != "podinfo" {
+		t.Fatalf("expected only the podinfo release under ./apps, got %v", releases)
+	}
+
+	msg := m.releasesCmd(&m.kustomizations[0])().(components.FileMsg)
+	if !msg.Ok {
+		t.Fatal("expected the releases to be found")
+	}
+	for _, want := range []string{"# apps/podinfo/release.yaml", "chart: podinfo", "replicaCount: 2"} {
+		if !strings.Contains(msg.Content, want) {
+			t.Errorf("expected %q in\n%s", want, msg.Content)
+		}
+	}
+	if strings.Contains(msg.Content, "ConfigMap") {
+		t.Errorf("expected only helm releases, got\n%s", msg.Content)
+	}
+}
@@ -0,0 +1,115 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package flux
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/log"
+	"github.com/mproffitt/delorian/pkg/components"
+	"github.com/mproffitt/delorian/pkg/registry"
+	"github.com/mproffitt/delorian/pkg/yaml"
+)
+
+// imagesCmd builds the selected kustomization, or every kustomization
+// in the same cluster when the cluster scope is active, and collects
+// the container images found in the output
+func (m *Model) imagesCmd(api *shortApi) tea.Cmd {
+	targets := []*shortApi{api}
+	scope := fmt.Sprintf("%s/%s", api.GetNamespace(), api.GetName())
+	if m.imageClusterScope {
+		root := api
+		for root.parent != nil {
+			root = root.parent
+		}
+		targets = append([]*shortApi{root}, root.descendants()...)
+		scope = fmt.Sprintf("cluster of %s/%s", root.GetNamespace(), root.GetName())
+	}
+
+	offline := m.offline
+	return func() tea.Msg {
+		inventory := components.NewImageInventory(scope)
+		for _, target := range targets {
+			out, err := target.fluxExec(context.Background(), target.buildArgs)
+			if err == nil {
+				var images []string
+				if images, err = yaml.Images([]byte(out)); err == nil {
+					for _, image := range images {
+						inventory.Add(image, target.GetName())
+					}
+					continue
+				}
+			}
+			// A single failing kustomization is reported as an error,
+			// otherwise the rest of the cluster is still inventoried
+			if len(targets) == 1 {
+				return components.ModelErrorMsg{Error: err}
+			}
+			log.Error("image inventory", "kustomization", target.GetName(), "error", err)
+		}
+		inventory.Sort()
+		if registry.Enabled && !offline {
+			checkRegistries(inventory)
+		}
+		return components.ImageInventoryMsg{Inventory: inventory}
+	}
+}
+
+// registryWorkers is the number of registries queried at once
+const registryWorkers = 8
+
+// checkRegistries queries the registry of each image for newer tags
+func checkRegistries(inventory *components.ImageInventory) {
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, registryWorkers)
+	for i := range inventory.Images {
+		image := &inventory.Images[i]
+		if image.Tag == "" {
+			continue
+		}
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer func() { <-sem; wg.Done() }()
+			latest, outdated, err := registry.Outdated(image.Repository, image.Tag)
+			if err != nil {
+				log.Debug("registry", "image", image.Reference(), "error", err)
+				return
+			}
+			image.Checked = true
+			image.Latest = latest
+			image.Outdated = outdated
+		}()
+	}
+	wg.Wait()
+}
+
+// descendants returns all kustomizations below this one
+func (s *shortApi) descendants() []*shortApi {
+	all := make([]*shortApi, 0)
+	for _, child := range s.children {
+		all = append(all, child)
+		all = append(all, child.descendants()...)
+	}
+	return all
+}
@@ -21,15 +21,41 @@ package flux
 
 import (
 	"bytes"
+	"errors"
+	"io"
 	"os"
 	"path/filepath"
+	"slices"
 
 	"github.com/charmbracelet/log"
+	"github.com/mproffitt/delorian/pkg/kustomize"
 	v3 "gopkg.in/yaml.v3"
 	"sigs.k8s.io/kustomize/api/types"
 )
 
+// maxKustomizationDepth limits how many directory resources are
+// followed from a single kustomization file
+const maxKustomizationDepth = 32
+
 func (m *Model) followKustomization(index int, path string, fluxKust *shortApi) {
+	m.followResources(index, path, fluxKust, make(map[string]bool), 0)
+}
+
+// followResources reads the kustomization file at path and links any
+// resources to the kustomization at index. Directory resources are
+// followed into their own kustomization file, skipping any already
+// visited so circular layouts cannot recurse forever
+func (m *Model) followResources(index int, path string, fluxKust *shortApi, visited map[string]bool, depth int) {
+	path, err := filepath.Abs(path)
+	if err != nil || visited[path] {
+		return
+	}
+	if depth > maxKustomizationDepth {
+		log.Warn("kustomization resources nested too deeply", "path", path)
+		return
+	}
+	visited[path] = true
+
 	f, err := os.ReadFile(filepath.Clean(path))
 	if err != nil {
 		return
@@ -37,13 +63,29 @@ func (m *Model) followKustomization(index int, path string, fluxKust *shortApi)
 
 	dec := v3.NewDecoder(bytes.NewReader(f))
 
-	var kustomization types.Kustomization
-	for dec.Decode(&kustomization) == nil {
+	for {
+		// Each document decodes into a fresh value so resources
+		// from one document don't leak into the next
+		var kustomization types.Kustomization
+		err := dec.Decode(&kustomization)
+		if errors.Is(err, io.EOF) {
+			return
+		}
+		if err != nil {
+			log.Warn("unable to decode kustomization", "path", path, "error", err)
+			return
+		}
+		// Flux kustomizations kept in the same file are matched
+		// by the walk rather than followed here
+		if classify(kustomization.APIVersion, kustomization.Kind) != kustomizeDoc {
+			continue
+		}
+
 		for _, resource := range kustomization.Resources {
 			// If the resources is a yaml file, get the real path
 			// to the file to allow for relative bases, then check
 			// if that file is a defined flux kustomization
-			np := filepath.Join(path, resource)
+			np := filepath.Join(filepath.Dir(path), resource)
 
 			// parse out relative paths, etc...
 			rp, err := filepath.Abs(np)
@@ -53,22 +95,27 @@ func (m *Model) followKustomization(index int, path string, fluxKust *shortApi)
 			}
 
 			// Is this resource pointing at a directory?
-			if fi, err := os.Stat(rp); err != nil || fi.IsDir() {
-				if err == nil {
-					m.followKustomization(index, rp, fluxKust)
-					return
+			if fi, err := os.Stat(rp); err == nil && fi.IsDir() {
+				fp, _ := kustomize.GetKustomization(filepath.Join(rp, kustomize.Kustomization))
+				if fp != "" {
+					m.followResources(index, fp, fluxKust, visited, depth+1)
 				}
+				continue
 			}
 
 			// is this a resource we're interested in?
 			//
 			// Match the kustomization that exists at this path then
 			// add that to the children of fluxKust
-			for j, v := range m.kustomizations {
-				if v.filepath == rp {
-					m.kustomizations[j].parent = &m.kustomizations[index]
+			for j := range m.kustomizations {
+				child := &m.kustomizations[j]
+				if j == index || child.GetPath() != rp {
+					continue
+				}
+				child.parent = &m.kustomizations[index]
+				if !slices.Contains(m.kustomizations[index].children, child) {
 					m.kustomizations[index].children = append(
-						m.kustomizations[index].children, &m.kustomizations[j])
+						m.kustomizations[index].children, child)
 				}
 			}
 
@@ -0,0 +1,100 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package flux
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/mproffitt/delorian/pkg/testutil"
+)
+
+func TestFollowKustomizationDocuments(t *testing.T) {
+	cases := map[string]struct {
+		content  string
+		expected []string
+	}{
+		"multiple documents": {
+			content: "resources:\n  - a.yaml\n---\npatches:\n  - path: patch.yaml\n" +
+				"---\nresources:\n  - b.yaml\n",
+			expected: []string{"a", "b"},
+		},
+		"malformed": {
+			content:  "resources:\n  - a.yaml\n---\nresources: [b.yaml\n",
+			expected: []string{"a"},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			root := t.TempDir()
+			path := testutil.WriteFile(t, root, "kustomization.yaml", tc.content)
+
+			m := &Model{kustomizations: []shortApi{
+				{root: root},
+				{filepath: filepath.Join(root, "a.yaml"), rawName: "a"},
+				{filepath: filepath.Join(root, "b.yaml"), rawName: "b"},
+			}}
+			m.followKustomization(0, path, &m.kustomizations[0])
+
+			var children []string
+			for _, child := range m.kustomizations[0].children {
+				children = append(children, child.rawName)
+			}
+			if !slices.Equal(children, tc.expected) {
+				t.Errorf("expected children %v, got %v", tc.expected, children)
+			}
+		})
+	}
+}
+
+func TestFollowKustomizationCycles(t *testing.T) {
+	cases := map[string]map[string]string{
+		"self": {
+			"app/kustomization.yaml": "resources:\n  - .\n",
+		},
+		"loop": {
+			"app/kustomization.yaml":  "resources:\n  - ../base\n",
+			"base/kustomization.yaml": "resources:\n  - ../app\n",
+		},
+	}
+	for name, files := range cases {
+		t.Run(name, func(t *testing.T) {
+			root := t.TempDir()
+			for file, content := range files {
+				testutil.WriteFile(t, root, file, content)
+			}
+
+			m := &Model{kustomizations: []shortApi{{root: root}}}
+			done := make(chan struct{})
+			go func() {
+				m.followKustomization(0, filepath.Join(root, "app", "kustomization.yaml"),
+					&m.kustomizations[0])
+				close(done)
+			}()
+			select {
+			case <-done:
+			case <-time.After(5 * time.Second):
+				t.Fatal("followKustomization did not return")
+			}
+		})
+	}
+}
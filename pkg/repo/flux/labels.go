@@ -0,0 +1,166 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package flux
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mproffitt/bmx/pkg/components/toast"
+	"github.com/mproffitt/delorian/pkg/components"
+	"github.com/mproffitt/delorian/pkg/components/picker"
+)
+
+// FilterLabels opens a picker to limit the list to kustomizations
+// carrying a label
+var FilterLabels = key.NewBinding(key.WithKeys("L"),
+	key.WithHelp("L", "Filter by label"))
+
+// DefaultLabelKeys are ownership labels commonly set on
+// kustomizations
+var DefaultLabelKeys = []string{"team", "tier", "owner"}
+
+// LabelKeys are the labels shown in the description of each
+// kustomization, set with SetLabelKeys
+var LabelKeys = DefaultLabelKeys
+
+// SetLabelKeys sets the labels shown in the list. An empty list
+// keeps the defaults
+func SetLabelKeys(keys []string) {
+	if len(keys) == 0 {
+		keys = DefaultLabelKeys
+	}
+	LabelKeys = keys
+}
+
+// fluxAnnotation is part of the key of every annotation flux
+// reads from its objects
+const fluxAnnotation = "toolkit.fluxcd.io/"
+
+// reconcileAnnotation pauses reconciliation when set to disabled
+const reconcileAnnotation = "kustomize.toolkit.fluxcd.io/reconcile"
+
+// LabelMsg limits the list to kustomizations carrying Label,
+// written as key=value. An empty label shows every kustomization
+type LabelMsg struct {
+	Label string
+}
+
+// LabelCmd returns a LabelMsg
+func LabelCmd(label string) tea.Cmd {
+	return func() tea.Msg {
+		return LabelMsg{Label: label}
+	}
+}
+
+// fluxAnnotations drops every annotation flux doesn't read so
+// large annotations aren't held for the lifetime of the scan
+func fluxAnnotations(annotations map[string]string) map[string]string {
+	maps.DeleteFunc(annotations, func(k, _ string) bool {
+		return !strings.Contains(k, fluxAnnotation)
+	})
+	if len(annotations) == 0 {
+		return nil
+	}
+	return annotations
+}
+
+// keyLabels returns the values of LabelKeys set on the
+// kustomization, written as key=value
+func (s *shortApi) keyLabels() []string {
+	var labels []string
+	for _, k := range LabelKeys {
+		if v, ok := s.Metadata.Labels[k]; ok {
+			labels = append(labels, k+"="+v)
+		}
+	}
+	return labels
+}
+
+// hasLabel reports if the kustomization carries label, written
+// as key=value. Every kustomization has the empty label
+func (s *shortApi) hasLabel(label string) bool {
+	if label == "" {
+		return true
+	}
+	k, v, _ := strings.Cut(label, "=")
+	value, ok := s.Metadata.Labels[k]
+	return ok && value == v
+}
+
+// reconcileDisabled reports if flux has been told to stop
+// reconciling the kustomization
+func (s *shortApi) reconcileDisabled() bool {
+	return s.Metadata.Annotations[reconcileAnnotation] == "disabled"
+}
+
+// labelFacets counts the kustomizations carrying each label,
+// ordered by label
+func (m *Model) labelFacets() ([]string, map[string]int) {
+	counts := make(map[string]int)
+	for i := range m.kustomizations {
+		k := &m.kustomizations[i]
+		if k.ftype == Base && !m.showBases {
+			continue
+		}
+		for name, value := range k.Metadata.Labels {
+			counts[name+"="+value]++
+		}
+	}
+	return slices.Sorted(maps.Keys(counts)), counts
+}
+
+// labelPickerCmd opens a picker listing every label set on the
+// kustomizations so the list can be limited to one of them
+func (m *Model) labelPickerCmd() tea.Cmd {
+	labels, counts := m.labelFacets()
+	if len(labels) == 0 {
+		return toast.NewToastCmd(toast.Info, "No kustomizations are labelled")
+	}
+	items := make([]picker.Item, 0, len(labels)+1)
+	if m.labelFilter != "" {
+		items = append(items, picker.Item{Label: "All kustomizations"})
+	}
+	for _, label := range labels {
+		items = append(items, picker.Item{
+			Label: fmt.Sprintf("%s (%d)", label, counts[label]),
+			Value: label,
+		})
+	}
+	return components.OpenDialogCmd(picker.New("Filter by label", items,
+		func(item picker.Item) tea.Cmd {
+			return LabelCmd(item.Value)
+		}))
+}
+
+// filterLabel limits the list to the kustomizations carrying label
+func (m *Model) filterLabel(label string) tea.Cmd {
+	m.labelFilter = label
+	status := ""
+	if label != "" {
+		status = "label " + label
+	}
+	_, cmd := m.Update(ModelReadyMsg{Ready: true})
+	return tea.Batch(cmd, components.StatusCmd("label", status))
+}
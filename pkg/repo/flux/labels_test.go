@@ -0,0 +1,106 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package flux
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/mproffitt/delorian/pkg/testutil"
+)
+
+const labelledDocs = `apiVersion: kustomize.toolkit.fluxcd.io/v1
+kind: Kustomization
+metadata:
+  name: payments
+  namespace: flux-system
+  labels:
+    team: payments
+    tier: backend
+  annotations:
+    kustomize.toolkit.fluxcd.io/reconcile: disabled
+    kubectl.kubernetes.io/last-applied-configuration: "{}"
+spec:
+  path: ./apps/payments
+---
+apiVersion: kustomize.toolkit.fluxcd.io/v1
+kind: Kustomization
+metadata:
+  name: search
+  namespace: flux-system
+  labels:
+    team: search
+    tier: backend
+spec:
+  path: ./apps/search
+---
+apiVersion: kustomize.toolkit.fluxcd.io/v1
+kind: Kustomization
+metadata:
+  name: infra
+  namespace: flux-system
+spec:
+  path: ./infra
+`
+
+func TestLabels(t *testing.T) {
+	testutil.Setup()
+	m := New("/repo")
+	m.kustomizations, _, _, _ = parseYaml([]byte(labelledDocs), "/repo", "/repo/clusters/apps.yaml")
+	for i := range m.kustomizations {
+		m.kustomizations[i].ftype = Complete
+	}
+
+	payments := &m.kustomizations[0]
+	if len(payments.Metadata.Annotations) != 1 || !payments.reconcileDisabled() {
+		t.Errorf("expected only the flux annotation to be kept, got %v", payments.Metadata.Annotations)
+	}
+	desc := payments.Description()
+	if !strings.Contains(desc, "team=payments tier=backend") || !strings.Contains(desc, "reconcile disabled") {
+		t.Errorf("expected the labels and paused state in %q", desc)
+	}
+
+	labels, counts := m.labelFacets()
+	want := []string{"team=payments", "team=search", "tier=backend"}
+	if !slices.Equal(labels, want) || counts["tier=backend"] != 2 {
+		t.Errorf("expected %v, got %v %v", want, labels, counts)
+	}
+
+	names := func() []string {
+		var names []string
+		for _, item := range m.Items() {
+			names = append(names, item.(*shortApi).GetName())
+		}
+		return names
+	}
+	m.labelFilter = "tier=backend"
+	if got := names(); !slices.Equal(got, []string{"payments", "search"}) {
+		t.Errorf("expected the backend kustomizations, got %v", got)
+	}
+	m.filterLabel("team=search")
+	if got := names(); !slices.Equal(got, []string{"search"}) {
+		t.Errorf("expected the search kustomization, got %v", got)
+	}
+	m.filterLabel("")
+	if got := names(); len(got) != 3 {
+		t.Errorf("expected every kustomization once the filter is cleared, got %v", got)
+	}
+}
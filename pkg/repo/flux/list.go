@@ -20,9 +20,16 @@
 package flux
 
 import (
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mproffitt/delorian/pkg/components"
 )
 
+// ToggleBases shows or hides base kustomizations in the list
+var ToggleBases = key.NewBinding(key.WithKeys("B"),
+	key.WithHelp("B", "Show / hide bases"))
+
 func (m *Model) newlist() *list.Model {
 	list := list.New(m.Items(), m.delegates.normal, 0, 0)
 	{
@@ -36,12 +43,55 @@ func (m *Model) newlist() *list.Model {
 	return &list
 }
 
+// reselect selects the kustomization in next which was selected in
+// previous so a rescan keeps the selection if it still exists
+func (m *Model) reselect(previous, next *list.Model) *list.Model {
+	if previous == nil {
+		return next
+	}
+	selected, ok := previous.SelectedItem().(*shortApi)
+	if !ok {
+		return next
+	}
+	for i, item := range next.Items() {
+		if item.(*shortApi).identity() == selected.identity() {
+			next.Select(i)
+			break
+		}
+	}
+	return next
+}
+
 func (m *Model) Items() []list.Item {
 	items := make([]list.Item, 0)
+	scope := m.scopedCluster()
 	for _, k := range m.kustomizations {
-		if k.ftype != Base {
+		if (k.ftype != Base || m.showBases) && !m.inFilteredCluster(k.GetPath()) && scope.holds(k.GetPath()) && k.hasLabel(m.labelFilter) {
 			items = append(items, &k)
 		}
 	}
 	return items
 }
+
+// basesAction wraps toggleBases so it can be undone
+func (m *Model) basesAction() components.Action {
+	name := "show bases"
+	if m.showBases {
+		name = "hide bases"
+	}
+	return components.Action{Name: name, Do: m.toggleBases, Undo: m.toggleBases}
+}
+
+// toggleBases switches between listing only the kustomizations
+// that are applied and listing their bases as well
+func (m *Model) toggleBases() tea.Cmd {
+	m.showBases = !m.showBases
+	m.list.SetItems(m.Items())
+
+	status := ""
+	if m.showBases {
+		status = "showing bases"
+	}
+	_, cmd := m.Update(components.TabChangedMsg{NewTab: m.lasttab})
+	return tea.Batch(cmd, m.selectedStatusCmd(), components.StatusCmd("bases", status))
+}
@@ -0,0 +1,42 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package flux
+
+import "testing"
+
+func TestItemsShowBases(t *testing.T) {
+	m := &Model{kustomizations: []shortApi{
+		{ftype: Complete},
+		{ftype: Patch},
+		{ftype: Base},
+	}}
+	m.kustomizations[2].parent = &m.kustomizations[0]
+	if n := len(m.Items()); n != 2 {
+		t.Errorf("expected bases to be hidden, got %d items", n)
+	}
+
+	m.showBases = true
+	if n := len(m.Items()); n != 3 {
+		t.Errorf("expected bases to be shown, got %d items", n)
+	}
+	if desc := m.kustomizations[2].Description(); desc != " (0) · base" {
+		t.Errorf("expected the base to be labelled, got %q", desc)
+	}
+}
@@ -0,0 +1,257 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package flux
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/log"
+	"github.com/mproffitt/delorian/pkg/components"
+	"github.com/mproffitt/delorian/pkg/theme"
+)
+
+// LiveStatus fetches the status of every Flux Kustomization from the
+// cluster and shows it in the list. Statuses are only fetched while
+// the cluster can be reached
+var LiveStatus bool
+
+// LiveInterval is how long fetched statuses are shown before they
+// are fetched again. When zero they are only fetched once the
+// cluster is reached
+var LiveInterval = 30 * time.Second
+
+// liveResource is the Flux Kustomization type in the cluster
+const liveResource = "kustomizations." + kustomizationApi
+
+// liveStatus is the state of a Flux Kustomization in the cluster
+type liveStatus struct {
+	Ready      string
+	Reason     string
+	Revision   string
+	Reconciled time.Time
+	Suspended  bool
+}
+
+// liveCache holds the statuses last fetched, keyed by namespace
+// and name. Every copy of a kustomization shares the cache so the
+// list shows new statuses without being rebuilt
+type liveCache struct {
+	sync.RWMutex
+	statuses map[string]liveStatus
+	tag      int
+}
+
+// LiveMsg carries the statuses fetched from the cluster. Fetches
+// carry the tag of the request that scheduled them so only the
+// latest is used
+type LiveMsg struct {
+	Tag      int
+	Statuses map[string]liveStatus
+	Error    error
+}
+
+// get returns the cached status for key
+func (c *liveCache) get(key string) (liveStatus, bool) {
+	if c == nil {
+		return liveStatus{}, false
+	}
+	c.RLock()
+	defer c.RUnlock()
+	status, ok := c.statuses[key]
+	return status, ok
+}
+
+// set replaces the cached statuses
+func (c *liveCache) set(statuses map[string]liveStatus) {
+	c.Lock()
+	defer c.Unlock()
+	c.statuses = statuses
+}
+
+// liveCmd fetches the statuses after delay. Any fetch already
+// scheduled is superseded
+func (m *Model) liveCmd(delay time.Duration) tea.Cmd {
+	if !LiveStatus || m.offline {
+		return nil
+	}
+	m.live.tag++
+	tag := m.live.tag
+	return tea.Tick(delay, func(time.Time) tea.Msg {
+		statuses, err := fetchLive(context.Background())
+		return LiveMsg{Tag: tag, Statuses: statuses, Error: err}
+	})
+}
+
+// fetchLive reads the status of every Flux Kustomization in the
+// cluster with a single request
+func fetchLive(ctx context.Context) (map[string]liveStatus, error) {
+	out, err := components.KubectlExec(ctx, []string{
+		"get", liveResource, "--all-namespaces", "-o", "json", "--request-timeout=10s",
+	})
+	if err != nil {
+		return nil, err
+	}
+	return parseLive([]byte(out))
+}
+
+// parseLive reads the statuses from a kubectl list of Flux
+// Kustomizations
+func parseLive(content []byte) (map[string]liveStatus, error) {
+	var list struct {
+		Items []struct {
+			Metadata struct {
+				Name      string `json:"name"`
+				Namespace string `json:"namespace"`
+			} `json:"metadata"`
+			Spec struct {
+				Suspend bool `json:"suspend"`
+			} `json:"spec"`
+			Status struct {
+				LastAppliedRevision string `json:"lastAppliedRevision"`
+				Conditions          []struct {
+					Type               string    `json:"type"`
+					Status             string    `json:"status"`
+					Reason             string    `json:"reason"`
+					LastTransitionTime time.Time `json:"lastTransitionTime"`
+				} `json:"conditions"`
+			} `json:"status"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal(content, &list); err != nil {
+		return nil, fmt.Errorf("reading kustomization status: %w", err)
+	}
+
+	statuses := make(map[string]liveStatus, len(list.Items))
+	for _, item := range list.Items {
+		status := liveStatus{
+			Ready:     "Unknown",
+			Revision:  item.Status.LastAppliedRevision,
+			Suspended: item.Spec.Suspend,
+		}
+		for _, c := range item.Status.Conditions {
+			if c.Type == "Ready" {
+				status.Ready = c.Status
+				status.Reason = c.Reason
+				status.Reconciled = c.LastTransitionTime
+			}
+		}
+		statuses[item.Metadata.Namespace+"/"+item.Metadata.Name] = status
+	}
+	return statuses, nil
+}
+
+// setLive caches fetched statuses and schedules the next fetch. A
+// failed fetch keeps the statuses already shown
+func (m *Model) setLive(msg LiveMsg) tea.Cmd {
+	if msg.Tag != m.live.tag {
+		return nil
+	}
+	status := components.StatusCmd("live", "")
+	if msg.Error != nil {
+		log.Warn("fetching live status", "error", msg.Error)
+		status = components.StatusCmd("live", theme.Glyph("⚠ live status unavailable"))
+	} else {
+		m.live.set(msg.Statuses)
+	}
+	if LiveInterval <= 0 {
+		return status
+	}
+	return tea.Batch(status, m.liveCmd(LiveInterval))
+}
+
+// clearLive forgets the cached statuses when the cluster can't be
+// reached so nothing stale is shown
+func (m *Model) clearLive() tea.Cmd {
+	m.live.tag++
+	m.live.set(nil)
+	return components.StatusCmd("live", "")
+}
+
+// liveStatus returns the status of the kustomization in the cluster
+func (s *shortApi) liveStatus() (liveStatus, bool) {
+	if s.ftype == Base {
+		return liveStatus{}, false
+	}
+	return s.live.get(s.GetNamespace() + "/" + s.GetName())
+}
+
+// badge marks the status in the title
+func (l liveStatus) badge() string {
+	switch {
+	case l.Suspended:
+		return theme.Glyph("○")
+	case l.Ready == "True":
+		return theme.Glyph("✔")
+	case l.Ready == "False":
+		return theme.Glyph("✕")
+	}
+	return theme.Glyph("⟳")
+}
+
+// String describes the status and when it was last reconciled
+func (l liveStatus) String() string {
+	state := "ready"
+	switch {
+	case l.Suspended:
+		state = "suspended"
+	case l.Ready == "True":
+	case l.Reason != "":
+		state = l.Reason
+	default:
+		state = "not ready"
+	}
+	parts := []string{"live " + state}
+	if l.Revision != "" {
+		parts = append(parts, shortRevision(l.Revision))
+	}
+	if !l.Reconciled.IsZero() {
+		parts = append(parts, ago(time.Since(l.Reconciled)))
+	}
+	return strings.Join(parts, " ")
+}
+
+// shortRevision cuts the digest in a revision such as
+// main@sha1:<digest> to the length git shows
+func shortRevision(revision string) string {
+	i := strings.LastIndexAny(revision, ":/")
+	if i >= 0 && len(revision)-i-1 > 7 {
+		return revision[:i+8]
+	}
+	return revision
+}
+
+// ago describes a duration in the past in its largest unit
+func ago(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(d.Hours()))
+	}
+	return fmt.Sprintf("%dd ago", int(d.Hours()/24))
+}
@@ -0,0 +1,113 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package flux
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+const liveList = `{
+  "items": [
+    {
+      "metadata": {"name": "apps", "namespace": "flux-system"},
+      "spec": {},
+      "status": {
+        "lastAppliedRevision": "main@sha1:1a2b3c4d5e6f7a8b9c0d",
+        "conditions": [
+          {"type": "Healthy", "status": "False", "reason": "Progressing"},
+          {"type": "Ready", "status": "True", "reason": "ReconciliationSucceeded",
+           "lastTransitionTime": "2025-01-01T10:00:00Z"}
+        ]
+      }
+    },
+    {
+      "metadata": {"name": "infra", "namespace": "flux-system"},
+      "spec": {"suspend": true},
+      "status": {
+        "conditions": [{"type": "Ready", "status": "False", "reason": "BuildFailed"}]
+      }
+    }
+  ]
+}`
+
+func TestParseLive(t *testing.T) {
+	statuses, err := parseLive([]byte(liveList))
+	if err != nil {
+		t.Fatal(err)
+	}
+	apps, ok := statuses["flux-system/apps"]
+	if !ok {
+		t.Fatal("expected a status for flux-system/apps")
+	}
+	if apps.Ready != "True" || apps.badge() != "✔" {
+		t.Errorf("expected apps to be ready, got %+v", apps)
+	}
+	if !strings.HasPrefix(apps.String(), "live ready main@sha1:1a2b3c4 ") {
+		t.Errorf("unexpected description %q", apps.String())
+	}
+	if infra := statuses["flux-system/infra"]; infra.badge() != "○" || infra.String() != "live suspended" {
+		t.Errorf("expected infra to be suspended, got %q", infra.String())
+	}
+
+	if _, err := parseLive([]byte("error: the server doesn't have a resource type")); err == nil {
+		t.Error("expected an error for output which isn't json")
+	}
+}
+
+func TestSetLive(t *testing.T) {
+	LiveStatus = true
+	t.Cleanup(func() { LiveStatus = false })
+
+	m := New(t.TempDir())
+	ns := "flux-system"
+	api := &shortApi{Metadata: shortMeta{Name: "apps", Namespace: &ns}, ftype: Complete, live: m.live}
+	if m.liveCmd(time.Hour) == nil {
+		t.Fatal("expected a fetch to be scheduled")
+	}
+	statuses := map[string]liveStatus{"flux-system/apps": {Ready: "False", Reason: "BuildFailed"}}
+
+	// Fetches superseded by a later request are dropped
+	m.setLive(LiveMsg{Tag: m.live.tag - 1, Statuses: statuses})
+	if _, ok := api.liveStatus(); ok {
+		t.Fatal("expected a stale fetch to be ignored")
+	}
+
+	m.setLive(LiveMsg{Tag: m.live.tag, Statuses: statuses})
+	if title := api.titleText(); title != "✕ apps" {
+		t.Errorf("expected the failed badge, got %q", title)
+	}
+	if desc := api.Description(); !strings.Contains(desc, "· live BuildFailed") {
+		t.Errorf("expected the live status in the description, got %q", desc)
+	}
+
+	// A failed fetch keeps the statuses already shown
+	m.setLive(LiveMsg{Tag: m.live.tag, Error: errors.New("connection refused")})
+	if _, ok := api.liveStatus(); !ok {
+		t.Error("expected the cached status to be kept after a failed fetch")
+	}
+
+	m.clearLive()
+	if _, ok := api.liveStatus(); ok {
+		t.Error("expected the statuses to be cleared when offline")
+	}
+}
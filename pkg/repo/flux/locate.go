@@ -0,0 +1,77 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package flux
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mproffitt/bmx/pkg/components/toast"
+	"github.com/mproffitt/delorian/pkg/components/treeview"
+)
+
+// LocateCluster highlights the cluster containing the selected
+// kustomization in the cluster tree
+var LocateCluster = key.NewBinding(key.WithKeys("t"),
+	key.WithHelp("t", "Locate in cluster tree"))
+
+// clusterBranch returns the names leading from the top of the
+// cluster tree to the deepest cluster directory containing path
+func (m *Model) clusterBranch(path string) []string {
+	return branch(m.groupClustersByRoot(), path)
+}
+
+// branch returns the names of the clusters holding path, from the
+// outermost to the innermost
+func branch(clusters []*cluster, path string) []string {
+	for _, c := range clusters {
+		if contains(c.filepath, path) {
+			return append([]string{c.name}, branch(c.children, path)...)
+		}
+	}
+	return nil
+}
+
+// contains reports if path is dir or sits beneath it
+func contains(dir, path string) bool {
+	if dir == "" {
+		return false
+	}
+	dir, _ = filepath.Abs(dir)
+	return path == dir || strings.HasPrefix(path, dir+separator)
+}
+
+// locate selects the cluster owning the kustomization in the
+// treeview and scrolls it into view
+func (m *Model) locate(api *shortApi) tea.Cmd {
+	tv, ok := m.treeview.(*treeview.Model)
+	if !ok {
+		return nil
+	}
+	branch := m.clusterBranch(api.GetPath())
+	tv.Select(branch)
+	if len(branch) == 0 {
+		return toast.NewToastCmd(toast.Warning,
+			api.GetName()+" is not inside a cluster directory")
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package flux
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/mproffitt/delorian/pkg/components/treeview"
+)
+
+func TestClusterBranch(t *testing.T) {
+	child := &cluster{name: "prod", filepath: "/repo/clusters/hub/prod", root: "/repo"}
+	hub := &cluster{
+		name:     "hub",
+		filepath: "/repo/clusters/hub",
+		root:     "/repo",
+		children: []*cluster{child},
+	}
+	m := &Model{roots: []string{"/repo"}, clusters: []*cluster{hub}}
+
+	cases := map[string][]string{
+		"/repo/clusters/hub/prod/apps.yaml": {"hub", "prod"},
+		"/repo/clusters/hub/flux.yaml":      {"hub"},
+		"/repo/clusters/hub-other/app.yaml": nil,
+		"/repo/apps/app.yaml":               nil,
+	}
+	for path, expected := range cases {
+		if branch := m.clusterBranch(path); !slices.Equal(branch, expected) {
+			t.Errorf("%s: expected %v, got %v", path, expected, branch)
+		}
+	}
+
+	hub.Select([]string{"hub", "prod"})
+	if !hub.selected || !child.selected {
+		t.Fatal("expected the branch to be selected")
+	}
+	if !strings.Contains(child.label(), treeview.Marker) || strings.Contains(hub.label(), treeview.Marker) {
+		t.Error("expected only the deepest cluster to be marked")
+	}
+
+	hub.Select([]string{"hub"})
+	if child.selected {
+		t.Error("expected the previous selection to be cleared")
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package flux
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// DefaultBaseMarkers match any directory ending in `bases`
+var DefaultBaseMarkers = []string{"*bases"}
+
+// BaseMarkers are the markers in use, set with SetBaseMarkers
+var BaseMarkers = DefaultBaseMarkers
+
+// baseMarkers matches a path containing a base directory
+var baseMarkers = compileMarkers(BaseMarkers)
+
+// SetBaseMarkers sets the directory names which hold shared
+// bases. Markers are directory names where `*` matches any run
+// of characters. An empty list keeps the defaults
+func SetBaseMarkers(markers []string) error {
+	if len(markers) == 0 {
+		markers = DefaultBaseMarkers
+	}
+	for _, marker := range markers {
+		if marker == "" || strings.ContainsAny(marker, `/\`) {
+			return fmt.Errorf("base marker %q must be a single directory name", marker)
+		}
+	}
+	BaseMarkers, baseMarkers = markers, compileMarkers(markers)
+	return nil
+}
+
+// compileMarkers builds a single expression matching any of the
+// markers as a whole element of a slash separated path
+func compileMarkers(markers []string) *regexp.Regexp {
+	alternatives := make([]string, len(markers))
+	for i, marker := range markers {
+		parts := strings.Split(marker, "*")
+		for j := range parts {
+			parts[j] = regexp.QuoteMeta(parts[j])
+		}
+		alternatives[i] = strings.Join(parts, "[^/]*")
+	}
+	return regexp.MustCompile(`(^|/)(` + strings.Join(alternatives, "|") + `)(/|$)`)
+}
+
+// inBase reports if path sits in, or is, a base directory
+func inBase(path string) bool {
+	return baseMarkers.MatchString(slash(path))
+}
@@ -0,0 +1,75 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package flux
+
+import "testing"
+
+func TestBaseMarkers(t *testing.T) {
+	t.Cleanup(func() { _ = SetBaseMarkers(nil) })
+
+	tests := []struct {
+		name    string
+		markers []string
+		path    string
+		expect  bool
+	}{
+		{name: "default", path: "apps/bases/podinfo", expect: true},
+		{name: "default suffix", path: "infra/shared-bases/ingress", expect: true},
+		{name: "default other", path: "clusters/production", expect: false},
+		{name: "custom", markers: []string{"_base", "components"}, path: "apps/_base", expect: true},
+		{name: "custom nested", markers: []string{"_base", "components"}, path: "x/components/y", expect: true},
+		{name: "custom partial", markers: []string{"base"}, path: "apps/database/x", expect: false},
+		{name: "custom replaces default", markers: []string{"base"}, path: "apps/bases/x", expect: false},
+		{name: "wildcard", markers: []string{"base*"}, path: "apps/base-v2/x", expect: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := SetBaseMarkers(tt.markers); err != nil {
+				t.Fatal(err)
+			}
+			if got := inBase(tt.path); got != tt.expect {
+				t.Errorf("expected %v for %q, got %v", tt.expect, tt.path, got)
+			}
+		})
+	}
+}
+
+func TestSetBaseMarkersInvalid(t *testing.T) {
+	t.Cleanup(func() { _ = SetBaseMarkers(nil) })
+	for _, marker := range []string{"", "apps/bases"} {
+		if err := SetBaseMarkers([]string{marker}); err == nil {
+			t.Errorf("expected %q to be rejected", marker)
+		}
+	}
+}
+
+func TestCheckClusterPathBaseMarkers(t *testing.T) {
+	t.Cleanup(func() { _ = SetBaseMarkers(nil) })
+	if err := SetBaseMarkers([]string{"_base"}); err != nil {
+		t.Fatal(err)
+	}
+	m := &Model{}
+	root := "/home/ops/databases/fleet"
+	m.checkClusterPath(root, root+"/clusters/_base/common")
+	m.checkClusterPath(root, root+"/clusters/staging")
+	if len(m.clusters) != 1 || m.clusters[0].name != "staging" {
+		t.Errorf("expected only staging, got %d clusters", len(m.clusters))
+	}
+}
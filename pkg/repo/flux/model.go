@@ -21,10 +21,14 @@ package flux
 
 import (
 	"path/filepath"
+	"regexp"
+	"slices"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/charlievieth/fastwalk"
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/list"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -42,36 +46,64 @@ type Model struct {
 	id             string
 	conf           fastwalk.Config
 	clusters       []*cluster
+	cache          *scanCache
+	cached         int
+	clusterFilter  *regexp.Regexp
+	clusterScope   []string
+	labelFilter    string
+	live           *liveCache
+	recent         []string
+	peek           peek
+	configs        []shortConfig
 	delegates      delegates
 	height         int
 	kustomizations []shortApi
 	lasttab        components.TabType
 	list           *list.Model
 	table          *table.Model
-	root           string
+	roots          []string
+	scannedAt      time.Time
+	showBases      bool
 	sources        []shortSource
+	releases       []shortRelease
 	width          int
 	focus          bool
+	offline        bool
+	poll           poll
+	scan           scan
+	files          *fileWatcher
+	watch          watch
+
+	imageClusterScope bool
 
 	treeview tea.Model
 }
 
 type delegates struct {
-	normal list.ItemDelegate
-	shaded list.ItemDelegate
+	normal truncatingDelegate
+	shaded truncatingDelegate
 }
 
-func New(root string) *Model {
-	root = strings.TrimRight(root, string(filepath.Separator))
+// New creates the repository model for one or more repository
+// roots. Where multiple roots are given, their kustomizations,
+// sources and clusters are merged into a single view
+func New(roots ...string) *Model {
+	// Normalised in a copy so the caller's slice is left alone
+	roots = slices.Clone(roots)
+	for i := range roots {
+		roots[i] = strings.TrimRight(roots[i], separator)
+	}
 	m := Model{
-		id: zone.NewPrefix(),
+		id: components.NewID(),
 		conf: fastwalk.Config{
 			Follow: true,
 		},
 		lasttab:        components.TabKustomize,
-		root:           root,
+		live:           &liveCache{},
+		roots:          roots,
 		kustomizations: make([]shortApi, 0),
 		sources:        make([]shortSource, 0),
+		releases:       make([]shortRelease, 0),
 	}
 	m.delegates = delegates{
 		normal: m.createListNormalDelegate(),
@@ -88,21 +120,54 @@ func (m *Model) Focus() {
 
 func (m *Model) Blur() {
 	m.focus = false
+	if m.treeFocused() {
+		m.toggleTree()
+	}
 	m.list.SetDelegate(m.delegates.shaded)
 }
 
 func (m *Model) Init() tea.Cmd {
-	cmd := m.walk()
+	return tea.Batch(m.scanCmd(), m.fileWatchCmd())
+}
 
-	var clusters []treeview.Tree
-	{
-		for i := range m.clusters {
-			clusters = append(clusters, m.clusters[i])
-			log.Debug("Adding cluster", "cluster", m.clusters[i].Name())
+// MultiRoot reports if the model is displaying more than one
+// repository root
+func (m *Model) MultiRoot() bool {
+	return len(m.roots) > 1
+}
+
+// groupClustersByRoot returns the top level clusters. When more
+// than one root is being scanned, clusters are nested beneath a
+// node named after the repository they were found in
+func (m *Model) groupClustersByRoot() []*cluster {
+	clusters := m.visibleClusters()
+	if !m.MultiRoot() {
+		return clusters
+	}
+	grouped := make([]*cluster, 0, len(m.roots))
+	for _, root := range m.roots {
+		parent := &cluster{
+			name:     rootName(root),
+			filepath: root,
+			root:     root,
+			children: make([]*cluster, 0),
+		}
+		for _, c := range clusters {
+			if c.root == root {
+				parent.children = append(parent.children, c)
+			}
+		}
+		if len(parent.children) > 0 {
+			grouped = append(grouped, parent)
 		}
 	}
-	m.treeview = treeview.New("clusters", clusters, m.width, m.height)
-	return cmd
+	return grouped
+}
+
+// rootName is the short name used to indicate which repository
+// root an item was discovered in
+func rootName(root string) string {
+	return filepath.Base(root)
 }
 
 func (m *Model) SetSize(w, h int) tea.Model {
@@ -117,7 +182,103 @@ func (m *Model) SetSize(w, h int) tea.Model {
 func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		m.clearPeek()
+		if m.list == nil {
+			// Nothing to act on until the first scan is done
+			break
+		}
+		if m.list.FilterState() == list.Filtering {
+			cmd = m.defaultHandler(msg)
+			break
+		}
+		if m.treeFocused() && !key.Matches(msg, SelectCluster) {
+			m.treeview, cmd = m.treeview.Update(msg)
+			break
+		}
+		switch {
+		case key.Matches(msg, CopyCommands):
+			if api, ok := m.selectedKustomization(); ok {
+				cmd = components.CopyCmd(api.fluxCommands(), "flux commands")
+			}
+		case key.Matches(msg, CopyBuildCommand):
+			if api, ok := m.selectedKustomization(); ok {
+				cmd = components.CopyCmd(api.buildCommand(), "kustomize build command")
+			}
+		case key.Matches(msg, OpenSource):
+			if api, ok := m.selectedKustomization(); ok {
+				cmd = sourceURLCmd(api, components.OpenURLCmd)
+			}
+		case key.Matches(msg, CopySourceURL):
+			if api, ok := m.selectedKustomization(); ok {
+				cmd = sourceURLCmd(api, func(url string) tea.Cmd {
+					return components.CopyCmd(url, "source URL")
+				})
+			}
+		case key.Matches(msg, EditSubstitutions):
+			if api, ok := m.selectedKustomization(); ok {
+				cmd = m.editSubstitutionsCmd(api)
+			}
+		case key.Matches(msg, EditOverlay):
+			if api, ok := m.selectedKustomization(); ok {
+				cmd = m.editOverlayCmd(api)
+			}
+		case key.Matches(msg, ToggleBases):
+			cmd = m.basesAction().Apply()
+		case key.Matches(msg, LocateCluster):
+			if api, ok := m.selectedKustomization(); ok {
+				cmd = m.locate(api)
+			}
+		case key.Matches(msg, SelectCluster):
+			m.toggleTree()
+		case key.Matches(msg, FilterLabels):
+			cmd = m.labelPickerCmd()
+		case key.Matches(msg, CompareSubstitutions):
+			if api, ok := m.selectedKustomization(); ok {
+				cmd = compareSubstitutionsCmd(api)
+			}
+		case key.Matches(msg, CompareEnvironments):
+			if api, ok := m.selectedKustomization(); ok {
+				cmd = m.compareEnvironmentsCmd(api)
+			}
+		case key.Matches(msg, ExportGraph):
+			cmd = m.exportGraphCmd()
+		case key.Matches(msg, EditNote):
+			if api, ok := m.selectedKustomization(); ok {
+				cmd = api.editNoteCmd()
+			}
+		default:
+			cmd = m.defaultHandler(msg)
+		}
+	case components.SubstitutionsMsg:
+		cmd = m.overridesAction(msg).Apply()
+	case components.OverlayEditMsg:
+		cmd = m.setEdit(msg)
+	case components.NoteMsg:
+		cmd = setNote(msg)
+	case components.ResourceDiffMsg:
+		if api, ok := m.selectedKustomization(); ok && !m.offline {
+			cmd = api.resourceDiffCmd(msg)
+		}
+	case components.RecursiveDiffMsg:
+		if api, ok := m.selectedKustomization(); ok && !m.offline {
+			cmd = m.recursiveDiffCmd(api)
+		}
+	case components.DefinitionMsg:
+		if api, ok := m.selectedKustomization(); ok {
+			cmd = api.definitionCmd(msg)
+		}
+	case components.ShowFileMsg:
+		m.lasttab = msg.Tab
 	case tea.MouseMsg:
+		if m.list == nil {
+			break
+		}
+		if msg.Action == tea.MouseActionMotion {
+			cmd = m.hover(msg)
+			break
+		}
+		m.clearPeek()
 		switch msg.Button {
 		case tea.MouseButtonWheelUp:
 			m.list.CursorUp()
@@ -149,24 +310,86 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			break
 		}*/
 		m.table = nil
-		m.list = m.newlist()
-		m.list.SetItems(m.Items())
-		api, ok := m.FindSelected()
-		cmd = components.FileCmd(api, ok)
+		m.list = m.reselect(m.list, m.newlist())
+		switch m.lasttab {
+		case components.TabKustomize, components.TabSource:
+			api, ok := m.FindSelected()
+			cmd = components.FileCmd(api, ok)
+		default:
+			_, cmd = m.Update(components.TabChangedMsg{NewTab: m.lasttab})
+		}
+		cmd = tea.Batch(cmd, m.selectedStatusCmd())
+	case components.ClusterStatusMsg:
+		m.offline = !msg.Online
+		if m.offline {
+			cmd = m.clearLive()
+			break
+		}
+		cmd = m.liveCmd(0)
+	case LiveMsg:
+		cmd = m.setLive(msg)
+	case components.ImageScopeMsg:
+		m.imageClusterScope = msg.Cluster
+		if api, ok := m.FindSelected(); ok && m.lasttab == components.TabImages {
+			cmd = m.imagesCmd(api.(*shortApi))
+		}
+	case components.RescanMsg:
+		cmd = m.rescan()
+	case ScanDoneMsg:
+		cmd = m.scanDone(msg)
+	case ScanCheckMsg:
+		cmd = m.scanCheck(msg)
+	case ScanStopMsg:
+		m.stopScan(msg)
+	case components.PollMsg:
+		cmd = m.polled(msg)
+	case components.FilesChangedMsg:
+		cmd = m.filesChanged()
+	case SelectMsg:
+		cmd = m.selectID(msg.ID)
+	case treeview.SelectMsg:
+		cmd = m.scopeTo(msg.Path)
+	case LabelMsg:
+		cmd = m.filterLabel(msg.Label)
+	case PeekMsg:
+		m.peeked(msg)
+	case components.WatchToggleMsg:
+		cmd = m.toggleWatch()
+	case components.WatchTickMsg:
+		cmd = m.watchTick(msg)
 	case components.TabChangedMsg:
 		m.lasttab = msg.NewTab
+		if m.list == nil {
+			// Not scanned yet. The tab is picked up when ready
+			break
+		}
 		api, ok := m.FindSelected()
 		if ok {
 			switch m.lasttab {
-			case components.TabFluxBuild:
+			case components.TabFluxBuild, components.TabResources:
 				cmd = api.(components.Flux).Build()
 			case components.TabFluxDiff:
-				cmd = api.(components.Flux).Diff()
+				if !m.offline {
+					cmd = api.(components.Flux).Diff()
+				}
+			case components.TabImages:
+				cmd = m.imagesCmd(api.(*shortApi))
+			case components.TabRefDiff:
+				cmd = api.(*shortApi).RefDiff(CompareRef)
+			case components.TabHelmRelease:
+				cmd = m.releasesCmd(api.(*shortApi))
 			case components.TabGraph:
+				cmd = m.graphCmd(api.(*shortApi))
+			case components.TabHealth, components.TabIntervals:
 			default:
 				cmd = components.FileCmd(api, ok)
 			}
 		}
+		// The tab has started loading so must be told when
+		// there is nothing to wait for
+		if cmd == nil {
+			cmd = components.LoadSkippedCmd()
+		}
 	default:
 		cmd = m.defaultHandler(msg)
 	}
@@ -181,33 +404,37 @@ func (m *Model) defaultHandler(msg tea.Msg) tea.Cmd {
 	m.list = &list
 	api, ok := m.FindSelected()
 	var fcmd tea.Cmd
+	if k, found := m.selectedKustomization(); found {
+		m.visited(k)
+	}
 	if ok {
 		switch m.lasttab {
-		case components.TabFluxBuild:
+		case components.TabFluxBuild, components.TabResources:
 			fcmd = api.(components.Flux).Build()
 		case components.TabFluxDiff:
-			fcmd = api.(components.Flux).Diff()
+			if !m.offline {
+				fcmd = api.(components.Flux).Diff()
+			}
+		case components.TabImages:
+			fcmd = m.imagesCmd(api.(*shortApi))
+		case components.TabRefDiff:
+			fcmd = api.(*shortApi).RefDiff(CompareRef)
+		case components.TabHelmRelease:
+			fcmd = m.releasesCmd(api.(*shortApi))
 		case components.TabGraph:
+			fcmd = m.graphCmd(api.(*shortApi))
+		case components.TabHealth, components.TabIntervals:
 		default:
 			fcmd = components.FileCmd(api, ok)
 		}
 	}
-	cmd = tea.Batch(cmd, fcmd)
+	cmd = tea.Batch(cmd, fcmd, m.selectedStatusCmd())
 	return cmd
 }
 
 func (m *Model) FindSelected() (api components.File, ok bool) {
-	var path, name string
-	item := m.list.SelectedItem().(*shortApi)
-	path = item.GetPath()
-	name = item.GetName()
-	for i, v := range m.kustomizations {
-		if v.GetPath() == path && v.GetName() == name {
-			a := &m.kustomizations[i]
-			api = a
-			ok = a != nil
-			break
-		}
+	if a, found := m.selectedKustomization(); found {
+		api, ok = a, true
 	}
 
 	switch m.lasttab {
@@ -221,6 +448,24 @@ func (m *Model) FindSelected() (api components.File, ok bool) {
 	return
 }
 
+// selectedKustomization returns the kustomization selected in
+// the list
+func (m *Model) selectedKustomization() (*shortApi, bool) {
+	if m.list == nil {
+		return nil, false
+	}
+	item, ok := m.list.SelectedItem().(*shortApi)
+	if !ok {
+		return nil, false
+	}
+	for i := range m.kustomizations {
+		if m.kustomizations[i].id == item.id {
+			return &m.kustomizations[i], true
+		}
+	}
+	return nil, false
+}
+
 func (m *Model) View() string {
 	treeviewHeight := len(m.clusters) + 3
 	for _, child := range m.clusters {
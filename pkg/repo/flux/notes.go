@@ -0,0 +1,128 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package flux
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/log"
+	"github.com/mproffitt/bmx/pkg/components/toast"
+	"github.com/mproffitt/delorian/pkg/components"
+	"github.com/mproffitt/delorian/pkg/components/notes"
+	"gopkg.in/yaml.v3"
+)
+
+// EditNote opens the local note kept for the selected kustomization
+var EditNote = key.NewBinding(key.WithKeys("n"),
+	key.WithHelp("n", "View or edit notes"))
+
+// NotesFile is where notes are kept between runs. Notes are kept in
+// memory only when this is empty
+var NotesFile string
+
+// noteStore holds the notes written against kustomizations, keyed
+// by namespace and name so they follow the kustomization between
+// scans and repositories
+type noteStore struct {
+	sync.Mutex
+	notes  map[string]string
+	loaded bool
+}
+
+var localNotes noteStore
+
+// load reads the notes file the first time it is needed
+func (n *noteStore) load() {
+	if n.loaded {
+		return
+	}
+	n.loaded = true
+	n.notes = make(map[string]string)
+	if NotesFile == "" {
+		return
+	}
+	content, err := os.ReadFile(NotesFile)
+	if err != nil {
+		return
+	}
+	if err := yaml.Unmarshal(content, &n.notes); err != nil {
+		log.Warn("reading notes", "file", NotesFile, "error", err)
+	}
+}
+
+// get returns the note for key or an empty string
+func (n *noteStore) get(key string) string {
+	n.Lock()
+	defer n.Unlock()
+	n.load()
+	return n.notes[key]
+}
+
+// set replaces the note for key and writes the notes file. An empty
+// note is removed
+func (n *noteStore) set(key, note string) error {
+	n.Lock()
+	defer n.Unlock()
+	n.load()
+	if note == "" {
+		delete(n.notes, key)
+	} else {
+		n.notes[key] = note
+	}
+	if NotesFile == "" {
+		return nil
+	}
+	content, err := yaml.Marshal(n.notes)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(NotesFile), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(NotesFile, content, 0o600)
+}
+
+// noteKey is the key the note for the kustomization is kept under
+func (s *shortApi) noteKey() string {
+	return s.GetNamespace() + "/" + s.GetName()
+}
+
+// note returns the note kept for the kustomization
+func (s *shortApi) note() string {
+	return localNotes.get(s.noteKey())
+}
+
+// editNoteCmd opens the note for the kustomization in an overlay
+func (s *shortApi) editNoteCmd() tea.Cmd {
+	return components.OpenDialogCmd(notes.New(s.noteKey(), s.GetName(), s.note()))
+}
+
+// setNote saves a note entered in the overlay
+func setNote(msg components.NoteMsg) tea.Cmd {
+	if err := localNotes.set(msg.Key, msg.Note); err != nil {
+		return toast.NewToastCmd(toast.Error, fmt.Sprintf("saving notes: %v", err))
+	}
+	return nil
+}
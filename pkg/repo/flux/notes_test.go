@@ -0,0 +1,70 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package flux
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mproffitt/delorian/pkg/components"
+)
+
+func TestNotes(t *testing.T) {
+	NotesFile = filepath.Join(t.TempDir(), "delorian", "notes.yaml")
+	localNotes = noteStore{}
+	t.Cleanup(func() {
+		NotesFile = ""
+		localNotes = noteStore{}
+	})
+
+	ns := "flux-system"
+	api := &shortApi{Metadata: shortMeta{Name: "apps", Namespace: &ns}}
+	if title := api.titleText(); title != "apps" {
+		t.Fatalf("expected no marker without a note, got %q", title)
+	}
+
+	if cmd := setNote(components.NoteMsg{Key: api.noteKey(), Note: "flaky"}); cmd != nil {
+		t.Fatalf("unexpected error saving note: %v", cmd())
+	}
+	if title := api.titleText(); title != "¶ apps" {
+		t.Errorf("expected the note marker, got %q", title)
+	}
+	content, err := os.ReadFile(NotesFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(content), "flux-system/apps: flaky") {
+		t.Errorf("note not written to the notes file:\n%s", content)
+	}
+
+	// A fresh store reads the note back from the file
+	localNotes = noteStore{}
+	if note := api.note(); note != "flaky" {
+		t.Errorf("expected the note to be read back, got %q", note)
+	}
+
+	setNote(components.NoteMsg{Key: api.noteKey()})
+	localNotes = noteStore{}
+	if note := api.note(); note != "" {
+		t.Errorf("expected the note to be removed, got %q", note)
+	}
+}
@@ -0,0 +1,226 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package flux
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"maps"
+	"os"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mproffitt/delorian/pkg/components"
+	"github.com/mproffitt/delorian/pkg/components/substitutions"
+	"github.com/mproffitt/delorian/pkg/theme"
+	yaml "gopkg.in/yaml.v3"
+)
+
+// EditSubstitutions opens the substitution editor for the
+// selected kustomization
+var EditSubstitutions = key.NewBinding(key.WithKeys("s"),
+	key.WithHelp("s", "Override substitutions"))
+
+// substitutions returns the postBuild substitutions for this
+// kustomization with any overrides applied
+func (s *shortApi) substitutions() map[string]string {
+	subs := s.defaultSubstitutions()
+	maps.Copy(subs, s.overrides)
+	return subs
+}
+
+// Substitutions returns the values flux substitutes into the
+// content shown for the kustomization. A kustomization as written
+// is substituted by the kustomization applying it, rendered
+// output by its own postBuild
+func (s *shortApi) Substitutions() map[string]string {
+	if s.ftype == Complete || s.ftype == Base {
+		if s.parent == nil {
+			return nil
+		}
+		return s.parent.substitutions()
+	}
+	return s.substitutions()
+}
+
+// defaultSubstitutions returns the postBuild substitutions as
+// written. Inline values take precedence over those read from
+// substituteFrom, as they do in flux
+func (s *shortApi) defaultSubstitutions() map[string]string {
+	subs := maps.Clone(s.substituteFrom)
+	if subs == nil {
+		subs = make(map[string]string)
+	}
+	if s.Spec.PostBuild != nil {
+		maps.Copy(subs, s.Spec.PostBuild.Substitute)
+	}
+	return subs
+}
+
+// resolve sets the name and spec path from their raw values
+// using the substitutions of the parent kustomization
+func (s *shortApi) resolve(substitutions map[string]string) {
+	s.Metadata.Name = substitute(s.rawName, substitutions)
+	if s.Spec.Path != nil {
+		*s.Spec.Path = substitute(s.rawPath, substitutions)
+	}
+}
+
+// fluxExecCmd runs flux with the arguments returned by argsFn
+func (s *shortApi) fluxExecCmd(argsFn func(file string) []string) tea.Cmd {
+	return func() tea.Msg {
+		out, err := s.fluxExec(context.Background(), argsFn)
+		if err != nil {
+			return components.ModelErrorMsg{Error: err}
+		}
+		return components.FluxExecMsg{Output: out}
+	}
+}
+
+// fluxExec runs flux with the arguments returned by argsFn.
+//
+// When overrides are set, or values were read from substituteFrom,
+// flux is given a temporary copy of the kustomization with them
+// merged into its substitutions
+func (s *shortApi) fluxExec(ctx context.Context, argsFn func(file string) []string) (string, error) {
+	if !s.specPathExists() {
+		return "", s.missingSpecPathError()
+	}
+	if len(s.overrides) == 0 && len(s.substituteFrom) == 0 {
+		return components.FluxExec(ctx, argsFn(s.GetPath()))
+	}
+
+	file, err := s.overrideFile()
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(file)
+	return components.FluxExec(ctx, argsFn(file))
+}
+
+// overrideFile writes the kustomization to a temporary file with
+// the overrides merged into spec.postBuild.substitute
+func (s *shortApi) overrideFile() (string, error) {
+	content, err := os.ReadFile(s.GetPath())
+	if err != nil {
+		return "", err
+	}
+
+	var doc map[string]any
+	dec := yaml.NewDecoder(bytes.NewReader(content))
+	for i := 0; i <= s.document; i++ {
+		doc = nil
+		if err := dec.Decode(&doc); err != nil {
+			return "", fmt.Errorf("failed to read kustomization %s from %s: %w",
+				s.GetName(), s.GetPath(), err)
+		}
+	}
+
+	metadata, _ := doc["metadata"].(map[string]any)
+	spec, _ := doc["spec"].(map[string]any)
+	if metadata == nil || spec == nil {
+		return "", fmt.Errorf("kustomization %s in %s has no metadata or spec",
+			s.GetName(), s.GetPath())
+	}
+	metadata["name"] = s.GetName()
+	postBuild, _ := spec["postBuild"].(map[string]any)
+	if postBuild == nil {
+		postBuild = make(map[string]any)
+		spec["postBuild"] = postBuild
+	}
+	postBuild["substitute"] = s.substitutions()
+
+	out, err := yaml.Marshal(doc)
+	if err != nil {
+		return "", err
+	}
+	f, err := os.CreateTemp("", "delorian-*.yaml")
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	if _, err := f.Write(out); err != nil {
+		os.Remove(f.Name())
+		return "", err
+	}
+	return f.Name(), nil
+}
+
+// editSubstitutionsCmd opens the substitution editor for the
+// given kustomization
+func (m *Model) editSubstitutionsCmd(api *shortApi) tea.Cmd {
+	return components.OpenDialogCmd(substitutions.New(api.id, api.GetName(),
+		api.defaultSubstitutions(), api.overrides))
+}
+
+// overridesAction wraps setOverrides so the overrides which were
+// in place before msg can be restored
+func (m *Model) overridesAction(msg components.SubstitutionsMsg) components.Action {
+	var previous map[string]string
+	for i := range m.kustomizations {
+		if m.kustomizations[i].id == msg.ID {
+			previous = m.kustomizations[i].overrides
+		}
+	}
+	return components.Action{
+		Name: "substitution overrides",
+		Do: func() tea.Cmd {
+			return m.setOverrides(msg)
+		},
+		Undo: func() tea.Cmd {
+			return m.setOverrides(components.SubstitutionsMsg{ID: msg.ID, Overrides: previous})
+		},
+	}
+}
+
+// setOverrides applies the overrides entered for a kustomization,
+// re-resolving its children and re-running the current view
+func (m *Model) setOverrides(msg components.SubstitutionsMsg) tea.Cmd {
+	var api *shortApi
+	count := 0
+	for i := range m.kustomizations {
+		if m.kustomizations[i].id == msg.ID {
+			api = &m.kustomizations[i]
+			api.overrides = nil
+			if len(msg.Overrides) > 0 {
+				api.overrides = msg.Overrides
+			}
+		}
+		if len(m.kustomizations[i].overrides) > 0 {
+			count++
+		}
+	}
+	if api == nil {
+		return nil
+	}
+
+	for _, child := range api.children {
+		child.resolve(api.substitutions())
+	}
+	m.list.SetItems(m.Items())
+
+	status := ""
+	if count > 0 {
+		status = fmt.Sprintf(theme.Glyph("✎ substitution overrides on %d kustomizations"), count)
+	}
+	_, cmd := m.Update(components.TabChangedMsg{NewTab: m.lasttab})
+	return tea.Batch(cmd, components.StatusCmd("overrides", status))
+}
@@ -0,0 +1,106 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package flux
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mproffitt/delorian/pkg/testutil"
+	yaml "gopkg.in/yaml.v3"
+)
+
+func TestOverrideFile(t *testing.T) {
+	root := t.TempDir()
+	doc := `apiVersion: kustomize.toolkit.fluxcd.io/v1
+kind: Kustomization
+metadata:
+  name: apps
+  namespace: flux-system
+spec:
+  path: ./apps/${cluster}
+  postBuild:
+    substitute:
+      cluster: staging
+      region: eu-west-1
+`
+	content := multiDoc + "---\n" + doc
+	path := testutil.WriteFile(t, root, filepath.Join("clusters", "apps.yaml"), content)
+
+	k, _, _, _ := parseYamlFromFile(root, path)
+	if len(k) != 2 {
+		t.Fatalf("expected 2 kustomizations, got %d", len(k))
+	}
+	api := &k[1]
+	api.overrides = map[string]string{"cluster": "prod"}
+
+	// The path is substituted by the parent, never by the
+	// kustomization's own postBuild or overrides
+	if want := filepath.Join(root, "apps", "${cluster}"); api.GetAbsoluteSpecPath() != want {
+		t.Errorf("spec path = %q, want %q", api.GetAbsoluteSpecPath(), want)
+	}
+	parent := &shortApi{overrides: map[string]string{"cluster": "dev"}}
+	api.resolve(parent.substitutions())
+	if want := filepath.Join(root, "apps", "dev"); api.GetAbsoluteSpecPath() != want {
+		t.Errorf("spec path = %q, want %q", api.GetAbsoluteSpecPath(), want)
+	}
+
+	file, err := api.overrideFile()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer os.Remove(file)
+
+	out, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var written shortApi
+	if err := yaml.Unmarshal(out, &written); err != nil {
+		t.Fatal(err)
+	}
+	if written.GetName() != "apps" {
+		t.Errorf("name = %q, want %q", written.GetName(), "apps")
+	}
+	subs := written.Spec.PostBuild.Substitute
+	if subs["cluster"] != "prod" || subs["region"] != "eu-west-1" {
+		t.Errorf("unexpected substitutions %v", subs)
+	}
+}
+
+func TestSubstitutionsShown(t *testing.T) {
+	parent := &shortApi{Spec: shortSpec{PostBuild: &postBuild{
+		Substitute: map[string]string{"cluster": "prod"}}}}
+	child := &shortApi{ftype: Complete, parent: parent, Spec: shortSpec{PostBuild: &postBuild{
+		Substitute: map[string]string{"cluster": "child"}}},
+		overrides: map[string]string{"tier": "web"}}
+
+	if got := child.Substitutions()["cluster"]; got != "prod" {
+		t.Errorf("expected the kustomization as written to use its parent, got %q", got)
+	}
+	if (&shortApi{ftype: Complete}).Substitutions() != nil {
+		t.Error("expected no substitutions without a parent")
+	}
+	child.ftype = Patch
+	if got := child.Substitutions(); got["cluster"] != "child" || got["tier"] != "web" {
+		t.Errorf("expected rendered output to use its own substitutions, got %v", got)
+	}
+}
@@ -0,0 +1,68 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package flux
+
+import (
+	"testing"
+)
+
+// windows swaps the separator for the duration of the test
+func windows(t *testing.T) {
+	t.Helper()
+	previous := separator
+	separator = `\`
+	t.Cleanup(func() { separator = previous })
+}
+
+func TestCheckClusterPathWindows(t *testing.T) {
+	windows(t)
+	root := `C:\Users\ops\fleet`
+	m := &Model{}
+	for _, path := range []string{
+		root + `\clusters\production\`,
+		root + `\clusters\production\flux-system`,
+		root + `\management-hub\eu-west`,
+		root + `\apps\bases\clusters\ignored`,
+		root + `\.git\clusters\ignored`,
+	} {
+		m.checkClusterPath(root, path)
+	}
+
+	names := make([]string, 0)
+	for _, c := range m.clusters {
+		names = append(names, c.name)
+	}
+	if len(names) != 2 || names[0] != "production" || names[1] != "eu-west" {
+		t.Fatalf("expected production and eu-west, got %v", names)
+	}
+	if !m.clusters[0].Matches("production") {
+		t.Errorf("expected the cluster to match its directory %q", m.clusters[0].filepath)
+	}
+}
+
+func TestHiddenWindows(t *testing.T) {
+	windows(t)
+	if !hidden(`C:\fleet\.git\HEAD`) {
+		t.Error("expected .git to be hidden")
+	}
+	if hidden(`C:\fleet\apps\podinfo.yaml`) {
+		t.Error("expected apps to be visible")
+	}
+}
@@ -0,0 +1,140 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package flux
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	zone "github.com/lrstanley/bubblezone"
+	"github.com/mproffitt/delorian/pkg/theme"
+)
+
+const (
+	// PeekDelay is how long the mouse must rest on a kustomization
+	// before its preview is shown
+	PeekDelay = 400 * time.Millisecond
+
+	// PeekLines caps the lines of the manifest shown in a preview
+	PeekLines = 12
+)
+
+// PeekMsg is sent once the mouse has rested on a kustomization for
+// PeekDelay. Tag identifies the hover it was started for so
+// earlier hovers are ignored
+type PeekMsg struct {
+	Tag int
+}
+
+// peek is the preview of the kustomization under the mouse
+type peek struct {
+	content string
+	id      string
+	tag     int
+	y       int
+}
+
+// hovered returns the kustomization in the list under the mouse
+func (m *Model) hovered(msg tea.MouseMsg) (*shortApi, bool) {
+	for _, item := range m.list.VisibleItems() {
+		k := item.(*shortApi)
+		if z := zone.Get(k.id); z != nil && z.InBounds(msg) {
+			return k, true
+		}
+	}
+	return nil, false
+}
+
+// hover starts the delay before previewing the kustomization under
+// the mouse. Moving to another kustomization restarts it, so
+// sweeping over the list doesn't read every file on the way
+func (m *Model) hover(msg tea.MouseMsg) tea.Cmd {
+	if m.list == nil {
+		return nil
+	}
+	k, ok := m.hovered(msg)
+	selected, _ := m.selectedKustomization()
+	switch {
+	case !ok || (selected != nil && selected.id == k.id):
+		// The selected kustomization is already in the primary view
+		m.clearPeek()
+		return nil
+	case k.id == m.peek.id:
+		return nil
+	}
+	m.clearPeek()
+	m.peek.id, m.peek.y = k.id, msg.Y
+	tag := m.peek.tag
+	return tea.Tick(PeekDelay, func(time.Time) tea.Msg {
+		return PeekMsg{Tag: tag}
+	})
+}
+
+// clearPeek hides the preview and abandons any pending hover
+func (m *Model) clearPeek() {
+	m.peek = peek{tag: m.peek.tag + 1}
+}
+
+// peeked fills the preview once the mouse has rested long enough
+func (m *Model) peeked(msg PeekMsg) {
+	if msg.Tag != m.peek.tag || m.peek.id == "" {
+		return
+	}
+	for i := range m.kustomizations {
+		if m.kustomizations[i].id == m.peek.id {
+			m.peek.content = m.kustomizations[i].preview(PeekLines)
+			return
+		}
+	}
+}
+
+// preview summarises the kustomization and shows the first lines
+// of its manifest as written. Nothing is built
+func (s *shortApi) preview(lines int) string {
+	options := []string{"metadata.name", s.GetName()}
+	if s.GetNamespace() != "" {
+		options = append(options, "metadata.namespace", s.GetNamespace())
+	}
+	manifest := strings.Split(strings.TrimSpace(readFile(s.GetPath(), options...)), "\n")
+	if len(manifest) > lines {
+		manifest = append(manifest[:lines], fmt.Sprintf(theme.Glyph("… %d more lines"), len(manifest)-lines))
+	}
+
+	title := lipgloss.NewStyle().Foreground(theme.Colours.BrightYellow).Bold(true).
+		Render(s.GetName())
+	summary := lipgloss.NewStyle().Foreground(theme.Colours.BrightBlack).
+		Render(s.Description())
+	body := lipgloss.NewStyle().Foreground(theme.Colours.Fg).
+		Render(strings.Join(manifest, "\n"))
+	return lipgloss.NewStyle().
+		Border(theme.RoundedBorder()).
+		BorderForeground(theme.Colours.Blue).
+		Padding(0, 1).
+		Render(lipgloss.JoinVertical(lipgloss.Left, title, summary, "", body))
+}
+
+// Peek returns the preview of the kustomization under the mouse
+// and the row it was hovered on, once it is ready
+func (m *Model) Peek() (string, int, bool) {
+	return m.peek.content, m.peek.y, m.peek.content != ""
+}
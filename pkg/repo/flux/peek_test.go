@@ -0,0 +1,60 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package flux
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mproffitt/delorian/pkg/testutil"
+)
+
+func TestPeek(t *testing.T) {
+	testutil.Setup()
+	root := t.TempDir()
+	doc := "apiVersion: kustomize.toolkit.fluxcd.io/v1\nkind: Kustomization\n" +
+		"metadata:\n  name: apps\n  namespace: flux-system\nspec:\n  path: ./apps\n" +
+		strings.Repeat("  # padding\n", 20)
+	testutil.WriteFile(t, root, "apps.yaml", doc)
+
+	m := New(root)
+	m.kustomizations, _, _, _ = parseYaml([]byte(doc), root, filepath.Join(root, "apps.yaml"))
+	m.kustomizations[0].ftype = Complete
+	m.peek = peek{id: m.kustomizations[0].id, tag: 3}
+
+	m.peeked(PeekMsg{Tag: 2})
+	if _, _, ok := m.Peek(); ok {
+		t.Fatal("expected an earlier hover to be ignored")
+	}
+	m.peeked(PeekMsg{Tag: 3})
+	content, _, ok := m.Peek()
+	if !ok || !strings.Contains(content, "name: apps") {
+		t.Fatalf("expected the manifest in the preview, got %q", content)
+	}
+	if !strings.Contains(content, "more lines") {
+		t.Errorf("expected the preview to be capped at %d lines, got %q", PeekLines, content)
+	}
+
+	m.clearPeek()
+	if _, _, ok := m.Peek(); ok || m.peek.tag != 4 {
+		t.Errorf("expected clearing to hide the preview and abandon the hover, got %+v", m.peek)
+	}
+}
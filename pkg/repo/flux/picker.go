@@ -0,0 +1,75 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package flux
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mproffitt/delorian/pkg/components"
+	"github.com/mproffitt/delorian/pkg/components/picker"
+)
+
+// SelectMsg selects the kustomization with the given id in the list
+type SelectMsg struct {
+	ID string
+}
+
+// SelectCmd returns a SelectMsg
+func SelectCmd(id string) tea.Cmd {
+	return func() tea.Msg {
+		return SelectMsg{ID: id}
+	}
+}
+
+// PickerCmd opens a picker listing the kustomizations in the list
+// so one can be selected without the sidebar on screen
+func (m *Model) PickerCmd() tea.Cmd {
+	if m.list == nil {
+		return nil
+	}
+	items := make([]picker.Item, 0, len(m.list.Items()))
+	for _, item := range m.list.Items() {
+		k := item.(*shortApi)
+		items = append(items, picker.Item{
+			Label: fmt.Sprintf("%s  %s", k.titleText(), k.Description()),
+			Value: k.id,
+		})
+	}
+	return components.OpenDialogCmd(picker.New("Go to kustomization", items,
+		func(item picker.Item) tea.Cmd {
+			return SelectCmd(item.Value)
+		}))
+}
+
+// selectID moves the list selection to the kustomization with id
+func (m *Model) selectID(id string) tea.Cmd {
+	if m.list == nil {
+		return nil
+	}
+	m.list.ResetFilter()
+	for i, item := range m.list.Items() {
+		if item.(*shortApi).id == id {
+			m.list.Select(i)
+			return m.defaultHandler(nil)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,160 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package flux
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mproffitt/bmx/pkg/components/toast"
+	"github.com/mproffitt/delorian/pkg/components"
+	"github.com/mproffitt/delorian/pkg/components/picker"
+	"github.com/mproffitt/delorian/pkg/components/playground"
+	"github.com/mproffitt/delorian/pkg/kustomize"
+	"github.com/mproffitt/delorian/pkg/theme"
+)
+
+// EditOverlay opens the kustomization file or one of its patches
+// in an editor to preview the effect of changes on the build
+var EditOverlay = key.NewBinding(key.WithKeys("e"),
+	key.WithHelp("e", "Edit overlay and preview"))
+
+// editOverlayCmd opens the overlay editor for the given
+// kustomization, asking which file to edit when there are several
+func (m *Model) editOverlayCmd(api *shortApi) tea.Cmd {
+	files := kustomize.OverlayFiles(api.GetAbsoluteSpecPath())
+	if len(files) == 0 {
+		return toast.NewToastCmd(toast.Warning,
+			"No kustomization.yaml found in "+api.GetAbsoluteSpecPath())
+	}
+	if len(files) == 1 {
+		return api.editFileCmd(files[0])
+	}
+
+	items := make([]picker.Item, 0, len(files))
+	for _, file := range files {
+		label := file
+		if rel, err := filepath.Rel(api.root, file); err == nil {
+			label = rel
+		}
+		if _, ok := api.edits[file]; ok {
+			label = theme.Glyph("✎ ") + label
+		}
+		items = append(items, picker.Item{Label: label, Value: file})
+	}
+	return components.OpenDialogCmd(picker.New("Edit overlay", items,
+		func(item picker.Item) tea.Cmd {
+			return api.editFileCmd(item.Value)
+		}))
+}
+
+// editFileCmd opens the editor for file, continuing from any
+// previous edit
+func (s *shortApi) editFileCmd(file string) tea.Cmd {
+	content, ok := s.edits[file]
+	if !ok {
+		b, err := os.ReadFile(file)
+		if err != nil {
+			return toast.NewToastCmd(toast.Error, err.Error())
+		}
+		content = string(b)
+	}
+	return components.OpenDialogCmd(playground.New(s.id, file, content))
+}
+
+// previewCmd builds the spec path with the edited files in place
+// of those on disk, applying the substitutions flux would
+func (s *shortApi) previewCmd() tea.Cmd {
+	files := make(map[string][]byte, len(s.edits))
+	for file, content := range s.edits {
+		files[file] = []byte(content)
+	}
+	path := s.GetAbsoluteSpecPath()
+	subs := s.substitutions()
+	return func() tea.Msg {
+		out, err := kustomize.ExecKustomizeOverlay(path, files)
+		if err != nil {
+			return components.ModelErrorMsg{Error: err}
+		}
+		return components.FluxExecMsg{Output: substitute(string(out), subs)}
+	}
+}
+
+// setEdit previews, saves or discards an edit made in the overlay
+// editor then re-runs the current view
+func (m *Model) setEdit(msg components.OverlayEditMsg) tea.Cmd {
+	var api *shortApi
+	count := 0
+	for i := range m.kustomizations {
+		if m.kustomizations[i].id == msg.ID {
+			api = &m.kustomizations[i]
+		}
+	}
+	if api == nil {
+		return nil
+	}
+
+	var cmds []tea.Cmd
+	switch msg.Action {
+	case components.EditPreview:
+		if api.edits == nil {
+			api.edits = make(map[string]string)
+		}
+		api.edits[msg.File] = msg.Content
+		if disk, err := os.ReadFile(msg.File); err == nil && string(disk) == msg.Content {
+			delete(api.edits, msg.File)
+		}
+	case components.EditSave:
+		if err := writeFile(msg.File, msg.Content); err != nil {
+			return toast.NewToastCmd(toast.Error, err.Error())
+		}
+		delete(api.edits, msg.File)
+		cmds = append(cmds, toast.NewToastCmd(toast.Success, "Saved "+msg.File))
+	case components.EditDiscard:
+		delete(api.edits, msg.File)
+	}
+
+	for i := range m.kustomizations {
+		if len(m.kustomizations[i].edits) > 0 {
+			count++
+		}
+	}
+	m.list.SetItems(m.Items())
+
+	status := ""
+	if count > 0 {
+		status = fmt.Sprintf(theme.Glyph("✎ unsaved overlay edits on %d kustomizations"), count)
+	}
+	_, cmd := m.Update(components.TabChangedMsg{NewTab: m.lasttab})
+	return tea.Batch(append(cmds, cmd, components.StatusCmd("edits", status))...)
+}
+
+// writeFile replaces the content of an existing file, keeping
+// its permissions
+func writeFile(file, content string) error {
+	fi, err := os.Stat(file)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(file, []byte(content), fi.Mode().Perm())
+}
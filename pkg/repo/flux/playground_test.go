@@ -0,0 +1,60 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package flux
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mproffitt/delorian/pkg/components"
+	"github.com/mproffitt/delorian/pkg/testutil"
+)
+
+func TestBuildPreviewsEdits(t *testing.T) {
+	root := t.TempDir()
+	apps := filepath.Join(root, "apps")
+	files := map[string]string{
+		"kustomization.yaml": "resources:\n  - configmap.yaml\n",
+		"configmap.yaml":     "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: app\ndata:\n  colour: blue\n",
+	}
+	for name, content := range files {
+		testutil.WriteFile(t, apps, name, content)
+	}
+
+	path := "./apps"
+	configmap := filepath.Join(apps, "configmap.yaml")
+	s := shortApi{
+		root:      root,
+		Spec:      shortSpec{Path: &path},
+		overrides: map[string]string{"COLOUR": "green"},
+		edits: map[string]string{
+			configmap: strings.ReplaceAll(files["configmap.yaml"], "blue", "${COLOUR}"),
+		},
+	}
+
+	msg, ok := s.Build()().(components.FluxExecMsg)
+	if !ok {
+		t.Fatalf("expected the preview output, got %#v", msg)
+	}
+	if !strings.Contains(msg.Output, "colour: green") {
+		t.Errorf("expected the edit to be built with substitutions, got\n%s", msg.Output)
+	}
+}
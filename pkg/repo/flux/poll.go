@@ -0,0 +1,116 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package flux
+
+import (
+	"maps"
+	"os"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/log"
+	"github.com/mproffitt/delorian/pkg/components"
+)
+
+// PollInterval is the delay between checks for changed files. When
+// zero the repository is only scanned again on request
+var PollInterval time.Duration
+
+// stamp records enough about a file to notice it has changed
+type stamp struct {
+	modTime time.Time
+	size    int64
+}
+
+// poll holds the files seen by the last scan. fallback is set
+// when the file watcher could not start and polling replaces it
+type poll struct {
+	files    map[string]stamp
+	tag      int
+	fallback bool
+}
+
+// record stores the stamp of a file or directory found during the
+// walk. Directories are included so added and removed files are
+// noticed as well as edits. Hidden paths such as .git change too
+// often to be useful and are skipped
+func (m *Model) record(path string, fi os.FileInfo) {
+	if hidden(path) {
+		return
+	}
+	m.Lock()
+	defer m.Unlock()
+	if m.poll.files == nil {
+		m.poll.files = make(map[string]stamp)
+	}
+	m.poll.files[path] = stamp{modTime: fi.ModTime(), size: fi.Size()}
+}
+
+// pollInterval is the delay between polls. The file watcher already
+// notices changes so polling is skipped while it runs, otherwise
+// the same change would be scanned twice
+func (m *Model) pollInterval() time.Duration {
+	switch {
+	case m.files != nil:
+		return 0
+	case m.poll.fallback && PollInterval <= 0:
+		return FileWatchFallback
+	}
+	return PollInterval
+}
+
+// pollCmd checks the recorded files for changes after the interval
+//
+// The files are copied so the check can run while a new scan
+// replaces them
+func (m *Model) pollCmd() tea.Cmd {
+	interval := m.pollInterval()
+	if interval <= 0 {
+		return nil
+	}
+	files, tag := maps.Clone(m.poll.files), m.poll.tag
+	return tea.Tick(interval, func(time.Time) tea.Msg {
+		return components.PollMsg{Tag: tag, Changed: changed(files)}
+	})
+}
+
+// changed reports if any of the files differ from their stamps
+func changed(files map[string]stamp) bool {
+	for path, s := range files {
+		fi, err := os.Stat(path)
+		if err != nil || !fi.ModTime().Equal(s.modTime) || fi.Size() != s.size {
+			log.Debug("file changed", "path", path)
+			return true
+		}
+	}
+	return false
+}
+
+// polled scans the repository again when files have changed,
+// otherwise it schedules the next check
+func (m *Model) polled(msg components.PollMsg) tea.Cmd {
+	if msg.Tag != m.poll.tag {
+		return nil
+	}
+	if !msg.Changed {
+		return m.pollCmd()
+	}
+	return tea.Sequence(ScanningStatusCmd(), components.RescanCmd())
+}
@@ -0,0 +1,55 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package flux
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/mproffitt/delorian/pkg/components"
+	"github.com/mproffitt/delorian/pkg/testutil"
+)
+
+func TestPollDetectsChanges(t *testing.T) {
+	testutil.Setup()
+	root := t.TempDir()
+	path := testutil.WriteFile(t, root, filepath.Join("clusters", "apps.yaml"), multiDoc)
+
+	m := New(root)
+	m.walk()
+	if _, ok := m.poll.files[path]; !ok {
+		t.Fatalf("expected %s to be recorded, got %v", path, m.poll.files)
+	}
+	if changed(m.poll.files) {
+		t.Error("expected no changes straight after a scan")
+	}
+
+	testutil.WriteFile(t, root, filepath.Join("clusters", "apps.yaml"), multiDoc+"\n")
+	if !changed(m.poll.files) {
+		t.Error("expected the edited file to be detected")
+	}
+
+	if cmd := m.polled(components.PollMsg{Tag: m.poll.tag - 1, Changed: true}); cmd != nil {
+		t.Error("expected polls from a previous scan to be ignored")
+	}
+	if cmd := m.polled(components.PollMsg{Tag: m.poll.tag, Changed: true}); cmd == nil {
+		t.Error("expected a rescan when files have changed")
+	}
+}
@@ -0,0 +1,92 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package flux
+
+import (
+	"fmt"
+	"slices"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mproffitt/bmx/pkg/components/toast"
+	"github.com/mproffitt/delorian/pkg/components"
+	"github.com/mproffitt/delorian/pkg/components/picker"
+)
+
+// MaxRecent is the number of recently viewed kustomizations
+// remembered for the session
+const MaxRecent = 10
+
+// visited moves the kustomization to the front of the recently
+// viewed list. Kustomizations are remembered by identity so they
+// are still found after a rescan
+func (m *Model) visited(api *shortApi) {
+	identity := api.identity()
+	if len(m.recent) > 0 && m.recent[0] == identity {
+		return
+	}
+	m.recent = slices.DeleteFunc(m.recent, func(r string) bool {
+		return r == identity
+	})
+	m.recent = slices.Insert(m.recent, 0, identity)
+	m.recent = m.recent[:min(len(m.recent), MaxRecent)]
+}
+
+// recentItems returns the recently viewed kustomizations still in
+// the list, most recent first, leaving out the one selected
+func (m *Model) recentItems() []*shortApi {
+	if m.list == nil {
+		return nil
+	}
+	selected, _ := m.selectedKustomization()
+	items := make([]*shortApi, 0, len(m.recent))
+	for _, identity := range m.recent {
+		for _, item := range m.list.Items() {
+			k := item.(*shortApi)
+			if k.identity() != identity {
+				continue
+			}
+			if selected == nil || k.id != selected.id {
+				items = append(items, k)
+			}
+			break
+		}
+	}
+	return items
+}
+
+// RecentCmd opens a picker listing the recently viewed
+// kustomizations to switch back to one of them
+func (m *Model) RecentCmd() tea.Cmd {
+	recent := m.recentItems()
+	if len(recent) == 0 {
+		return toast.NewToastCmd(toast.Info, "No other kustomizations viewed yet")
+	}
+	items := make([]picker.Item, 0, len(recent))
+	for _, k := range recent {
+		items = append(items, picker.Item{
+			Label: fmt.Sprintf("%s  %s", k.titleText(), k.Description()),
+			Value: k.id,
+		})
+	}
+	return components.OpenDialogCmd(picker.New("Recently viewed", items,
+		func(item picker.Item) tea.Cmd {
+			return SelectCmd(item.Value)
+		}))
+}
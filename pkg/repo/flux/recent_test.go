@@ -0,0 +1,79 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package flux
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mproffitt/delorian/pkg/testutil"
+)
+
+func TestRecent(t *testing.T) {
+	testutil.Setup()
+	root := t.TempDir()
+	for _, name := range []string{"apps", "infra", "monitoring"} {
+		testutil.WriteFile(t, root, filepath.Join("clusters", name+".yaml"), strings.ReplaceAll(multiDoc, "apps", name))
+	}
+
+	m := New(root)
+	m.walk()
+	m.Update(ModelReadyMsg{Ready: true})
+	byName := make(map[string]*shortApi)
+	for _, item := range m.list.Items() {
+		byName[item.(*shortApi).GetName()] = item.(*shortApi)
+	}
+	for _, name := range []string{"apps", "infra", "monitoring", "apps"} {
+		m.Update(SelectMsg{ID: byName[name].id})
+	}
+
+	names := func() []string {
+		var names []string
+		for _, k := range m.recentItems() {
+			names = append(names, k.GetName())
+		}
+		return names
+	}
+	// apps is selected so is left out, leaving the others most
+	// recent first
+	if got := fmt.Sprint(names()); got != "[monitoring infra]" {
+		t.Errorf("unexpected recent list %s", got)
+	}
+	if m.RecentCmd() == nil {
+		t.Error("expected a picker of recent kustomizations")
+	}
+
+	// The list is kept by identity across a rescan
+	m.walk()
+	m.Update(ModelReadyMsg{Ready: true})
+	m.Update(SelectMsg{ID: m.list.Items()[0].(*shortApi).id})
+	if got := len(m.recentItems()); got != 2 {
+		t.Errorf("expected 2 recent kustomizations after a rescan, got %d", got)
+	}
+
+	for i := 0; i < MaxRecent*2; i++ {
+		m.visited(&shortApi{root: root, rawName: fmt.Sprint(i)})
+	}
+	if len(m.recent) != MaxRecent {
+		t.Errorf("expected the recent list to be capped at %d, got %d", MaxRecent, len(m.recent))
+	}
+}
@@ -0,0 +1,106 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package flux
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mproffitt/delorian/pkg/components"
+)
+
+// MaxConcurrentDiffs limits the number of flux processes run at
+// once by a recursive diff
+var MaxConcurrentDiffs = 4
+
+// dependents returns api followed by every kustomization it
+// applies or which depends on it, directly or indirectly. The
+// graph built during the walk is followed breadth first so
+// closer kustomizations are listed first
+func (m *Model) dependents(api *shortApi) []*shortApi {
+	seen := map[string]bool{api.id: true}
+	queue := []*shortApi{api}
+	for i := 0; i < len(queue); i++ {
+		current := queue[i]
+		next := append([]*shortApi{}, current.children...)
+		for j := range m.kustomizations {
+			if m.kustomizations[j].dependsOn(current) {
+				next = append(next, &m.kustomizations[j])
+			}
+		}
+		for _, k := range next {
+			if !seen[k.id] {
+				seen[k.id] = true
+				queue = append(queue, k)
+			}
+		}
+	}
+	return queue
+}
+
+// dependsOn reports if s lists other in spec.dependsOn. A
+// dependency without a namespace is in the namespace of s
+func (s *shortApi) dependsOn(other *shortApi) bool {
+	for _, dep := range s.Spec.DependsOn {
+		namespace := dep.Namespace
+		if namespace == "" {
+			namespace = s.GetNamespace()
+		}
+		if dep.Name == other.GetName() && namespace == other.GetNamespace() {
+			return true
+		}
+	}
+	return false
+}
+
+// recursiveDiffCmd diffs api and its dependents concurrently,
+// returning the output of each as its own section
+func (m *Model) recursiveDiffCmd(api *shortApi) tea.Cmd {
+	subtree := m.dependents(api)
+	return func() tea.Msg {
+		sections := make([]components.FluxSection, len(subtree))
+		limit := make(chan struct{}, max(1, MaxConcurrentDiffs))
+		var wg sync.WaitGroup
+		for i, k := range subtree {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				limit <- struct{}{}
+				defer func() { <-limit }()
+
+				out, err := k.fluxExec(context.Background(), k.diffArgs)
+				sections[i] = components.FluxSection{
+					Title:  k.GetNamespace() + "/" + k.GetName(),
+					Output: out,
+					Error:  err,
+				}
+			}()
+		}
+		wg.Wait()
+
+		scope := api.GetName()
+		if n := len(subtree) - 1; n > 0 {
+			scope = fmt.Sprintf("%s and %d dependent kustomizations", scope, n)
+		}
+		return components.FluxExecMsg{Sections: sections, Scope: scope}
+	}
+}
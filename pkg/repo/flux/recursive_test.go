@@ -0,0 +1,54 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package flux
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestDependents(t *testing.T) {
+	ns := "flux-system"
+	k := func(id, name string, deps ...dependency) shortApi {
+		return shortApi{id: id, Metadata: shortMeta{Name: name, Namespace: &ns},
+			Spec: shortSpec{DependsOn: deps}}
+	}
+	m := &Model{kustomizations: []shortApi{
+		k("1", "infra"),
+		k("2", "apps", dependency{Name: "infra"}),
+		k("3", "monitoring", dependency{Name: "apps", Namespace: ns}),
+		k("4", "elsewhere", dependency{Name: "infra", Namespace: "other"}),
+		k("5", "podinfo"),
+		// cycles must not loop forever
+		k("6", "loop", dependency{Name: "monitoring"}),
+	}}
+	m.kustomizations[2].Spec.DependsOn = append(m.kustomizations[2].Spec.DependsOn,
+		dependency{Name: "loop"})
+	m.kustomizations[1].children = []*shortApi{&m.kustomizations[4]}
+
+	got := make([]string, 0)
+	for _, d := range m.dependents(&m.kustomizations[0]) {
+		got = append(got, d.GetName())
+	}
+	want := []string{"infra", "apps", "podinfo", "monitoring", "loop"}
+	if !slices.Equal(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
@@ -0,0 +1,103 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package flux
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/log"
+	"github.com/mproffitt/delorian/pkg/components"
+	"github.com/mproffitt/delorian/pkg/yaml"
+)
+
+// CompareRef is the git ref the working tree is compared against
+// on the Ref Diff tab
+var CompareRef = "main"
+
+// RefDiff renders this kustomization from the working tree and
+// from ref and returns the structural difference between them
+func (s *shortApi) RefDiff(ref string) tea.Cmd {
+	return func() tea.Msg {
+		ctx := context.Background()
+		current, err := s.fluxExec(ctx, s.buildArgs)
+		if err != nil {
+			return components.ModelErrorMsg{Error: err}
+		}
+
+		previous, err := s.buildAtRef(ctx, ref)
+		if err != nil {
+			return components.ModelErrorMsg{Error: err}
+		}
+
+		out, err := yaml.Diff([]byte(previous), []byte(current))
+		if err != nil {
+			return components.ModelErrorMsg{Error: err}
+		}
+		return components.FluxExecMsg{Output: out}
+	}
+}
+
+// buildAtRef checks ref out into a temporary worktree and builds
+// this kustomization from there. A kustomization which does not
+// exist at ref renders as empty
+func (s *shortApi) buildAtRef(ctx context.Context, ref string) (string, error) {
+	top, _, err := components.Run(ctx, "git", []string{"-C", s.root, "rev-parse", "--show-toplevel"})
+	if err != nil {
+		return "", fmt.Errorf("%s is not in a git repository: %w", s.root, err)
+	}
+	top = strings.TrimSpace(top)
+	rel, err := filepath.Rel(top, s.root)
+	if err != nil {
+		return "", err
+	}
+
+	dir, err := os.MkdirTemp("", "delorian-ref-")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(dir)
+
+	worktree := filepath.Join(dir, "tree")
+	if _, _, err := components.Run(ctx, "git", []string{
+		"-C", top, "worktree", "add", "--detach", worktree, ref,
+	}); err != nil {
+		return "", fmt.Errorf("failed to check out %s: %w", ref, err)
+	}
+	defer func() {
+		// The worktree is removed even once ctx is done
+		if _, _, err := components.Run(context.WithoutCancel(ctx), "git", []string{
+			"-C", top, "worktree", "remove", "--force", worktree,
+		}); err != nil {
+			log.Error("ref diff", "worktree", worktree, "error", err)
+		}
+	}()
+
+	at := *s
+	at.root = filepath.Join(worktree, rel)
+	if _, err := os.Stat(at.GetPath()); os.IsNotExist(err) {
+		return "", nil
+	}
+	return at.fluxExec(ctx, at.buildArgs)
+}
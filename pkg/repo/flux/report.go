@@ -0,0 +1,77 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package flux
+
+import (
+	"strings"
+
+	"github.com/mproffitt/delorian/pkg/report"
+)
+
+// Report adds the counts from the last scan and the classification
+// of the selected kustomization to r
+func (m *Model) Report(r *report.Report) {
+	m.Lock()
+	defer m.Unlock()
+
+	r.Repository.Roots = len(m.roots)
+	r.Repository.Clusters = len(m.clusters)
+	r.Repository.Sources = len(m.sources)
+	r.Repository.ScannedAt = m.scannedAt
+	for _, k := range m.kustomizations {
+		r.Repository.Kustomizations[k.ftype.String()]++
+	}
+
+	if m.list == nil {
+		return
+	}
+	if api, ok := m.selectedKustomization(); ok {
+		r.Selected = m.classify(api)
+	}
+}
+
+// classify describes how the traversal understood api
+func (m *Model) classify(api *shortApi) *report.Classification {
+	c := report.Classification{
+		Kind:          api.Kind,
+		Name:          api.GetName(),
+		Namespace:     api.GetNamespace(),
+		Type:          api.ftype.String(),
+		File:          api.GetPath(),
+		Document:      api.document,
+		Root:          api.root,
+		Path:          api.GetAbsoluteSpecPath(),
+		RawPath:       api.rawPath,
+		Kustomize:     api.kustomize,
+		Children:      len(api.children),
+		Clusters:      m.clusterBranch(api.GetPath()),
+		Substitutions: api.substitutions(),
+		Overrides:     len(api.overrides),
+		Edits:         len(api.edits),
+	}
+	if api.parent != nil {
+		c.Parent = api.parent.GetName()
+	}
+	if api.source != nil {
+		c.Source = strings.Join([]string{api.source.Kind,
+			api.source.GetNamespace(), api.source.GetName()}, "/")
+	}
+	return &c
+}
@@ -0,0 +1,65 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package flux
+
+import (
+	"testing"
+
+	"github.com/mproffitt/delorian/pkg/config"
+	"github.com/mproffitt/delorian/pkg/report"
+)
+
+func TestReportCounts(t *testing.T) {
+	m := &Model{
+		roots: []string{"/repo"},
+		kustomizations: []shortApi{
+			{ftype: Complete}, {ftype: Complete}, {ftype: Base},
+		},
+		sources: []shortSource{{}},
+	}
+	r := report.New(&config.Config{})
+	m.Report(r)
+	if r.Repository.Kustomizations["complete"] != 2 || r.Repository.Kustomizations["base"] != 1 {
+		t.Errorf("unexpected counts %v", r.Repository.Kustomizations)
+	}
+	if r.Repository.Roots != 1 || r.Repository.Sources != 1 || r.Selected != nil {
+		t.Errorf("unexpected stats %+v", r.Repository)
+	}
+}
+
+func TestClassify(t *testing.T) {
+	name := "podinfo"
+	m := &Model{kustomizations: []shortApi{
+		{Kind: "Kustomization", Metadata: shortMeta{Name: "apps"}, ftype: Complete},
+		{Kind: "Kustomization", Metadata: shortMeta{Name: name}, ftype: Patch, root: "/repo",
+			filepath: "apps/podinfo.yaml", overrides: map[string]string{"a": "b"}},
+	}}
+	child := &m.kustomizations[1]
+	child.parent = &m.kustomizations[0]
+	child.source = &shortSource{Kind: "GitRepository", shortMeta: shortMeta{Name: "fleet"}}
+
+	c := m.classify(child)
+	if c.Type != "patch" || c.Parent != "apps" || c.Overrides != 1 {
+		t.Errorf("unexpected classification %+v", c)
+	}
+	if c.File != "/repo/apps/podinfo.yaml" || c.Source != "GitRepository//fleet" {
+		t.Errorf("unexpected file or source %q %q", c.File, c.Source)
+	}
+}
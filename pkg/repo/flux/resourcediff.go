@@ -0,0 +1,98 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package flux
+
+import (
+	"context"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mproffitt/delorian/pkg/components"
+	"github.com/mproffitt/delorian/pkg/yaml"
+	v3 "gopkg.in/yaml.v3"
+)
+
+// resourceDiffCmd compares a single resource from the build of this
+// kustomization with the object in the cluster. This is quicker and
+// quieter than diffing the whole kustomization when one resource is
+// being worked on
+func (s *shortApi) resourceDiffCmd(msg components.ResourceDiffMsg) tea.Cmd {
+	return func() tea.Msg {
+		ctx := context.Background()
+		built, err := s.fluxExec(ctx, s.buildArgs)
+		if err != nil {
+			return components.ModelErrorMsg{Error: err}
+		}
+
+		desired, apiVersion := findDocument(built, msg)
+		live, err := components.KubectlExec(ctx, liveArgs(msg, apiVersion))
+		if err != nil {
+			return components.ModelErrorMsg{Error: err}
+		}
+		if strings.TrimSpace(live) != "" && desired != "" {
+			restricted, err := yaml.Restrict([]byte(live), []byte(desired))
+			if err != nil {
+				return components.ModelErrorMsg{Error: err}
+			}
+			live = string(restricted)
+		}
+
+		out, err := yaml.Diff([]byte(live), []byte(desired))
+		if err != nil {
+			return components.ModelErrorMsg{Error: err}
+		}
+		scope := strings.Join([]string{msg.Kind, msg.Namespace, msg.Name}, "/")
+		if msg.Namespace == "" {
+			scope = msg.Kind + "/" + msg.Name
+		}
+		return components.FluxExecMsg{Output: out, Scope: scope}
+	}
+}
+
+// findDocument returns the built resource matching msg and its
+// apiVersion. A resource missing from the build returns empty
+func findDocument(built string, msg components.ResourceDiffMsg) (string, string) {
+	for _, d := range yaml.Documents(built) {
+		if d.Kind != msg.Kind || d.Name != msg.Name || d.Namespace != msg.Namespace {
+			continue
+		}
+		var object struct {
+			ApiVersion string `yaml:"apiVersion"`
+		}
+		_ = v3.Unmarshal([]byte(d.Raw), &object)
+		return d.Raw, object.ApiVersion
+	}
+	return "", ""
+}
+
+// liveArgs are the kubectl arguments to fetch the resource from
+// the cluster. The group and version are included when known so
+// custom resources with common kinds resolve to the right type
+func liveArgs(msg components.ResourceDiffMsg, apiVersion string) []string {
+	resource := msg.Kind
+	if group, version, ok := strings.Cut(apiVersion, "/"); ok {
+		resource = strings.Join([]string{msg.Kind, version, group}, ".")
+	}
+	args := []string{"get", resource, msg.Name, "-o", "yaml", "--ignore-not-found"}
+	if msg.Namespace != "" {
+		args = append(args, "-n", msg.Namespace)
+	}
+	return args
+}
@@ -0,0 +1,88 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package flux
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/mproffitt/delorian/pkg/components"
+)
+
+const resourceBuild = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings
+  namespace: apps
+---
+apiVersion: monitoring.coreos.com/v1
+kind: ServiceMonitor
+metadata:
+  name: podinfo
+  namespace: apps
+`
+
+func TestFindDocument(t *testing.T) {
+	msg := components.ResourceDiffMsg{Kind: "ServiceMonitor", Namespace: "apps", Name: "podinfo"}
+	raw, apiVersion := findDocument(resourceBuild, msg)
+	if raw == "" || apiVersion != "monitoring.coreos.com/v1" {
+		t.Fatalf("expected the ServiceMonitor, got %q %q", raw, apiVersion)
+	}
+
+	msg.Namespace = "other"
+	if raw, _ := findDocument(resourceBuild, msg); raw != "" {
+		t.Errorf("expected no match in another namespace, got %q", raw)
+	}
+}
+
+func TestLiveArgs(t *testing.T) {
+	tests := []struct {
+		name       string
+		msg        components.ResourceDiffMsg
+		apiVersion string
+		expect     []string
+	}{
+		{
+			name:       "core",
+			msg:        components.ResourceDiffMsg{Kind: "ConfigMap", Namespace: "apps", Name: "settings"},
+			apiVersion: "v1",
+			expect:     []string{"get", "ConfigMap", "settings", "-o", "yaml", "--ignore-not-found", "-n", "apps"},
+		},
+		{
+			name:       "grouped",
+			msg:        components.ResourceDiffMsg{Kind: "ServiceMonitor", Namespace: "apps", Name: "podinfo"},
+			apiVersion: "monitoring.coreos.com/v1",
+			expect: []string{"get", "ServiceMonitor.v1.monitoring.coreos.com", "podinfo",
+				"-o", "yaml", "--ignore-not-found", "-n", "apps"},
+		},
+		{
+			name:   "cluster scoped",
+			msg:    components.ResourceDiffMsg{Kind: "Namespace", Name: "apps"},
+			expect: []string{"get", "Namespace", "apps", "-o", "yaml", "--ignore-not-found"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := liveArgs(tt.msg, tt.apiVersion); !slices.Equal(got, tt.expect) {
+				t.Errorf("expected %v, got %v", tt.expect, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,185 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+package flux
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mproffitt/bmx/pkg/components/toast"
+	"github.com/mproffitt/delorian/pkg/components"
+	"github.com/mproffitt/delorian/pkg/components/picker"
+	"github.com/mproffitt/delorian/pkg/theme"
+)
+
+// ScanTimeFormat is the format of the last scan time shown in
+// the status bar
+const ScanTimeFormat = "15:04:05"
+
+// ScanWarnAfter is how long a scan runs before warning that it is
+// taking longer than expected. Zero never warns
+var ScanWarnAfter = 30 * time.Second
+
+// ScanLimit is how long a scan runs before offering to stop it and
+// show what was found so far. Zero never offers
+var ScanLimit = 5 * time.Minute
+
+// scan is a walk of the repository roots running in the background
+type scan struct {
+	cancel  context.CancelFunc
+	visited *atomic.Int64
+	tag     int
+}
+
+// ScanDoneMsg carries what a scan found back to the model
+type ScanDoneMsg struct {
+	tag   int
+	found *Model
+	err   error
+}
+
+// ScanCheckMsg is sent while a scan runs, first to warn that it is
+// slow and then to offer to stop it once it passes ScanLimit
+type ScanCheckMsg struct {
+	tag   int
+	limit bool
+}
+
+// ScanStopMsg stops the scan in progress, keeping what was found
+type ScanStopMsg struct {
+	tag int
+}
+
+// scanCmd walks the repository roots in the background. A scan
+// already running is abandoned in favour of the new one
+func (m *Model) scanCmd() tea.Cmd {
+	if m.scan.cancel != nil {
+		m.scan.cancel()
+	}
+	if m.cache == nil && !NoCache {
+		m.cache = loadCache(m.roots)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	visited := &atomic.Int64{}
+	m.scan.tag++
+	m.scan.cancel, m.scan.visited = cancel, visited
+	tag := m.scan.tag
+	return tea.Batch(
+		func() tea.Msg {
+			found, err := m.find(ctx, visited)
+			return ScanDoneMsg{tag: tag, found: found, err: err}
+		},
+		scanCheckCmd(tag, ScanWarnAfter, false),
+		scanCheckCmd(tag, ScanLimit, true),
+		components.ScanStartedCmd(!m.cache.empty()))
+}
+
+// scanCheckCmd sends a ScanCheckMsg after the given time
+func scanCheckCmd(tag int, after time.Duration, limit bool) tea.Cmd {
+	if after <= 0 {
+		return nil
+	}
+	return tea.Tick(after, func(time.Time) tea.Msg {
+		return ScanCheckMsg{tag: tag, limit: limit}
+	})
+}
+
+// scanning reports if the scan with tag is still running
+func (m *Model) scanning(tag int) bool {
+	return m.scan.cancel != nil && m.scan.tag == tag
+}
+
+// scanDone uses what the scan found unless a newer scan replaced it
+func (m *Model) scanDone(msg ScanDoneMsg) tea.Cmd {
+	if !m.scanning(msg.tag) {
+		return nil
+	}
+	m.scan.cancel()
+	m.scan.cancel = nil
+	return m.scanned(msg.found, msg.err)
+}
+
+// scanCheck warns that the scan is slow, or offers to stop it when
+// it has passed ScanLimit
+func (m *Model) scanCheck(msg ScanCheckMsg) tea.Cmd {
+	if !m.scanning(msg.tag) {
+		return nil
+	}
+	visited := m.scan.visited.Load()
+	if !msg.limit {
+		return tea.Batch(
+			toast.NewToastCmd(toast.Warning, fmt.Sprintf(
+				"Scan is taking longer than expected (%d files so far)", visited)),
+			components.StatusCmd("scan", fmt.Sprintf(theme.Glyph("⟳ scanning… %d files"), visited)))
+	}
+	tag := msg.tag
+	items := []picker.Item{
+		{Label: "Keep scanning", Value: "continue"},
+		{Label: "Stop and show what was found", Value: "stop"},
+	}
+	title := fmt.Sprintf("Scan still running after %s (%d files)", ScanLimit, visited)
+	return components.OpenDialogCmd(picker.New(title, items, func(item picker.Item) tea.Cmd {
+		if item.Value == "stop" {
+			return func() tea.Msg { return ScanStopMsg{tag: tag} }
+		}
+		return scanCheckCmd(tag, ScanLimit, true)
+	}))
+}
+
+// stopScan ends the walk early. What was found is still linked and
+// shown once the walk returns
+func (m *Model) stopScan(msg ScanStopMsg) {
+	if m.scanning(msg.tag) {
+		m.scan.cancel()
+	}
+}
+
+// ScannedAt returns the time the repository was last scanned
+func (m *Model) ScannedAt() time.Time {
+	return m.scannedAt
+}
+
+// scannedStatusCmd shows the time of the last scan in the
+// status bar, and how many files were loaded from the cache
+func (m *Model) scannedStatusCmd() tea.Cmd {
+	status := "scanned " + m.scannedAt.Format(ScanTimeFormat)
+	if m.cached > 0 {
+		status = fmt.Sprintf(theme.Glyph("%s · %d files from cache"), status, m.cached)
+	}
+	return components.StatusCmd("scan", status)
+}
+
+// ScanningStatusCmd shows that a scan is in progress. It should
+// be sequenced before a RescanMsg so the status is visible while
+// the scan runs
+func ScanningStatusCmd() tea.Cmd {
+	return components.StatusCmd("scan", theme.Glyph("⟳ scanning…"))
+}
+
+// rescan walks the repository roots again. What the previous scan
+// found is shown until the new one completes
+func (m *Model) rescan() tea.Cmd {
+	return tea.Batch(m.Init(),
+		components.StatusCmd("overrides", ""),
+		components.StatusCmd("edits", ""),
+		components.StatusCmd("health", ""))
+}
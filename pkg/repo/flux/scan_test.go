@@ -0,0 +1,194 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package flux
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mproffitt/delorian/pkg/components"
+	"github.com/mproffitt/delorian/pkg/testutil"
+)
+
+func TestScanInBackground(t *testing.T) {
+	testutil.Setup()
+	warn, limit := ScanWarnAfter, ScanLimit
+	ScanWarnAfter, ScanLimit = 0, 0
+	defer func() { ScanWarnAfter, ScanLimit = warn, limit }()
+
+	root := t.TempDir()
+	testutil.WriteFile(t, root, filepath.Join("clusters", "apps"+".yaml"), strings.ReplaceAll(multiDoc, "apps", "apps"))
+
+	m := New(root)
+	done := scanDone(t, m.Init())
+	if len(m.kustomizations) != 0 {
+		t.Fatalf("expected nothing to change until the scan is done")
+	}
+	if m.scanCheck(ScanCheckMsg{tag: done.tag}) == nil {
+		t.Errorf("expected a warning while the scan runs")
+	}
+	m.Update(done)
+	if len(m.kustomizations) != 1 {
+		t.Fatalf("expected 1 kustomization, got %d", len(m.kustomizations))
+	}
+	if m.scanCheck(ScanCheckMsg{tag: done.tag}) != nil {
+		t.Errorf("expected no warning once the scan is done")
+	}
+
+	// A scan replaced by a newer one is ignored
+	testutil.WriteFile(t, root, filepath.Join("clusters", "infra"+".yaml"), strings.ReplaceAll(multiDoc, "apps", "infra"))
+	first, second := m.scanCmd(), m.scanCmd()
+	m.Update(scanDone(t, first))
+	if len(m.kustomizations) != 1 {
+		t.Errorf("expected the abandoned scan to be ignored, got %d", len(m.kustomizations))
+	}
+	m.Update(scanDone(t, second))
+	if len(m.kustomizations) != 2 {
+		t.Errorf("expected 2 kustomizations from the newer scan, got %d", len(m.kustomizations))
+	}
+
+	// Stopping the scan before it finds anything is fatal
+	stopped := m.scanCmd()
+	m.Update(ScanStopMsg{tag: m.scan.tag})
+	_, cmd := m.Update(scanDone(t, stopped))
+	if msg, ok := cmd().(components.ModelFatalMsg); !ok || !strings.Contains(msg.Error.Error(), "stopped") {
+		t.Errorf("expected the stopped scan to be reported, got %#v", cmd())
+	}
+}
+
+// messages runs cmd and every command batched within it, returning
+// the messages they produce
+func messages(cmd tea.Cmd) []tea.Msg {
+	if cmd == nil {
+		return nil
+	}
+	msg := cmd()
+	if batch, ok := msg.(tea.BatchMsg); ok {
+		var msgs []tea.Msg
+		for _, c := range batch {
+			msgs = append(msgs, messages(c)...)
+		}
+		return msgs
+	}
+	return []tea.Msg{msg}
+}
+
+// scanDone runs the scan started by cmd, returning its result
+func scanDone(t *testing.T, cmd tea.Cmd) ScanDoneMsg {
+	t.Helper()
+	for _, msg := range messages(cmd) {
+		if msg, ok := msg.(ScanDoneMsg); ok {
+			return msg
+		}
+	}
+	t.Fatal("expected the scan to run as a command")
+	return ScanDoneMsg{}
+}
+
+// scanStarted returns the ScanStartedMsg sent by cmd
+func scanStarted(t *testing.T, cmd tea.Cmd) components.ScanStartedMsg {
+	t.Helper()
+	for _, msg := range messages(cmd) {
+		if msg, ok := msg.(components.ScanStartedMsg); ok {
+			return msg
+		}
+	}
+	t.Fatal("expected the scan to announce that it started")
+	return components.ScanStartedMsg{}
+}
+
+func TestScanStarted(t *testing.T) {
+	testutil.Setup()
+	warn, limit := ScanWarnAfter, ScanLimit
+	ScanWarnAfter, ScanLimit = 0, 0
+	NoCache = false
+	t.Cleanup(func() {
+		ScanWarnAfter, ScanLimit = warn, limit
+		NoCache = true
+	})
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+
+	root := t.TempDir()
+	testutil.WriteFile(t, root, filepath.Join("clusters", "apps.yaml"), multiDoc)
+	if scanStarted(t, New(root).scanCmd()).FromCache {
+		t.Error("expected the first run to scan without a cache")
+	}
+
+	m := New(root)
+	m.walk()
+	if !scanStarted(t, New(root).scanCmd()).FromCache {
+		t.Error("expected the next run to load from the cache")
+	}
+}
+
+func TestRescanCmd(t *testing.T) {
+	testutil.Setup()
+	warn, limit := ScanWarnAfter, ScanLimit
+	ScanWarnAfter, ScanLimit = 0, 0
+	NoCache = false
+	t.Cleanup(func() {
+		ScanWarnAfter, ScanLimit = warn, limit
+		NoCache = true
+	})
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+
+	root := t.TempDir()
+	testutil.WriteFile(t, root, filepath.Join("clusters", "apps.yaml"), multiDoc)
+	m := New(root)
+	m.walk()
+	if len(m.kustomizations) != 1 {
+		t.Fatalf("expected 1 kustomization, got %d", len(m.kustomizations))
+	}
+
+	// A scan abandoned for a rescan after its walk finished leaves
+	// the cache alone
+	testutil.WriteFile(t, root, filepath.Join("clusters", "infra.yaml"),
+		strings.ReplaceAll(multiDoc, "apps", "infra"))
+	abandoned := scanDone(t, m.scanCmd())
+	_, cmd := m.Update(components.RescanMsg{})
+	m.Update(abandoned)
+	if len(m.cache.seen) != 0 || m.cache.hits != 0 {
+		t.Errorf("expected the abandoned scan not to write to the cache, got %d seen", len(m.cache.seen))
+	}
+	if len(m.kustomizations) != 1 {
+		t.Errorf("expected the abandoned scan to be ignored, got %d", len(m.kustomizations))
+	}
+
+	var done bool
+	for _, msg := range messages(cmd) {
+		if msg, ok := msg.(ScanDoneMsg); ok {
+			m.Update(msg)
+			done = true
+		}
+	}
+	if !done {
+		t.Fatal("expected the rescan to finish with a ScanDoneMsg")
+	}
+	if len(m.kustomizations) != 2 {
+		t.Errorf("expected 2 kustomizations after the rescan, got %d", len(m.kustomizations))
+	}
+	if m.cached != 1 {
+		t.Errorf("expected the unchanged file to come from the cache, got %d", m.cached)
+	}
+}
@@ -0,0 +1,118 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package flux
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mproffitt/delorian/pkg/components"
+	"github.com/mproffitt/delorian/pkg/components/treeview"
+)
+
+// SelectCluster moves the focus to the cluster tree so a cluster
+// can be picked to limit the list to
+var SelectCluster = key.NewBinding(key.WithKeys("c"),
+	key.WithHelp("c", "Select cluster in tree"))
+
+// clusterTree returns the treeview if it has been created
+func (m *Model) clusterTree() (*treeview.Model, bool) {
+	tv, ok := m.treeview.(*treeview.Model)
+	return tv, ok
+}
+
+// treeFocused reports if keys are being sent to the cluster tree
+func (m *Model) treeFocused() bool {
+	tv, ok := m.clusterTree()
+	return ok && tv.Focused()
+}
+
+// toggleTree moves the focus between the list and the cluster tree
+func (m *Model) toggleTree() {
+	tv, ok := m.clusterTree()
+	if !ok {
+		return
+	}
+	if tv.Focused() {
+		tv.Blur()
+		m.list.SetDelegate(m.delegates.normal)
+		return
+	}
+	tv.Focus()
+	m.list.SetDelegate(m.delegates.shaded)
+}
+
+// scopeTo limits the list to the kustomizations inside the
+// cluster at path. Selecting the same cluster again shows
+// every cluster
+func (m *Model) scopeTo(path []string) tea.Cmd {
+	if slices.Equal(path, m.clusterScope) {
+		path = nil
+	}
+	m.clusterScope = path
+	if m.treeFocused() {
+		m.toggleTree()
+	}
+
+	status := ""
+	if len(path) > 0 {
+		status = "cluster " + strings.Join(path, "/")
+	}
+	_, cmd := m.Update(ModelReadyMsg{Ready: true})
+	return tea.Batch(cmd, components.StatusCmd("cluster", status))
+}
+
+// scopedCluster returns the cluster the list is limited to. Nil
+// is returned when there is no scope or the cluster no longer
+// exists
+func (m *Model) scopedCluster() *cluster {
+	if len(m.clusterScope) == 0 {
+		return nil
+	}
+	clusters := m.groupClustersByRoot()
+	var found *cluster
+	for _, name := range m.clusterScope {
+		found = nil
+		for _, c := range clusters {
+			if c.name == name {
+				found = c
+				break
+			}
+		}
+		if found == nil {
+			return nil
+		}
+		clusters = found.children
+	}
+	return found
+}
+
+// holds reports if path is inside the directory of the cluster or
+// one of its children. A nil cluster holds every path
+func (c *cluster) holds(path string) bool {
+	if c == nil {
+		return true
+	}
+	return slices.ContainsFunc(c.dirs(), func(dir string) bool {
+		return contains(dir, path)
+	})
+}
@@ -0,0 +1,79 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package flux
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/mproffitt/delorian/pkg/testutil"
+)
+
+func TestClusterScope(t *testing.T) {
+	testutil.Setup()
+	prod := &cluster{name: "prod", filepath: "/repo/clusters/hub/prod", root: "/repo"}
+	hub := &cluster{
+		name:     "hub",
+		filepath: "/repo/clusters/hub",
+		root:     "/repo",
+		children: []*cluster{prod},
+	}
+	dev := &cluster{name: "dev", filepath: "/repo/clusters/dev", root: "/repo"}
+	m := New("/repo")
+	m.clusters = []*cluster{hub, dev}
+	for _, path := range []string{"clusters/hub/flux.yaml", "clusters/hub/prod/apps.yaml", "clusters/dev/apps.yaml"} {
+		m.kustomizations = append(m.kustomizations, shortApi{
+			Metadata: shortMeta{Name: path},
+			filepath: path,
+			root:     "/repo",
+			ftype:    Complete,
+		})
+	}
+	names := func() []string {
+		var names []string
+		for _, item := range m.Items() {
+			names = append(names, item.(*shortApi).GetName())
+		}
+		return names
+	}
+
+	cases := []struct {
+		scope []string
+		want  []string
+	}{
+		{nil, []string{"clusters/hub/flux.yaml", "clusters/hub/prod/apps.yaml", "clusters/dev/apps.yaml"}},
+		{[]string{"hub"}, []string{"clusters/hub/flux.yaml", "clusters/hub/prod/apps.yaml"}},
+		{[]string{"hub", "prod"}, []string{"clusters/hub/prod/apps.yaml"}},
+		{[]string{"dev"}, []string{"clusters/dev/apps.yaml"}},
+		{[]string{"missing"}, []string{"clusters/hub/flux.yaml", "clusters/hub/prod/apps.yaml", "clusters/dev/apps.yaml"}},
+	}
+	for _, tc := range cases {
+		m.clusterScope = tc.scope
+		if got := names(); !slices.Equal(got, tc.want) {
+			t.Errorf("%v: expected %v, got %v", tc.scope, tc.want, got)
+		}
+	}
+
+	m.clusterScope = []string{"dev"}
+	m.scopeTo([]string{"dev"})
+	if m.clusterScope != nil {
+		t.Errorf("expected selecting the scoped cluster again to clear it, got %v", m.clusterScope)
+	}
+}
@@ -0,0 +1,97 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package flux
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mproffitt/bmx/pkg/components/toast"
+)
+
+var (
+	// OpenSource opens the repository of the source of the
+	// selected kustomization in the browser
+	OpenSource = key.NewBinding(key.WithKeys("o"),
+		key.WithHelp("o", "Open source repository"))
+
+	// CopySourceURL copies the repository URL of the source of
+	// the selected kustomization to the clipboard
+	CopySourceURL = key.NewBinding(key.WithKeys("O"),
+		key.WithHelp("O", "Copy source repository URL"))
+)
+
+// browseURL converts the URL of a source into one a browser can
+// open. SSH and scp style git URLs and OCI references are served
+// over https by every common host
+func browseURL(raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return "", fmt.Errorf("no url")
+	}
+
+	// scp style git@host:org/repo has no scheme
+	if !strings.Contains(raw, "://") {
+		userHost, path, ok := strings.Cut(raw, ":")
+		if !ok {
+			return "", fmt.Errorf("unrecognised url %q", raw)
+		}
+		_, host, found := strings.Cut(userHost, "@")
+		if !found {
+			host = userHost
+		}
+		raw = "https://" + host + "/" + strings.TrimPrefix(path, "/")
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", err
+	}
+	switch u.Scheme {
+	case "http", "https":
+	case "ssh", "git", "oci":
+		u.Scheme = "https"
+		u.User = nil
+		u.Host = u.Hostname()
+	default:
+		return "", fmt.Errorf("unsupported scheme %q", u.Scheme)
+	}
+	u.Path = strings.TrimSuffix(u.Path, ".git")
+	return u.String(), nil
+}
+
+// sourceURLCmd passes the browsable URL of the source of k to
+// action, or warns when there isn't one
+func sourceURLCmd(k *shortApi, action func(string) tea.Cmd) tea.Cmd {
+	source := k.GetSource()
+	if source == nil {
+		return toast.NewToastCmd(toast.Warning,
+			fmt.Sprintf("The source of %s is not in the scanned repository", k.GetName()))
+	}
+	u, err := browseURL(source.url)
+	if err != nil {
+		return toast.NewToastCmd(toast.Warning,
+			fmt.Sprintf("%s %s has %s", source.Kind, source.GetName(), err))
+	}
+	return action(u)
+}
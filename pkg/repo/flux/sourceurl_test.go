@@ -0,0 +1,70 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package flux
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestBrowseURL(t *testing.T) {
+	tests := []struct {
+		raw, expect string
+	}{
+		{"https://github.com/example/fleet", "https://github.com/example/fleet"},
+		{"https://github.com/example/fleet.git", "https://github.com/example/fleet"},
+		{"ssh://git@github.com/example/fleet", "https://github.com/example/fleet"},
+		{"ssh://git@gitlab.example.com:2222/example/fleet.git", "https://gitlab.example.com/example/fleet"},
+		{"git@github.com:example/fleet.git", "https://github.com/example/fleet"},
+		{"oci://ghcr.io/example/manifests", "https://ghcr.io/example/manifests"},
+	}
+	for _, tt := range tests {
+		got, err := browseURL(tt.raw)
+		if err != nil || got != tt.expect {
+			t.Errorf("%s: expected %s, got %s (%v)", tt.raw, tt.expect, got, err)
+		}
+	}
+
+	for _, raw := range []string{"", "s3://bucket/path", "no-scheme"} {
+		if _, err := browseURL(raw); err == nil {
+			t.Errorf("%q: expected an error", raw)
+		}
+	}
+}
+
+func TestSourceURL(t *testing.T) {
+	doc := multiDoc + "spec:\n  url: ssh://git@github.com/example/fleet.git\n"
+	m := &Model{}
+	m.kustomizations, m.sources, _, _ = parseYaml([]byte(doc), "/repo", "/repo/clusters/apps.yaml")
+	if len(m.sources) != 1 || m.sources[0].url == "" {
+		t.Fatalf("expected the source url to be captured, got %+v", m.sources)
+	}
+	m.setSource(0)
+
+	var opened string
+	sourceURLCmd(&m.kustomizations[0], func(url string) tea.Cmd {
+		opened = url
+		return nil
+	})
+	if opened != "https://github.com/example/fleet" {
+		t.Errorf("expected the source to be opened, got %q", opened)
+	}
+}
@@ -0,0 +1,75 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package flux
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/mproffitt/delorian/pkg/components"
+)
+
+// specPathExists reports if the spec path of this kustomization
+// can be found in the scanned repository. Paths which still
+// contain substitutions are assumed to exist
+func (s *shortApi) specPathExists() bool {
+	if s.Spec.Path == nil || strings.Contains(s.GetAbsoluteSpecPath(), "${") {
+		return true
+	}
+	_, err := os.Stat(s.GetAbsoluteSpecPath())
+	return err == nil
+}
+
+// sourceDescription names the source the spec path belongs to
+func (s *shortApi) sourceDescription() string {
+	if s.Spec.Source == nil {
+		return "its source"
+	}
+	return fmt.Sprintf("%s %s/%s", s.Spec.Source.Kind,
+		s.GetSourceNamespace(), s.GetSourceName())
+}
+
+// missingSpecPathError explains that the kustomization cannot be
+// built because its spec path is not in the scanned repository,
+// which usually means it belongs to a different source
+func (s *shortApi) missingSpecPathError() error {
+	return &components.FluxError{
+		Summary: fmt.Sprintf("source for %s is not available in the scanned repository",
+			s.GetName()),
+		Hint: fmt.Sprintf("spec.path %s resolves to %s which does not exist. "+
+			"It may belong to %s, which is not checked out here",
+			*s.Spec.Path, s.GetAbsoluteSpecPath(), s.sourceDescription()),
+	}
+}
+
+// validateSpecPath reports a spec path which cannot be found in
+// the scanned repository
+func validateSpecPath(k *shortApi) []components.HealthIssue {
+	// An undefined source is already reported by validateSource
+	if k.specPathExists() || (k.Spec.Source != nil && k.source == nil) {
+		return nil
+	}
+	return []components.HealthIssue{
+		k.issue(components.SeverityWarning, fmt.Sprintf(
+			"spec.path %s does not exist in the scanned repository, "+
+				"it may belong to %s", *k.Spec.Path, k.sourceDescription())),
+	}
+}
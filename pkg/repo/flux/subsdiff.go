@@ -0,0 +1,135 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package flux
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mproffitt/bmx/pkg/components/dialog"
+	"github.com/mproffitt/bmx/pkg/components/toast"
+	"github.com/mproffitt/delorian/pkg/components"
+	"github.com/mproffitt/delorian/pkg/components/diffview"
+)
+
+// CompareSubstitutions shows the substitutions the selected
+// kustomization receives and how its own differ from its parent
+// and siblings
+var CompareSubstitutions = key.NewBinding(key.WithKeys("S"),
+	key.WithHelp("S", "Compare substitutions"))
+
+// substitutionsWidth is the width of the comparison dialog
+const substitutionsWidth = 72
+
+// substitutionDiff describes the substitutions of k as diff
+// entries. The first lists the values flux substitutes into k from
+// its parent, the rest compare the values k sets for its own
+// output with those of its parent and each sibling
+func substitutionDiff(k *shortApi) []diffview.DiffEntry {
+	entries := make([]diffview.DiffEntry, 0)
+	own := k.substitutions()
+	if k.parent != nil {
+		inherited := k.parent.substitutions()
+		entry := diffview.DiffEntry{Title: fmt.Sprintf("Substituted into %s by %s",
+			k.GetName(), k.parent.GetName())}
+		for _, name := range slices.Sorted(maps.Keys(inherited)) {
+			entry.Changes = append(entry.Changes, diffview.DiffChange{Key: name, Title: inherited[name]})
+		}
+		entries = append(entries, entry)
+		entries = append(entries, compareSubstitutions(k.GetName(), own, k.parent.GetName(), inherited))
+
+		for _, sibling := range k.parent.children {
+			if sibling == k {
+				continue
+			}
+			entries = append(entries, compareSubstitutions(k.GetName(), own,
+				sibling.GetName(), sibling.substitutions()))
+		}
+	}
+	if len(entries) == 0 && len(own) > 0 {
+		entry := diffview.DiffEntry{Title: "Substitutions of " + k.GetName()}
+		for _, name := range slices.Sorted(maps.Keys(own)) {
+			entry.Changes = append(entry.Changes, diffview.DiffChange{Key: name, Title: own[name]})
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// compareSubstitutions lists the variables whose values differ
+// between name and other. Values of name are shown as additions
+// and those of other as deletions
+func compareSubstitutions(name string, values map[string]string,
+	other string, otherValues map[string]string) diffview.DiffEntry {
+	entry := diffview.DiffEntry{Title: fmt.Sprintf("%s compared with %s", name, other)}
+	variables := slices.Sorted(maps.Keys(values))
+	for v := range maps.Keys(otherValues) {
+		if _, ok := values[v]; !ok {
+			variables = append(variables, v)
+		}
+	}
+	slices.Sort(variables)
+
+	for _, v := range variables {
+		value, ok := values[v]
+		otherValue, otherOk := otherValues[v]
+		change := diffview.DiffChange{Key: v}
+		set := diffview.ChangeSet{}
+		switch {
+		case ok && otherOk && value == otherValue:
+			continue
+		case !otherOk:
+			change.Title = "only set by " + name
+			set.Addition = []string{value}
+		case !ok:
+			change.Title = "only set by " + other
+			set.Deletion = []string{otherValue}
+		default:
+			change.Title = "different"
+			set.Addition = []string{value}
+			set.Deletion = []string{otherValue}
+		}
+		change.Changes = []diffview.ChangeSet{set}
+		entry.Changes = append(entry.Changes, change)
+	}
+	if len(entry.Changes) == 0 {
+		entry.Title += " (same values)"
+	}
+	return entry
+}
+
+// compareSubstitutionsCmd opens the comparison for k
+func compareSubstitutionsCmd(k *shortApi) tea.Cmd {
+	entries := substitutionDiff(k)
+	if len(entries) == 0 {
+		return toast.NewToastCmd(toast.Info, "No substitutions to compare for "+k.GetName())
+	}
+	views := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		views = append(views, entry.View(substitutionsWidth))
+	}
+	content := strings.TrimRight(lipgloss.JoinVertical(lipgloss.Left, views...), "\n")
+	return components.OpenDialogCmd(dialog.NewOKDialog(content, substitutionsWidth))
+}
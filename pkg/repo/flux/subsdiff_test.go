@@ -0,0 +1,71 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package flux
+
+import (
+	"testing"
+
+	"github.com/mproffitt/delorian/pkg/components/diffview"
+)
+
+func TestSubstitutionDiff(t *testing.T) {
+	withSubs := func(name string, subs map[string]string) *shortApi {
+		return &shortApi{Metadata: shortMeta{Name: name},
+			Spec: shortSpec{PostBuild: &postBuild{Substitute: subs}}}
+	}
+	parent := withSubs("clusters", map[string]string{"cluster": "prod", "region": "eu"})
+	apps := withSubs("apps", map[string]string{"cluster": "prod", "region": "us", "tier": "web"})
+	infra := withSubs("infra", map[string]string{"cluster": "prod", "region": "us", "tier": "web"})
+	parent.children = []*shortApi{apps, infra}
+	apps.parent, infra.parent = parent, parent
+
+	entries := substitutionDiff(apps)
+	if len(entries) != 3 {
+		t.Fatalf("expected the inherited values and two comparisons, got %d", len(entries))
+	}
+	if e := entries[0]; e.Title != "Substituted into apps by clusters" || len(e.Changes) != 2 ||
+		e.Changes[1].Key != "region" || e.Changes[1].Title != "eu" {
+		t.Errorf("unexpected inherited values %+v", e)
+	}
+
+	want := []diffview.DiffChange{
+		{Key: "region", Title: "different",
+			Changes: []diffview.ChangeSet{{Addition: []string{"us"}, Deletion: []string{"eu"}}}},
+		{Key: "tier", Title: "only set by apps",
+			Changes: []diffview.ChangeSet{{Addition: []string{"web"}}}},
+	}
+	if e := entries[1]; e.Title != "apps compared with clusters" || len(e.Changes) != len(want) {
+		t.Fatalf("unexpected parent comparison %+v", e)
+	}
+	for i, change := range entries[1].Changes {
+		set, w := change.Changes[0], want[i].Changes[0]
+		if change.Key != want[i].Key || change.Title != want[i].Title ||
+			len(set.Addition) != len(w.Addition) || len(set.Deletion) != len(w.Deletion) {
+			t.Errorf("expected %+v, got %+v", want[i], change)
+		}
+	}
+	if e := entries[2]; e.Title != "apps compared with infra (same values)" || len(e.Changes) != 0 {
+		t.Errorf("expected matching siblings to be noted, got %+v", e)
+	}
+
+	if entries := substitutionDiff(&shortApi{}); len(entries) != 0 {
+		t.Errorf("expected nothing to compare, got %+v", entries)
+	}
+}
@@ -0,0 +1,108 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package flux
+
+import (
+	"encoding/base64"
+	"maps"
+
+	"github.com/charmbracelet/log"
+)
+
+// substituteRef names a ConfigMap or Secret whose data is used
+// for postBuild substitutions
+type substituteRef struct {
+	Kind     string `yaml:"kind"`
+	Name     string `yaml:"name"`
+	Optional bool   `yaml:"optional,omitempty"`
+}
+
+// shortConfig is a ConfigMap or Secret found in the repository
+type shortConfig struct {
+	shortMeta
+	Kind string
+
+	data     map[string]string
+	filepath string
+	root     string
+}
+
+// newConfig reads the data of a ConfigMap or Secret. Secret data
+// is base64 encoded and values which cannot be decoded, such as
+// those encrypted with sops, are left out
+func newConfig(doc shortApi, root, path string) shortConfig {
+	data := make(map[string]string, len(doc.Data)+len(doc.StringData))
+	for k, v := range doc.Data {
+		if doc.Kind != "Secret" {
+			data[k] = v
+			continue
+		}
+		decoded, err := base64.StdEncoding.DecodeString(v)
+		if err != nil {
+			log.Debug("skipping secret value", "secret", doc.Metadata.Name, "key", k, "error", err)
+			continue
+		}
+		data[k] = string(decoded)
+	}
+	maps.Copy(data, doc.StringData)
+	return shortConfig{
+		shortMeta: doc.Metadata,
+		Kind:      doc.Kind,
+		data:      data,
+		filepath:  path,
+		root:      root,
+	}
+}
+
+// GetNamespace gets the namespace for the config
+func (c *shortConfig) GetNamespace() string {
+	if c.Namespace == nil {
+		return ""
+	}
+	return *c.Namespace
+}
+
+// substituteFrom merges the data of the ConfigMaps and Secrets the
+// kustomization substitutes from, in the order they are listed.
+// Those which cannot be found in the same repository are assumed
+// to live in the cluster and are skipped
+func (m *Model) substituteFrom(k *shortApi) map[string]string {
+	if k.Spec.PostBuild == nil || len(k.Spec.PostBuild.SubstituteFrom) == 0 {
+		return nil
+	}
+	subs := make(map[string]string)
+	for _, ref := range k.Spec.PostBuild.SubstituteFrom {
+		found := false
+		for i := range m.configs {
+			c := &m.configs[i]
+			if c.root == k.root && c.Kind == ref.Kind && c.Name == ref.Name &&
+				c.GetNamespace() == k.GetNamespace() {
+				maps.Copy(subs, c.data)
+				found = true
+				break
+			}
+		}
+		if !found {
+			log.Debug("substituteFrom not found in repository", "kustomization", k.GetName(),
+				"kind", ref.Kind, "name", ref.Name, "namespace", k.GetNamespace())
+		}
+	}
+	return subs
+}
@@ -0,0 +1,99 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package flux
+
+import (
+	"maps"
+	"testing"
+)
+
+const substituteFromDocs = `apiVersion: kustomize.toolkit.fluxcd.io/v1
+kind: Kustomization
+metadata:
+  name: apps
+  namespace: flux-system
+spec:
+  path: ./apps/${cluster_name}
+  postBuild:
+    substitute:
+      region: eu-west-1
+    substituteFrom:
+      - kind: ConfigMap
+        name: cluster-vars
+      - kind: Secret
+        name: cluster-secrets
+      - kind: ConfigMap
+        name: in-cluster-only
+        optional: true
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cluster-vars
+  namespace: flux-system
+data:
+  cluster_name: prod
+  region: us-east-1
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: cluster-secrets
+  namespace: flux-system
+data:
+  token: c2VjcmV0
+  sops: ENC[AES256_GCM,data:abc]
+stringData:
+  cluster_name: production
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cluster-vars
+  namespace: other
+data:
+  cluster_name: wrong
+`
+
+func TestSubstituteFrom(t *testing.T) {
+	m := New("/repo")
+	m.kustomizations, m.sources, _, m.configs = parseYaml([]byte(substituteFromDocs), "/repo", "/repo/clusters/apps.yaml")
+	if len(m.configs) != 3 {
+		t.Fatalf("expected 3 configs, got %d", len(m.configs))
+	}
+
+	k := &m.kustomizations[0]
+	k.substituteFrom = m.substituteFrom(k)
+	k.overrides = map[string]string{"token": "override"}
+
+	expected := map[string]string{
+		// the secret is listed after the config map so wins
+		"cluster_name": "production",
+		// inline substitutions take precedence
+		"region": "eu-west-1",
+		"token":  "override",
+	}
+	if got := k.substitutions(); !maps.Equal(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+	if got := k.defaultSubstitutions()["token"]; got != "secret" {
+		t.Errorf("expected the decoded secret value, got %q", got)
+	}
+}
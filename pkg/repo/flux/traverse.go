@@ -34,18 +34,26 @@ package flux
 import (
 	"bytes"
 	"cmp"
+	"context"
+	"errors"
 	"fmt"
 	"io/fs"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/charlievieth/fastwalk"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/log"
-	"github.com/google/uuid"
+	"github.com/mproffitt/bmx/pkg/components/toast"
 	"github.com/mproffitt/delorian/pkg/components"
+	"github.com/mproffitt/delorian/pkg/components/treeview"
 	"github.com/mproffitt/delorian/pkg/kustomize"
+	"github.com/mproffitt/delorian/pkg/theme"
 	"golang.org/x/exp/slices"
 	yaml "gopkg.in/yaml.v3"
 )
@@ -53,47 +61,180 @@ import (
 const (
 	kustomizationApi = "kustomize.toolkit.fluxcd.io"
 	sourceApi        = "source.toolkit.fluxcd.io"
+	helmApi          = "helm.toolkit.fluxcd.io"
+
+	ociRepository = "OCIRepository"
+	gitRepository = "GitRepository"
+	bucket        = "Bucket"
 )
 
+// parsed holds what was found in a single yaml file
+type parsed struct {
+	kustomizations []shortApi
+	sources        []shortSource
+	releases       []shortRelease
+	configs        []shortConfig
+}
+
+// parseJob is a yaml file waiting to be parsed
+type parseJob struct {
+	root string
+	path string
+	fi   fs.FileInfo
+}
+
+// parsePool parses the files found by the walk on a bounded number
+// of workers. Results are appended to the model by a single
+// collector so the model is only locked while draining them
+type parsePool struct {
+	jobs      chan parseJob
+	results   chan parsed
+	workers   sync.WaitGroup
+	collected chan struct{}
+}
+
+// newParsePool starts one worker for each processor available
+func (m *Model) newParsePool() *parsePool {
+	p := parsePool{
+		jobs:      make(chan parseJob),
+		results:   make(chan parsed),
+		collected: make(chan struct{}),
+	}
+	for range runtime.GOMAXPROCS(0) {
+		p.workers.Add(1)
+		go func() {
+			defer p.workers.Done()
+			for job := range p.jobs {
+				var r parsed
+				r.kustomizations, r.sources, r.releases, r.configs = m.parseFile(job.root, job.path, job.fi)
+				p.results <- r
+			}
+		}()
+	}
+	go func() {
+		defer close(p.collected)
+		for r := range p.results {
+			m.Lock()
+			m.kustomizations = append(m.kustomizations, r.kustomizations...)
+			m.sources = append(m.sources, r.sources...)
+			m.releases = append(m.releases, r.releases...)
+			m.configs = append(m.configs, r.configs...)
+			m.Unlock()
+		}
+	}()
+	return &p
+}
+
+// parse queues the file at path
+func (p *parsePool) parse(root, path string, fi fs.FileInfo) {
+	p.jobs <- parseJob{root: root, path: path, fi: fi}
+}
+
+// wait blocks until every queued file has been parsed and
+// collected. Nothing may be queued afterwards
+func (p *parsePool) wait() {
+	close(p.jobs)
+	p.workers.Wait()
+	close(p.results)
+	<-p.collected
+}
+
+// walk scans the repository roots and links together what was
+// found, blocking until both are complete
 func (m *Model) walk() tea.Cmd {
+	if m.cache == nil && !NoCache {
+		m.cache = loadCache(m.roots)
+	}
+	return m.scanned(m.find(context.Background(), nil))
+}
+
+// find walks the repository roots and parses every yaml file into
+// a new model. What m holds is left untouched so it can still be
+// shown while the walk runs. The walk stops early when ctx is done
+// and every file and directory visited is counted in visited
+func (m *Model) find(ctx context.Context, visited *atomic.Int64) (*Model, error) {
 	/*
 	 * First, gather every single flux kustomization irrespective of whether
 	 * this is a base or not. It will be filtered later
 	 */
-	rootFn := func(path string, d fs.DirEntry, err error) error {
-		if err != nil {
-			return err
-		}
-		fi, err := os.Stat(path)
-		if err != nil || fi.IsDir() {
-			m.checkClusterPath(path)
+	found := &Model{conf: m.conf, roots: m.roots, cache: m.cache.view()}
+	pool := found.newParsePool()
+	rootFn := func(root string) fs.WalkDirFunc {
+		return func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if visited != nil {
+				visited.Add(1)
+			}
+			fi, err := os.Stat(path)
+			if err != nil || fi.IsDir() {
+				if err == nil {
+					found.record(path, fi)
+				}
+				found.checkClusterPath(root, path)
+				return err
+			}
+
+			filetypes := []string{".yaml", ".yml"}
+			ext := filepath.Ext(d.Name())
+			if !slices.Contains(filetypes, strings.ToLower(ext)) {
+				return nil
+			}
+
+			// Collect any kustomizations or sources stored in this file
+			found.record(path, fi)
+			pool.parse(root, path, fi)
 			return err
 		}
+	}
 
-		filetypes := []string{".yaml", ".yml"}
-		ext := filepath.Ext(d.Name())
-		if !slices.Contains(filetypes, strings.ToLower(ext)) {
-			return nil
+	// Load all kustomizations and sources first from each repo
+	var err error
+	for _, root := range m.roots {
+		if err = fastwalk.Walk(&found.conf, root, rootFn(root)); err != nil {
+			break
 		}
-
-		// Collect any kustomizations or sources stored in this file
-		k, s := parseYamlFromFile(m.root, path)
-		m.Lock()
-		m.kustomizations = append(m.kustomizations, k...)
-		m.sources = append(m.sources, s...)
-		m.Unlock()
-		return err
 	}
+	pool.wait()
+	return found, err
+}
+
+// scanned replaces everything m holds with what find found and
+// links it together. A walk stopped early keeps what was found
+// before it stopped
+func (m *Model) scanned(found *Model, err error) tea.Cmd {
+	m.kustomizations = found.kustomizations
+	m.sources = found.sources
+	m.releases = found.releases
+	m.configs = found.configs
+	m.clusters = found.clusters
+	m.poll.files = found.poll.files
+	m.poll.tag++
 
-	// Load all kustomizations and sources first from the repo
-	if err := fastwalk.Walk(&m.conf, m.root, rootFn); err != nil {
+	stopped := errors.Is(err, context.Canceled)
+	if err != nil && !stopped {
 		return components.ModelErrorCmd(err)
 	}
+	m.sortScanned()
 
 	if len(m.kustomizations) == 0 {
 		err := fmt.Errorf("no kustomizations found\nare you sure this is a flux repository?")
+		if stopped {
+			err = fmt.Errorf("the scan was stopped before any kustomizations were found")
+		}
 		return components.ModelFatalCmd(err)
 	}
+	// A stopped walk hasn't seen every file so its cache would
+	// drop those it missed
+	m.cached = 0
+	if found.cache != nil && !stopped {
+		m.cache = found.cache
+		m.cached = m.cache.finish(m.roots)
+	}
 
 	// Now we have all kustomizations in the repo, we can start to organise them
 	//
@@ -102,7 +243,10 @@ func (m *Model) walk() tea.Cmd {
 	var cmds []tea.Cmd
 	ready := true
 	for i := range m.kustomizations {
+		m.kustomizations[i].multiroot = m.MultiRoot()
+		m.kustomizations[i].live = m.live
 		m.kustomizations[i].children = make([]*shortApi, 0)
+		m.kustomizations[i].substituteFrom = m.substituteFrom(&m.kustomizations[i])
 		err := m.followFluxKustomization(i, &m.kustomizations[i])
 		if err != nil {
 			cmds = append(cmds, components.ModelErrorCmd(err))
@@ -113,6 +257,64 @@ func (m *Model) walk() tea.Cmd {
 
 	m.reparentClusters()
 
+	issues := m.validate()
+	cmds = append(cmds, components.HealthReportCmd(append(issues, m.orphans()...)),
+		components.CadenceReportCmd(m.cadence()))
+	if len(issues) > 0 {
+		cmds = append(cmds, components.StatusCmd("health",
+			fmt.Sprintf(theme.Glyph("⚠ %d health issues"), len(issues))))
+	}
+
+	m.sortKustomizations()
+
+	var clusters []treeview.Tree
+	for _, c := range m.groupClustersByRoot() {
+		clusters = append(clusters, c)
+		log.Debug("Adding cluster", "cluster", c.Name())
+	}
+	m.treeview = treeview.New("clusters", clusters, m.width, m.height)
+
+	m.scannedAt = time.Now()
+	if stopped {
+		cmds = append(cmds, toast.NewToastCmd(toast.Warning,
+			"Scan stopped early, some kustomizations may be missing"))
+	}
+	cmds = append(cmds, m.scannedStatusCmd(), ModelReadyCmd(ready), m.pollCmd())
+	return tea.Batch(cmds...)
+}
+
+// sortScanned orders what was found by file and position in the
+// file. Files are parsed concurrently so arrive in any order
+func (m *Model) sortScanned() {
+	slices.SortStableFunc(m.kustomizations, func(a, b shortApi) int {
+		return cmp.Or(
+			strings.Compare(a.root, b.root),
+			strings.Compare(a.filepath, b.filepath),
+			cmp.Compare(a.document, b.document))
+	})
+	slices.SortStableFunc(m.sources, func(a, b shortSource) int {
+		return strings.Compare(a.filepath, b.filepath)
+	})
+	slices.SortStableFunc(m.releases, func(a, b shortRelease) int {
+		return strings.Compare(a.filepath, b.filepath)
+	})
+	slices.SortStableFunc(m.configs, func(a, b shortConfig) int {
+		return strings.Compare(a.filepath, b.filepath)
+	})
+}
+
+// sortKustomizations orders the kustomizations with those that
+// apply the most children first.
+//
+// Parents, children and sources point into the kustomizations
+// slice so each pointer is moved to wherever its kustomization
+// ends up once sorted
+func (m *Model) sortKustomizations() {
+	before := make(map[*shortApi]string, len(m.kustomizations))
+	for i := range m.kustomizations {
+		before[&m.kustomizations[i]] = m.kustomizations[i].id
+	}
+
 	slices.SortStableFunc(m.kustomizations, func(a, b shortApi) int {
 		if len(a.children) == len(b.children) {
 			return strings.Compare(a.GetName(), b.GetName())
@@ -120,8 +322,29 @@ func (m *Model) walk() tea.Cmd {
 		return cmp.Compare(len(b.children), len(a.children))
 	})
 
-	cmds = append(cmds, ModelReadyCmd(ready))
-	return tea.Batch(cmds...)
+	after := make(map[string]*shortApi, len(m.kustomizations))
+	for i := range m.kustomizations {
+		after[m.kustomizations[i].id] = &m.kustomizations[i]
+	}
+	move := func(k *shortApi) *shortApi {
+		if k == nil {
+			return nil
+		}
+		return after[before[k]]
+	}
+	for i := range m.kustomizations {
+		k := &m.kustomizations[i]
+		k.parent = move(k.parent)
+		for j := range k.children {
+			k.children[j] = move(k.children[j])
+		}
+	}
+	for i := range m.sources {
+		m.sources[i].parent = move(m.sources[i].parent)
+		for j := range m.sources[i].children {
+			m.sources[i].children[j] = move(m.sources[i].children[j])
+		}
+	}
 }
 
 // This function is for walking the kustomization path and
@@ -130,12 +353,14 @@ func (m *Model) walk() tea.Cmd {
 func (m *Model) followFluxKustomization(index int, fluxKust *shortApi) error {
 	log.Debug("walking", "path", fluxKust.filepath)
 	path := fluxKust.filepath
-	if !strings.HasPrefix(path, m.root) {
-		path = filepath.Join(m.root, path)
+	if !strings.HasPrefix(path, fluxKust.root) {
+		path = filepath.Join(fluxKust.root, path)
 	}
 	fp, kust := kustomize.GetKustomization(path)
 	fluxKust.kustomize = fp
-	if kust == nil || slices.Contains(kust.Resources, filepath.Base(path)) {
+	// A flux kustomization kept in the kustomization file itself is
+	// never a resource of that file so it can't be a base of it
+	if kust == nil || fp == path || slices.Contains(kust.Resources, filepath.Base(path)) {
 		fluxKust.ftype = Complete
 	} else {
 		for _, p := range kust.Patches {
@@ -150,34 +375,37 @@ func (m *Model) followFluxKustomization(index int, fluxKust *shortApi) error {
 			return err
 		}
 
-		// parse directory with kustomization
+		// parse directory with kustomization. The file may also
+		// hold flux kustomizations so carry on to match those
 		filename := d.Name()
 		filename = filename[0 : len(filename)-len(filepath.Ext(filename))]
 		if filename == kustomize.Kustomization {
 			m.followKustomization(index, path, fluxKust)
-			return nil
 		}
 
 		// parse non-kust directory
 		switch {
 		case d.Type().IsRegular():
+			matched := false
 			for i := range m.kustomizations {
-				// Match the kustomization at this path. This then becomes a child of fluxKust
-				if path == m.kustomizations[i].filepath {
-					log.Debug("Matching", "path", path, "kust", *fluxKust.Spec.Path)
-					(*fluxKust).children = append((*fluxKust).children, &m.kustomizations[i])
-					m.kustomizations[i].parent = fluxKust
-
-					if fluxKust.Spec.PostBuild != nil {
-						m.kustomizations[i].Metadata.Name = m.ParseSubstitutions(
-							m.kustomizations[i].Metadata.Name,
-							fluxKust.Spec.PostBuild.Substitute)
-						*m.kustomizations[i].Spec.Path = m.ParseSubstitutions(
-							filepath.Join(m.root, *m.kustomizations[i].Spec.Path),
-							fluxKust.Spec.PostBuild.Substitute)
-					}
-					return nil
+				// Match every flux kustomization in the file at this
+				// path. These then become children of fluxKust. A
+				// kustomization inside its own spec path is skipped
+				child := &m.kustomizations[i]
+				if child == fluxKust || path != child.GetPath() {
+					continue
 				}
+				log.Debug("Matching", "path", path, "kust", fluxKust.GetName())
+				// The kustomization file may already have linked it
+				if !slices.Contains(fluxKust.children, child) {
+					fluxKust.children = append(fluxKust.children, child)
+				}
+				child.parent = fluxKust
+				child.resolve(fluxKust.substitutions())
+				matched = true
+			}
+			if matched {
+				return nil
 			}
 			for s, v := range m.sources {
 				if v.filepath == path {
@@ -188,7 +416,20 @@ func (m *Model) followFluxKustomization(index int, fluxKust *shortApi) error {
 		return nil
 	}
 
+	// Without a spec.path flux builds from the root of the source.
+	// Walking the whole root would claim every kustomization in the
+	// repository so only a kustomization file kept at the root is
+	// followed, and only when the source was found here
 	if fluxKust.Spec.Path == nil {
+		m.setSource(index)
+		if fluxKust.Spec.Source != nil && fluxKust.source == nil {
+			log.Debug("no local source for path-less kustomization", "name", fluxKust.GetName())
+			return nil
+		}
+		file, _ := kustomize.GetKustomization(filepath.Join(fluxKust.root, kustomize.Kustomization))
+		if file != "" {
+			m.followKustomization(index, file, fluxKust)
+		}
 		return nil
 	}
 
@@ -196,49 +437,122 @@ func (m *Model) followFluxKustomization(index int, fluxKust *shortApi) error {
 	return fastwalk.Walk(&m.conf, kpath, pathFn)
 }
 
+// setSource links the kustomization at index to the source named
+// by its sourceRef
 func (m *Model) setSource(index int) {
+	k := &m.kustomizations[index]
+	if k.Spec.Source == nil {
+		return
+	}
+
+	// A source in the same repository root is preferred but one in
+	// any other scanned root is used when the kustomization refers
+	// to a source defined in another repository
+	match := -1
 	for s := range m.sources {
-		if m.kustomizations[index].Spec.Source == nil {
-			return
+		if !k.refersTo(&m.sources[s]) {
+			continue
 		}
-		if m.kustomizations[index].Spec.Source.Kind == m.sources[s].Kind {
-			var (
-				kName      = m.kustomizations[index].GetSourceName()
-				kNamespace = m.kustomizations[index].GetSourceNamespace()
-				sName      = m.sources[s].GetName()
-				sNamespace = m.sources[s].GetNamespace()
-			)
-
-			log.Debug("checking source", "kName", kName, "kNamespace",
-				kNamespace, "sName", sName, "sNamespace", sNamespace)
-			if kName == sName && kNamespace == sNamespace {
-				var has bool
-				for _, c := range m.sources[s].children {
-					// block duplication
-					if c.GetName() == kName && c.GetNamespace() == kNamespace {
-						has = true
-					}
-				}
-				if !has {
-					m.sources[s].children = append(m.sources[s].children, &m.kustomizations[index])
-					m.kustomizations[index].source = &m.sources[s]
-				}
-			}
+		if match < 0 || m.sources[s].root == k.root {
+			match = s
+		}
+		if m.sources[s].root == k.root {
+			break
 		}
 	}
+	if match < 0 {
+		return
+	}
+
+	source := &m.sources[match]
+	if !slices.Contains(source.children, k) {
+		source.children = append(source.children, k)
+	}
+	k.source = source
+}
+
+// refersTo reports if the sourceRef of the kustomization names source
+func (s *shortApi) refersTo(source *shortSource) bool {
+	if s.Spec.Source.Kind != source.Kind {
+		return false
+	}
+	log.Debug("checking source", "kName", s.GetSourceName(), "kNamespace",
+		s.GetSourceNamespace(), "sName", source.GetName(), "sNamespace", source.GetNamespace())
+	return s.GetSourceName() == source.GetName() &&
+		s.GetSourceNamespace() == source.GetNamespace()
 }
 
 func (m *Model) ParseSubstitutions(where string, substitutions map[string]string) string {
-	for k, v := range substitutions {
-		replace := fmt.Sprintf("${%s}", k)
-		where = strings.ReplaceAll(where, replace, v)
+	return substitute(where, substitutions)
+}
+
+// substitute replaces each `${key}` in where with its value.
+//
+// The envsubst defaults `${key:=default}` and `${key:-default}`
+// fall back to the default, which may itself hold variables,
+// when key is unset or empty. Variables with neither a value
+// nor a default are left as written
+func substitute(where string, substitutions map[string]string) string {
+	var out strings.Builder
+	for {
+		start := strings.Index(where, "${")
+		if start < 0 {
+			break
+		}
+		end := closingBrace(where, start+2)
+		if end < 0 {
+			break
+		}
+		out.WriteString(where[:start])
+		expr := where[start+2 : end]
+		key, fallback, hasDefault := cutDefault(expr)
+		switch value, ok := substitutions[key]; {
+		case ok && (value != "" || !hasDefault):
+			out.WriteString(value)
+		case hasDefault:
+			out.WriteString(substitute(fallback, substitutions))
+		default:
+			out.WriteString(where[start : end+1])
+		}
+		where = where[end+1:]
 	}
-	return where
+	out.WriteString(where)
+	return out.String()
 }
 
-func parseYamlFromFile(root, path string) (kustomizations []shortApi, sources []shortSource) {
+// closingBrace returns the index of the brace closing the
+// variable opened before from, or -1 if it is never closed
+func closingBrace(s string, from int) int {
+	depth := 1
+	for i := from; i < len(s); i++ {
+		switch s[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// cutDefault splits a variable expression into the key and
+// the default given with `:=` or `:-`
+func cutDefault(expr string) (key, fallback string, ok bool) {
+	for i := 0; i+1 < len(expr); i++ {
+		if expr[i] == ':' && (expr[i+1] == '=' || expr[i+1] == '-') {
+			return expr[:i], expr[i+2:], true
+		}
+	}
+	return expr, "", false
+}
+
+func parseYamlFromFile(root, path string) (kustomizations []shortApi, sources []shortSource, releases []shortRelease, configs []shortConfig) {
 	kustomizations = make([]shortApi, 0)
 	sources = make([]shortSource, 0)
+	releases = make([]shortRelease, 0)
 	f, err := os.ReadFile(filepath.Clean(path))
 	if err != nil {
 		return
@@ -246,35 +560,94 @@ func parseYamlFromFile(root, path string) (kustomizations []shortApi, sources []
 	return parseYaml(f, root, path)
 }
 
-func parseYaml(input []byte, root, path string) (kustomizations []shortApi, sources []shortSource) {
+func parseYaml(input []byte, root, path string) (kustomizations []shortApi, sources []shortSource, releases []shortRelease, configs []shortConfig) {
+	return fromDocuments(decodeYaml(input), root, path)
+}
+
+// decodedDoc is a document the scan uses along with its position
+// in the file it was read from
+type decodedDoc struct {
+	Index int      `json:"index"`
+	Doc   shortApi `json:"doc"`
+}
+
+// decodeYaml decodes the documents in input, keeping only those
+// the scan uses
+func decodeYaml(input []byte) []decodedDoc {
+	docs := make([]decodedDoc, 0)
 	dec := yaml.NewDecoder(bytes.NewReader(input))
+	for document := 0; ; document++ {
+		// Each document must decode into a fresh value, otherwise
+		// pointer fields such as sourceRef are shared between
+		// documents in the same file
+		var doc shortApi
+		if dec.Decode(&doc) != nil {
+			break
+		}
+		switch classify(doc.ApiVersion, doc.Kind) {
+		case otherDoc, kustomizeDoc:
+			continue
+		}
+		doc.Metadata.Annotations = fluxAnnotations(doc.Metadata.Annotations)
+		docs = append(docs, decodedDoc{Index: document, Doc: doc})
+	}
+	return docs
+}
 
-	var doc shortApi
-	for dec.Decode(&doc) == nil {
-		api := strings.Split(doc.ApiVersion, "/")[0]
-		switch api {
-		case kustomizationApi:
+// fromDocuments sorts the decoded documents of the file at path
+// into kustomizations, sources, helm releases and configs
+func fromDocuments(docs []decodedDoc, root, path string) (kustomizations []shortApi, sources []shortSource, releases []shortRelease, configs []shortConfig) {
+	for _, d := range docs {
+		doc, document := d.Doc, d.Index
+		switch classify(doc.ApiVersion, doc.Kind) {
+		case fluxKustomizationDoc:
 			if doc.Spec.Source != nil && doc.Spec.Source.Namespace == nil {
 				doc.Spec.Source.Namespace = doc.Metadata.Namespace
 			}
-			doc.id = uuid.NewString()[:8]
+			doc.id = components.NewID()
+			doc.document = document
+			doc.rawName = doc.Metadata.Name
+			if doc.Spec.Path != nil {
+				doc.rawPath = *doc.Spec.Path
+			}
 			doc.root = root
-			doc.filepath = strings.TrimPrefix(path, root+string(filepath.Separator))
+			doc.filepath = strings.TrimPrefix(path, root+separator)
 			log.Debug("ROOT STRING", "root", root, "filepath", doc.filepath)
 			// Everything starts out as a base until determined otherwise
 			doc.ftype = Base
 			kustomizations = append(kustomizations, doc)
-		case sourceApi:
+		case sourceDoc:
 			source := shortSource{
-				id:   uuid.NewString()[:8],
+				id:   components.NewID(),
 				Kind: doc.Kind,
 				shortMeta: shortMeta{
 					Name:      doc.Metadata.Name,
 					Namespace: doc.Metadata.Namespace,
 				},
+				bucket:   doc.Spec.BucketName,
+				endpoint: doc.Spec.Endpoint,
 				filepath: path,
+				interval: doc.Spec.Interval,
+				ref:      doc.Spec.Ref,
+				root:     root,
+				timeout:  doc.Spec.Timeout,
+				url:      doc.Spec.URL,
+			}
+			if doc.Spec.SecretRef != nil {
+				source.secret = doc.Spec.SecretRef.Name
 			}
 			sources = append(sources, source)
+		case helmReleaseDoc:
+			releases = append(releases, shortRelease{
+				shortMeta: shortMeta{
+					Name:      doc.Metadata.Name,
+					Namespace: doc.Metadata.Namespace,
+				},
+				filepath: path,
+				root:     root,
+			})
+		case configDoc:
+			configs = append(configs, newConfig(doc, root, path))
 		}
 	}
 	return
@@ -0,0 +1,448 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package flux
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/mproffitt/delorian/pkg/components"
+	"github.com/mproffitt/delorian/pkg/testutil"
+)
+
+const multiDoc = `apiVersion: kustomize.toolkit.fluxcd.io/v1
+kind: Kustomization
+metadata:
+  name: apps
+  namespace: flux-system
+spec:
+  path: ./apps
+  sourceRef:
+    kind: GitRepository
+    name: flux-system
+---
+apiVersion: source.toolkit.fluxcd.io/v1
+kind: GitRepository
+metadata:
+  name: flux-system
+  namespace: flux-system
+`
+
+func TestParseYamlDeterministicIDs(t *testing.T) {
+	previous := components.NewID
+	defer func() { components.NewID = previous }()
+
+	components.NewID = components.SequentialIDs("id")
+	kustomizations, sources, _, _ := parseYaml([]byte(multiDoc), "/repo", "/repo/clusters/apps.yaml")
+	if len(kustomizations) != 1 || len(sources) != 1 {
+		t.Fatalf("expected 1 kustomization and 1 source, got %d and %d",
+			len(kustomizations), len(sources))
+	}
+
+	if kustomizations[0].id != "id0001" {
+		t.Errorf("expected kustomization id %q, got %q", "id0001", kustomizations[0].id)
+	}
+	if sources[0].id != "id0002" {
+		t.Errorf("expected source id %q, got %q", "id0002", sources[0].id)
+	}
+}
+
+func TestRescan(t *testing.T) {
+	testutil.Setup()
+	root := t.TempDir()
+	testutil.WriteFile(t, root, filepath.Join("clusters", "apps.yaml"), multiDoc)
+
+	m := New(root)
+	m.walk()
+	if len(m.kustomizations) != 1 {
+		t.Fatalf("expected 1 kustomization, got %d", len(m.kustomizations))
+	}
+	first := m.ScannedAt()
+	if first.IsZero() {
+		t.Fatal("expected the scan time to be recorded")
+	}
+
+	testutil.WriteFile(t, root, filepath.Join("clusters", "infra.yaml"), strings.ReplaceAll(multiDoc, "apps", "infra"))
+	m.walk()
+	if len(m.kustomizations) != 2 {
+		t.Errorf("expected 2 kustomizations after rescan, got %d", len(m.kustomizations))
+	}
+	if m.ScannedAt().Before(first) {
+		t.Errorf("expected the scan time to be updated")
+	}
+}
+
+func TestSelectID(t *testing.T) {
+	testutil.Setup()
+	root := t.TempDir()
+	for _, name := range []string{"apps", "infra"} {
+		testutil.WriteFile(t, root, filepath.Join("clusters", name+".yaml"), strings.ReplaceAll(multiDoc, "apps", name))
+	}
+
+	m := New(root)
+	m.walk()
+	m.Update(ModelReadyMsg{Ready: true})
+	if m.PickerCmd() == nil {
+		t.Fatal("expected a picker once the list is ready")
+	}
+
+	last := m.list.Items()[len(m.list.Items())-1].(*shortApi)
+	m.Update(SelectMsg{ID: last.id})
+	if selected, ok := m.selectedKustomization(); !ok || selected.id != last.id {
+		t.Errorf("expected %s to be selected", last.GetName())
+	}
+}
+
+func TestPathlessKustomization(t *testing.T) {
+	testutil.Setup()
+	root := t.TempDir()
+	pathless := func(name, source string) string {
+		return "apiVersion: kustomize.toolkit.fluxcd.io/v1\nkind: Kustomization\n" +
+			"metadata:\n  name: " + name + "\n  namespace: flux-system\n" +
+			"spec:\n  sourceRef:\n    kind: GitRepository\n    name: " + source + "\n"
+	}
+
+	testutil.WriteFile(t, root, "clusters/prod/root.yaml", pathless("root", "flux-system")+
+		"---\napiVersion: source.toolkit.fluxcd.io/v1\nkind: GitRepository\n"+
+		"metadata:\n  name: flux-system\n  namespace: flux-system\n")
+	testutil.WriteFile(t, root, "clusters/prod/remote.yaml", pathless("remote", "elsewhere"))
+	testutil.WriteFile(t, root, "clusters/prod/apps.yaml", strings.ReplaceAll(multiDoc, "./apps", "./clusters/prod/apps"))
+	testutil.WriteFile(t, root, "kustomization.yaml", "resources:\n- clusters/prod/apps.yaml\n")
+
+	m := New(root)
+	m.walk()
+
+	kustomizations := make(map[string]*shortApi)
+	for i := range m.kustomizations {
+		kustomizations[m.kustomizations[i].GetName()] = &m.kustomizations[i]
+	}
+	if len(kustomizations) != 3 {
+		t.Fatalf("expected 3 kustomizations, got %d", len(kustomizations))
+	}
+
+	rootKust := kustomizations["root"]
+	if rootKust.GetAbsoluteSpecPath() != root {
+		t.Errorf("expected the spec path to default to %s, got %s", root, rootKust.GetAbsoluteSpecPath())
+	}
+	if rootKust.source == nil {
+		t.Error("expected the source to be resolved")
+	}
+	if apps := kustomizations["apps"]; apps.parent != rootKust {
+		t.Errorf("expected apps to be a child of root")
+	}
+	if !strings.Contains(rootKust.Description(), "source root") {
+		t.Errorf("expected the description to note the source root, got %q", rootKust.Description())
+	}
+
+	if remote := kustomizations["remote"]; len(remote.children) != 0 || remote.source != nil {
+		t.Errorf("expected nothing to be resolved for a remote source")
+	}
+}
+
+const ociDocs = `apiVersion: kustomize.toolkit.fluxcd.io/v1
+kind: Kustomization
+metadata:
+  name: podinfo
+  namespace: apps
+spec:
+  path: ./
+  sourceRef:
+    kind: OCIRepository
+    name: podinfo
+    namespace: flux-system
+---
+apiVersion: kustomize.toolkit.fluxcd.io/v1
+kind: Kustomization
+metadata:
+  name: local
+  namespace: flux-system
+spec:
+  path: ./
+  sourceRef:
+    kind: OCIRepository
+    name: podinfo
+---
+apiVersion: source.toolkit.fluxcd.io/v1beta2
+kind: OCIRepository
+metadata:
+  name: podinfo
+  namespace: flux-system
+spec:
+  url: oci://ghcr.io/stefanprodan/manifests/podinfo
+  ref:
+    tag: 6.5.0
+---
+apiVersion: source.toolkit.fluxcd.io/v1
+kind: GitRepository
+metadata:
+  name: podinfo
+  namespace: flux-system
+spec:
+  url: https://github.com/stefanprodan/podinfo
+`
+
+func TestOCIRepositorySource(t *testing.T) {
+	root := t.TempDir()
+	path := testutil.WriteFile(t, root, filepath.Join("clusters", "podinfo.yaml"), ociDocs)
+
+	m := New(root)
+	m.kustomizations, m.sources, _, _ = parseYamlFromFile(root, path)
+	for i := range m.kustomizations {
+		m.setSource(i)
+		source := m.kustomizations[i].GetSource()
+		if source == nil {
+			t.Fatalf("expected %s to be linked to its source", m.kustomizations[i].GetName())
+		}
+		if source.Kind != ociRepository {
+			t.Errorf("expected %s to be linked to an OCIRepository, got %s",
+				m.kustomizations[i].GetName(), source.Kind)
+		}
+	}
+
+	content := m.kustomizations[0].GetSource().GetContent()
+	want := "# artifact: oci://ghcr.io/stefanprodan/manifests/podinfo:6.5.0\n"
+	if !strings.HasPrefix(content, want) {
+		t.Errorf("expected content to start with %q, got %q", want, content)
+	}
+	if strings.Contains(content, "GitRepository") {
+		t.Errorf("expected only the OCIRepository, got %q", content)
+	}
+}
+
+func TestArtifact(t *testing.T) {
+	url := "oci://ghcr.io/org/manifests"
+	for _, tc := range []struct {
+		ref  *reference
+		want string
+	}{
+		{nil, url + ":latest"},
+		{&reference{Tag: "v1"}, url + ":v1"},
+		{&reference{Tag: "v1", Digest: "sha256:abc"}, url + "@sha256:abc"},
+		{&reference{SemVer: ">=1.0.0"}, url + ":>=1.0.0 (semver)"},
+	} {
+		s := shortSource{Kind: ociRepository, url: url, ref: tc.ref}
+		if got := s.artifact(); got != tc.want {
+			t.Errorf("expected %q, got %q", tc.want, got)
+		}
+	}
+}
+
+const sourceDocs = `apiVersion: source.toolkit.fluxcd.io/v1
+kind: GitRepository
+metadata:
+  name: fleet
+  namespace: flux-system
+spec:
+  interval: 1m
+  url: ssh://git@github.com/org/fleet
+  ref:
+    branch: main
+    tag: v1.2.0
+  secretRef:
+    name: fleet-auth
+---
+apiVersion: source.toolkit.fluxcd.io/v1
+kind: GitRepository
+metadata:
+  name: defaults
+spec:
+  url: https://github.com/org/defaults
+---
+apiVersion: source.toolkit.fluxcd.io/v1
+kind: Bucket
+metadata:
+  name: assets
+spec:
+  interval: 10m
+  endpoint: minio.example.com
+  bucketName: assets
+---
+apiVersion: source.toolkit.fluxcd.io/v1
+kind: HelmRepository
+metadata:
+  name: charts
+spec:
+  interval: 1h
+  url: https://charts.example.com
+`
+
+func TestSourceSummary(t *testing.T) {
+	_, sources, _, _ := parseYaml([]byte(sourceDocs), "/repo", "/repo/sources.yaml")
+	want := map[string][][2]string{
+		"fleet": {
+			{"url", "ssh://git@github.com/org/fleet"},
+			{"ref", "tag v1.2.0"},
+			{"interval", "1m"},
+			{"secret", "fleet-auth"},
+		},
+		"defaults": {
+			{"url", "https://github.com/org/defaults"},
+			{"ref", "default branch"},
+		},
+		"assets": {
+			{"bucket", "minio.example.com/assets"},
+			{"interval", "10m"},
+		},
+		"charts": {
+			{"url", "https://charts.example.com"},
+			{"interval", "1h"},
+		},
+	}
+	if len(sources) != len(want) {
+		t.Fatalf("expected %d sources, got %d", len(want), len(sources))
+	}
+	for _, s := range sources {
+		if got := s.summary(); !slices.Equal(got, want[s.GetName()]) {
+			t.Errorf("%s: expected %v, got %v", s.GetName(), want[s.GetName()], got)
+		}
+	}
+}
+
+func TestSubstitute(t *testing.T) {
+	subs := map[string]string{"cluster": "prod", "region": "eu", "empty": ""}
+	for _, tc := range []struct {
+		name  string
+		where string
+		want  string
+	}{
+		{"literal", "./clusters/${cluster}", "./clusters/prod"},
+		{"unset is kept", "./clusters/${missing}", "./clusters/${missing}"},
+		{"unset with := default", "./apps/${tier:=web}", "./apps/web"},
+		{"unset with :- default", "./apps/${tier:-web}", "./apps/web"},
+		{"set overrides default", "./clusters/${cluster:=dev}", "./clusters/prod"},
+		{"empty uses default", "${empty:-none}", "none"},
+		{"empty default", "apps${tier:=}", "apps"},
+		{"nested default", "${tier:=${region}-${cluster}}", "eu-prod"},
+		{"nested default unset", "${tier:=${zone:-a}}/x", "a/x"},
+		{"set skips nested default", "${cluster:=${region}}", "prod"},
+		{"several", "${region}/${cluster}/${tier:-web}", "eu/prod/web"},
+		{"unclosed", "./apps/${cluster", "./apps/${cluster"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := substitute(tc.where, subs); got != tc.want {
+				t.Errorf("expected %q, got %q", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestTabChangedSkipsLoad(t *testing.T) {
+	testutil.Setup()
+	root := t.TempDir()
+	testutil.WriteFile(t, root, filepath.Join("clusters", "apps.yaml"), multiDoc)
+
+	m := New(root)
+	m.walk()
+	m.Update(ModelReadyMsg{Ready: true})
+	_, cmd := m.Update(components.TabChangedMsg{NewTab: components.TabHealth})
+	if cmd == nil {
+		t.Fatal("expected the tab to be told there is nothing to load")
+	}
+	if _, ok := cmd().(components.LoadSkippedMsg); !ok {
+		t.Errorf("expected a LoadSkippedMsg, got %T", cmd())
+	}
+}
+
+// BenchmarkWalk scans a repository of mostly plain manifests, as
+// found in large monorepos, where parsing dominates the scan
+func BenchmarkWalk(b *testing.B) {
+	root := b.TempDir()
+	manifest := "apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: web\n" +
+		"spec:\n  template:\n    spec:\n      containers:\n        - name: web\n          image: nginx\n" +
+		"---\napiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: web\ndata:\n  level: info\n"
+	for i := range 10 {
+		name := fmt.Sprintf("app%02d", i)
+		dir := filepath.Join("apps", name)
+		kustomization := strings.NewReplacer("name: apps", "name: "+name,
+			"path: ./apps", "path: ./apps/"+name).Replace(multiDoc)
+		testutil.WriteFile(b, root, filepath.Join("apps", name+".yaml"), kustomization)
+		for j := range 200 {
+			testutil.WriteFile(b, root, filepath.Join(dir, fmt.Sprintf("web%03d.yaml", j)), manifest)
+		}
+	}
+
+	b.ResetTimer()
+	for range b.N {
+		m := New(root)
+		m.walk()
+	}
+}
+
+func TestRescanKeepsSelection(t *testing.T) {
+	testutil.Setup()
+	root := t.TempDir()
+	testutil.WriteFile(t, root, filepath.Join("clusters", "apps"+".yaml"), strings.ReplaceAll(multiDoc, "apps", "apps"))
+	testutil.WriteFile(t, root, filepath.Join("clusters", "infra"+".yaml"), strings.ReplaceAll(multiDoc, "apps", "infra"))
+
+	m := New(root)
+	m.walk()
+	m.Update(ModelReadyMsg{Ready: true})
+	m.list.Select(1)
+	selected := m.list.SelectedItem().(*shortApi).GetName()
+
+	testutil.WriteFile(t, root, filepath.Join("clusters", "base"+".yaml"), strings.ReplaceAll(multiDoc, "apps", "base"))
+	m.walk()
+	m.Update(ModelReadyMsg{Ready: true})
+	if got := m.list.SelectedItem().(*shortApi).GetName(); got != selected {
+		t.Errorf("expected %s to stay selected after the rescan, got %s", selected, got)
+	}
+}
+
+func TestNewKeepsRoots(t *testing.T) {
+	roots := []string{"/repo/a/", "/repo/b"}
+	m := New(roots...)
+	if !slices.Equal(roots, []string{"/repo/a/", "/repo/b"}) {
+		t.Errorf("expected the caller's roots to be left alone, got %v", roots)
+	}
+	if !slices.Equal(m.roots, []string{"/repo/a", "/repo/b"}) {
+		t.Errorf("expected the model's roots to be trimmed, got %v", m.roots)
+	}
+}
+
+func TestCrossRootSource(t *testing.T) {
+	testutil.Setup()
+	fleet, apps := t.TempDir(), t.TempDir()
+	source := multiDoc[strings.Index(multiDoc, "apiVersion: source"):]
+	kustomization := multiDoc[:strings.Index(multiDoc, "---")]
+	testutil.WriteFile(t, fleet, filepath.Join("clusters", "source.yaml"), source)
+	testutil.WriteFile(t, apps, filepath.Join("clusters", "apps.yaml"), kustomization)
+
+	m := New(fleet, apps)
+	m.walk()
+	if len(m.kustomizations) != 1 || len(m.sources) != 1 {
+		t.Fatalf("expected 1 kustomization and 1 source, got %d and %d",
+			len(m.kustomizations), len(m.sources))
+	}
+	linked := m.kustomizations[0].GetSource()
+	if linked == nil || linked.root != fleet {
+		t.Fatalf("expected apps to use the source in the other root, got %v", linked)
+	}
+
+	// A source with the same name in the kustomization's own root
+	// is preferred
+	testutil.WriteFile(t, apps, filepath.Join("clusters", "source.yaml"), source)
+	m.walk()
+	if linked := m.kustomizations[0].GetSource(); linked == nil || linked.root != apps {
+		t.Errorf("expected apps to use the source in its own root, got %v", linked)
+	}
+}
@@ -20,9 +20,12 @@
 package flux
 
 import (
+	"fmt"
 	"os"
+	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mproffitt/delorian/pkg/components"
 	"github.com/mproffitt/delorian/pkg/kustomize"
 )
 
@@ -34,10 +37,21 @@ const (
 	Complete
 )
 
+func (f FluxFileType) String() string {
+	switch f {
+	case Base:
+		return "base"
+	case Patch:
+		return "patch"
+	}
+	return "complete"
+}
+
 // cluster is for building a tree of how clusters fit together in the repo
 type cluster struct {
 	name     string
 	filepath string
+	root     string
 	children []*cluster
 	selected bool
 }
@@ -51,14 +65,44 @@ type shortApi struct {
 	Metadata   shortMeta `yaml:"metadata"`
 	Spec       shortSpec `yaml:"spec"`
 
+	// Data and StringData are only set on ConfigMaps and
+	// Secrets, which are decoded through the same type
+	Data       map[string]string `yaml:"data,omitempty"`
+	StringData map[string]string `yaml:"stringData,omitempty"`
+
 	id        string
 	children  []*shortApi
+	document  int
 	filepath  string
 	ftype     FluxFileType
+	issues    []components.HealthIssue
+	unmet     int
 	kustomize string
 	parent    *shortApi
 	source    *shortSource
 	root      string
+	multiroot bool
+
+	// live holds the statuses fetched from the cluster, shared
+	// with the model
+	live *liveCache
+
+	// overrides are substitutions entered by the user which
+	// take precedence over spec.postBuild.substitute
+	overrides map[string]string
+
+	// substituteFrom holds the values read from the ConfigMaps
+	// and Secrets in spec.postBuild.substituteFrom
+	substituteFrom map[string]string
+
+	// edits are the unsaved contents of overlay files, keyed by
+	// path, which are built in place of the files on disk
+	edits map[string]string
+
+	// rawName and rawPath hold the name and spec path as
+	// written, before substitutions from the parent are applied
+	rawName string
+	rawPath string
 }
 
 // shortMeta contains only the relevant information
@@ -66,6 +110,11 @@ type shortApi struct {
 type shortMeta struct {
 	Name      string  `yaml:"name"`
 	Namespace *string `yaml:"namespace,omitempty"`
+
+	// Labels and annotations describe and filter kustomizations.
+	// Only the annotations read by flux are kept
+	Labels      map[string]string `yaml:"labels,omitempty"`
+	Annotations map[string]string `yaml:"annotations,omitempty"`
 }
 
 // shortSpec is used by the kustomization type to ensure
@@ -76,27 +125,72 @@ type shortSpec struct {
 	Path      *string      `yaml:"path,omitempty"`
 	Source    *shortSource `yaml:"sourceRef,omitempty"`
 	PostBuild *postBuild   `yaml:"postBuild,omitempty"`
+	DependsOn []dependency `yaml:"dependsOn,omitempty"`
+	Interval  string       `yaml:"interval,omitempty"`
+	Timeout   string       `yaml:"timeout,omitempty"`
+
+	// The remaining fields are only set on sources, which are
+	// decoded through the same type. Buckets have an endpoint
+	// and bucket name in place of a url
+	URL        string       `yaml:"url,omitempty"`
+	Ref        *reference   `yaml:"ref,omitempty"`
+	SecretRef  *localObject `yaml:"secretRef,omitempty"`
+	Endpoint   string       `yaml:"endpoint,omitempty"`
+	BucketName string       `yaml:"bucketName,omitempty"`
+}
+
+// reference is the revision a GitRepository or OCIRepository
+// follows
+type reference struct {
+	Branch string `yaml:"branch,omitempty"`
+	Tag    string `yaml:"tag,omitempty"`
+	SemVer string `yaml:"semver,omitempty"`
+	Name   string `yaml:"name,omitempty"`
+	Commit string `yaml:"commit,omitempty"`
+	Digest string `yaml:"digest,omitempty"`
+}
+
+// localObject names an object in the same namespace
+type localObject struct {
+	Name string `yaml:"name"`
+}
+
+// dependency is a reference to another kustomization that
+// must be ready before this one is reconciled
+type dependency struct {
+	Name      string `yaml:"name"`
+	Namespace string `yaml:"namespace,omitempty"`
 }
 
 // postBuild contains relevant substitutions.
 //
-// Note: with this, we ignore ConfigMap and Secret
-// substitutions as they require accessing the cluster
-// and that would seriously impact loading performance
+// ConfigMaps and Secrets named in substituteFrom are only
+// read from the repository. Those kept in the cluster are
+// skipped as reading them would seriously impact loading
+// performance
 type postBuild struct {
-	Substitute map[string]string `yaml:"substitute,omitempty"`
+	Substitute     map[string]string `yaml:"substitute,omitempty"`
+	SubstituteFrom []substituteRef   `yaml:"substituteFrom,omitempty"`
 }
 
 // shortSource is just enough information to distinctly
-// identify a gitrepository resource type
+// identify a source such as a GitRepository or OCIRepository
 type shortSource struct {
 	shortMeta `yaml:",inline"`
 	Kind      string `yaml:"kind"`
 
+	bucket   string
 	children []*shortApi
+	endpoint string
 	filepath string
 	id       string
+	interval string
 	parent   *shortApi
+	ref      *reference
+	root     string
+	secret   string
+	timeout  string
+	url      string
 }
 
 // GetName gets the name of the source
@@ -114,9 +208,94 @@ func (s *shortSource) GetNamespace() string {
 }
 
 // GetContent for source this only reads the
-// details from the file
+// details from the file, headed by a summary of what
+// the source points at
 func (s *shortSource) GetContent() string {
-	return readFile(s.filepath)
+	options := []string{"metadata.name", s.GetName()}
+	if s.GetNamespace() != "" {
+		options = append(options, "metadata.namespace", s.GetNamespace())
+	}
+	content, err := os.ReadFile(s.filepath)
+	if err != nil {
+		return err.Error()
+	}
+	filtered, err := kustomize.FilterSource(content, s.Kind, options...)
+	if err != nil {
+		return err.Error()
+	}
+	var summary strings.Builder
+	for _, field := range s.summary() {
+		fmt.Fprintf(&summary, "# %-9s %s\n", field[0]+":", field[1])
+	}
+	return summary.String() + string(filtered)
+}
+
+// summary lists what the source points at as name, value
+// pairs. Fields missing from the source are left out
+func (s *shortSource) summary() [][2]string {
+	var fields [][2]string
+	add := func(name, value string) {
+		if value != "" {
+			fields = append(fields, [2]string{name, value})
+		}
+	}
+	switch s.Kind {
+	case ociRepository:
+		add("artifact", s.artifact())
+	case bucket:
+		if s.endpoint != "" {
+			add("bucket", s.endpoint+"/"+s.bucket)
+		}
+	default:
+		add("url", s.url)
+	}
+	if s.Kind == gitRepository {
+		add("ref", s.revision())
+	}
+	add("interval", s.interval)
+	add("secret", s.secret)
+	return fields
+}
+
+// revision describes the git reference a GitRepository
+// follows, in the order of precedence flux gives them
+func (s *shortSource) revision() string {
+	if s.ref == nil {
+		return "default branch"
+	}
+	switch {
+	case s.ref.Commit != "":
+		return "commit " + s.ref.Commit
+	case s.ref.Name != "":
+		return s.ref.Name
+	case s.ref.SemVer != "":
+		return "semver " + s.ref.SemVer
+	case s.ref.Tag != "":
+		return "tag " + s.ref.Tag
+	case s.ref.Branch != "":
+		return "branch " + s.ref.Branch
+	}
+	return "default branch"
+}
+
+// artifact returns the reference of the OCI artifact an
+// OCIRepository resolves to. Flux pulls the latest tag when
+// no ref is given
+func (s *shortSource) artifact() string {
+	if s.Kind != ociRepository || s.url == "" {
+		return ""
+	}
+	switch {
+	case s.ref == nil:
+		return s.url + ":latest"
+	case s.ref.Digest != "":
+		return s.url + "@" + s.ref.Digest
+	case s.ref.SemVer != "":
+		return fmt.Sprintf("%s:%s (semver)", s.url, s.ref.SemVer)
+	case s.ref.Tag != "":
+		return s.url + ":" + s.ref.Tag
+	}
+	return s.url + ":latest"
 }
 
 // GetPath gets the filepath for the source
@@ -124,6 +303,15 @@ func (s *shortSource) GetPath() string {
 	return s.filepath
 }
 
+// shortRelease identifies a helm release and the file it is
+// defined in so it can be shown with the kustomization which
+// applies it
+type shortRelease struct {
+	shortMeta
+	filepath string
+	root     string
+}
+
 // ModelReadyMsg is sent when the model is loaded
 type ModelReadyMsg struct {
 	Ready bool
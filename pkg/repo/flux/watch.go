@@ -0,0 +1,78 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package flux
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mproffitt/delorian/pkg/components"
+)
+
+// WatchInterval is the delay between diffs when watch mode is enabled
+var WatchInterval = 30 * time.Second
+
+// watch holds the state of the watch-and-diff loop
+type watch struct {
+	enabled     bool
+	lastChecked time.Time
+	tag         int
+}
+
+// toggleWatch starts or pauses the watch loop
+//
+// Pausing bumps the tag so any tick already in flight is ignored
+func (m *Model) toggleWatch() tea.Cmd {
+	m.watch.enabled = !m.watch.enabled
+	m.watch.tag++
+	if !m.watch.enabled {
+		return components.StatusCmd("watch", "watch paused")
+	}
+	return tea.Batch(
+		components.StatusCmd("watch", fmt.Sprintf("watching every %s", WatchInterval)),
+		components.WatchTickCmd(WatchInterval, m.watch.tag),
+	)
+}
+
+// watchTick re-runs the diff for the selected kustomization when the
+// diff tab is active and schedules the next tick
+func (m *Model) watchTick(msg components.WatchTickMsg) tea.Cmd {
+	if !m.watch.enabled || msg.Tag != m.watch.tag {
+		return nil
+	}
+
+	next := components.WatchTickCmd(WatchInterval, m.watch.tag)
+	if m.offline || m.lasttab != components.TabFluxDiff || m.list == nil {
+		return next
+	}
+
+	api, ok := m.FindSelected()
+	if !ok {
+		return next
+	}
+	m.watch.lastChecked = msg.Time
+	status := fmt.Sprintf("last checked %s", m.watch.lastChecked.Format(time.TimeOnly))
+	return tea.Batch(
+		next,
+		components.StatusCmd("watch", status),
+		tea.Sequence(components.WatchRefreshCmd(), api.(components.Flux).Diff()),
+	)
+}
@@ -0,0 +1,73 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package report
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+)
+
+// MaxLogLines is the number of log lines kept for the report
+const MaxLogLines = 100
+
+// Log keeps the most recent log lines. The logger writes to it as
+// well as to the log file so reports have context without needing
+// debug logging to have been turned on first
+var Log = &Recent{}
+
+// Recent is an io.Writer which keeps the last MaxLogLines lines
+// written to it
+type Recent struct {
+	mu      sync.Mutex
+	lines   []string
+	partial bytes.Buffer
+}
+
+// Write splits p into lines, keeping any trailing partial line
+// until the rest of it arrives
+func (r *Recent) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.partial.Write(p)
+	for {
+		line, err := r.partial.ReadString('\n')
+		if err != nil {
+			// put back the incomplete line
+			r.partial.Reset()
+			r.partial.WriteString(line)
+			break
+		}
+		r.lines = append(r.lines, strings.TrimRight(line, "\r\n"))
+	}
+	if over := len(r.lines) - MaxLogLines; over > 0 {
+		r.lines = r.lines[over:]
+	}
+	return len(p), nil
+}
+
+// Lines returns a copy of the lines kept, oldest first
+func (r *Recent) Lines() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	lines := make([]string, len(r.lines))
+	copy(lines, r.lines)
+	return lines
+}
@@ -0,0 +1,199 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// Package report bundles the state of delorian into a single file
+// which can be attached to bug reports
+package report
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/mproffitt/delorian/pkg/components"
+	"github.com/mproffitt/delorian/pkg/config"
+	"gopkg.in/yaml.v3"
+)
+
+// Filename is where the report is written, relative to the
+// working directory
+var Filename = "delorian-report.yaml"
+
+// Redact hides absolute paths and substitution values in the
+// report. It is set by the --report-redact flag
+var Redact = true
+
+// Redacted replaces values hidden from the report
+const Redacted = "<redacted>"
+
+// Tools are the commands delorian runs along with the arguments
+// which print their version
+var Tools = map[string][]string{
+	"flux":    {"version", "--client"},
+	"kubectl": {"version", "--client"},
+	"helm":    {"version", "--short"},
+}
+
+// Report is the state of the application at the time of export.
+// It contains counts rather than the contents of the repository
+type Report struct {
+	Generated  time.Time         `yaml:"generated"`
+	Redacted   bool              `yaml:"redacted"`
+	Settings   map[string]string `yaml:"settings"`
+	Config     config.Config     `yaml:"config"`
+	Tools      map[string]string `yaml:"tools"`
+	Repository Stats             `yaml:"repository"`
+	Selected   *Classification   `yaml:"selected,omitempty"`
+	Log        []string          `yaml:"log"`
+}
+
+// Stats counts what was found by the last scan
+type Stats struct {
+	Roots          int            `yaml:"roots"`
+	Clusters       int            `yaml:"clusters"`
+	Sources        int            `yaml:"sources"`
+	Kustomizations map[string]int `yaml:"kustomizations"`
+	ScannedAt      time.Time      `yaml:"scannedAt"`
+}
+
+// Classification is how the traversal understood the selected
+// kustomization
+type Classification struct {
+	Kind          string            `yaml:"kind"`
+	Name          string            `yaml:"name"`
+	Namespace     string            `yaml:"namespace,omitempty"`
+	Type          string            `yaml:"type"`
+	File          string            `yaml:"file"`
+	Document      int               `yaml:"document"`
+	Root          string            `yaml:"root"`
+	Path          string            `yaml:"path,omitempty"`
+	RawPath       string            `yaml:"rawPath,omitempty"`
+	Kustomize     string            `yaml:"kustomize,omitempty"`
+	Parent        string            `yaml:"parent,omitempty"`
+	Children      int               `yaml:"children"`
+	Source        string            `yaml:"source,omitempty"`
+	Clusters      []string          `yaml:"clusters,omitempty"`
+	Substitutions map[string]string `yaml:"substitutions,omitempty"`
+	Overrides     int               `yaml:"overrides"`
+	Edits         int               `yaml:"edits"`
+}
+
+// New creates a report for cfg including the most recent lines
+// written to Log
+func New(cfg *config.Config) *Report {
+	return &Report{
+		Generated: time.Now(),
+		Settings:  map[string]string{},
+		Config:    *cfg,
+		Tools:     map[string]string{},
+		Repository: Stats{
+			Kustomizations: map[string]int{},
+		},
+		Log: Log.Lines(),
+	}
+}
+
+// DetectTools records the version of each of the Tools. This
+// runs the tools so should be called from a tea.Cmd
+func (r *Report) DetectTools() {
+	for name, args := range Tools {
+		r.Tools[name] = version(name, args)
+	}
+}
+
+// version returns the first line printed by the tool
+func version(name string, args []string) string {
+	if _, err := components.LookPath(name); err != nil {
+		return "not found"
+	}
+	out, _, err := components.Run(context.Background(), name, args)
+	if err != nil {
+		return "error: " + err.Error()
+	}
+	line, _, _ := strings.Cut(out, "\n")
+	return line
+}
+
+// Redact hides the roots and home directory of the user in every
+// path and log line, and the values of all substitutions
+func (r *Report) Redact(roots ...string) {
+	replacer := newReplacer(roots)
+	r.Redacted = true
+
+	profiles := make([]config.Profile, len(r.Config.Profiles))
+	for i, p := range r.Config.Profiles {
+		p.Root = replacer.Replace(p.Root)
+		if p.Subpath != "" {
+			p.Subpath = Redacted
+		}
+		profiles[i] = p
+	}
+	r.Config.Profiles = profiles
+
+	for k, v := range r.Settings {
+		r.Settings[k] = replacer.Replace(v)
+	}
+	for i := range r.Log {
+		r.Log[i] = replacer.Replace(r.Log[i])
+	}
+
+	if s := r.Selected; s != nil {
+		s.File = replacer.Replace(s.File)
+		s.Root = replacer.Replace(s.Root)
+		s.Path = replacer.Replace(s.Path)
+		s.Kustomize = replacer.Replace(s.Kustomize)
+		substitutions := make(map[string]string, len(s.Substitutions))
+		for k := range s.Substitutions {
+			substitutions[k] = Redacted
+		}
+		s.Substitutions = substitutions
+	}
+}
+
+// newReplacer swaps each root for a numbered placeholder and the
+// home directory for ~. Roots are tried first as they are usually
+// inside the home directory
+func newReplacer(roots []string) *strings.Replacer {
+	pairs := make([]string, 0, 2*len(roots)+2)
+	for i, root := range roots {
+		if root == "" {
+			continue
+		}
+		placeholder := "<root>"
+		if len(roots) > 1 {
+			placeholder = fmt.Sprintf("<root%d>", i+1)
+		}
+		pairs = append(pairs, root, placeholder)
+	}
+	if home, err := os.UserHomeDir(); err == nil && home != "" && home != "/" {
+		pairs = append(pairs, home, "~")
+	}
+	return strings.NewReplacer(pairs...)
+}
+
+// Write saves the report as YAML to path
+func (r *Report) Write(path string) error {
+	content, err := yaml.Marshal(r)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, content, 0o600)
+}
@@ -0,0 +1,89 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package report
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mproffitt/delorian/pkg/config"
+	"gopkg.in/yaml.v3"
+)
+
+func TestRecent(t *testing.T) {
+	r := &Recent{}
+	_, _ = r.Write([]byte("first\nsec"))
+	_, _ = r.Write([]byte("ond\n"))
+	if got := r.Lines(); len(got) != 2 || got[1] != "second" {
+		t.Fatalf("expected partial lines to be joined, got %q", got)
+	}
+
+	for i := range MaxLogLines {
+		fmt.Fprintf(r, "line %d\n", i)
+	}
+	got := r.Lines()
+	if len(got) != MaxLogLines || got[0] != "line 0" {
+		t.Errorf("expected the oldest lines to be dropped, got %d starting %q", len(got), got[0])
+	}
+}
+
+func TestRedact(t *testing.T) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		t.Skip("no home directory")
+	}
+	root := filepath.Join(home, "src", "fleet")
+
+	cfg := &config.Config{Profiles: []config.Profile{
+		{Name: "prod", Root: root, Subpath: "clusters/prod"},
+	}}
+	r := New(cfg)
+	r.Log = []string{"scanning " + root, "reading " + filepath.Join(home, ".kube", "config")}
+	r.Selected = &Classification{
+		File:          filepath.Join(root, "apps", "podinfo.yaml"),
+		Root:          root,
+		Substitutions: map[string]string{"cluster_name": "prod-eu"},
+	}
+	r.Redact(root)
+
+	if r.Config.Profiles[0].Root != "<root>" || r.Config.Profiles[0].Subpath != Redacted {
+		t.Errorf("expected the profile paths to be redacted, got %+v", r.Config.Profiles[0])
+	}
+	if cfg.Profiles[0].Root != root {
+		t.Errorf("expected the config to be left alone, got %q", cfg.Profiles[0].Root)
+	}
+	if r.Selected.File != filepath.Join("<root>", "apps", "podinfo.yaml") {
+		t.Errorf("unexpected file %q", r.Selected.File)
+	}
+	if r.Selected.Substitutions["cluster_name"] != Redacted {
+		t.Errorf("expected substitution values to be redacted")
+	}
+
+	content, err := yaml.Marshal(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(content), home) {
+		t.Errorf("expected no home directory in the report:\n%s", content)
+	}
+}
@@ -0,0 +1,91 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// Package testutil contains helpers for driving components in tests
+// without a real terminal and comparing their rendered output against
+// golden files stored in the package `testdata` directory.
+package testutil
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	zone "github.com/lrstanley/bubblezone"
+	"github.com/mproffitt/delorian/pkg/components"
+	"github.com/muesli/termenv"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+// Setup prepares the global rendering state for deterministic output
+//
+// Colour is disabled so golden files contain layout only, the
+// global zone manager is created but disabled so that zone markers
+// are not written into the rendered output and element identifiers
+// are generated sequentially rather than at random.
+func Setup() {
+	lipgloss.SetColorProfile(termenv.Ascii)
+	lipgloss.SetHasDarkBackground(true)
+	zone.NewGlobal()
+	zone.SetEnabled(false)
+	components.NewID = components.SequentialIDs("id")
+}
+
+// Drive sends each message in turn to the model and returns the
+// resulting model. Any commands returned are discarded so that
+// timers and external processes are never started.
+func Drive(m tea.Model, msgs ...tea.Msg) tea.Model {
+	for _, msg := range msgs {
+		m, _ = m.Update(msg)
+	}
+	return m
+}
+
+// RequireGolden compares the output against the golden file named
+// after the current test. Run the tests with `-update` to rewrite
+// the golden files from the current output.
+func RequireGolden(t testing.TB, got string) {
+	t.Helper()
+	name := strings.ReplaceAll(t.Name(), "/", "_") + ".golden"
+	path := filepath.Join("testdata", name)
+
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+			t.Fatalf("unable to create testdata directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(got), 0600); err != nil {
+			t.Fatalf("unable to write golden file %q: %v", path, err)
+		}
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("unable to read golden file %q: %v", path, err)
+	}
+
+	if got != string(want) {
+		t.Errorf("output does not match %s\n--- got ---\n%s\n--- want ---\n%s",
+			path, got, string(want))
+	}
+}
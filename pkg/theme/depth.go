@@ -0,0 +1,98 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package theme
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	bmx "github.com/mproffitt/bmx/pkg/theme"
+	"github.com/muesli/termenv"
+)
+
+// DepthAuto detects the colour depth from the terminal
+const DepthAuto = "auto"
+
+// depths are the colour depths which can be forced
+var depths = map[string]termenv.Profile{
+	"truecolor": termenv.TrueColor,
+	"256":       termenv.ANSI256,
+	"16":        termenv.ANSI,
+	"none":      termenv.Ascii,
+}
+
+// Depths lists the accepted colour depths
+func Depths() string {
+	return strings.Join([]string{DepthAuto, "truecolor", "256", "16", "none"}, ", ")
+}
+
+// SetDepth forces the colour depth, or keeps the depth detected
+// from the terminal for DepthAuto.
+//
+// Hex colours are mapped to the nearest colour a 256 colour
+// terminal supports. With only 16 colours the nearest match loses
+// most of the palette so the standard ANSI colours are used
+// instead, which the terminal's own theme keeps legible
+func SetDepth(depth string) error {
+	profile := lipgloss.ColorProfile()
+	if depth != DepthAuto {
+		p, ok := depths[depth]
+		if !ok {
+			return fmt.Errorf("unknown colour depth %q, use one of %s", depth, Depths())
+		}
+		profile = p
+		lipgloss.SetColorProfile(p)
+	}
+	if profile == termenv.ANSI {
+		Colours = ansiColours()
+		bmx.Colours = bmx.ColourStyles(Colours)
+	}
+	return nil
+}
+
+// ansiColours maps the palette onto the 16 standard colours
+func ansiColours() ColourStyles {
+	ansi := func(dark, light string) lipgloss.AdaptiveColor {
+		return lipgloss.AdaptiveColor{Dark: dark, Light: light}
+	}
+	return ColourStyles{
+		Fg:           ansi("7", "0"),
+		Bg:           ansi("0", "15"),
+		SelectionBg:  ansi("8", "7"),
+		Cursor:       ansi("15", "0"),
+		BrightBlack:  ansi("8", "8"),
+		BrightBlue:   ansi("12", "4"),
+		BrightCyan:   ansi("14", "6"),
+		BrightGreen:  ansi("10", "2"),
+		BrightPurple: ansi("13", "5"),
+		BrightRed:    ansi("9", "1"),
+		BrightWhite:  ansi("15", "0"),
+		BrightYellow: ansi("11", "3"),
+		Black:        ansi("8", "8"),
+		Blue:         ansi("4", "4"),
+		Cyan:         ansi("6", "6"),
+		Green:        ansi("2", "2"),
+		Purple:       ansi("5", "5"),
+		Red:          ansi("1", "1"),
+		White:        ansi("7", "0"),
+		Yellow:       ansi("3", "3"),
+	}
+}
@@ -0,0 +1,50 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package theme
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	bmx "github.com/mproffitt/bmx/pkg/theme"
+	"github.com/muesli/termenv"
+)
+
+func TestSetDepth(t *testing.T) {
+	palette, profile := Colours, lipgloss.ColorProfile()
+	t.Cleanup(func() {
+		Colours = palette
+		bmx.Colours = bmx.ColourStyles(palette)
+		lipgloss.SetColorProfile(profile)
+	})
+
+	if err := SetDepth("256"); err != nil || lipgloss.ColorProfile() != termenv.ANSI256 {
+		t.Fatalf("expected 256 colours to be forced, got %v %v", lipgloss.ColorProfile(), err)
+	}
+	if Colours.Blue != palette.Blue {
+		t.Error("expected hex colours to be kept for 256 colour terminals")
+	}
+	if err := SetDepth("16"); err != nil || Colours.Blue.Dark != "4" {
+		t.Errorf("expected the ANSI palette for 16 colours, got %v %v", Colours.Blue, err)
+	}
+	if err := SetDepth("millions"); err == nil {
+		t.Error("expected an unknown depth to be rejected")
+	}
+}
@@ -0,0 +1,125 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package theme
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// GlyphsAuto draws plain ASCII on the linux console, which lacks
+// most of the glyphs used, and unicode everywhere else
+const GlyphsAuto = "auto"
+
+// ASCII draws the interface with plain ASCII in place of box
+// drawing characters and other glyphs, for terminals and fonts
+// which can't show them
+var ASCII bool
+
+// glyphs pairs each glyph with the ASCII drawn in its place
+var glyphs = []string{
+	// Borders and trees
+	"─", "-", "━", "-", "═", "=",
+	"│", "|", "┃", "|", "║", "|",
+	"╭", "+", "╮", "+", "╯", "+", "╰", "+",
+	"┌", "+", "┐", "+", "└", "+", "┘", "+",
+	"├", "+", "┤", "+", "┬", "+", "┴", "+", "┼", "+",
+	// Indicators
+	"►", ">", "➤", ">", "▸", ">", "⮟", "v", "±", "~",
+	"●", "*", "○", "o", "◉", "@", "•", "*", "·", "-",
+	"✔", "+", "✓", "+", "✕", "x", "⚠", "!", "ℹ", "i",
+	"✎", "*", "⇢", ">", "⛁", "#", "⟳", "@", "⬆", "^",
+	"¶", "#",
+	"∅", "0", "…", ".", "█", "#", "░", ".",
+	// Key hints
+	"←", "<", "→", ">", "↑", "^", "↓", "v",
+	"↩", "<", "↹", ">", "⇧", "^", "␣", "_",
+}
+
+// plain replaces every glyph. Replacements are padded to the width
+// of the glyph so the layout is kept
+var plain = func() *strings.Replacer {
+	pairs := make([]string, len(glyphs))
+	for i := 0; i+1 < len(glyphs); i += 2 {
+		pairs[i] = glyphs[i]
+		pairs[i+1] = glyphs[i+1] + strings.Repeat(" ",
+			max(0, ansi.StringWidth(glyphs[i])-len(glyphs[i+1])))
+	}
+	return strings.NewReplacer(pairs...)
+}()
+
+// Glyphs lists the accepted glyph modes
+func Glyphs() string {
+	return strings.Join([]string{GlyphsAuto, "unicode", "ascii"}, ", ")
+}
+
+// SetGlyphs chooses between unicode and ASCII drawing
+func SetGlyphs(mode string) error {
+	switch mode {
+	case GlyphsAuto:
+		ASCII = os.Getenv("TERM") == "linux"
+	case "unicode":
+		ASCII = false
+	case "ascii":
+		ASCII = true
+	default:
+		return fmt.Errorf("unknown glyphs %q, use one of %s", mode, Glyphs())
+	}
+	return nil
+}
+
+// Glyph returns s with its glyphs replaced by ASCII when ASCII is
+// set, or unchanged otherwise. It is only for text drawn by the
+// interface itself, never for manifests or other user content
+func Glyph(s string) string {
+	if !ASCII {
+		return s
+	}
+	return plain.Replace(s)
+}
+
+// Border returns b drawn with Glyph
+func Border(b lipgloss.Border) lipgloss.Border {
+	if !ASCII {
+		return b
+	}
+	for _, side := range []*string{
+		&b.Top, &b.Bottom, &b.Left, &b.Right,
+		&b.TopLeft, &b.TopRight, &b.BottomLeft, &b.BottomRight,
+		&b.MiddleLeft, &b.MiddleRight, &b.Middle, &b.MiddleTop, &b.MiddleBottom,
+	} {
+		*side = Glyph(*side)
+	}
+	return b
+}
+
+// RoundedBorder is lipgloss.RoundedBorder drawn with Glyph
+func RoundedBorder() lipgloss.Border {
+	return Border(lipgloss.RoundedBorder())
+}
+
+// NormalBorder is lipgloss.NormalBorder drawn with Glyph
+func NormalBorder() lipgloss.Border {
+	return Border(lipgloss.NormalBorder())
+}
@@ -0,0 +1,70 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package theme
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestGlyph(t *testing.T) {
+	t.Cleanup(func() { ASCII = false })
+
+	if Glyph("► drifted ±") != "► drifted ±" {
+		t.Error("expected unicode to be kept by default")
+	}
+
+	if err := SetGlyphs("ascii"); err != nil {
+		t.Fatal(err)
+	}
+	if got := Glyph("► drifted ±"); got != "> drifted ~" {
+		t.Errorf("expected glyphs to be swapped, got %q", got)
+	}
+	for i := 0; i+1 < len(glyphs); i += 2 {
+		if got := Glyph(glyphs[i]); ansi.StringWidth(got) != ansi.StringWidth(glyphs[i]) {
+			t.Errorf("expected %q to keep its width, got %q", glyphs[i], got)
+		}
+	}
+
+	if err := SetGlyphs("fancy"); err == nil {
+		t.Error("expected an unknown mode to be rejected")
+	}
+}
+
+func TestBorder(t *testing.T) {
+	t.Cleanup(func() { ASCII = false })
+
+	// Content is drawn as given, only the border is swapped
+	render := func() string {
+		return lipgloss.NewStyle().Border(RoundedBorder()).Render("► │")
+	}
+	if got := render(); got != "╭───╮\n│► ││\n╰───╯" {
+		t.Errorf("expected a rounded border by default, got\n%s", got)
+	}
+	ASCII = true
+	if got := render(); got != "+---+\n|► │|\n+---+" {
+		t.Errorf("expected an ASCII border, got\n%s", got)
+	}
+	if got := Border(TabActiveBorder); got.TopLeft != "+" || got.Bottom != " " {
+		t.Errorf("expected the tab border to be swapped, got %+v", got)
+	}
+}
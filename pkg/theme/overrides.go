@@ -0,0 +1,98 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package theme
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	bmx "github.com/mproffitt/bmx/pkg/theme"
+	yaml "gopkg.in/yaml.v3"
+)
+
+// hex matches the #rgb and #rrggbb colours lipgloss accepts
+var hex = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// override is a colour pair from the theme file. Either side may
+// be left out to keep the default
+type override struct {
+	Dark  string `yaml:"dark"`
+	Light string `yaml:"light"`
+}
+
+// Load reads colour overrides from filename, keyed by the names of
+// the ColourStyles fields ignoring case. Colours which are not
+// given keep their defaults. Unknown keys and invalid hex values
+// are returned as warnings and the default kept in their place.
+//
+// A missing file is not an error. Call Load before SetDepth, as
+// the 16 colour palette replaces any overrides
+func Load(filename string) ([]string, error) {
+	content, err := os.ReadFile(filename)
+	switch {
+	case os.IsNotExist(err):
+		return nil, nil
+	case err != nil:
+		return nil, err
+	}
+	overrides := make(map[string]override)
+	if err := yaml.Unmarshal(content, &overrides); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", filename, err)
+	}
+
+	warnings := make([]string, 0)
+	colours := reflect.ValueOf(&Colours).Elem()
+	for key, o := range overrides {
+		field := colours.FieldByNameFunc(func(name string) bool {
+			return strings.EqualFold(name, key)
+		})
+		if !field.IsValid() {
+			warnings = append(warnings, fmt.Sprintf("unknown colour %q", key))
+			continue
+		}
+		colour := field.Interface().(lipgloss.AdaptiveColor)
+		for _, side := range []struct {
+			name  string
+			value string
+			into  *string
+		}{
+			{"dark", o.Dark, &colour.Dark},
+			{"light", o.Light, &colour.Light},
+		} {
+			switch {
+			case side.value == "":
+			case !hex.MatchString(side.value):
+				warnings = append(warnings, fmt.Sprintf(
+					"%s.%s: %q is not a hex colour, using the default", key, side.name, side.value))
+			default:
+				*side.into = side.value
+			}
+		}
+		field.Set(reflect.ValueOf(colour))
+	}
+	bmx.Colours = bmx.ColourStyles(Colours)
+	slices.Sort(warnings)
+	return warnings, nil
+}
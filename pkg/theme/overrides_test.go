@@ -0,0 +1,80 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package theme
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	bmx "github.com/mproffitt/bmx/pkg/theme"
+)
+
+func TestLoad(t *testing.T) {
+	palette := Colours
+	t.Cleanup(func() {
+		Colours = palette
+		bmx.Colours = bmx.ColourStyles(palette)
+	})
+
+	filename := filepath.Join(t.TempDir(), "theme.yaml")
+	content := `blue:
+  dark: "#0000ff"
+brightBlack:
+  dark: "#123"
+  light: navy
+purple:
+  light: "#abcdef"
+orange:
+  dark: "#ff8800"
+`
+	if err := os.WriteFile(filename, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	warnings, err := Load(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		`brightBlack.light: "navy" is not a hex colour, using the default`,
+		`unknown colour "orange"`,
+	}
+	if !slices.Equal(warnings, want) {
+		t.Errorf("expected %q, got %q", want, warnings)
+	}
+
+	if Colours.Blue.Dark != "#0000ff" || Colours.Blue.Light != palette.Blue.Light {
+		t.Errorf("expected only the dark blue to change, got %v", Colours.Blue)
+	}
+	if Colours.BrightBlack.Dark != "#123" || Colours.BrightBlack.Light != palette.BrightBlack.Light {
+		t.Errorf("expected the invalid light colour to keep its default, got %v", Colours.BrightBlack)
+	}
+	if Colours.Purple.Light != "#abcdef" || Colours.Red != palette.Red {
+		t.Errorf("expected unlisted colours to keep their defaults, got %v %v", Colours.Purple, Colours.Red)
+	}
+	if bmx.Colours.Blue.Dark != "#0000ff" {
+		t.Error("expected the overrides to be shared with bmx")
+	}
+
+	if warnings, err := Load(filepath.Join(t.TempDir(), "missing.yaml")); err != nil || len(warnings) != 0 {
+		t.Errorf("expected a missing file to be ignored, got %v %v", warnings, err)
+	}
+}
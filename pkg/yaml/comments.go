@@ -0,0 +1,122 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package yaml
+
+import (
+	"bytes"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+// Comments copies the comments written in source onto the matching
+// nodes of rendered, which kustomize builds without them. Mapping
+// keys are matched by name and sequence items by their name field,
+// or by position when they have none. Rendered is returned unchanged
+// when nothing is copied or either document fails to parse
+func Comments(rendered, source string) string {
+	var to, from yaml.Node
+	if yaml.Unmarshal([]byte(rendered), &to) != nil || yaml.Unmarshal([]byte(source), &from) != nil {
+		return rendered
+	}
+	if !copyComments(&to, &from) {
+		return rendered
+	}
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&to); err != nil {
+		return rendered
+	}
+	return buf.String()
+}
+
+// copyComments copies the comments of from and its children onto
+// to, keeping any to already has. It reports if any were copied
+func copyComments(to, from *yaml.Node) bool {
+	copied := false
+	for _, c := range []struct{ to, from *string }{
+		{&to.HeadComment, &from.HeadComment},
+		{&to.LineComment, &from.LineComment},
+		{&to.FootComment, &from.FootComment},
+	} {
+		if *c.to == "" && *c.from != "" {
+			*c.to = *c.from
+			copied = true
+		}
+	}
+	if to.Kind != from.Kind {
+		return copied
+	}
+
+	switch to.Kind {
+	case yaml.DocumentNode:
+		for i := range min(len(to.Content), len(from.Content)) {
+			copied = copyComments(to.Content[i], from.Content[i]) || copied
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(from.Content); i += 2 {
+			for j := 0; j+1 < len(to.Content); j += 2 {
+				if to.Content[j].Value != from.Content[i].Value {
+					continue
+				}
+				copied = copyComments(to.Content[j], from.Content[i]) || copied
+				copied = copyComments(to.Content[j+1], from.Content[i+1]) || copied
+				break
+			}
+		}
+	case yaml.SequenceNode:
+		for i, item := range from.Content {
+			if j := matchItem(to.Content, item, i); j >= 0 {
+				copied = copyComments(to.Content[j], item) || copied
+			}
+		}
+	}
+	return copied
+}
+
+// matchItem returns the index of the item in items matching item,
+// found at index in its own sequence, or -1 when there is none
+func matchItem(items []*yaml.Node, item *yaml.Node, index int) int {
+	if name := field(item, "name"); name != "" {
+		for i, candidate := range items {
+			if field(candidate, "name") == name {
+				return i
+			}
+		}
+		return -1
+	}
+	if index < len(items) {
+		return index
+	}
+	return -1
+}
+
+// field returns the scalar value of key in a mapping node
+func field(n *yaml.Node, key string) string {
+	if n.Kind != yaml.MappingNode {
+		return ""
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if n.Content[i].Value == key && n.Content[i+1].Kind == yaml.ScalarNode {
+			return n.Content[i+1].Value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,72 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package yaml
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestComments(t *testing.T) {
+	source := `# podinfo serves the demo page
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: podinfo
+spec:
+  replicas: 2 # scaled by the hpa
+  template:
+    spec:
+      containers:
+        # the app itself, the sidecar is added later
+        - name: podinfo
+          image: ghcr.io/stefanprodan/podinfo:6.0.0
+`
+	rendered := `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: podinfo
+  namespace: apps
+spec:
+  replicas: 2
+  template:
+    spec:
+      containers:
+      - image: envoy
+        name: sidecar
+      - image: ghcr.io/stefanprodan/podinfo:6.0.0
+        name: podinfo
+`
+	got := Comments(rendered, source)
+	for _, want := range []string{
+		"# podinfo serves the demo page",
+		"replicas: 2 # scaled by the hpa",
+		"# the app itself, the sidecar is added later\n        - image: ghcr.io",
+		"namespace: apps",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q in\n%s", want, got)
+		}
+	}
+
+	if got := Comments(rendered, "kind: Deployment\n"); got != rendered {
+		t.Errorf("expected the rendered document unchanged without comments, got\n%s", got)
+	}
+}
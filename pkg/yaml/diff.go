@@ -0,0 +1,263 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package yaml
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+// object is a single kubernetes object found in a multi
+// document stream
+type object struct {
+	id      string
+	content map[string]any
+}
+
+// Diff compares two multi document streams of kubernetes objects
+// and describes the differences in the same format as `flux diff`
+// so the output can be displayed by the diff view.
+//
+// Objects are matched on kind, namespace and name. Objects only
+// present in to are reported as created and those only present in
+// from as deleted
+func Diff(from, to []byte) (string, error) {
+	before, err := objects(from)
+	if err != nil {
+		return "", err
+	}
+	after, err := objects(to)
+	if err != nil {
+		return "", err
+	}
+
+	ids := make([]string, 0, len(after))
+	for _, o := range after {
+		ids = append(ids, o.id)
+	}
+	for _, o := range before {
+		if !slices.Contains(ids, o.id) {
+			ids = append(ids, o.id)
+		}
+	}
+
+	var out strings.Builder
+	for _, id := range ids {
+		b, inBefore := find(before, id)
+		a, inAfter := find(after, id)
+		switch {
+		case !inBefore:
+			fmt.Fprintf(&out, "► %s created\n\n", id)
+		case !inAfter:
+			fmt.Fprintf(&out, "► %s deleted\n\n", id)
+		default:
+			changes := compare("", b.content, a.content)
+			if len(changes) == 0 {
+				continue
+			}
+			fmt.Fprintf(&out, "► %s drifted\n\n", id)
+			for _, c := range changes {
+				out.WriteString(c)
+				out.WriteString("\n")
+			}
+		}
+	}
+	return out.String(), nil
+}
+
+func objects(input []byte) ([]object, error) {
+	dec := yaml.NewDecoder(bytes.NewReader(input))
+	result := make([]object, 0)
+	for {
+		var doc map[string]any
+		err := dec.Decode(&doc)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if doc == nil {
+			continue
+		}
+		metadata, _ := doc["metadata"].(map[string]any)
+		result = append(result, object{
+			id: fmt.Sprintf("%v/%v/%v", doc["kind"],
+				value(metadata, "namespace"), value(metadata, "name")),
+			content: doc,
+		})
+	}
+	return result, nil
+}
+
+func value(m map[string]any, key string) any {
+	if v, ok := m[key]; ok {
+		return v
+	}
+	return ""
+}
+
+func find(objects []object, id string) (object, bool) {
+	for _, o := range objects {
+		if o.id == id {
+			return o, true
+		}
+	}
+	return object{}, false
+}
+
+// compare returns a change block for each difference between
+// from and to beneath path
+func compare(path string, from, to any) []string {
+	if reflect.DeepEqual(from, to) {
+		return nil
+	}
+
+	fromMap, fromOk := from.(map[string]any)
+	toMap, toOk := to.(map[string]any)
+	if fromOk && toOk {
+		changes := make([]string, 0)
+		keys := slices.Sorted(maps.Keys(fromMap))
+		for _, k := range slices.Sorted(maps.Keys(toMap)) {
+			if _, ok := fromMap[k]; !ok {
+				keys = append(keys, k)
+			}
+		}
+		for _, k := range keys {
+			f, inFrom := fromMap[k]
+			t, inTo := toMap[k]
+			switch {
+			case !inFrom:
+				changes = append(changes, block(path, "+ one map entry added:",
+					"", indent(map[string]any{k: t}, "    ")))
+			case !inTo:
+				changes = append(changes, block(path, "- one map entry removed:",
+					"", indent(map[string]any{k: f}, "    ")))
+			default:
+				changes = append(changes, compare(join(path, k), f, t)...)
+			}
+		}
+		return changes
+	}
+
+	fromList, fromOk := from.([]any)
+	toList, toOk := to.([]any)
+	if fromOk && toOk && len(fromList) == len(toList) {
+		changes := make([]string, 0)
+		for i := range fromList {
+			changes = append(changes, compare(join(path, fmt.Sprint(i)), fromList[i], toList[i])...)
+		}
+		return changes
+	}
+
+	return []string{block(path, "± value change",
+		indent(from, "    - "), indent(to, "    + "))}
+}
+
+// block formats a single change in the `flux diff` layout
+func block(path, title string, lines ...string) string {
+	if path == "" {
+		path = "(root level)"
+	}
+	parts := []string{path, "  " + title}
+	for _, l := range lines {
+		if l != "" {
+			parts = append(parts, l)
+		}
+	}
+	return strings.Join(parts, "\n") + "\n"
+}
+
+// indent renders a value as yaml with each non empty line
+// prefixed by prefix
+func indent(v any, prefix string) string {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(v); err != nil {
+		buf.Reset()
+		buf.WriteString(fmt.Sprint(v))
+	}
+	lines := make([]string, 0)
+	for _, line := range strings.Split(strings.TrimRight(buf.String(), "\n"), "\n") {
+		if strings.TrimSpace(line) != "" {
+			lines = append(lines, prefix+line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func join(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// Restrict returns the object in live limited to the fields set in
+// desired. The cluster adds defaults, status and metadata to every
+// object it stores. Restricting the live object to what was applied
+// keeps these out of a diff against the desired state
+func Restrict(live, desired []byte) ([]byte, error) {
+	var l, d any
+	if err := yaml.Unmarshal(live, &l); err != nil {
+		return nil, err
+	}
+	if err := yaml.Unmarshal(desired, &d); err != nil {
+		return nil, err
+	}
+	if l == nil {
+		return nil, nil
+	}
+	return yaml.Marshal(restrict(l, d))
+}
+
+func restrict(live, desired any) any {
+	liveMap, liveOk := live.(map[string]any)
+	desiredMap, desiredOk := desired.(map[string]any)
+	if liveOk && desiredOk {
+		kept := make(map[string]any)
+		for k, v := range desiredMap {
+			if l, ok := liveMap[k]; ok {
+				kept[k] = restrict(l, v)
+			}
+		}
+		return kept
+	}
+
+	liveList, liveOk := live.([]any)
+	desiredList, desiredOk := desired.([]any)
+	if liveOk && desiredOk && len(liveList) == len(desiredList) {
+		kept := make([]any, len(liveList))
+		for i := range liveList {
+			kept[i] = restrict(liveList[i], desiredList[i])
+		}
+		return kept
+	}
+	return live
+}
@@ -0,0 +1,132 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package yaml
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	from := `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: podinfo
+  namespace: default
+spec:
+  replicas: 1
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: legacy
+  namespace: default
+`
+	to := `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: podinfo
+  namespace: default
+  labels:
+    app: podinfo
+spec:
+  replicas: 2
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: podinfo
+  namespace: default
+`
+	got, err := Diff([]byte(from), []byte(to))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := `► Deployment/default/podinfo drifted
+
+metadata
+  + one map entry added:
+    labels:
+      app: podinfo
+
+spec.replicas
+  ± value change
+    - 1
+    + 2
+
+► Service/default/podinfo created
+
+► ConfigMap/default/legacy deleted
+
+`
+	if got != want {
+		t.Errorf("unexpected diff\ngot:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestDiffIdentical(t *testing.T) {
+	doc := "kind: ConfigMap\nmetadata:\n  name: a\ndata:\n  key: value\n"
+	got, err := Diff([]byte(doc), []byte(doc))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != "" {
+		t.Errorf("expected no diff, got %q", got)
+	}
+}
+
+func TestRestrict(t *testing.T) {
+	live := `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: app
+  namespace: default
+  uid: 1234
+  resourceVersion: "42"
+data:
+  colour: red
+  extra: value
+`
+	desired := `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: app
+  namespace: default
+data:
+  colour: blue
+`
+	restricted, err := Restrict([]byte(live), []byte(desired))
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := Diff(restricted, []byte(desired))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, "data.colour") {
+		t.Errorf("expected the changed value to be reported, got\n%s", out)
+	}
+	for _, unwanted := range []string{"uid", "resourceVersion", "extra"} {
+		if strings.Contains(out, unwanted) {
+			t.Errorf("expected %s to be ignored, got\n%s", unwanted, out)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+package yaml
+
+import (
+	"fmt"
+	"strings"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+// Document is a single kubernetes object from a multi document
+// stream, holding the exact text it was written with
+type Document struct {
+	Kind      string
+	Namespace string
+	Name      string
+	Raw       string
+
+	// Line is where the document starts in the stream, counting
+	// from zero
+	Line int
+}
+
+// Title identifies the document as kind/namespace/name, or
+// kind/name for cluster scoped objects
+func (d Document) Title() string {
+	if d.Namespace == "" {
+		return fmt.Sprintf("%s/%s", d.Kind, d.Name)
+	}
+	return fmt.Sprintf("%s/%s/%s", d.Kind, d.Namespace, d.Name)
+}
+
+// Documents splits a multi document stream on its `---`
+// separators. Documents which are empty or have no kind are
+// skipped
+func Documents(input string) []Document {
+	documents := make([]Document, 0)
+	for _, c := range split(input) {
+		raw := c.text
+		var object struct {
+			Kind     string `yaml:"kind"`
+			Metadata struct {
+				Name      string `yaml:"name"`
+				Namespace string `yaml:"namespace"`
+			} `yaml:"metadata"`
+		}
+		if err := yaml.Unmarshal([]byte(raw), &object); err != nil || object.Kind == "" {
+			continue
+		}
+		documents = append(documents, Document{
+			Kind:      object.Kind,
+			Namespace: object.Metadata.Namespace,
+			Name:      object.Metadata.Name,
+			Raw:       raw,
+			Line:      c.line,
+		})
+	}
+	return documents
+}
+
+// chunk is the text of a document and the line it starts on
+type chunk struct {
+	text string
+	line int
+}
+
+// split returns the text of each document in input
+func split(input string) []chunk {
+	chunks := make([]chunk, 0)
+	current := make([]string, 0)
+	start := 0
+	flush := func() {
+		joined := strings.Join(current, "\n")
+		text := strings.Trim(joined, "\n")
+		if strings.TrimSpace(text) != "" {
+			skipped := len(joined) - len(strings.TrimLeft(joined, "\n"))
+			chunks = append(chunks, chunk{text: text + "\n", line: start + skipped})
+		}
+		current = current[:0]
+	}
+	for i, line := range strings.Split(input, "\n") {
+		if strings.HasPrefix(line, "---") && strings.TrimSpace(strings.TrimPrefix(line, "---")) == "" {
+			flush()
+			start = i + 1
+			continue
+		}
+		current = append(current, line)
+	}
+	flush()
+	return chunks
+}
@@ -0,0 +1,66 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+package yaml
+
+import "testing"
+
+func TestDocuments(t *testing.T) {
+	input := `---
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: podinfo
+---
+# comments are kept
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: podinfo-config
+  namespace: podinfo
+data:
+  key: "value"
+---
+---
+`
+	docs := Documents(input)
+	if len(docs) != 2 {
+		t.Fatalf("expected 2 documents, got %d", len(docs))
+	}
+	if docs[0].Title() != "Namespace/podinfo" {
+		t.Errorf("unexpected title %q", docs[0].Title())
+	}
+	if docs[1].Title() != "ConfigMap/podinfo/podinfo-config" {
+		t.Errorf("unexpected title %q", docs[1].Title())
+	}
+	want := `# comments are kept
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: podinfo-config
+  namespace: podinfo
+data:
+  key: "value"
+`
+	if docs[1].Raw != want {
+		t.Errorf("raw document not preserved\ngot:\n%s\nwant:\n%s", docs[1].Raw, want)
+	}
+	if docs[0].Line != 1 || docs[1].Line != 6 {
+		t.Errorf("expected the documents to start on lines 1 and 6, got %d and %d", docs[0].Line, docs[1].Line)
+	}
+}
@@ -0,0 +1,55 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package yaml
+
+import (
+	"strings"
+
+	"github.com/mikefarah/yq/v4/pkg/yqlib"
+)
+
+// imagesExpression finds every string `image` field in a document.
+//
+// This covers containers, init containers and ephemeral containers
+// in any workload as well as image fields in custom resources
+const imagesExpression = `.. | select(tag == "!!map" and has("image")) | .image | select(tag == "!!str")`
+
+// Images returns every container image referenced in the input
+// in the order they are found. The result is not deduplicated
+func Images(input []byte) ([]string, error) {
+	prefs := yqlib.NewDefaultYamlPreferences()
+	decoder := yqlib.NewYamlDecoder(prefs)
+	encoder := yqlib.NewYamlEncoder(prefs)
+	output, err := yqlib.NewStringEvaluator().
+		Evaluate(imagesExpression, string(input), encoder, decoder)
+	if err != nil {
+		return nil, err
+	}
+
+	images := make([]string, 0)
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || line == "---" {
+			continue
+		}
+		images = append(images, strings.Trim(line, `"'`))
+	}
+	return images, nil
+}
@@ -0,0 +1,108 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package yaml
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// Field is a value which differs between the streams compared by
+// Matrix. Values and Present hold one entry for each stream, in
+// the order the streams were given
+type Field struct {
+	Object  string
+	Path    string
+	Values  []string
+	Present []bool
+}
+
+// Matrix compares any number of multi document streams of
+// kubernetes objects field by field and returns every field whose
+// value is not the same in all of them, sorted by object and path.
+//
+// Objects are matched on kind, namespace and name as in Diff. A
+// field missing from a stream, including one from an object the
+// stream doesn't have, is reported as not present
+func Matrix(streams ...[]byte) ([]Field, error) {
+	type id struct{ object, path string }
+	flat := make([]map[id]string, len(streams))
+	ids := make(map[id]bool)
+	for i, stream := range streams {
+		objs, err := objects(stream)
+		if err != nil {
+			return nil, err
+		}
+		flat[i] = make(map[id]string)
+		for _, o := range objs {
+			values := make(map[string]string)
+			flatten("", o.content, values)
+			for path, v := range values {
+				flat[i][id{o.id, path}] = v
+				ids[id{o.id, path}] = true
+			}
+		}
+	}
+
+	fields := make([]Field, 0)
+	for _, key := range slices.SortedFunc(maps.Keys(ids), func(a, b id) int {
+		return cmp.Or(cmp.Compare(a.object, b.object), cmp.Compare(a.path, b.path))
+	}) {
+		field := Field{Object: key.object, Path: key.path,
+			Values: make([]string, len(streams)), Present: make([]bool, len(streams))}
+		same := true
+		for i := range streams {
+			field.Values[i], field.Present[i] = flat[i][key]
+			same = same && field.Present[i] == field.Present[0] &&
+				field.Values[i] == field.Values[0]
+		}
+		if !same {
+			fields = append(fields, field)
+		}
+	}
+	return fields, nil
+}
+
+// flatten records every scalar beneath path in v. Empty maps and
+// lists are kept so adding the first entry shows as a difference
+func flatten(path string, v any, into map[string]string) {
+	switch v := v.(type) {
+	case map[string]any:
+		if len(v) == 0 {
+			into[path] = "{}"
+		}
+		for k, child := range v {
+			flatten(join(path, k), child, into)
+		}
+	case []any:
+		if len(v) == 0 {
+			into[path] = "[]"
+		}
+		for i, child := range v {
+			flatten(join(path, fmt.Sprint(i)), child, into)
+		}
+	case nil:
+		into[path] = "null"
+	default:
+		into[path] = fmt.Sprint(v)
+	}
+}
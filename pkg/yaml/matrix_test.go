@@ -0,0 +1,83 @@
+// Copyright (c) 2025 Martin Proffitt <mprooffitt@choclab.net>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of
+// the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS
+// FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR
+// COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER
+// IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package yaml
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMatrix(t *testing.T) {
+	dev := []byte(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: podinfo
+  namespace: apps
+spec:
+  replicas: 1
+  template:
+    spec:
+      containers:
+        - name: podinfo
+          image: podinfo:6.0.0
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: only-dev
+  namespace: apps
+`)
+	prod := []byte(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: podinfo
+  namespace: apps
+spec:
+  replicas: 3
+  template:
+    spec:
+      containers:
+        - name: podinfo
+          image: podinfo:6.0.0
+`)
+	fields, err := Matrix(dev, prod)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Field{
+		{Object: "ConfigMap/apps/only-dev", Path: "apiVersion",
+			Values: []string{"v1", ""}, Present: []bool{true, false}},
+		{Object: "ConfigMap/apps/only-dev", Path: "kind",
+			Values: []string{"ConfigMap", ""}, Present: []bool{true, false}},
+		{Object: "ConfigMap/apps/only-dev", Path: "metadata.name",
+			Values: []string{"only-dev", ""}, Present: []bool{true, false}},
+		{Object: "ConfigMap/apps/only-dev", Path: "metadata.namespace",
+			Values: []string{"apps", ""}, Present: []bool{true, false}},
+		{Object: "Deployment/apps/podinfo", Path: "spec.replicas",
+			Values: []string{"1", "3"}, Present: []bool{true, true}},
+	}
+	if !reflect.DeepEqual(fields, want) {
+		t.Errorf("expected %+v, got %+v", want, fields)
+	}
+
+	if fields, err := Matrix(prod, prod); err != nil || len(fields) != 0 {
+		t.Errorf("expected identical streams to match, got %+v %v", fields, err)
+	}
+}